@@ -12,6 +12,7 @@ import (
 	"novels-backend/internal/config"
 	"novels-backend/internal/database"
 	"novels-backend/internal/http/routes"
+	"novels-backend/internal/telemetry"
 	"novels-backend/pkg/logger"
 
 	"github.com/rs/zerolog"
@@ -29,6 +30,17 @@ func main() {
 		Str("port", cfg.Server.Port).
 		Msg("Starting Novels API server")
 
+	// Трейсинг (OpenTelemetry); без OTEL_EXPORTER_OTLP_ENDPOINT span-ы не экспортируются
+	shutdownTracing, err := telemetry.InitTracing(context.Background(), telemetry.TracingOptions{
+		Endpoint:    cfg.Telemetry.OTLPEndpoint,
+		Insecure:    cfg.Telemetry.OTLPInsecure,
+		ServiceName: cfg.Telemetry.ServiceName,
+		SampleRatio: cfg.Telemetry.SampleRatio,
+	}, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to init tracing")
+	}
+
 	// Подключаемся к базе данных
 	db, err := database.Connect(cfg.Database)
 	if err != nil {
@@ -38,13 +50,17 @@ func main() {
 
 	log.Info().Msg("Connected to database")
 
-	// Запускаем миграции
-	if err := database.RunMigrations(db); err != nil {
-		log.Fatal().Err(err).Msg("Failed to run migrations")
+	// Запускаем миграции (при DB_AUTO_MIGRATE=false их применяет cmd/migrate,
+	// а /readyz не пускает трафик, пока остаются неприменённые)
+	if cfg.Database.AutoMigrate {
+		if err := database.RunMigrations(db); err != nil {
+			log.Fatal().Err(err).Msg("Failed to run migrations")
+		}
+		log.Info().Msg("Database migrations completed")
+	} else {
+		log.Info().Msg("Auto-migrate disabled, skipping migrations")
 	}
 
-	log.Info().Msg("Database migrations completed")
-
 	// Создаём роутер
 	router, scheduler := routes.NewRouter(db, cfg, log)
 
@@ -76,15 +92,38 @@ func main() {
 
 	log.Info().Msg("Shutting down server...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
-	// Stop scheduler first (so jobs won't run during shutdown)
-	schedulerCancel()
-	scheduler.Stop()
+	// 1. /readyz отвечает 503, импорты перестают забираться из очереди, активные run'ы
+	//    останавливаются по чекпоинту и возвращаются в очередь для других инстансов
+	scheduler.Drain(ctx)
+
+	// 2. Даём балансировщику заметить 503 и перестать слать запросы
+	if cfg.Server.DrainDelay > 0 {
+		log.Info().Dur("delay", cfg.Server.DrainDelay).Msg("Waiting for load balancer to drain the instance")
+		select {
+		case <-time.After(cfg.Server.DrainDelay):
+		case <-ctx.Done():
+		}
+	}
 
+	// 3. Дожидаемся текущих HTTP-запросов
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal().Err(err).Msg("Server forced to shutdown")
+		log.Error().Err(err).Msg("Server forced to shutdown")
+	}
+
+	// 4. Дожидаемся идущих фоновых задач; по истечении таймаута они отменяются
+	if err := scheduler.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Background jobs did not finish in time")
+	}
+	schedulerCancel()
+
+	// Отправляем накопленные span-ы
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer flushCancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		log.Warn().Err(err).Msg("Failed to flush traces")
 	}
 
 	log.Info().Msg("Server exited gracefully")
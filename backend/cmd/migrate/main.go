@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"novels-backend/internal/config"
+	"novels-backend/internal/database"
+)
+
+const usage = `Usage: migrate [flags] <command>
+
+Commands:
+  up            apply all pending migrations
+  down [N]      roll back the last N applied migrations (default 1)
+  status        list migrations and whether they are applied
+  redo          roll back and re-apply the last applied migration
+  create NAME   create the next NNN_NAME.up.sql / .down.sql pair in -dir
+
+Flags:
+`
+
+func main() {
+	var dryRun bool
+	var dir string
+
+	flag.BoolVar(&dryRun, "dry-run", false, "Print what would be applied or rolled back without changing the database")
+	flag.StringVar(&dir, "dir", "internal/database/migrations", "Migrations directory (for create)")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "ERROR: create requires a migration name")
+			os.Exit(2)
+		}
+		up, down, err := database.CreateMigration(dir, args[1], time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ERROR:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Created", up)
+		fmt.Println("Created", down)
+		return
+	}
+
+	cfg := config.Load()
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR: connect db:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+		os.Exit(1)
+	}
+	migrator.DryRun = dryRun
+	migrator.Logf = func(format string, args ...interface{}) {
+		fmt.Printf(format+"\n", args...)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, migrator, args); err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+		if errors.Is(err, database.ErrChecksumMismatch) {
+			os.Exit(3)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, migrator *database.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx)
+		if err == nil && len(done) == 0 {
+			fmt.Println("OK: database is up to date")
+		}
+		return err
+
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			v, err := strconv.Atoi(args[1])
+			if err != nil || v < 1 {
+				return fmt.Errorf("down: N must be a positive integer, got %q", args[1])
+			}
+			n = v
+		}
+		done, err := migrator.Down(ctx, n)
+		if err == nil && len(done) == 0 {
+			fmt.Println("OK: nothing to roll back")
+		}
+		return err
+
+	case "redo":
+		_, err := migrator.Redo(ctx)
+		return err
+
+	case "status":
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		pending := 0
+		for _, st := range states {
+			appliedAt := "-"
+			if st.AppliedAt != nil {
+				appliedAt = st.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			reversible := ""
+			if !st.Reversible && st.Status != database.MigrationMissing {
+				reversible = "  (irreversible)"
+			}
+			fmt.Printf("%-9s %-25s %s%s\n", st.Status, appliedAt, st.Version, reversible)
+			if st.Status == database.MigrationPending {
+				pending++
+			}
+		}
+		fmt.Printf("%d migrations, %d pending\n", len(states), pending)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown command %q (see -h)", args[0])
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/config"
+	"novels-backend/internal/database"
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/importer"
+	"novels-backend/internal/orchestrator"
+	"novels-backend/internal/orchestrator/importers"
+	"novels-backend/internal/repository"
+)
+
+const usage = `Usage: novelctl <command> [flags]
+
+Commands:
+  import    import a book from a supported site (see novelctl import -h)
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "import":
+		os.Exit(runImport(os.Args[2:]))
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "ERROR: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// importOutput is the --json result of novelctl import.
+type importOutput struct {
+	DryRun   bool                  `json:"dryRun"`
+	Importer string                `json:"importer"`
+	Preview  *importer.BookPreview `json:"preview,omitempty"`
+	Run      *models.ImportRun     `json:"run,omitempty"`
+	Error    string                `json:"error,omitempty"`
+}
+
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	var (
+		pageURL    string
+		proposal   string
+		resume     string
+		cookie     string
+		dryRun     bool
+		jsonOut    bool
+		from, to   int
+		timeout    time.Duration
+		verboseLog bool
+	)
+	fs.StringVar(&pageURL, "url", "", "Book page URL (may also be given as the first argument)")
+	fs.StringVar(&proposal, "proposal", "", "Proposal ID to link the imported novel to (its original link is used if --url is empty)")
+	fs.StringVar(&resume, "resume", "", "Continue a paused or failed novelctl run from its checkpoint")
+	fs.StringVar(&cookie, "cookie", "", "Raw Cookie header value for the source site")
+	fs.BoolVar(&dryRun, "dry-run", false, "Only parse and print book metadata and the chapter list, write nothing")
+	fs.BoolVar(&jsonOut, "json", false, "Print the result as JSON")
+	fs.IntVar(&from, "from", 0, "First chapter to import, 1-based (0 = from the first)")
+	fs.IntVar(&to, "to", 0, "Last chapter to import, inclusive (0 = up to the last)")
+	fs.DurationVar(&timeout, "timeout", 6*time.Hour, "Give up after this long")
+	fs.BoolVar(&verboseLog, "v", false, "Verbose logging")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: novelctl import [flags] [url]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if pageURL == "" && fs.NArg() > 0 {
+		pageURL = fs.Arg(0)
+	}
+
+	fail := func(code int, err error) int {
+		if jsonOut {
+			printJSON(importOutput{DryRun: dryRun, Error: err.Error()})
+		}
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+		return code
+	}
+
+	req := orchestrator.LocalImport{URL: pageURL, Cookie: cookie, From: from, To: to}
+	if proposal != "" {
+		id, err := uuid.Parse(proposal)
+		if err != nil {
+			return fail(2, fmt.Errorf("invalid --proposal: %w", err))
+		}
+		req.ProposalID = &id
+	}
+	if resume != "" {
+		id, err := uuid.Parse(resume)
+		if err != nil {
+			return fail(2, fmt.Errorf("invalid --resume: %w", err))
+		}
+		req.RunID = id
+	}
+	switch {
+	case req.RunID == uuid.Nil && pageURL == "" && req.ProposalID == nil:
+		return fail(2, errors.New("--url, --proposal or --resume is required"))
+	case dryRun && req.RunID != uuid.Nil:
+		return fail(2, errors.New("--dry-run cannot be combined with --resume"))
+	case from < 0 || to < 0 || (to > 0 && to < from):
+		return fail(2, fmt.Errorf("invalid chapter range --from %d --to %d", from, to))
+	}
+
+	level := zerolog.InfoLevel
+	if !verboseLog {
+		level = zerolog.WarnLevel
+	}
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05"}).
+		Level(level).With().Timestamp().Logger()
+
+	cfg := config.Load()
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fail(1, fmt.Errorf("connect db: %w", err))
+	}
+	defer db.Close()
+
+	votingRepo := repository.NewVotingRepository(db)
+	orch := orchestrator.NewImportOrchestrator(
+		db,
+		votingRepo,
+		repository.NewImportRunsRepository(db),
+		repository.NewImportJobsRepository(db),
+		repository.NewImportRunCookiesRepository(db),
+		repository.NewNovelSourcesRepository(db),
+		repository.NewSourceLinksRepository(db),
+		nil,
+		cfg.UploadsDir,
+		importers.All(),
+		orchestrator.QueueOptions{},
+		logger,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if dryRun {
+		link := pageURL
+		if link == "" {
+			p, err := votingRepo.GetProposalByID(ctx, *req.ProposalID)
+			if err != nil {
+				return fail(1, err)
+			}
+			if p == nil {
+				return fail(1, fmt.Errorf("proposal %s not found", req.ProposalID))
+			}
+			link = p.OriginalLink
+		}
+		name, preview, err := orch.Preview(ctx, link, cookie)
+		if err != nil {
+			return fail(1, err)
+		}
+		selectRange(preview, from, to)
+		if jsonOut {
+			printJSON(importOutput{DryRun: true, Importer: name, Preview: preview})
+		} else {
+			printPreview(name, preview)
+		}
+		return 0
+	}
+
+	if !jsonOut {
+		req.OnChapter = func(cp *importer.Checkpoint, saved int) {
+			fmt.Fprintf(os.Stderr, "\rchapters: %d/%d (saved %d)", cp.NextIndex, cp.TotalChapters, saved)
+		}
+	}
+	run, err := orch.RunLocal(ctx, req)
+	if !jsonOut && req.OnChapter != nil {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		if run != nil && !jsonOut {
+			fmt.Fprintf(os.Stderr, "run_id=%s status=%s\n", run.ID, run.Status)
+		}
+		if jsonOut {
+			out := importOutput{Importer: importerName(run), Run: run, Error: err.Error()}
+			printJSON(out)
+			fmt.Fprintln(os.Stderr, "ERROR:", err)
+			return 1
+		}
+		return fail(1, err)
+	}
+
+	if jsonOut {
+		printJSON(importOutput{Importer: run.Importer, Run: run})
+		return 0
+	}
+	fmt.Printf("OK: run_id=%s importer=%s status=%s chapters=%d/%d", run.ID, run.Importer, run.Status, run.ProgressCurrent, run.ProgressTotal)
+	if run.NovelID != nil {
+		fmt.Printf(" novel_id=%s", run.NovelID)
+	}
+	fmt.Println()
+	return 0
+}
+
+// selectRange keeps the chapters --from/--to would import.
+func selectRange(preview *importer.BookPreview, from, to int) {
+	chapters := preview.Chapters
+	if to > 0 && to < len(chapters) {
+		chapters = chapters[:to]
+	}
+	if from > 1 {
+		if from-1 >= len(chapters) {
+			chapters = nil
+		} else {
+			chapters = chapters[from-1:]
+		}
+	}
+	preview.Chapters = chapters
+}
+
+func printPreview(importerName string, p *importer.BookPreview) {
+	fmt.Printf("importer:    %s\n", importerName)
+	fmt.Printf("url:         %s\n", p.URL)
+	fmt.Printf("title:       %s\n", p.Title)
+	if p.Author != "" {
+		fmt.Printf("author:      %s\n", p.Author)
+	}
+	if p.Category != "" {
+		fmt.Printf("category:    %s\n", p.Category)
+	}
+	if len(p.Tags) > 0 {
+		fmt.Printf("tags:        %s\n", strings.Join(p.Tags, ", "))
+	}
+	if p.CoverURL != "" {
+		fmt.Printf("cover:       %s\n", p.CoverURL)
+	}
+	if p.Description != "" {
+		fmt.Printf("description: %s\n", strings.ReplaceAll(p.Description, "\n", " "))
+	}
+	fmt.Printf("chapters:    %d\n", len(p.Chapters))
+	for _, ch := range p.Chapters {
+		fmt.Printf("  %5d  #%-6g %s\n", ch.Index, ch.Number, ch.Title)
+	}
+}
+
+func importerName(run *models.ImportRun) string {
+	if run == nil {
+		return ""
+	}
+	return run.Importer
+}
+
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,210 @@
+// Package achievements implements the rule language of achievements.condition.
+//
+// A condition compares a metric with a threshold or combines other conditions:
+//
+//	{"metric": "chapters_read", "gte": 100}
+//	{"metric": "comments_with_likes", "params": {"minLikes": 20}, "gte": 10}
+//	{"all": [{"metric": "chapters_read_in_novel", "gte": 100}, {"metric": "reading_streak_days", "gte": 7}]}
+//	{"any": [{"metric": "comments", "gte": 100}, {"metric": "bookmarks", "gte": 100}]}
+//
+// Metrics are listed in metrics.go; their values are computed by the repository.
+package achievements
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDepth bounds the nesting of all/any, so a condition cannot make evaluation arbitrarily deep.
+const maxDepth = 4
+
+var ErrEmptyCondition = errors.New("achievement has no condition")
+
+// Condition is a parsed achievement condition. Exactly one of Metric, All and Any is set.
+type Condition struct {
+	Metric string           `json:"metric,omitempty"`
+	Params map[string]int64 `json:"params,omitempty"`
+	Gte    int64            `json:"gte,omitempty"`
+	All    []Condition      `json:"all,omitempty"`
+	Any    []Condition      `json:"any,omitempty"`
+}
+
+// Ref identifies a metric value: the metric and its parameter (the default one if omitted).
+type Ref struct {
+	Metric string
+	Param  int64
+}
+
+func (r Ref) String() string {
+	if m, ok := metrics[r.Metric]; ok && m.Param != "" {
+		return fmt.Sprintf("%s(%s=%d)", r.Metric, m.Param, r.Param)
+	}
+	return r.Metric
+}
+
+// Values holds metric values of one user.
+type Values map[Ref]int64
+
+// Progress is how far a user is from meeting a condition. For composite conditions Current and
+// Target add up the leaves ("all") or come from the leaf closest to being met ("any").
+type Progress struct {
+	Current int64 `json:"current"`
+	Target  int64 `json:"target"`
+	Done    bool  `json:"done"`
+}
+
+// Parse parses and validates a condition. An empty or null condition returns ErrEmptyCondition.
+func Parse(raw []byte) (*Condition, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" || trimmed == "{}" {
+		return nil, ErrEmptyCondition
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.DisallowUnknownFields()
+	var c Condition
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid condition: unexpected data after the condition")
+	}
+	if err := c.validate(0); err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	return &c, nil
+}
+
+func (c *Condition) validate(depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("nested deeper than %d levels", maxDepth)
+	}
+	set := 0
+	for _, ok := range []bool{c.Metric != "", len(c.All) > 0, len(c.Any) > 0} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New(`exactly one of "metric", "all" and "any" must be set`)
+	}
+
+	if c.Metric == "" {
+		if c.Gte != 0 || len(c.Params) > 0 {
+			return errors.New(`"gte" and "params" are only allowed with "metric"`)
+		}
+		for i := range c.All {
+			if err := c.All[i].validate(depth + 1); err != nil {
+				return err
+			}
+		}
+		for i := range c.Any {
+			if err := c.Any[i].validate(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	m, ok := metrics[c.Metric]
+	if !ok {
+		return fmt.Errorf("unknown metric %q", c.Metric)
+	}
+	if c.Gte < 1 {
+		return fmt.Errorf("%s: \"gte\" must be at least 1", c.Metric)
+	}
+	for name, v := range c.Params {
+		if name != m.Param || m.Param == "" {
+			return fmt.Errorf("%s: unknown param %q", c.Metric, name)
+		}
+		if v < 0 {
+			return fmt.Errorf("%s: param %q must not be negative", c.Metric, name)
+		}
+	}
+	return nil
+}
+
+func (c *Condition) ref() Ref {
+	m := metrics[c.Metric]
+	ref := Ref{Metric: c.Metric}
+	if m.Param != "" {
+		ref.Param = m.Default
+		if v, ok := c.Params[m.Param]; ok {
+			ref.Param = v
+		}
+	}
+	return ref
+}
+
+// Refs returns the metric values the condition needs, without duplicates.
+func (c *Condition) Refs() []Ref {
+	seen := map[Ref]bool{}
+	var out []Ref
+	c.walk(func(leaf *Condition) {
+		if ref := leaf.ref(); !seen[ref] {
+			seen[ref] = true
+			out = append(out, ref)
+		}
+	})
+	return out
+}
+
+// TriggeredBy reports whether t can change the outcome of the condition.
+func (c *Condition) TriggeredBy(t Trigger) bool {
+	triggered := false
+	c.walk(func(leaf *Condition) {
+		if metrics[leaf.Metric].triggeredBy(t) {
+			triggered = true
+		}
+	})
+	return triggered
+}
+
+func (c *Condition) walk(leaf func(*Condition)) {
+	if c.Metric != "" {
+		leaf(c)
+		return
+	}
+	for i := range c.All {
+		c.All[i].walk(leaf)
+	}
+	for i := range c.Any {
+		c.Any[i].walk(leaf)
+	}
+}
+
+// Evaluate returns the progress of a user with the given metric values; missing values are 0.
+func (c *Condition) Evaluate(values Values) Progress {
+	switch {
+	case c.Metric != "":
+		v := values[c.ref()]
+		return Progress{Current: min(v, c.Gte), Target: c.Gte, Done: v >= c.Gte}
+	case len(c.All) > 0:
+		p := Progress{Done: true}
+		for i := range c.All {
+			sub := c.All[i].Evaluate(values)
+			p.Current += sub.Current
+			p.Target += sub.Target
+			p.Done = p.Done && sub.Done
+		}
+		return p
+	default:
+		var best Progress
+		for i := range c.Any {
+			sub := c.Any[i].Evaluate(values)
+			if i == 0 || sub.Done && !best.Done || sub.Done == best.Done && ratio(sub) > ratio(best) {
+				best = sub
+			}
+		}
+		return best
+	}
+}
+
+func ratio(p Progress) float64 {
+	if p.Target == 0 {
+		return 0
+	}
+	return float64(p.Current) / float64(p.Target)
+}
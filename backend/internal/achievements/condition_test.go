@@ -0,0 +1,215 @@
+package achievements
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, raw string) *Condition {
+	t.Helper()
+	c, err := Parse([]byte(raw))
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", raw, err)
+	}
+	return c
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, raw := range []string{"", "  ", "null", "{}"} {
+		if _, err := Parse([]byte(raw)); !errors.Is(err, ErrEmptyCondition) {
+			t.Errorf("Parse(%q) error = %v, want ErrEmptyCondition", raw, err)
+		}
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"not json", `metric = chapters_read`, "invalid condition"},
+		{"truncated", `{"metric": "chapters_read", "gte": 1`, "invalid condition"},
+		{"trailing data", `{"metric": "chapters_read", "gte": 1} {"metric": "comments", "gte": 1}`, "unexpected data"},
+		{"array", `[{"metric": "chapters_read", "gte": 1}]`, "invalid condition"},
+		{"unknown field", `{"metric": "chapters_read", "lte": 1}`, "unknown field"},
+		{"fractional threshold", `{"metric": "chapters_read", "gte": 1.5}`, "invalid condition"},
+		{"unknown metric", `{"metric": "likes_given", "gte": 1}`, "unknown metric"},
+		{"missing threshold", `{"metric": "chapters_read"}`, "must be at least 1"},
+		{"negative threshold", `{"metric": "chapters_read", "gte": -3}`, "must be at least 1"},
+		{"param on a metric without params", `{"metric": "comments", "params": {"minLikes": 1}, "gte": 1}`, "unknown param"},
+		{"unknown param", `{"metric": "comments_with_likes", "params": {"maxLikes": 1}, "gte": 1}`, "unknown param"},
+		{"negative param", `{"metric": "comments_with_likes", "params": {"minLikes": -1}, "gte": 1}`, "must not be negative"},
+		{"metric and all", `{"metric": "comments", "gte": 1, "all": [{"metric": "bookmarks", "gte": 1}]}`, "exactly one of"},
+		{"all and any", `{"all": [{"metric": "comments", "gte": 1}], "any": [{"metric": "bookmarks", "gte": 1}]}`, "exactly one of"},
+		{"empty all", `{"all": []}`, "exactly one of"},
+		{"threshold on a group", `{"gte": 5, "any": [{"metric": "comments", "gte": 1}]}`, "only allowed with"},
+		{"invalid nested leaf", `{"all": [{"metric": "comments", "gte": 1}, {"any": [{"metric": "nope", "gte": 1}]}]}`, "unknown metric"},
+		{
+			"nested too deep",
+			`{"all": [{"any": [{"all": [{"any": [{"all": [{"metric": "comments", "gte": 1}]}]}]}]}]}`,
+			"nested deeper",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.raw))
+			if err == nil {
+				t.Fatal("Parse succeeded")
+			}
+			if errors.Is(err, ErrEmptyCondition) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMaxDepth(t *testing.T) {
+	mustParse(t, `{"all": [{"any": [{"all": [{"any": [{"metric": "comments", "gte": 1}]}]}]}]}`)
+}
+
+func TestRefs(t *testing.T) {
+	c := mustParse(t, `{"all": [
+		{"metric": "comments_with_likes", "gte": 1},
+		{"metric": "comments_with_likes", "params": {"minLikes": 10}, "gte": 5},
+		{"any": [
+			{"metric": "comments_with_likes", "params": {"minLikes": 50}, "gte": 1},
+			{"metric": "chapters_read", "gte": 10}
+		]}
+	]}`)
+
+	got := c.Refs()
+	want := []Ref{
+		{Metric: MetricCommentsWithLikes, Param: 10},
+		{Metric: MetricCommentsWithLikes, Param: 50},
+		{Metric: MetricChaptersRead},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Refs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ref %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if s := want[0].String(); s != "comments_with_likes(minLikes=10)" {
+		t.Errorf("String = %q", s)
+	}
+}
+
+func TestTriggeredBy(t *testing.T) {
+	c := mustParse(t, `{"any": [{"metric": "chapters_read", "gte": 1}, {"metric": "comments_with_likes", "gte": 1}]}`)
+	for trigger, want := range map[Trigger]bool{
+		TriggerReadChapter:  true,
+		TriggerCommentLiked: true,
+		TriggerComment:      true,
+		TriggerBookmark:     false,
+		TriggerProposalWon:  false,
+	} {
+		if got := c.TriggeredBy(trigger); got != want {
+			t.Errorf("TriggeredBy(%s) = %v, want %v", trigger, got, want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	chapters := Ref{Metric: MetricChaptersRead}
+	streak := Ref{Metric: MetricReadingStreakDays}
+	comments := Ref{Metric: MetricComments}
+	liked := Ref{Metric: MetricCommentsWithLikes, Param: 20}
+
+	tests := []struct {
+		name   string
+		raw    string
+		values Values
+		want   Progress
+	}{
+		{
+			name:   "leaf below threshold",
+			raw:    `{"metric": "chapters_read", "gte": 100}`,
+			values: Values{chapters: 40},
+			want:   Progress{Current: 40, Target: 100},
+		},
+		{
+			name:   "leaf at threshold",
+			raw:    `{"metric": "chapters_read", "gte": 100}`,
+			values: Values{chapters: 100},
+			want:   Progress{Current: 100, Target: 100, Done: true},
+		},
+		{
+			name:   "current is capped at the target",
+			raw:    `{"metric": "chapters_read", "gte": 100}`,
+			values: Values{chapters: 250},
+			want:   Progress{Current: 100, Target: 100, Done: true},
+		},
+		{
+			name: "missing value is zero",
+			raw:  `{"metric": "chapters_read", "gte": 5}`,
+			want: Progress{Current: 0, Target: 5},
+		},
+		{
+			name:   "param selects the value",
+			raw:    `{"metric": "comments_with_likes", "params": {"minLikes": 20}, "gte": 10}`,
+			values: Values{liked: 10, {Metric: MetricCommentsWithLikes, Param: 10}: 3},
+			want:   Progress{Current: 10, Target: 10, Done: true},
+		},
+		{
+			name:   "all adds up its leaves",
+			raw:    `{"all": [{"metric": "chapters_read", "gte": 100}, {"metric": "reading_streak_days", "gte": 7}]}`,
+			values: Values{chapters: 150, streak: 3},
+			want:   Progress{Current: 103, Target: 107},
+		},
+		{
+			name:   "all is done when every leaf is",
+			raw:    `{"all": [{"metric": "chapters_read", "gte": 100}, {"metric": "reading_streak_days", "gte": 7}]}`,
+			values: Values{chapters: 100, streak: 7},
+			want:   Progress{Current: 107, Target: 107, Done: true},
+		},
+		{
+			name:   "any reports the leaf closest to being met",
+			raw:    `{"any": [{"metric": "comments", "gte": 100}, {"metric": "chapters_read", "gte": 10}]}`,
+			values: Values{comments: 60, chapters: 2},
+			want:   Progress{Current: 60, Target: 100},
+		},
+		{
+			name:   "any prefers a met leaf",
+			raw:    `{"any": [{"metric": "comments", "gte": 100}, {"metric": "chapters_read", "gte": 10}]}`,
+			values: Values{comments: 99, chapters: 10},
+			want:   Progress{Current: 10, Target: 10, Done: true},
+		},
+		{
+			// chapters_read OR (comments AND reading_streak_days): nesting decides the grouping.
+			name:   "any over all is met by the all branch",
+			raw:    `{"any": [{"metric": "chapters_read", "gte": 500}, {"all": [{"metric": "comments", "gte": 10}, {"metric": "reading_streak_days", "gte": 3}]}]}`,
+			values: Values{chapters: 1, comments: 10, streak: 3},
+			want:   Progress{Current: 13, Target: 13, Done: true},
+		},
+		{
+			name:   "any over all is not met by half of the all branch",
+			raw:    `{"any": [{"metric": "chapters_read", "gte": 500}, {"all": [{"metric": "comments", "gte": 10}, {"metric": "reading_streak_days", "gte": 3}]}]}`,
+			values: Values{chapters: 1, comments: 10},
+			want:   Progress{Current: 10, Target: 13},
+		},
+		{
+			// (chapters_read OR comments) AND reading_streak_days.
+			name:   "all over any needs the any branch",
+			raw:    `{"all": [{"any": [{"metric": "chapters_read", "gte": 500}, {"metric": "comments", "gte": 10}]}, {"metric": "reading_streak_days", "gte": 3}]}`,
+			values: Values{comments: 10, streak: 2},
+			want:   Progress{Current: 12, Target: 13},
+		},
+		{
+			name:   "all over any is met when both sides are",
+			raw:    `{"all": [{"any": [{"metric": "chapters_read", "gte": 500}, {"metric": "comments", "gte": 10}]}, {"metric": "reading_streak_days", "gte": 3}]}`,
+			values: Values{comments: 10, streak: 3},
+			want:   Progress{Current: 13, Target: 13, Done: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustParse(t, tt.raw).Evaluate(tt.values); got != tt.want {
+				t.Errorf("Evaluate = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
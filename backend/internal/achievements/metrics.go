@@ -0,0 +1,128 @@
+package achievements
+
+import (
+	"sort"
+
+	"novels-backend/internal/domain/models"
+)
+
+// Trigger is something that happened to a user and may change the value of some metrics.
+// XP event types are triggers as they are (see ForXPEvent); the others come from domain events.
+type Trigger string
+
+const (
+	TriggerReadChapter  Trigger = Trigger(models.XPEventReadChapter)
+	TriggerComment      Trigger = Trigger(models.XPEventComment)
+	TriggerBookmark     Trigger = Trigger(models.XPEventBookmark)
+	TriggerDailyLogin   Trigger = Trigger(models.XPEventDailyLogin)
+	TriggerCommentLiked Trigger = "comment_liked"
+	TriggerProposalWon  Trigger = "proposal_won"
+)
+
+// ForXPEvent returns the trigger of an XP event type.
+func ForXPEvent(eventType models.XPEventType) Trigger {
+	return Trigger(eventType)
+}
+
+// Metric is a per-user counter conditions can compare with a threshold. Its value is computed
+// by the repository (see repository.AchievementRepository.MetricValues); Triggers list what
+// can change it, so an event only re-evaluates the achievements it can affect.
+type Metric struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Param       string    `json:"param,omitempty"`
+	Default     int64     `json:"default,omitempty"`
+	Triggers    []Trigger `json:"triggers"`
+}
+
+// Metric names.
+const (
+	MetricChaptersRead        = "chapters_read"
+	MetricChaptersReadInNovel = "chapters_read_in_novel"
+	MetricReadingStreakDays   = "reading_streak_days"
+	MetricActivityStreakDays  = "activity_streak_days"
+	MetricComments            = "comments"
+	MetricCommentsWithLikes   = "comments_with_likes"
+	MetricBookmarks           = "bookmarks"
+	MetricWonProposalsVoted   = "won_proposals_voted"
+)
+
+var metrics = map[string]Metric{
+	MetricChaptersRead: {
+		Name:        MetricChaptersRead,
+		Description: "chapters read",
+		Triggers:    []Trigger{TriggerReadChapter},
+	},
+	MetricChaptersReadInNovel: {
+		Name:        MetricChaptersReadInNovel,
+		Description: "most chapters read in one novel",
+		Triggers:    []Trigger{TriggerReadChapter},
+	},
+	MetricReadingStreakDays: {
+		Name:        MetricReadingStreakDays,
+		Description: "longest run of consecutive days (UTC) with chapters read",
+		Triggers:    []Trigger{TriggerReadChapter},
+	},
+	MetricActivityStreakDays: {
+		Name:        MetricActivityStreakDays,
+		Description: "longest streak of days with a login or chapter read (user's timezone; freezes bridge missed days)",
+		Triggers:    []Trigger{TriggerDailyLogin},
+	},
+	MetricComments: {
+		Name:        MetricComments,
+		Description: "comments not deleted",
+		Triggers:    []Trigger{TriggerComment},
+	},
+	MetricCommentsWithLikes: {
+		Name:        MetricCommentsWithLikes,
+		Description: "comments not deleted with at least minLikes likes",
+		Param:       "minLikes",
+		Default:     10,
+		Triggers:    []Trigger{TriggerComment, TriggerCommentLiked},
+	},
+	MetricBookmarks: {
+		Name:        MetricBookmarks,
+		Description: "novels in bookmarks",
+		Triggers:    []Trigger{TriggerBookmark},
+	},
+	MetricWonProposalsVoted: {
+		Name:        MetricWonProposalsVoted,
+		Description: "proposals the user voted for that won the daily vote",
+		Triggers:    []Trigger{TriggerProposalWon},
+	},
+}
+
+// LookupMetric returns the metric registered under name.
+func LookupMetric(name string) (Metric, bool) {
+	m, ok := metrics[name]
+	return m, ok
+}
+
+// Metrics returns all metrics sorted by name.
+func Metrics() []Metric {
+	out := make([]Metric, 0, len(metrics))
+	for _, m := range metrics {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Triggered reports whether any metric changes on t; other triggers need no evaluation.
+func Triggered(t Trigger) bool {
+	for _, m := range metrics {
+		if m.triggeredBy(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Metric) triggeredBy(t Trigger) bool {
+	for _, mt := range m.Triggers {
+		if mt == t {
+			return true
+		}
+	}
+	return false
+}
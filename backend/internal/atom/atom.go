@@ -0,0 +1,115 @@
+// Package atom contains the Atom (RFC 4287) document model shared by the OPDS catalog
+// and the update feeds.
+package atom
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	NS           = "http://www.w3.org/2005/Atom"
+	NSDublinCore = "http://purl.org/dc/terms/"
+	NSOPDS       = "http://opds-spec.org/2010/catalog"
+	NSOpenSearch = "http://a9.com/-/spec/opensearch/1.1/"
+
+	ContentType = "application/atom+xml; charset=utf-8"
+)
+
+// Feed is an Atom feed document. The OPDS and OpenSearch namespaces are declared when set.
+type Feed struct {
+	XMLName      xml.Name `xml:"feed"`
+	XMLNS        string   `xml:"xmlns,attr"`
+	XMLNSDC      string   `xml:"xmlns:dc,attr,omitempty"`
+	XMLNSOPDS    string   `xml:"xmlns:opds,attr,omitempty"`
+	XMLNSOS      string   `xml:"xmlns:opensearch,attr,omitempty"`
+	ID           string   `xml:"id"`
+	Title        string   `xml:"title"`
+	Subtitle     string   `xml:"subtitle,omitempty"`
+	Updated      string   `xml:"updated"`
+	Icon         string   `xml:"icon,omitempty"`
+	Author       *Person  `xml:"author,omitempty"`
+	Links        []Link   `xml:"link"`
+	TotalResults int      `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage int      `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex   int      `xml:"opensearch:startIndex,omitempty"`
+	Entries      []Entry  `xml:"entry"`
+}
+
+// Entry is an Atom entry. DC* fields are Dublin Core terms used by OPDS catalogs.
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    string     `xml:"updated"`
+	Published  string     `xml:"published,omitempty"`
+	Authors    []Person   `xml:"author"`
+	Categories []Category `xml:"category"`
+	DCLanguage string     `xml:"dc:language,omitempty"`
+	DCIssued   string     `xml:"dc:issued,omitempty"`
+	Summary    *Text      `xml:"summary,omitempty"`
+	Content    *Text      `xml:"content,omitempty"`
+	Links      []Link     `xml:"link"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type Category struct {
+	Term   string `xml:"term,attr"`
+	Label  string `xml:"label,attr,omitempty"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+}
+
+type Link struct {
+	Rel         string `xml:"rel,attr,omitempty"`
+	Href        string `xml:"href,attr"`
+	Type        string `xml:"type,attr,omitempty"`
+	Title       string `xml:"title,attr,omitempty"`
+	FacetGroup  string `xml:"opds:facetGroup,attr,omitempty"`
+	ActiveFacet bool   `xml:"opds:activeFacet,attr,omitempty"`
+}
+
+// Text is an Atom text construct; Type is "text" (default) or "html".
+type Text struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+// NewFeed creates a feed with the Atom namespace declared.
+func NewFeed(id, title string, updated time.Time) *Feed {
+	return &Feed{XMLNS: NS, ID: id, Title: title, Updated: FormatTime(updated)}
+}
+
+// FormatTime formats t as an RFC 3339 date, the only format Atom allows.
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Write sends the feed with the given content type (ContentType if empty).
+func Write(w http.ResponseWriter, feed *Feed, contentType string) error {
+	if contentType == "" {
+		contentType = ContentType
+	}
+	w.Header().Set("Content-Type", contentType)
+	return Encode(w, feed)
+}
+
+// Encode writes the feed as an XML document.
+func Encode(w io.Writer, feed *Feed) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
@@ -0,0 +1,217 @@
+// Package cache caches read-mostly service results (catalog lists, genres, platform stats)
+// in Redis or in process memory.
+//
+// Values are stored as JSON under versioned namespaces: invalidating a namespace bumps its
+// generation, so every instance stops seeing the old entries at once and they expire on their own.
+// Concurrent misses of the same key are collapsed into one load per process, and a short lock in
+// the backend keeps other instances from loading the same key at the same time.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Backend is a key-value store with expiry.
+type Backend interface {
+	Name() string
+	// Get returns the value of key; ok is false if there is none.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX sets key only if it does not exist and reports whether it did.
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
+	// Incr atomically increments the integer stored at key (missing = 0) and returns the new value.
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+const (
+	// lockTTL bounds how long other instances wait for a load holding the lock.
+	lockTTL      = 10 * time.Second
+	lockWait     = 2 * time.Second
+	lockPollStep = 50 * time.Millisecond
+)
+
+// Store caches values of several namespaces in one backend.
+type Store struct {
+	backend Backend
+	prefix  string
+	logger  zerolog.Logger
+	flights flightGroup
+
+	mu    sync.Mutex
+	stats map[string]*NamespaceStats
+}
+
+// New creates a store. Keys are prefixed with prefix so several apps can share a Redis database.
+func New(backend Backend, prefix string, logger zerolog.Logger) *Store {
+	return &Store{
+		backend: backend,
+		prefix:  prefix,
+		logger:  logger.With().Str("component", "cache").Str("backend", backend.Name()).Logger(),
+		stats:   map[string]*NamespaceStats{},
+	}
+}
+
+// Backend returns the backend of the store.
+func (s *Store) Backend() Backend {
+	return s.backend
+}
+
+// Namespace is a group of keys with one TTL that is invalidated as a whole.
+type Namespace struct {
+	store *Store
+	name  string
+	ttl   time.Duration
+}
+
+// Namespace returns the namespace name; entries live for ttl unless invalidated earlier.
+// A nil store returns a nil namespace, which caches nothing.
+func (s *Store) Namespace(name string, ttl time.Duration) *Namespace {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	if _, ok := s.stats[name]; !ok {
+		s.stats[name] = &NamespaceStats{Namespace: name, TTLSeconds: ttl.Seconds()}
+	}
+	s.mu.Unlock()
+	return &Namespace{store: s, name: name, ttl: ttl}
+}
+
+func (ns *Namespace) genKey() string {
+	return ns.store.prefix + ":gen:" + ns.name
+}
+
+func (ns *Namespace) generation(ctx context.Context) (int64, error) {
+	raw, ok, err := ns.store.backend.Get(ctx, ns.genKey())
+	if err != nil || !ok {
+		return 0, err
+	}
+	return strconv.ParseInt(string(raw), 10, 64)
+}
+
+// Invalidate drops every entry of the namespace.
+func (ns *Namespace) Invalidate(ctx context.Context) error {
+	if ns == nil {
+		return nil
+	}
+	ns.store.count(ns.name, func(st *NamespaceStats) { st.Invalidations++ })
+	if _, err := ns.store.backend.Incr(ctx, ns.genKey()); err != nil {
+		ns.store.count(ns.name, func(st *NamespaceStats) { st.Errors++ })
+		return fmt.Errorf("invalidate cache namespace %s: %w", ns.name, err)
+	}
+	return nil
+}
+
+// Load returns the cached value of key in ns, calling load on a miss and caching its result.
+// Backend errors are logged and make Load fall through to load, so the cache never takes the
+// endpoint down with it. Errors of load are returned and not cached.
+func Load[T any](ctx context.Context, ns *Namespace, key string, load func(context.Context) (T, error)) (T, error) {
+	if ns == nil {
+		return load(ctx)
+	}
+	s := ns.store
+
+	gen, err := ns.generation(ctx)
+	if err != nil {
+		s.backendError(ns.name, "generation", err)
+		s.count(ns.name, func(st *NamespaceStats) { st.Misses++ })
+		return load(ctx)
+	}
+	fullKey := fmt.Sprintf("%s:%s:%d:%s", s.prefix, ns.name, gen, key)
+
+	var out T
+	if raw, ok := s.get(ctx, ns.name, fullKey); ok {
+		if err := json.Unmarshal(raw, &out); err == nil {
+			s.count(ns.name, func(st *NamespaceStats) { st.Hits++ })
+			return out, nil
+		}
+	}
+	s.count(ns.name, func(st *NamespaceStats) { st.Misses++ })
+
+	raw, shared, err := s.flights.do(fullKey, func() ([]byte, error) {
+		// The load is shared by every caller waiting on this key, so it must not be
+		// cancelled together with the request that happened to start it.
+		return s.fill(context.WithoutCancel(ctx), ns, fullKey, func(ctx context.Context) (any, error) {
+			return load(ctx)
+		})
+	})
+	if shared {
+		s.count(ns.name, func(st *NamespaceStats) { st.SharedLoads++ })
+	}
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, fmt.Errorf("decode cached %s: %w", ns.name, err)
+	}
+	return out, nil
+}
+
+// fill loads and stores one key. Only one instance loads a key at a time: the others wait
+// a little for the value to appear and load it themselves if it does not.
+func (s *Store) fill(ctx context.Context, ns *Namespace, fullKey string, load func(context.Context) (any, error)) ([]byte, error) {
+	lockKey := fullKey + ":lock"
+	locked, err := s.backend.SetNX(ctx, lockKey, []byte("1"), lockTTL)
+	if err != nil {
+		s.backendError(ns.name, "lock", err)
+	}
+	if err == nil && !locked {
+		s.count(ns.name, func(st *NamespaceStats) { st.LockWaits++ })
+		deadline := time.Now().Add(lockWait)
+		for time.Now().Before(deadline) {
+			time.Sleep(lockPollStep)
+			if raw, ok := s.get(ctx, ns.name, fullKey); ok {
+				return raw, nil
+			}
+		}
+	}
+
+	start := time.Now()
+	v, err := load(ctx)
+	s.count(ns.name, func(st *NamespaceStats) {
+		st.Loads++
+		st.loadTime += time.Since(start)
+		if err != nil {
+			st.LoadErrors++
+		}
+	})
+	if locked {
+		if derr := s.backend.Delete(ctx, lockKey); derr != nil {
+			s.backendError(ns.name, "unlock", derr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", ns.name, err)
+	}
+	if err := s.backend.Set(ctx, fullKey, raw, ns.ttl); err != nil {
+		s.backendError(ns.name, "set", err)
+	}
+	return raw, nil
+}
+
+func (s *Store) get(ctx context.Context, namespace, key string) ([]byte, bool) {
+	raw, ok, err := s.backend.Get(ctx, key)
+	if err != nil {
+		s.backendError(namespace, "get", err)
+		return nil, false
+	}
+	return raw, ok
+}
+
+func (s *Store) backendError(namespace, op string, err error) {
+	s.count(namespace, func(st *NamespaceStats) { st.Errors++ })
+	s.logger.Warn().Err(err).Str("namespace", namespace).Str("op", op).Msg("Cache backend error")
+}
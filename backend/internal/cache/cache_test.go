@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func newTestStore() *Store {
+	return New(NewMemory(100), "test", zerolog.Nop())
+}
+
+func namespaceStats(s *Store, name string) NamespaceStats {
+	for _, st := range s.Stats() {
+		if st.Namespace == name {
+			return st
+		}
+	}
+	return NamespaceStats{}
+}
+
+func TestLoadCachesValues(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	ns := s.Namespace("lists", time.Minute)
+
+	calls := 0
+	load := func(context.Context) ([]string, error) {
+		calls++
+		return []string{"a", "b"}, nil
+	}
+	for i := 0; i < 3; i++ {
+		got, err := Load(ctx, ns, "popular", load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0] != "a" {
+			t.Fatalf("Load = %v", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("loader ran %d times, want 1", calls)
+	}
+	if st := namespaceStats(s, "lists"); st.Hits != 2 || st.Misses != 1 || st.Loads != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestLoadExpiresWithTTL(t *testing.T) {
+	ctx := context.Background()
+	ns := newTestStore().Namespace("lists", 20*time.Millisecond)
+
+	calls := 0
+	load := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+	if v, _ := Load(ctx, ns, "k", load); v != 1 {
+		t.Fatalf("first Load = %d", v)
+	}
+	if v, _ := Load(ctx, ns, "k", load); v != 1 {
+		t.Fatalf("cached Load = %d", v)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if v, _ := Load(ctx, ns, "k", load); v != 2 {
+		t.Errorf("Load after TTL = %d, want a fresh value", v)
+	}
+}
+
+func TestLoadErrorsAreNotCached(t *testing.T) {
+	ctx := context.Background()
+	ns := newTestStore().Namespace("lists", time.Minute)
+
+	failure := errors.New("db down")
+	if _, err := Load(ctx, ns, "k", func(context.Context) (int, error) { return 0, failure }); !errors.Is(err, failure) {
+		t.Fatalf("error = %v, want %v", err, failure)
+	}
+	v, err := Load(ctx, ns, "k", func(context.Context) (int, error) { return 7, nil })
+	if err != nil || v != 7 {
+		t.Errorf("Load after error = %d, %v; want 7", v, err)
+	}
+}
+
+func TestLoadRunsLoaderOnceForConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	ns := s.Namespace("lists", time.Minute)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	const callers = 20
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := Load(ctx, ns, "popular", load)
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = v
+		}(i)
+	}
+	// Let every caller miss and queue up behind the first load.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader ran %d times, want 1", n)
+	}
+	for i, v := range results {
+		if v != "value" {
+			t.Errorf("caller %d got %q", i, v)
+		}
+	}
+	if st := namespaceStats(s, "lists"); st.SharedLoads != callers-1 {
+		t.Errorf("shared loads = %d, want %d", st.SharedLoads, callers-1)
+	}
+}
+
+func TestLoadSharedLoaderIgnoresCallerCancellation(t *testing.T) {
+	ns := newTestStore().Namespace("lists", time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	v, err := Load(ctx, ns, "k", func(ctx context.Context) (int, error) {
+		return 1, ctx.Err()
+	})
+	if err != nil || v != 1 {
+		t.Errorf("Load = %d, %v; the loader got the cancelled request context", v, err)
+	}
+}
+
+func TestInvalidateDropsNamespace(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	lists := s.Namespace("lists", time.Minute)
+	stats := s.Namespace("stats", time.Minute)
+
+	calls := map[string]int{}
+	load := func(name string) func(context.Context) (int, error) {
+		return func(context.Context) (int, error) {
+			calls[name]++
+			return calls[name], nil
+		}
+	}
+	_, _ = Load(ctx, lists, "k", load("lists"))
+	_, _ = Load(ctx, stats, "k", load("stats"))
+
+	if err := lists.Invalidate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := Load(ctx, lists, "k", load("lists")); v != 2 {
+		t.Errorf("lists after Invalidate = %d, want a fresh value", v)
+	}
+	if v, _ := Load(ctx, stats, "k", load("stats")); v != 1 {
+		t.Errorf("stats = %d, another namespace was invalidated", v)
+	}
+}
+
+func TestNilNamespaceCachesNothing(t *testing.T) {
+	var s *Store
+	ns := s.Namespace("lists", time.Minute)
+
+	calls := 0
+	for i := 0; i < 2; i++ {
+		_, _ = Load(context.Background(), ns, "k", func(context.Context) (int, error) {
+			calls++
+			return calls, nil
+		})
+	}
+	if calls != 2 {
+		t.Errorf("loader ran %d times, want 2", calls)
+	}
+	if err := ns.Invalidate(context.Background()); err != nil {
+		t.Errorf("Invalidate on nil namespace: %v", err)
+	}
+}
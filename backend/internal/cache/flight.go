@@ -0,0 +1,42 @@
+package cache
+
+import "sync"
+
+// flightGroup collapses concurrent calls with the same key into one.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	wg  sync.WaitGroup
+	val []byte
+	err error
+}
+
+// do runs fn once for all concurrent callers with the same key; shared reports whether the
+// caller got the result of a call started by someone else.
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) (val []byte, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flight{}
+	}
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		f.wg.Wait()
+		return f.val, true, f.err
+	}
+	f := &flight{}
+	f.wg.Add(1)
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		f.wg.Done()
+	}()
+	f.val, f.err = fn()
+	return f.val, false, f.err
+}
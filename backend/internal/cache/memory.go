@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Memory is an in-process backend, for tests, development and single-instance deployments.
+// With several instances each keeps its own copy, so invalidation only reaches the instance
+// that handled the event and the others catch up when their entries expire.
+type Memory struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	maxEntries int
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // zero = never
+}
+
+// NewMemory creates an in-memory backend holding at most maxEntries keys.
+func NewMemory(maxEntries int) *Memory {
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+	return &Memory{entries: map[string]memoryEntry{}, maxEntries: maxEntries}
+}
+
+func (m *Memory) Name() string { return "memory" }
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.live(key, time.Now())
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), e.value...), true, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(key, value, ttl)
+	return nil
+}
+
+func (m *Memory) SetNX(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.live(key, time.Now()); ok {
+		return false, nil
+	}
+	m.put(key, value, ttl)
+	return true, nil
+}
+
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, k := range keys {
+		delete(m.entries, k)
+	}
+	return nil
+}
+
+func (m *Memory) Incr(_ context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	if e, ok := m.live(key, time.Now()); ok {
+		v, err := strconv.ParseInt(string(e.value), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		n = v
+	}
+	n++
+	m.entries[key] = memoryEntry{value: []byte(strconv.FormatInt(n, 10))}
+	return n, nil
+}
+
+func (m *Memory) live(key string, now time.Time) (memoryEntry, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return e, false
+	}
+	if !e.expiresAt.IsZero() && now.After(e.expiresAt) {
+		delete(m.entries, key)
+		return e, false
+	}
+	return e, true
+}
+
+func (m *Memory) put(key string, value []byte, ttl time.Duration) {
+	if _, exists := m.entries[key]; !exists && len(m.entries) >= m.maxEntries {
+		m.evict()
+	}
+	e := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	m.entries[key] = e
+}
+
+// evict drops expired entries and, if that is not enough, entries expiring soonest
+// (keys without expiry, such as namespace generations, are kept).
+func (m *Memory) evict() {
+	now := time.Now()
+	for k, e := range m.entries {
+		if !e.expiresAt.IsZero() && now.After(e.expiresAt) {
+			delete(m.entries, k)
+		}
+	}
+	for len(m.entries) >= m.maxEntries {
+		victim := ""
+		var soonest time.Time
+		for k, e := range m.entries {
+			if e.expiresAt.IsZero() {
+				continue
+			}
+			if victim == "" || e.expiresAt.Before(soonest) {
+				victim, soonest = k, e.expiresAt
+			}
+		}
+		if victim == "" {
+			return
+		}
+		delete(m.entries, victim)
+	}
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryTTL(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(10)
+
+	if err := m.Set(ctx, "short", []byte("v"), 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set(ctx, "forever", []byte("v"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := m.Get(ctx, "short"); !ok {
+		t.Fatal("entry expired too early")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok, _ := m.Get(ctx, "short"); ok {
+		t.Error("entry outlived its TTL")
+	}
+	if _, ok, _ := m.Get(ctx, "forever"); !ok {
+		t.Error("entry without TTL expired")
+	}
+}
+
+func TestMemorySetNX(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(10)
+
+	if ok, _ := m.SetNX(ctx, "lock", []byte("1"), 20*time.Millisecond); !ok {
+		t.Fatal("SetNX did not set a missing key")
+	}
+	if ok, _ := m.SetNX(ctx, "lock", []byte("2"), time.Second); ok {
+		t.Fatal("SetNX overwrote a live key")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if ok, _ := m.SetNX(ctx, "lock", []byte("3"), time.Second); !ok {
+		t.Error("SetNX did not take over an expired key")
+	}
+}
+
+func TestMemoryIncr(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(10)
+
+	for want := int64(1); want <= 3; want++ {
+		n, err := m.Incr(ctx, "gen")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("Incr = %d, want %d", n, want)
+		}
+	}
+	_ = m.Set(ctx, "text", []byte("abc"), 0)
+	if _, err := m.Incr(ctx, "text"); err == nil {
+		t.Error("Incr of a non-integer succeeded")
+	}
+}
+
+func TestMemoryReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(10)
+
+	value := []byte("abc")
+	_ = m.Set(ctx, "k", value, 0)
+	value[0] = 'x'
+	got, _, _ := m.Get(ctx, "k")
+	got[1] = 'y'
+	if again, _, _ := m.Get(ctx, "k"); string(again) != "abc" {
+		t.Errorf("stored value changed to %q", again)
+	}
+}
+
+func TestMemoryEvictsSoonestExpiring(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(3)
+
+	_ = m.Set(ctx, "gen", []byte("1"), 0)
+	_ = m.Set(ctx, "soon", []byte("v"), time.Minute)
+	_ = m.Set(ctx, "late", []byte("v"), time.Hour)
+	_ = m.Set(ctx, "new", []byte("v"), time.Hour)
+
+	if _, ok, _ := m.Get(ctx, "soon"); ok {
+		t.Error("entry expiring soonest was not evicted")
+	}
+	for _, k := range []string{"gen", "late", "new"} {
+		if _, ok, _ := m.Get(ctx, k); !ok {
+			t.Errorf("%s was evicted", k)
+		}
+	}
+}
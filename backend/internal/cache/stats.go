@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"sort"
+	"time"
+)
+
+// NamespaceStats are the counters of one namespace since the process started.
+type NamespaceStats struct {
+	Namespace  string  `json:"namespace"`
+	TTLSeconds float64 `json:"ttlSeconds"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	HitRatio   float64 `json:"hitRatio"`
+	// Loads is how many times the underlying call ran; SharedLoads counts misses that waited
+	// for a load already in flight in this process, LockWaits those that waited for another instance.
+	Loads         int64   `json:"loads"`
+	LoadErrors    int64   `json:"loadErrors"`
+	SharedLoads   int64   `json:"sharedLoads"`
+	LockWaits     int64   `json:"lockWaits"`
+	AvgLoadMillis float64 `json:"avgLoadMillis"`
+	Invalidations int64   `json:"invalidations"`
+	// Errors are backend failures (the cache was bypassed).
+	Errors int64 `json:"errors"`
+
+	loadTime time.Duration
+}
+
+func (s *Store) count(namespace string, fn func(*NamespaceStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.stats[namespace]
+	if !ok {
+		st = &NamespaceStats{Namespace: namespace}
+		s.stats[namespace] = st
+	}
+	fn(st)
+}
+
+// Stats returns the counters of every namespace, ordered by name.
+func (s *Store) Stats() []NamespaceStats {
+	if s == nil {
+		return []NamespaceStats{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]NamespaceStats, 0, len(s.stats))
+	for _, st := range s.stats {
+		c := *st
+		if total := c.Hits + c.Misses; total > 0 {
+			c.HitRatio = float64(c.Hits) / float64(total)
+		}
+		if c.Loads > 0 {
+			c.AvgLoadMillis = float64(c.loadTime) / float64(c.Loads) / float64(time.Millisecond)
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Namespace < out[j].Namespace })
+	return out
+}
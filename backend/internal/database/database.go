@@ -1,26 +1,32 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"embed"
 	"fmt"
-	"sort"
-	"strings"
+	"time"
 
 	"novels-backend/internal/config"
+	"novels-backend/internal/telemetry"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
 )
 
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
-// Connect подключается к PostgreSQL базе данных
+// Connect подключается к PostgreSQL базе данных.
+// Запросы пишутся в метрики и трейсы (см. instrumented.go), статистика пула — в метрики.
 func Connect(cfg config.DatabaseConfig) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", cfg.URL)
+	connector, err := pq.NewConnector(cfg.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	sqlDB := sql.OpenDB(instrumentedConnector{Connector: connector})
+	db := sqlx.NewDb(sqlDB, "postgres")
 
 	// Настраиваем пул соединений
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
@@ -29,93 +35,65 @@ func Connect(cfg config.DatabaseConfig) (*sqlx.DB, error) {
 
 	// Проверяем соединение
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
+	telemetry.RegisterDBStats(sqlDB, "postgres")
 
 	return db, nil
 }
 
-// RunMigrations выполняет все миграции базы данных
-func RunMigrations(db *sqlx.DB) error {
-	// Создаём таблицу для отслеживания миграций
-	_, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS schema_migrations (
-			version VARCHAR(255) PRIMARY KEY,
-			applied_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-		)
-	`)
+// ConnectRedis подключается к Redis (кэш, лимиты запросов)
+func ConnectRedis(ctx context.Context, cfg config.RedisConfig) (*redis.Client, error) {
+	opts, err := redis.ParseURL(cfg.URL)
 	if err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
+		return nil, fmt.Errorf("failed to parse redis url: %w", err)
 	}
+	client := redis.NewClient(opts)
 
-	// Получаем список примененных миграций
-	var appliedMigrations []string
-	err = db.Select(&appliedMigrations, "SELECT version FROM schema_migrations ORDER BY version")
-	if err != nil {
-		return fmt.Errorf("failed to get applied migrations: %w", err)
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
 	}
 
-	// Создаём множество для быстрого поиска
-	appliedSet := make(map[string]bool)
-	for _, v := range appliedMigrations {
-		appliedSet[v] = true
-	}
+	return client, nil
+}
 
-	// Получаем список файлов миграций
-	entries, err := migrationsFS.ReadDir("migrations")
+// PendingMigrations возвращает версии встроенных миграций, ещё не применённых к базе
+// (для проверки готовности: инстанс с новой схемой не должен работать со старой базой)
+func PendingMigrations(ctx context.Context, db *sqlx.DB) ([]string, error) {
+	migrations, err := loadMigrations()
 	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
+		return nil, err
 	}
-
-	// Собираем только .sql файлы
-	var migrationFiles []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
-			migrationFiles = append(migrationFiles, entry.Name())
-		}
+	var applied []string
+	if err := db.SelectContext(ctx, &applied, "SELECT version FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
 	}
-
-	// Сортируем по имени (миграции должны иметь префикс типа 001_, 002_)
-	sort.Strings(migrationFiles)
-
-	// Применяем новые миграции
-	for _, filename := range migrationFiles {
-		version := strings.TrimSuffix(filename, ".sql")
-		
-		if appliedSet[version] {
-			continue // Уже применена
-		}
-
-		// Читаем содержимое миграции
-		content, err := migrationsFS.ReadFile("migrations/" + filename)
-		if err != nil {
-			return fmt.Errorf("failed to read migration %s: %w", filename, err)
-		}
-
-		// Применяем миграцию в транзакции
-		tx, err := db.Beginx()
-		if err != nil {
-			return fmt.Errorf("failed to begin transaction: %w", err)
-		}
-
-		_, err = tx.Exec(string(content))
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to execute migration %s: %w", filename, err)
-		}
-
-		_, err = tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", version)
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to record migration %s: %w", filename, err)
-		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("failed to commit migration %s: %w", filename, err)
+	appliedSet := make(map[string]bool, len(applied))
+	for _, v := range applied {
+		appliedSet[v] = true
+	}
+	pending := []string{}
+	for _, mig := range migrations {
+		if !appliedSet[mig.Version] {
+			pending = append(pending, mig.Version)
 		}
-
-		fmt.Printf("Applied migration: %s\n", filename)
 	}
+	return pending, nil
+}
 
-	return nil
+// RunMigrations применяет все неприменённые миграции (см. Migrator.Up)
+func RunMigrations(db *sqlx.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	migrator.Logf = func(format string, args ...interface{}) {
+		fmt.Printf(format+"\n", args...)
+	}
+	_, err = migrator.Up(context.Background())
+	return err
 }
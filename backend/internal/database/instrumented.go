@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"novels-backend/internal/telemetry"
+)
+
+// instrumentedConnector оборачивает соединения драйвера: время каждого запроса пишется в
+// метрики, а если в контексте есть span (запрос пришёл из HTTP или фоновой задачи) —
+// запрос становится его дочерним span-ом.
+type instrumentedConnector struct {
+	driver.Connector
+}
+
+func (c instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn}, nil
+}
+
+type instrumentedConn struct {
+	driver.Conn
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, done := observeQuery(ctx, query)
+	res, err := execer.ExecContext(ctx, query, args)
+	done(err)
+	return res, err
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, done := observeQuery(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	done(err)
+	return rows, err
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// observeQuery начинает замер запроса. Span создаётся только внутри уже идущего трейса,
+// чтобы фоновые опросы очередей не порождали по трейсу на каждый SELECT.
+func observeQuery(ctx context.Context, query string) (context.Context, func(error)) {
+	op := queryOp(query)
+	start := time.Now()
+
+	var span trace.Span
+	if trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		ctx, span = telemetry.StartSpan(ctx, "db "+op,
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", op),
+			attribute.String("db.statement", truncateQuery(query)),
+		)
+	}
+	return ctx, func(err error) {
+		telemetry.ObserveQuery(op, time.Since(start), err)
+		if span != nil {
+			telemetry.EndSpan(span, err)
+		}
+	}
+}
+
+// queryOp возвращает тип запроса (select, insert, ...) по первому слову; для WITH — "with".
+func queryOp(query string) string {
+	q := strings.TrimSpace(query)
+	if i := strings.IndexAny(q, " \t\n("); i > 0 {
+		q = q[:i]
+	}
+	switch op := strings.ToLower(q); op {
+	case "select", "insert", "update", "delete", "with", "begin", "commit", "rollback":
+		return op
+	default:
+		return "other"
+	}
+}
+
+func truncateQuery(query string) string {
+	const max = 2000
+	if len(query) > max {
+		return query[:max]
+	}
+	return query
+}
@@ -0,0 +1,454 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrationLockKey ключ advisory lock, под которым выполняются миграции: реплики, стартующие
+// одновременно, ждут друг друга, а не применяют одну миграцию дважды
+const migrationLockKey int64 = 0x6e6f76656c73 // "novels"
+
+var (
+	// ErrChecksumMismatch применённая миграция была изменена после применения
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	// ErrIrreversible у миграции нет down-файла
+	ErrIrreversible = errors.New("migration has no down file")
+)
+
+// Migration встроенная миграция. Файлы: NNN_name.up.sql + NNN_name.down.sql;
+// старые NNN_name.sql — то же, что up без down (необратимы)
+type Migration struct {
+	Version  string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Reversible есть ли у миграции down-файл
+func (m Migration) Reversible() bool {
+	return m.Down != ""
+}
+
+// Статусы миграции в Status
+const (
+	MigrationApplied  = "applied"
+	MigrationPending  = "pending"
+	MigrationModified = "modified" // применена, но файл с тех пор изменён
+	MigrationMissing  = "missing"  // применена, но файла нет (база новее кода)
+)
+
+// MigrationState состояние одной миграции
+type MigrationState struct {
+	Version    string     `json:"version"`
+	Status     string     `json:"status"`
+	Reversible bool       `json:"reversible"`
+	AppliedAt  *time.Time `json:"appliedAt,omitempty"`
+}
+
+type appliedMigration struct {
+	Version   string    `db:"version"`
+	AppliedAt time.Time `db:"applied_at"`
+	Checksum  *string   `db:"checksum"`
+}
+
+// Migrator применяет и откатывает встроенные миграции
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+	// DryRun только возвращает миграции, которые были бы выполнены
+	DryRun bool
+	// Logf вызывается на каждую выполненную миграцию (nil — молча)
+	Logf func(format string, args ...interface{})
+}
+
+// NewMigrator загружает встроенные миграции
+func NewMigrator(db *sqlx.DB) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrations возвращает встроенные миграции по возрастанию версии
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationsFS.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := map[string]*Migration{}
+	get := func(version string) *Migration {
+		if byVersion[version] == nil {
+			byVersion[version] = &Migration{Version: version}
+		}
+		return byVersion[version]
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		content, err := migrationsFS.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		switch {
+		case strings.HasSuffix(name, ".down.sql"):
+			get(strings.TrimSuffix(name, ".down.sql")).Down = string(content)
+		default:
+			mig := get(strings.TrimSuffix(strings.TrimSuffix(name, ".sql"), ".up"))
+			if mig.Up != "" {
+				return nil, fmt.Errorf("migration %s has both .sql and .up.sql files", mig.Version)
+			}
+			sum := sha256.Sum256(content)
+			mig.Up = string(content)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %s has a down file but no up file", mig.Version)
+		}
+		out = append(out, *mig)
+	}
+	// Сортируем по имени (миграции должны иметь префикс типа 001_, 002_)
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+func (m *Migrator) find(version string) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+func (m *Migrator) logf(format string, args ...interface{}) {
+	if m.Logf != nil {
+		m.Logf(format, args...)
+	}
+}
+
+// withLock выполняет fn на отдельном соединении под advisory lock миграций
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// ensureMigrationsTable создаёт таблицу учёта миграций (и колонку checksum для старых баз)
+func ensureMigrationsTable(ctx context.Context, q sqlx.ExecerContext) error {
+	_, err := q.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum VARCHAR(64);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+func loadApplied(ctx context.Context, q sqlx.QueryerContext) (map[string]appliedMigration, error) {
+	var rows []appliedMigration
+	if err := sqlx.SelectContext(ctx, q, &rows, "SELECT version, applied_at, checksum FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	applied := make(map[string]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// verify проверяет, что применённые миграции не менялись. Миграции, применённые до появления
+// контрольных сумм, получают сумму текущего файла
+func (m *Migrator) verify(ctx context.Context, conn *sqlx.Conn, applied map[string]appliedMigration) error {
+	var modified []string
+	for _, mig := range m.migrations {
+		row, ok := applied[mig.Version]
+		if !ok {
+			continue
+		}
+		switch {
+		case row.Checksum == nil:
+			if m.DryRun {
+				continue
+			}
+			if _, err := conn.ExecContext(ctx, "UPDATE schema_migrations SET checksum = $1 WHERE version = $2", mig.Checksum, mig.Version); err != nil {
+				return fmt.Errorf("failed to record checksum of %s: %w", mig.Version, err)
+			}
+		case *row.Checksum != mig.Checksum:
+			modified = append(modified, mig.Version)
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("%w: %s (add a new migration instead of editing an applied one)", ErrChecksumMismatch, strings.Join(modified, ", "))
+	}
+	return nil
+}
+
+// Up применяет все неприменённые миграции по порядку и возвращает их версии
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	var done []string
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(ctx, conn, applied); err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig); err != nil {
+				return err
+			}
+			done = append(done, mig.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down откатывает n последних применённых миграций (от новых к старым) и возвращает их версии.
+// Останавливается с ErrIrreversible на миграции без down-файла
+func (m *Migrator) Down(ctx context.Context, n int) ([]string, error) {
+	var done []string
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(ctx, conn, applied); err != nil {
+			return err
+		}
+		versions := make([]string, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+		if n < len(versions) {
+			versions = versions[:n]
+		}
+		for _, v := range versions {
+			if err := m.rollback(ctx, conn, v); err != nil {
+				return err
+			}
+			done = append(done, v)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Redo откатывает и заново применяет последнюю применённую миграцию
+func (m *Migrator) Redo(ctx context.Context) (string, error) {
+	var version string
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(ctx, conn, applied); err != nil {
+			return err
+		}
+		for v := range applied {
+			if v > version {
+				version = v
+			}
+		}
+		if version == "" {
+			return fmt.Errorf("no applied migrations")
+		}
+		if err := m.rollback(ctx, conn, version); err != nil {
+			return err
+		}
+		mig, _ := m.find(version)
+		return m.apply(ctx, conn, mig)
+	})
+	return version, err
+}
+
+// Status возвращает состояние всех миграций: встроенных и применённых, но отсутствующих в коде
+func (m *Migrator) Status(ctx context.Context) ([]MigrationState, error) {
+	if err := ensureMigrationsTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	applied, err := loadApplied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationState, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := MigrationState{Version: mig.Version, Status: MigrationPending, Reversible: mig.Reversible()}
+		if row, ok := applied[mig.Version]; ok {
+			appliedAt := row.AppliedAt
+			st.AppliedAt = &appliedAt
+			st.Status = MigrationApplied
+			if row.Checksum != nil && *row.Checksum != mig.Checksum {
+				st.Status = MigrationModified
+			}
+			delete(applied, mig.Version)
+		}
+		out = append(out, st)
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		out = append(out, MigrationState{Version: row.Version, Status: MigrationMissing, AppliedAt: &appliedAt})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, mig Migration) error {
+	if m.DryRun {
+		m.logf("Would apply migration: %s", mig.Version)
+		return nil
+	}
+	err := inTx(ctx, conn, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", mig.Version, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, checksum) VALUES ($1, $2)", mig.Version, mig.Checksum); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", mig.Version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.logf("Applied migration: %s", mig.Version)
+	return nil
+}
+
+func (m *Migrator) rollback(ctx context.Context, conn *sqlx.Conn, version string) error {
+	mig, ok := m.find(version)
+	if !ok {
+		return fmt.Errorf("applied migration %s not found in this build", version)
+	}
+	if !mig.Reversible() {
+		return fmt.Errorf("%w: %s", ErrIrreversible, version)
+	}
+	if m.DryRun {
+		m.logf("Would roll back migration: %s", version)
+		return nil
+	}
+	err := inTx(ctx, conn, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+			return fmt.Errorf("failed to roll back migration %s: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", version); err != nil {
+			return fmt.Errorf("failed to unrecord migration %s: %w", version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.logf("Rolled back migration: %s", version)
+	return nil
+}
+
+func inTx(ctx context.Context, conn *sqlx.Conn, fn func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
+
+var (
+	migrationNumberRe = regexp.MustCompile(`^(\d+)_`)
+	migrationNameRe   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// CreateMigration создаёт в dir пару NNN_name.up.sql / NNN_name.down.sql со следующим номером
+// и возвращает пути к файлам
+func CreateMigration(dir, name string, now time.Time) (string, string, error) {
+	name = strings.Trim(migrationNameRe.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is empty")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+	next := 1
+	for _, entry := range entries {
+		if match := migrationNumberRe.FindStringSubmatch(entry.Name()); match != nil {
+			if n, err := strconv.Atoi(match[1]); err == nil && n >= next {
+				next = n + 1
+			}
+		}
+	}
+
+	version := fmt.Sprintf("%03d_%s", next, name)
+	upPath := filepath.Join(dir, version+".up.sql")
+	downPath := filepath.Join(dir, version+".down.sql")
+	date := now.Format("2006-01-02")
+	files := map[string]string{
+		upPath:   fmt.Sprintf("-- Migration: %s\n-- Description: \n-- Created: %s\n\n", version, date),
+		downPath: fmt.Sprintf("-- Migration: %s (down)\n-- Description: \n-- Created: %s\n\n", version, date),
+	}
+	for _, path := range []string{upPath, downPath} {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		_, err = f.WriteString(files[path])
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return upPath, downPath, nil
+}
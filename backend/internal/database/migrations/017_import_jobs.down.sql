@@ -0,0 +1,5 @@
+-- Migration: 017_import_jobs (down)
+-- Description: Drop the durable import queue
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS import_jobs;
@@ -0,0 +1,27 @@
+-- Migration: 017_import_jobs
+-- Description: Durable Postgres-backed queue for import runs (leases, heartbeats, per-importer concurrency)
+-- Created: 2026-10-17
+
+-- One job row per import run. Workers claim jobs with FOR UPDATE SKIP LOCKED and keep
+-- them leased via heartbeats; an expired lease means the owning process died and the
+-- job may be reclaimed (the run resumes from import_runs.checkpoint).
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL UNIQUE REFERENCES import_runs(id) ON DELETE CASCADE,
+    proposal_id UUID NOT NULL REFERENCES novel_proposals(id) ON DELETE CASCADE,
+    importer TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'leased', 'done')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    lease_owner TEXT NULL,
+    lease_expires_at TIMESTAMPTZ NULL,
+    heartbeat_at TIMESTAMPTZ NULL,
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    last_error TEXT NULL,
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_claim ON import_jobs (importer, status, available_at);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_lease ON import_jobs (status, lease_expires_at) WHERE status = 'leased';
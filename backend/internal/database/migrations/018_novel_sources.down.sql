@@ -0,0 +1,6 @@
+-- Migration: 018_novel_sources (down)
+-- Description: Drop source sync state and the import run kind
+-- Created: 2026-10-17
+
+ALTER TABLE import_runs DROP COLUMN IF EXISTS kind;
+DROP TABLE IF EXISTS novel_sources;
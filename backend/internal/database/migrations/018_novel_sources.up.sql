@@ -0,0 +1,34 @@
+-- Migration: 018_novel_sources
+-- Description: Link imported novels to their source for incremental chapter sync
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS novel_sources (
+    novel_id UUID PRIMARY KEY REFERENCES novels(id) ON DELETE CASCADE,
+    proposal_id UUID NOT NULL REFERENCES novel_proposals(id) ON DELETE CASCADE,
+    importer TEXT NOT NULL,
+    source_url TEXT NOT NULL,
+    sync_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    sync_interval_minutes INTEGER NOT NULL DEFAULT 1440 CHECK (sync_interval_minutes > 0),
+    next_sync_at TIMESTAMPTZ NOT NULL DEFAULT NOW() + INTERVAL '1 day',
+    last_synced_at TIMESTAMPTZ NULL,
+    last_sync_status TEXT NULL,
+    last_sync_error TEXT NULL,
+    last_sync_new_chapters INTEGER NOT NULL DEFAULT 0,
+    last_sync_run_id UUID NULL REFERENCES import_runs(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_novel_sources_due ON novel_sources (next_sync_at) WHERE sync_enabled = TRUE;
+
+-- import runs: initial import vs follow-up sync
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'import';
+
+-- Backfill from novels already imported from proposals
+INSERT INTO novel_sources (novel_id, proposal_id, importer, source_url)
+SELECT DISTINCT ON (r.novel_id) r.novel_id, r.proposal_id, r.importer, p.original_link
+FROM import_runs r
+JOIN novel_proposals p ON p.id = r.proposal_id
+WHERE r.status = 'succeeded' AND r.novel_id IS NOT NULL
+ORDER BY r.novel_id, r.finished_at DESC NULLS LAST
+ON CONFLICT (novel_id) DO NOTHING;
@@ -0,0 +1,5 @@
+-- Migration: 019_translation_jobs (down)
+-- Description: Drop the machine translation queue
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS translation_jobs;
@@ -0,0 +1,26 @@
+-- Migration: 019_translation_jobs
+-- Description: Machine-translation queue for chapters of novels in translation
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS translation_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    chapter_id UUID NOT NULL REFERENCES chapters(id) ON DELETE CASCADE,
+    novel_id UUID NOT NULL REFERENCES novels(id) ON DELETE CASCADE,
+    source_lang VARCHAR(10) NOT NULL,
+    target_lang VARCHAR(10) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'done', 'failed', 'skipped')),
+    provider TEXT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    error TEXT NULL,
+    locked_by TEXT NULL,
+    locked_until TIMESTAMPTZ NULL,
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (chapter_id, target_lang)
+);
+
+CREATE INDEX IF NOT EXISTS idx_translation_jobs_claim ON translation_jobs (status, available_at);
+CREATE INDEX IF NOT EXISTS idx_translation_jobs_novel ON translation_jobs (novel_id, status);
@@ -0,0 +1,67 @@
+-- Migration: 020_novel_glossary (down)
+-- Description: Drop the novel glossary and restore apply_edit_request without glossary edits
+-- Created: 2026-10-17
+
+-- The 'glossary' value added to edit_field_type stays: PostgreSQL cannot drop an enum value.
+-- Glossary changes left in pending edit requests fall through to the ELSE branch and are ignored.
+
+CREATE OR REPLACE FUNCTION apply_edit_request(p_request_id UUID, p_moderator_id UUID)
+RETURNS BOOLEAN AS $$
+DECLARE
+    v_request novel_edit_requests%ROWTYPE;
+    v_change RECORD;
+BEGIN
+    -- Получаем запрос
+    SELECT * INTO v_request FROM novel_edit_requests WHERE id = p_request_id AND status = 'pending';
+    IF NOT FOUND THEN
+        RETURN FALSE;
+    END IF;
+    
+    -- Применяем каждое изменение
+    FOR v_change IN 
+        SELECT * FROM novel_edit_request_changes WHERE request_id = p_request_id
+    LOOP
+        -- Записываем в историю
+        INSERT INTO novel_edit_history (novel_id, request_id, user_id, field_type, lang, old_value, new_value)
+        VALUES (v_request.novel_id, p_request_id, v_request.user_id, v_change.field_type, v_change.lang, v_change.old_value, v_change.new_value);
+        
+        -- Применяем изменение в зависимости от типа поля
+        CASE v_change.field_type
+            WHEN 'title' THEN
+                IF v_change.lang IS NOT NULL THEN
+                    UPDATE novel_localizations SET title = v_change.new_value, updated_at = NOW()
+                    WHERE novel_id = v_request.novel_id AND lang = v_change.lang;
+                END IF;
+            WHEN 'description' THEN
+                IF v_change.lang IS NOT NULL THEN
+                    UPDATE novel_localizations SET description = v_change.new_value, updated_at = NOW()
+                    WHERE novel_id = v_request.novel_id AND lang = v_change.lang;
+                END IF;
+            WHEN 'cover_url' THEN
+                UPDATE novels SET cover_url = v_change.new_value, updated_at = NOW()
+                WHERE id = v_request.novel_id;
+            WHEN 'release_year' THEN
+                UPDATE novels SET release_year = CAST(v_change.new_value AS INTEGER), updated_at = NOW()
+                WHERE id = v_request.novel_id;
+            WHEN 'original_chapters_count' THEN
+                UPDATE novels SET original_chapters_count = CAST(v_change.new_value AS INTEGER), updated_at = NOW()
+                WHERE id = v_request.novel_id;
+            WHEN 'translation_status' THEN
+                UPDATE novels SET translation_status = v_change.new_value::translation_status, updated_at = NOW()
+                WHERE id = v_request.novel_id;
+            ELSE
+                -- Другие типы обрабатываем отдельно в приложении
+                NULL;
+        END CASE;
+    END LOOP;
+    
+    -- Обновляем статус запроса
+    UPDATE novel_edit_requests 
+    SET status = 'approved', moderator_id = p_moderator_id, reviewed_at = NOW(), updated_at = NOW()
+    WHERE id = p_request_id;
+    
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS novel_glossary_terms;
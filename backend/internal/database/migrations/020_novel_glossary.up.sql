@@ -0,0 +1,98 @@
+-- Migration: 020_novel_glossary
+-- Description: Per-novel translation glossary (names, ranks, places) and glossary wiki edits
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS novel_glossary_terms (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    novel_id UUID NOT NULL REFERENCES novels(id) ON DELETE CASCADE,
+    source_term TEXT NOT NULL,
+    -- {"ru": {"target": "Линь Дун", "forms": {"gen": "Линь Дуна"}}, "en": {"target": "Lin Dong"}}
+    translations JSONB NOT NULL DEFAULT '{}'::jsonb,
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT novel_glossary_terms_source_not_empty CHECK (length(btrim(source_term)) > 0),
+    UNIQUE (novel_id, source_term)
+);
+
+CREATE INDEX IF NOT EXISTS idx_novel_glossary_terms_novel ON novel_glossary_terms(novel_id);
+
+-- Glossary entries proposed through wiki edits: new_value holds the entry as JSON
+-- ({"sourceTerm": ..., "translations": {...}, "notes": ...}).
+ALTER TYPE edit_field_type ADD VALUE IF NOT EXISTS 'glossary';
+
+CREATE OR REPLACE FUNCTION apply_edit_request(p_request_id UUID, p_moderator_id UUID)
+RETURNS BOOLEAN AS $$
+DECLARE
+    v_request novel_edit_requests%ROWTYPE;
+    v_change RECORD;
+    v_entry JSONB;
+BEGIN
+    -- Получаем запрос
+    SELECT * INTO v_request FROM novel_edit_requests WHERE id = p_request_id AND status = 'pending';
+    IF NOT FOUND THEN
+        RETURN FALSE;
+    END IF;
+    
+    -- Применяем каждое изменение
+    FOR v_change IN 
+        SELECT * FROM novel_edit_request_changes WHERE request_id = p_request_id
+    LOOP
+        -- Записываем в историю
+        INSERT INTO novel_edit_history (novel_id, request_id, user_id, field_type, lang, old_value, new_value)
+        VALUES (v_request.novel_id, p_request_id, v_request.user_id, v_change.field_type, v_change.lang, v_change.old_value, v_change.new_value);
+        
+        -- Применяем изменение в зависимости от типа поля
+        CASE v_change.field_type
+            WHEN 'title' THEN
+                IF v_change.lang IS NOT NULL THEN
+                    UPDATE novel_localizations SET title = v_change.new_value, updated_at = NOW()
+                    WHERE novel_id = v_request.novel_id AND lang = v_change.lang;
+                END IF;
+            WHEN 'description' THEN
+                IF v_change.lang IS NOT NULL THEN
+                    UPDATE novel_localizations SET description = v_change.new_value, updated_at = NOW()
+                    WHERE novel_id = v_request.novel_id AND lang = v_change.lang;
+                END IF;
+            WHEN 'cover_url' THEN
+                UPDATE novels SET cover_url = v_change.new_value, updated_at = NOW()
+                WHERE id = v_request.novel_id;
+            WHEN 'release_year' THEN
+                UPDATE novels SET release_year = CAST(v_change.new_value AS INTEGER), updated_at = NOW()
+                WHERE id = v_request.novel_id;
+            WHEN 'original_chapters_count' THEN
+                UPDATE novels SET original_chapters_count = CAST(v_change.new_value AS INTEGER), updated_at = NOW()
+                WHERE id = v_request.novel_id;
+            WHEN 'translation_status' THEN
+                UPDATE novels SET translation_status = v_change.new_value::translation_status, updated_at = NOW()
+                WHERE id = v_request.novel_id;
+            WHEN 'glossary' THEN
+                -- Переводы по языкам сливаются с уже существующей записью термина
+                v_entry := v_change.new_value::jsonb;
+                INSERT INTO novel_glossary_terms (novel_id, source_term, translations, notes, created_by)
+                VALUES (
+                    v_request.novel_id,
+                    btrim(v_entry->>'sourceTerm'),
+                    COALESCE(v_entry->'translations', '{}'::jsonb),
+                    NULLIF(v_entry->>'notes', ''),
+                    v_request.user_id
+                )
+                ON CONFLICT (novel_id, source_term) DO UPDATE SET
+                    translations = novel_glossary_terms.translations || EXCLUDED.translations,
+                    notes = COALESCE(EXCLUDED.notes, novel_glossary_terms.notes),
+                    updated_at = NOW();
+            ELSE
+                -- Другие типы обрабатываем отдельно в приложении
+                NULL;
+        END CASE;
+    END LOOP;
+    
+    -- Обновляем статус запроса
+    UPDATE novel_edit_requests 
+    SET status = 'approved', moderator_id = p_moderator_id, reviewed_at = NOW(), updated_at = NOW()
+    WHERE id = p_request_id;
+    
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,9 @@
+-- Migration: 021_retranslation_requests (down)
+-- Description: Drop retranslation requests, their quota ledger and partial translation jobs
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS retranslation_quota_transactions;
+DROP TABLE IF EXISTS retranslation_requests;
+ALTER TABLE translation_jobs DROP COLUMN IF EXISTS overwrite;
+ALTER TABLE translation_jobs DROP COLUMN IF EXISTS paragraph_to;
+ALTER TABLE translation_jobs DROP COLUMN IF EXISTS paragraph_from;
@@ -0,0 +1,59 @@
+-- Migration: 021_retranslation_requests
+-- Description: Premium chapter retranslation requests, moderation queue and quota ledger
+-- Created: 2026-10-17
+
+-- Partial re-runs of the pipeline: a paragraph range and permission to replace manual text.
+ALTER TABLE translation_jobs ADD COLUMN IF NOT EXISTS paragraph_from INTEGER NULL;
+ALTER TABLE translation_jobs ADD COLUMN IF NOT EXISTS paragraph_to INTEGER NULL;
+ALTER TABLE translation_jobs ADD COLUMN IF NOT EXISTS overwrite BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS retranslation_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chapter_id UUID NOT NULL REFERENCES chapters(id) ON DELETE CASCADE,
+    novel_id UUID NOT NULL REFERENCES novels(id) ON DELETE CASCADE,
+    lang VARCHAR(10) NOT NULL,
+    -- 1-based, inclusive; NULL = whole chapter
+    paragraph_from INTEGER NULL,
+    paragraph_to INTEGER NULL,
+    reason TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'rejected', 'completed', 'failed')),
+    moderator_id UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    moderator_comment TEXT NULL,
+    reviewed_at TIMESTAMPTZ NULL,
+    translation_job_id UUID NULL REFERENCES translation_jobs(id) ON DELETE SET NULL,
+    -- Text of the chapter translation at the time the request was accepted
+    previous_content TEXT NULL,
+    previous_source VARCHAR(50) NULL,
+    error TEXT NULL,
+    completed_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT retranslation_requests_range CHECK (
+        (paragraph_from IS NULL AND paragraph_to IS NULL)
+        OR (paragraph_from >= 1 AND paragraph_to >= paragraph_from)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_retranslation_requests_status ON retranslation_requests(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_retranslation_requests_user ON retranslation_requests(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_retranslation_requests_job ON retranslation_requests(translation_job_id)
+    WHERE translation_job_id IS NOT NULL;
+
+-- Quota ledger, same shape as ticket_transactions: every request debits one unit of the
+-- subscription period's allowance, a request that fails on our side is refunded.
+CREATE TABLE IF NOT EXISTS retranslation_quota_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subscription_id UUID NULL REFERENCES subscriptions(id) ON DELETE SET NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    delta INTEGER NOT NULL,
+    reason VARCHAR(100) NOT NULL,
+    ref_type VARCHAR(50),
+    ref_id UUID,
+    idempotency_key VARCHAR(255) UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_retranslation_quota_user_period ON retranslation_quota_transactions(user_id, period_start);
@@ -0,0 +1,7 @@
+-- Migration: 022_chapter_content_revisions (down)
+-- Description: Drop chapter text history and its trigger
+-- Created: 2026-10-17
+
+DROP TRIGGER IF EXISTS record_chapter_contents_revision ON chapter_contents;
+DROP FUNCTION IF EXISTS record_chapter_content_revision();
+DROP TABLE IF EXISTS chapter_content_revisions;
@@ -0,0 +1,59 @@
+-- Migration: 022_chapter_content_revisions
+-- Description: Revision history of chapter texts (every write to chapter_contents)
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS chapter_content_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    chapter_id UUID NOT NULL REFERENCES chapters(id) ON DELETE CASCADE,
+    lang VARCHAR(10) NOT NULL,
+    content TEXT NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('manual', 'auto', 'import')),
+    word_count INTEGER NOT NULL DEFAULT 0,
+    author_id UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    -- e.g. "import:tadu", "translation_job:<id>", "rollback:<revision id>"
+    note TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chapter_content_revisions_chapter
+    ON chapter_content_revisions(chapter_id, lang, created_at DESC);
+
+-- Revisions are written by a trigger so that no writer (admin API, importers, MT pipeline,
+-- manual SQL) can bypass history. Writers describe themselves through transaction-local
+-- settings app.revision_author / app.revision_note (see repository.SetRevisionContext).
+CREATE OR REPLACE FUNCTION record_chapter_content_revision()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.content IS NOT DISTINCT FROM NEW.content AND OLD.source IS NOT DISTINCT FROM NEW.source THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO chapter_content_revisions (chapter_id, lang, content, source, word_count, author_id, note)
+    VALUES (
+        NEW.chapter_id,
+        NEW.lang,
+        NEW.content,
+        CASE WHEN NEW.source IN ('manual', 'auto') THEN NEW.source ELSE 'import' END,
+        NEW.word_count,
+        NULLIF(current_setting('app.revision_author', true), '')::uuid,
+        NULLIF(current_setting('app.revision_note', true), '')
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS record_chapter_contents_revision ON chapter_contents;
+CREATE TRIGGER record_chapter_contents_revision
+    AFTER INSERT OR UPDATE ON chapter_contents
+    FOR EACH ROW
+    EXECUTE FUNCTION record_chapter_content_revision();
+
+-- Existing texts become the first revision
+INSERT INTO chapter_content_revisions (chapter_id, lang, content, source, word_count, note, created_at)
+SELECT cc.chapter_id, cc.lang, cc.content,
+       CASE WHEN cc.source IN ('manual', 'auto') THEN cc.source ELSE 'import' END,
+       cc.word_count, 'baseline', cc.updated_at
+FROM chapter_contents cc
+WHERE NOT EXISTS (
+    SELECT 1 FROM chapter_content_revisions r WHERE r.chapter_id = cc.chapter_id AND r.lang = cc.lang
+);
@@ -0,0 +1,7 @@
+-- Migration: 023_export_full_feature (down)
+-- Description: Remove the full-export plan feature
+-- Created: 2026-10-17
+
+UPDATE subscription_plans
+SET features = features - 'canExportFull',
+    updated_at = NOW();
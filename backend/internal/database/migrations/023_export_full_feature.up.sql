@@ -0,0 +1,13 @@
+-- Migration: 023_export_full_feature
+-- Description: Subscription feature for exporting large chapter ranges to EPUB
+-- Created: 2026-10-17
+
+UPDATE subscription_plans
+SET features = jsonb_set(features, '{canExportFull}', 'true'::jsonb, true),
+    updated_at = NOW()
+WHERE code IN ('premium', 'vip');
+
+UPDATE subscription_plans
+SET features = jsonb_set(features, '{canExportFull}', 'false'::jsonb, true),
+    updated_at = NOW()
+WHERE code NOT IN ('premium', 'vip') AND NOT (features ? 'canExportFull');
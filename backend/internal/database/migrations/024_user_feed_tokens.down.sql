@@ -0,0 +1,5 @@
+-- Migration: 024_user_feed_tokens (down)
+-- Description: Drop per-user feed tokens
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS user_feed_tokens;
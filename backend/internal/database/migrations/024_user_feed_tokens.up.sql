@@ -0,0 +1,16 @@
+-- Migration: 024_user_feed_tokens
+-- Description: Per-user revocable tokens for OPDS/Atom feeds (readers can't send a JWT)
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS user_feed_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- sha256 of the token; the token itself is shown once on creation
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_feed_tokens_user ON user_feed_tokens(user_id, created_at DESC);
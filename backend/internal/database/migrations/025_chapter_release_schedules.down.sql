@@ -0,0 +1,14 @@
+-- Migration: 025_chapter_release_schedules (down)
+-- Description: Drop release schedules and the release queue trigger
+-- Created: 2026-10-17
+
+-- published_at backfilled by the up migration is kept; chapters still waiting in a release
+-- queue (published_at IS NULL) become visible again, as NULL meant "published" before.
+DROP TRIGGER IF EXISTS queue_scheduled_chapters ON chapters;
+DROP FUNCTION IF EXISTS queue_scheduled_chapter();
+DROP INDEX IF EXISTS idx_chapters_release_queue;
+DROP TABLE IF EXISTS novel_release_schedules;
+
+UPDATE subscription_plans
+SET features = features - 'earlyAccessHours',
+    updated_at = NOW();
@@ -0,0 +1,59 @@
+-- Migration: 025_chapter_release_schedules
+-- Description: Per-novel release cadence; new chapters of scheduled novels wait in a release queue
+-- Created: 2026-10-17
+
+-- Until now a missing published_at meant "published". From here on a chapter is live only when
+-- published_at <= NOW(); NULL means it waits in the release queue of its novel.
+UPDATE chapters SET published_at = created_at WHERE published_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS novel_release_schedules (
+    novel_id UUID PRIMARY KEY REFERENCES novels(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    chapters_per_release INTEGER NOT NULL DEFAULT 1 CHECK (chapters_per_release BETWEEN 1 AND 100),
+    -- local "HH:MM" release times
+    release_times TEXT[] NOT NULL DEFAULT ARRAY['18:00'],
+    -- 0 = Sunday ... 6 = Saturday; empty = every day
+    weekdays SMALLINT[] NOT NULL DEFAULT '{}',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    -- slot the queue was last released into; the next slot is computed from it
+    last_release_at TIMESTAMPTZ NULL,
+    updated_by UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chapters_release_queue
+    ON chapters(novel_id, number) WHERE published_at IS NULL;
+
+-- New chapters of novels with an enabled schedule go to the queue, whoever inserts them
+-- (importers, source sync, admin API). Writers that set an explicit date on purpose
+-- opt out with the transaction-local setting app.chapter_release = 'manual'.
+CREATE OR REPLACE FUNCTION queue_scheduled_chapter()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF current_setting('app.chapter_release', true) = 'manual' THEN
+        RETURN NEW;
+    END IF;
+    IF EXISTS (SELECT 1 FROM novel_release_schedules WHERE novel_id = NEW.novel_id AND enabled) THEN
+        NEW.published_at := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS queue_scheduled_chapters ON chapters;
+CREATE TRIGGER queue_scheduled_chapters
+    BEFORE INSERT ON chapters
+    FOR EACH ROW
+    EXECUTE FUNCTION queue_scheduled_chapter();
+
+-- Early access to scheduled chapters for subscribers (hours before published_at)
+UPDATE subscription_plans
+SET features = jsonb_set(features, '{earlyAccessHours}', '12'::jsonb, true),
+    updated_at = NOW()
+WHERE code = 'premium' AND NOT (features ? 'earlyAccessHours');
+
+UPDATE subscription_plans
+SET features = jsonb_set(features, '{earlyAccessHours}', '24'::jsonb, true),
+    updated_at = NOW()
+WHERE code = 'vip' AND NOT (features ? 'earlyAccessHours');
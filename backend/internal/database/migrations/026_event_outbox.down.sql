@@ -0,0 +1,6 @@
+-- Migration: 026_event_outbox (down)
+-- Description: Drop the domain event outbox
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS event_outbox_deliveries;
+DROP TABLE IF EXISTS event_outbox;
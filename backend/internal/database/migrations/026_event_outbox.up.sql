@@ -0,0 +1,33 @@
+-- Migration: 026_event_outbox
+-- Description: Transactional outbox for domain events with per-handler delivery records
+-- Created: 2026-10-17
+
+-- Events are written in the same transaction as the state change they describe and delivered
+-- to subscribers by the outbox dispatcher (at least once, with retries and dead-lettering).
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_name VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'delivered', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 10,
+    error TEXT NULL,
+    locked_by TEXT NULL,
+    locked_until TIMESTAMPTZ NULL,
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_claim ON event_outbox (status, available_at);
+CREATE INDEX IF NOT EXISTS idx_event_outbox_delivered ON event_outbox (delivered_at) WHERE status = 'delivered';
+
+-- A handler (identified by its subscription key) that already processed an event is skipped
+-- when the event is retried because another handler failed.
+CREATE TABLE IF NOT EXISTS event_outbox_deliveries (
+    event_id UUID NOT NULL REFERENCES event_outbox(id) ON DELETE CASCADE,
+    handler_key VARCHAR(100) NOT NULL,
+    delivered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, handler_key)
+);
@@ -0,0 +1,5 @@
+-- Migration: 027_rate_limit_settings (down)
+-- Description: Remove rate limit policies from app_settings (built-in defaults apply)
+-- Created: 2026-10-17
+
+DELETE FROM app_settings WHERE key = 'rate_limits';
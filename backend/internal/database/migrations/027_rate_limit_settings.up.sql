@@ -0,0 +1,16 @@
+-- Migration: 027_rate_limit_settings
+-- Description: Admin-editable rate limit policies in app_settings
+-- Created: 2026-10-17
+
+-- Each policy is a token bucket: up to "limit" requests in a burst, refilled over "period".
+-- "by" is "ip" or "user" (anonymous requests fall back to IP); "limit": 0 disables a policy.
+-- Policies missing here use the built-in defaults.
+INSERT INTO app_settings (key, value, description) VALUES
+    ('rate_limits', '{
+        "auth_login":      {"limit": 10, "period": "1m", "by": "ip"},
+        "auth_register":   {"limit": 5,  "period": "1h", "by": "ip"},
+        "comments_create": {"limit": 10, "period": "1m", "by": "user"},
+        "votes":           {"limit": 30, "period": "1m", "by": "user"},
+        "upload":          {"limit": 20, "period": "1h", "by": "user"}
+    }'::jsonb, 'Лимиты запросов по группам маршрутов (token bucket)')
+ON CONFLICT (key) DO NOTHING;
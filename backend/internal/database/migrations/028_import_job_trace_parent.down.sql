@@ -0,0 +1,5 @@
+-- Migration: 028_import_job_trace_parent (down)
+-- Description: Drop the trace context of import jobs
+-- Created: 2026-10-17
+
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS trace_parent;
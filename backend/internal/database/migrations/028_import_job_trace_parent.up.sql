@@ -0,0 +1,7 @@
+-- Migration: 028_import_job_trace_parent
+-- Description: Carry the trace of the request that enqueued an import over to the queue worker
+-- Created: 2026-10-17
+
+-- W3C traceparent of the span that enqueued the job; the worker continues that trace, so an
+-- import started from the admin panel shows up as one trace down to the parser-service calls.
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS trace_parent TEXT;
@@ -0,0 +1,9 @@
+-- Migration: 029_import_run_origin (down)
+-- Description: Drop CLI import runs without a proposal and restore NOT NULL proposal_id
+-- Created: 2026-10-17
+
+DELETE FROM import_runs WHERE proposal_id IS NULL;
+ALTER TABLE import_runs ALTER COLUMN proposal_id SET NOT NULL;
+
+ALTER TABLE import_runs DROP COLUMN IF EXISTS source_url;
+ALTER TABLE import_runs DROP COLUMN IF EXISTS origin;
@@ -0,0 +1,10 @@
+-- Migration: 029_import_run_origin
+-- Description: Record imports started from cmd/novelctl in import_runs: such runs may have no
+--              proposal and carry the source URL themselves
+-- Created: 2026-10-17
+
+ALTER TABLE import_runs ALTER COLUMN proposal_id DROP NOT NULL;
+
+-- queue: enqueued by the API and run by import queue workers; cli: run in-process by novelctl
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS origin TEXT NOT NULL DEFAULT 'queue';
+ALTER TABLE import_runs ADD COLUMN IF NOT EXISTS source_url TEXT NULL;
@@ -0,0 +1,6 @@
+-- Migration: 030_source_links (down)
+-- Description: Drop source links and the proposal title trigram index
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_novel_proposals_title_trgm;
+DROP TABLE IF EXISTS source_links;
@@ -0,0 +1,63 @@
+-- Migration: 030_source_links
+-- Description: One row per source book (canonical URL) shared by proposals and imported novels,
+--              so the same book cannot be proposed twice; trigram index for similar proposal titles
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS source_links (
+    -- canonical book page URL as produced by the importer (see ProposalImporter.CanonicalURL)
+    canonical_url TEXT PRIMARY KEY,
+    importer TEXT NOT NULL DEFAULT '',
+    -- the proposal of the book; a rejected or deleted proposal frees the URL for a new one
+    proposal_id UUID NULL REFERENCES novel_proposals(id) ON DELETE SET NULL,
+    -- the novel imported from the URL; while set, the book cannot be proposed again
+    novel_id UUID NULL REFERENCES novels(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_source_links_proposal_id ON source_links (proposal_id) WHERE proposal_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_source_links_novel_id ON source_links (novel_id) WHERE novel_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_novel_proposals_title_trgm ON novel_proposals USING GIN (title gin_trgm_ops);
+
+-- Backfill from proposals and from novels imported by novelctl without a proposal.
+-- Same rules as the importers: the book id taken from any page of the book.
+WITH candidates AS (
+    SELECT p.original_link AS link, p.id AS proposal_id, p.novel_id, p.created_at,
+           CASE WHEN p.novel_id IS NOT NULL THEN 0 ELSE 1 END AS priority
+    FROM novel_proposals p
+    WHERE p.status <> 'rejected' OR p.novel_id IS NOT NULL
+    UNION ALL
+    SELECT r.source_url, NULL, r.novel_id, r.created_at, 0
+    FROM import_runs r
+    WHERE r.proposal_id IS NULL AND r.novel_id IS NOT NULL AND r.source_url IS NOT NULL AND r.status = 'succeeded'
+), canon AS (
+    SELECT
+        CASE
+            WHEN m.tadu IS NOT NULL THEN 'https://www.tadu.com/book/' || m.tadu || '/'
+            WHEN m.shuba IS NOT NULL THEN 'https://www.69shuba.com/book/' || m.shuba || '.htm'
+            WHEN m.kks IS NOT NULL THEN 'https://101kks.com/book/' || m.kks || '.html'
+            WHEN m.fanqie IS NOT NULL THEN 'https://fanqienovel.com/page/' || m.fanqie
+        END AS canonical_url,
+        CASE
+            WHEN m.tadu IS NOT NULL THEN 'tadu'
+            WHEN m.shuba IS NOT NULL THEN '69shuba'
+            WHEN m.kks IS NOT NULL THEN '101kks'
+            WHEN m.fanqie IS NOT NULL THEN 'fanqie'
+        END AS importer,
+        c.proposal_id, c.novel_id, c.created_at, c.priority
+    FROM candidates c,
+    LATERAL (
+        SELECT
+            substring(lower(c.link) FROM 'tadu\.com/book/(?:catalogue/)?([0-9]+)') AS tadu,
+            substring(lower(c.link) FROM '69shuba\.com/(?:book|txt)/([0-9]+)') AS shuba,
+            substring(lower(c.link) FROM '101kks\.com/(?:book|txt)/([0-9]+)') AS kks,
+            substring(lower(c.link) FROM 'fanqienovel\.com/page/([0-9]+)') AS fanqie
+    ) m
+)
+INSERT INTO source_links (canonical_url, importer, proposal_id, novel_id, created_at)
+SELECT DISTINCT ON (canonical_url) canonical_url, importer, proposal_id, novel_id, created_at
+FROM canon
+WHERE canonical_url IS NOT NULL
+ORDER BY canonical_url, priority, created_at
+ON CONFLICT (canonical_url) DO NOTHING;
@@ -0,0 +1,15 @@
+-- Migration: 031_achievement_rules (down)
+-- Description: Drop achievement progress, backfill state and the achievements added by the up migration
+-- Created: 2026-10-17
+
+DELETE FROM achievements WHERE code IN ('devoted_reader', 'streak_7', 'popular_commentator', 'kingmaker');
+UPDATE achievements SET condition = NULL
+WHERE code IN ('first_chapter', 'bookworm_10', 'bookworm_100', 'bookworm_1000', 'first_comment',
+               'commentator_10', 'commentator_100', 'first_bookmark', 'collector_10', 'collector_100');
+
+DROP TABLE IF EXISTS user_achievement_progress;
+DROP INDEX IF EXISTS idx_xp_events_user_type;
+
+DROP TRIGGER IF EXISTS trg_reset_achievement_backfill ON achievements;
+DROP FUNCTION IF EXISTS reset_achievement_backfill();
+ALTER TABLE achievements DROP COLUMN IF EXISTS backfilled_at;
@@ -0,0 +1,62 @@
+-- Migration: 031_achievement_rules
+-- Description: Declarative achievement conditions (see package achievements), per-user progress
+--              and retroactive backfill of new or changed achievements
+-- Created: 2026-10-17
+
+-- NULL until the backfill job has evaluated the achievement for every user; reset when the
+-- condition changes, so existing users get achievements they already qualify for.
+ALTER TABLE achievements ADD COLUMN IF NOT EXISTS backfilled_at TIMESTAMPTZ NULL;
+
+CREATE OR REPLACE FUNCTION reset_achievement_backfill()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.condition IS DISTINCT FROM OLD.condition THEN
+        NEW.backfilled_at = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_reset_achievement_backfill ON achievements;
+CREATE TRIGGER trg_reset_achievement_backfill
+    BEFORE UPDATE ON achievements
+    FOR EACH ROW EXECUTE FUNCTION reset_achievement_backfill();
+
+-- Progress of users towards achievements they have not unlocked yet.
+CREATE TABLE IF NOT EXISTS user_achievement_progress (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_id UUID NOT NULL REFERENCES achievements(id) ON DELETE CASCADE,
+    current BIGINT NOT NULL DEFAULT 0,
+    target BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, achievement_id)
+);
+
+-- Metric queries read the events of one type of a batch of users.
+CREATE INDEX IF NOT EXISTS idx_xp_events_user_type ON xp_events (user_id, type);
+
+UPDATE achievements SET condition = c.condition::jsonb
+FROM (VALUES
+    ('first_chapter',   '{"metric": "chapters_read", "gte": 1}'),
+    ('bookworm_10',     '{"metric": "chapters_read", "gte": 10}'),
+    ('bookworm_100',    '{"metric": "chapters_read", "gte": 100}'),
+    ('bookworm_1000',   '{"metric": "chapters_read", "gte": 1000}'),
+    ('first_comment',   '{"metric": "comments", "gte": 1}'),
+    ('commentator_10',  '{"metric": "comments", "gte": 10}'),
+    ('commentator_100', '{"metric": "comments", "gte": 100}'),
+    ('first_bookmark',  '{"metric": "bookmarks", "gte": 1}'),
+    ('collector_10',    '{"metric": "bookmarks", "gte": 10}'),
+    ('collector_100',   '{"metric": "bookmarks", "gte": 100}')
+) AS c(code, condition)
+WHERE achievements.code = c.code AND achievements.condition IS NULL;
+
+INSERT INTO achievements (code, title, description, icon_key, condition, xp_reward) VALUES
+    ('devoted_reader', 'Преданный читатель', 'Прочитать 100 глав одной новеллы', 'book-heart',
+        '{"metric": "chapters_read_in_novel", "gte": 100}', 300),
+    ('streak_7', 'Неделя без перерыва', 'Читать главы 7 дней подряд', 'flame',
+        '{"metric": "reading_streak_days", "gte": 7}', 150),
+    ('popular_commentator', 'Голос сообщества', 'Оставить 10 комментариев, набравших 20+ лайков', 'star',
+        '{"metric": "comments_with_likes", "params": {"minLikes": 20}, "gte": 10}', 400),
+    ('kingmaker', 'Делатель королей', 'Проголосовать за предложку, победившую в голосовании', 'trophy',
+        '{"metric": "won_proposals_voted", "gte": 1}', 100)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,11 @@
+-- Migration: 032_streaks (down)
+-- Description: Drop streaks, activity days, streak rewards and the user timezone
+-- Created: 2026-10-17
+
+DELETE FROM achievements WHERE code = 'streak_30';
+
+DROP TABLE IF EXISTS user_streak_rewards;
+DROP TABLE IF EXISTS user_activity_days;
+DROP TABLE IF EXISTS user_streaks;
+
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,46 @@
+-- Migration: 032_streaks
+-- Description: Daily activity streaks: per-user timezone, activity days, streak freezes and
+--              milestone rewards
+-- Created: 2026-10-17
+
+-- Local day boundaries of streaks; NULL means the default timezone (STREAK_DEFAULT_TIMEZONE).
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NULL;
+
+CREATE TABLE IF NOT EXISTS user_streaks (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    current_streak INTEGER NOT NULL DEFAULT 0,
+    longest_streak INTEGER NOT NULL DEFAULT 0,
+    -- First day of the current streak; milestone rewards are granted once per streak.
+    started_on DATE NULL,
+    last_active_on DATE NULL,
+    -- Streak freezes held: each one covers a missed day.
+    freezes INTEGER NOT NULL DEFAULT 0 CHECK (freezes >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Days (local to the user's timezone) with activity. Days covered by a freeze have no kinds.
+CREATE TABLE IF NOT EXISTS user_activity_days (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    kinds TEXT[] NOT NULL DEFAULT '{}', -- login, read
+    frozen BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (user_id, day)
+);
+
+-- Milestone bonuses; granted_at stays NULL until the XP and tickets are granted.
+CREATE TABLE IF NOT EXISTS user_streak_rewards (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    milestone INTEGER NOT NULL,
+    started_on DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    granted_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (user_id, milestone, started_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_streak_rewards_pending
+    ON user_streak_rewards (user_id) WHERE granted_at IS NULL;
+
+INSERT INTO achievements (code, title, description, icon_key, condition, xp_reward) VALUES
+    ('streak_30', 'Месяц без пропусков', 'Заходить на сайт 30 дней подряд', 'calendar-check',
+        '{"metric": "activity_streak_days", "gte": 30}', 300)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,11 @@
+-- Migration: 033_read_verification (down)
+-- Description: Drop read sessions, the one-award-per-chapter index and clawback columns
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS uq_xp_events_read_chapter;
+
+ALTER TABLE xp_events DROP COLUMN IF EXISTS revoke_reason;
+ALTER TABLE xp_events DROP COLUMN IF EXISTS revoked_by;
+ALTER TABLE xp_events DROP COLUMN IF EXISTS revoked_at;
+
+DROP TABLE IF EXISTS chapter_read_sessions;
@@ -0,0 +1,65 @@
+-- Migration: 033_read_verification
+-- Description: Server-side verification of chapter reads before read_chapter XP is awarded,
+--              one award per (user, chapter) and XP clawback
+-- Created: 2026-10-17
+
+-- A continuous read of a chapter, fed by progress saves. read_chapter XP is awarded once the
+-- chapter was open long enough for its length and the position moved forward.
+CREATE TABLE IF NOT EXISTS chapter_read_sessions (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chapter_id UUID NOT NULL REFERENCES chapters(id) ON DELETE CASCADE,
+    novel_id UUID NOT NULL REFERENCES novels(id) ON DELETE CASCADE,
+    -- Shortest text of the chapter over its languages; the dwell time is derived from it.
+    words INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    start_position INTEGER NOT NULL DEFAULT 0,
+    max_position INTEGER NOT NULL DEFAULT 0,
+    last_position INTEGER NOT NULL DEFAULT 0,
+    updates INTEGER NOT NULL DEFAULT 1,
+    -- Saves with a position behind the previous one.
+    regressions INTEGER NOT NULL DEFAULT 0,
+    -- Set when the read qualified but a daily cap was reached.
+    capped_at TIMESTAMPTZ NULL,
+    awarded_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (user_id, chapter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chapter_read_sessions_user_started
+    ON chapter_read_sessions (user_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_chapter_read_sessions_user_awarded
+    ON chapter_read_sessions (user_id, awarded_at) WHERE awarded_at IS NOT NULL;
+
+ALTER TABLE xp_events ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ NULL;
+ALTER TABLE xp_events ADD COLUMN IF NOT EXISTS revoked_by UUID NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE xp_events ADD COLUMN IF NOT EXISTS revoke_reason TEXT NULL;
+
+-- Concurrent progress saves could award a chapter twice; drop the duplicates (keeping the first
+-- award) and take their XP back before enforcing one award per (user, chapter).
+WITH dupes AS (
+    DELETE FROM xp_events e
+    USING (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, ref_id ORDER BY created_at, id) AS rn
+        FROM xp_events
+        WHERE type = 'read_chapter'
+    ) d
+    WHERE e.id = d.id AND d.rn > 1
+    RETURNING e.user_id, e.delta
+)
+UPDATE user_xp x SET
+    xp_total = x.xp_total - s.delta,
+    level = GREATEST(1, LEAST(100, FLOOR(SQRT(GREATEST(x.xp_total - s.delta, 0) / 100.0))))::int,
+    updated_at = NOW()
+FROM (SELECT user_id, SUM(delta) AS delta FROM dupes GROUP BY user_id) s
+WHERE x.user_id = s.user_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_xp_events_read_chapter
+    ON xp_events (user_id, ref_id) WHERE type = 'read_chapter';
+
+-- Chapters awarded before verification count as verified reads.
+INSERT INTO chapter_read_sessions (user_id, chapter_id, novel_id, started_at, last_seen_at, awarded_at)
+SELECT e.user_id, e.ref_id, c.novel_id, e.created_at, e.created_at, e.created_at
+FROM xp_events e
+JOIN chapters c ON c.id = e.ref_id
+WHERE e.type = 'read_chapter'
+ON CONFLICT (user_id, chapter_id) DO NOTHING;
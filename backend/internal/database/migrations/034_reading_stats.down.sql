@@ -0,0 +1,9 @@
+-- Migration: 034_reading_stats (down)
+-- Description: Drop reading days, sittings, year reviews and the public stats toggle
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS user_year_reviews;
+DROP TABLE IF EXISTS user_reading_sittings;
+DROP TABLE IF EXISTS user_reading_days;
+
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS stats_public;
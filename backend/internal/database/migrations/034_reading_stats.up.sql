@@ -0,0 +1,60 @@
+-- Migration: 034_reading_stats
+-- Description: Reading time tracking per user/novel/day, reading sittings, cached year in review
+--              and the public stats toggle
+-- Created: 2026-10-17
+
+-- Whether the reading statistics are shown on the public profile.
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS stats_public BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Reading per novel and day (local to the user's timezone). seconds grows with each progress save,
+-- chapters and words when a chapter read is verified (chapter_read_sessions.awarded_at).
+CREATE TABLE IF NOT EXISTS user_reading_days (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    novel_id UUID NOT NULL REFERENCES novels(id) ON DELETE CASCADE,
+    seconds INTEGER NOT NULL DEFAULT 0,
+    chapters INTEGER NOT NULL DEFAULT 0,
+    words BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day, novel_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_reading_days_day ON user_reading_days (day);
+
+-- Uninterrupted reading: progress saves no more than READING_STATS_IDLE_GAP apart. The latest
+-- sitting of a user is the one being extended.
+CREATE TABLE IF NOT EXISTS user_reading_sittings (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    seconds INTEGER NOT NULL DEFAULT 0,
+    chapters INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_reading_sittings_longest
+    ON user_reading_sittings (user_id, seconds DESC);
+
+-- Year in review, generated by the year_review job.
+CREATE TABLE IF NOT EXISTS user_year_reviews (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    data JSONB NOT NULL,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, year)
+);
+
+-- Verified reads so far have no reading time, but count as chapters and words. Reads backfilled
+-- by 033 have no word count. Europe/Moscow is the STREAK_DEFAULT_TIMEZONE default.
+INSERT INTO user_reading_days (user_id, day, novel_id, chapters, words)
+SELECT s.user_id,
+       (s.awarded_at AT TIME ZONE COALESCE(p.timezone, 'Europe/Moscow'))::date,
+       s.novel_id, COUNT(*), SUM(COALESCE(NULLIF(s.words, 0), w.words, 0))
+FROM chapter_read_sessions s
+LEFT JOIN user_profiles p ON p.user_id = s.user_id
+LEFT JOIN LATERAL (
+    SELECT MIN(word_count) FILTER (WHERE word_count > 0) AS words
+    FROM chapter_contents WHERE chapter_id = s.chapter_id
+) w ON TRUE
+WHERE s.awarded_at IS NOT NULL
+GROUP BY 1, 2, 3
+ON CONFLICT (user_id, day, novel_id) DO NOTHING;
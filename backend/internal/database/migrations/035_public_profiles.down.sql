@@ -0,0 +1,16 @@
+-- Migration: 035_public_profiles (down)
+-- Description: Drop profile section privacy and the proposal submission time
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_comments_user_created;
+DROP INDEX IF EXISTS idx_edit_requests_user_reviewed;
+DROP INDEX IF EXISTS idx_novel_proposals_user_submitted;
+
+ALTER TABLE novel_proposals DROP COLUMN IF EXISTS submitted_at;
+
+ALTER TABLE user_profiles
+    DROP COLUMN IF EXISTS achievements_public,
+    DROP COLUMN IF EXISTS collections_public,
+    DROP COLUMN IF EXISTS bookmarks_public,
+    DROP COLUMN IF EXISTS comments_public,
+    DROP COLUMN IF EXISTS activity_public;
@@ -0,0 +1,27 @@
+-- Migration: 035_public_profiles
+-- Description: Per-section privacy of the public profile and the submission time of proposals
+--              for the profile activity feed
+-- Created: 2026-10-17
+
+-- Which sections of the profile other users see (reading statistics: stats_public, see 034).
+-- Bookmark statistics are opt-in.
+ALTER TABLE user_profiles
+    ADD COLUMN IF NOT EXISTS achievements_public BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS collections_public BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS bookmarks_public BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS comments_public BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS activity_public BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- When the proposal left the draft. Proposals submitted before this migration get their
+-- creation time.
+ALTER TABLE novel_proposals ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMPTZ;
+
+UPDATE novel_proposals SET submitted_at = created_at
+WHERE status <> 'draft' AND submitted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_novel_proposals_user_submitted
+    ON novel_proposals(user_id, submitted_at DESC) WHERE submitted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_edit_requests_user_reviewed
+    ON novel_edit_requests(user_id, reviewed_at DESC) WHERE status = 'approved';
+CREATE INDEX IF NOT EXISTS idx_comments_user_created
+    ON comments(user_id, created_at DESC) WHERE is_deleted = FALSE;
@@ -0,0 +1,5 @@
+-- Migration: 036_disable_one_shot_syncs (down)
+-- Description: Nothing to undo: re-enabling sync of fanqie novels would only bring back failing runs
+-- Created: 2026-10-17
+
+SELECT 1;
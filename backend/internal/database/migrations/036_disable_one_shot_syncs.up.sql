@@ -0,0 +1,9 @@
+-- Migration: 036_disable_one_shot_syncs
+-- Description: Stop syncing novels imported by one-shot importers (fanqie)
+-- Created: 2026-10-17
+
+-- The 018 backfill followed every imported novel, including fanqie ones, which cannot be
+-- synced: their sync runs were queued every interval and always failed.
+UPDATE novel_sources
+SET sync_enabled = FALSE, updated_at = NOW()
+WHERE importer = 'fanqie' AND sync_enabled = TRUE;
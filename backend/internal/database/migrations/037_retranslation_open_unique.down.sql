@@ -0,0 +1,5 @@
+-- Migration: 037_retranslation_open_unique (down)
+-- Description: Drop the open retranslation request unique index; rejected duplicates stay rejected
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_retranslation_requests_open;
@@ -0,0 +1,36 @@
+-- Migration: 037_retranslation_open_unique
+-- Description: At most one open (pending or accepted) retranslation request per chapter translation
+-- Created: 2026-10-17
+
+-- Concurrent submissions could both pass the open-request check and both debit the quota.
+-- Reject the duplicates that got in (keeping the accepted one, otherwise the oldest) and
+-- refund them, the same way RetranslationRepository.Refund does.
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY chapter_id, lang
+        ORDER BY (status = 'accepted') DESC, created_at, id
+    ) AS rn
+    FROM retranslation_requests
+    WHERE status IN ('pending', 'accepted')
+), rejected AS (
+    UPDATE retranslation_requests rr
+    SET status = 'rejected',
+        moderator_comment = 'duplicate request',
+        reviewed_at = NOW(),
+        updated_at = NOW()
+    FROM ranked
+    WHERE rr.id = ranked.id AND ranked.rn > 1
+    RETURNING rr.id
+)
+INSERT INTO retranslation_quota_transactions
+    (user_id, subscription_id, period_start, delta, reason, ref_type, ref_id, idempotency_key)
+SELECT t.user_id, t.subscription_id, t.period_start, 1, 'duplicate_request', t.ref_type, t.ref_id,
+       'retranslation_refund:' || t.ref_id::text
+FROM retranslation_quota_transactions t
+JOIN rejected ON rejected.id = t.ref_id
+WHERE t.ref_type = 'retranslation_request' AND t.delta < 0
+ON CONFLICT (idempotency_key) DO NOTHING;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_retranslation_requests_open
+    ON retranslation_requests(chapter_id, lang)
+    WHERE status IN ('pending', 'accepted');
@@ -59,6 +59,7 @@ type ChapterListItem struct {
 	PublishedAt   *time.Time `db:"published_at" json:"published_at,omitempty"`
 	IsRead        bool       `json:"is_read"`
 	IsNew         bool       `json:"is_new"` // Вышла за последние 24 часа
+	IsEarlyAccess bool       `json:"is_early_access"` // Еще не вышла, доступна по раннему доступу
 	CommentsCount int        `json:"comments_count"`
 }
 
@@ -78,6 +79,8 @@ type CreateChapterRequest struct {
 	Slug     *string                       `json:"slug,omitempty" validate:"omitempty,max=255"`
 	Title    *string                       `json:"title,omitempty" validate:"omitempty,max=500"`
 	Contents []CreateChapterContentRequest `json:"contents" validate:"required,min=1,dive"`
+	// PublishedAt — явная дата выхода; без нее глава новеллы с расписанием встает в очередь
+	PublishedAt *time.Time `json:"published_at,omitempty"`
 }
 
 // CreateChapterContentRequest запрос на создание содержимого главы
@@ -93,6 +96,8 @@ type UpdateChapterRequest struct {
 	Slug     *string                       `json:"slug,omitempty" validate:"omitempty,max=255"`
 	Title    *string                       `json:"title,omitempty" validate:"omitempty,max=500"`
 	Contents []CreateChapterContentRequest `json:"contents,omitempty" validate:"omitempty,dive"`
+	// PublishedAt переносит выход главы (в том числе достает ее из очереди)
+	PublishedAt *time.Time `json:"published_at,omitempty"`
 }
 
 // ReadingProgress прогресс чтения
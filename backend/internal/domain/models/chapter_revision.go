@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChapterContentRevision is a stored version of a chapter text in one language.
+// Revisions are written for every change of chapter_contents (admin edits, imports, MT, rollbacks).
+type ChapterContentRevision struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	ChapterID uuid.UUID  `json:"chapterId" db:"chapter_id"`
+	Lang      string     `json:"lang" db:"lang"`
+	Content   string     `json:"content,omitempty" db:"content"`
+	Source    string     `json:"source" db:"source"` // manual, auto, import
+	WordCount int        `json:"wordCount" db:"word_count"`
+	AuthorID  *uuid.UUID `json:"authorId,omitempty" db:"author_id"`
+	Note      *string    `json:"note,omitempty" db:"note"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+}
+
+type ParagraphDiffOp string
+
+const (
+	ParagraphEqual  ParagraphDiffOp = "equal"
+	ParagraphInsert ParagraphDiffOp = "insert"
+	ParagraphDelete ParagraphDiffOp = "delete"
+)
+
+// ParagraphDiff is one paragraph of a diff. OldIndex/NewIndex are 1-based paragraph
+// numbers (blank lines not counted) in the from/to revision.
+type ParagraphDiff struct {
+	Op       ParagraphDiffOp `json:"op"`
+	OldIndex *int            `json:"oldIndex,omitempty"`
+	NewIndex *int            `json:"newIndex,omitempty"`
+	Text     string          `json:"text"`
+}
+
+// ChapterRevisionDiff is a paragraph-level diff between two revisions.
+type ChapterRevisionDiff struct {
+	From       uuid.UUID       `json:"from"`
+	To         uuid.UUID       `json:"to"`
+	Added      int             `json:"added"`
+	Removed    int             `json:"removed"`
+	Unchanged  int             `json:"unchanged"`
+	Paragraphs []ParagraphDiff `json:"paragraphs"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// ExportChapter is a chapter included in an export, without its text.
+type ExportChapter struct {
+	ID          uuid.UUID `db:"id"`
+	Number      float64   `db:"number"`
+	Title       *string   `db:"title"`
+	ContentHash string    `db:"content_hash"` // md5 of the text in the export language
+}
+
+// EPUBExport is a prepared EPUB export: what goes into the book and where it is cached.
+type EPUBExport struct {
+	Novel    *NovelWithLocalization
+	Lang     string
+	Authors  []string
+	Chapters []ExportChapter
+	// Key is the content hash the generated file is cached under.
+	Key      string
+	FileName string
+}
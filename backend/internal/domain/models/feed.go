@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedChapter is a published chapter as listed in an update feed.
+type FeedChapter struct {
+	ID          uuid.UUID `db:"id"`
+	NovelID     uuid.UUID `db:"novel_id"`
+	NovelSlug   string    `db:"novel_slug"`
+	NovelTitle  string    `db:"novel_title"`
+	Number      float64   `db:"number"`
+	Title       *string   `db:"title"`
+	PublishedAt time.Time `db:"published_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedToken lets e-reader apps and feed readers access personal feeds without a JWT.
+type FeedToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"-" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+}
+
+// CreateFeedTokenRequest represents request to create a feed token
+type CreateFeedTokenRequest struct {
+	Name string `json:"name"`
+}
+
+// CreatedFeedToken is returned once on creation; only the hash of Token is stored.
+type CreatedFeedToken struct {
+	FeedToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GlossaryTranslation is the rendering of a glossary term in one language.
+// Forms holds inflected variants keyed by grammatical case/gender (e.g. "gen", "dat", "fem").
+type GlossaryTranslation struct {
+	Target string            `json:"target"`
+	Forms  map[string]string `json:"forms,omitempty"`
+}
+
+// GlossaryTranslations maps language code to translation; stored as JSONB.
+type GlossaryTranslations map[string]GlossaryTranslation
+
+func (t GlossaryTranslations) Value() (driver.Value, error) {
+	if t == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(t)
+}
+
+func (t *GlossaryTranslations) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*t = GlossaryTranslations{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("glossary translations: unsupported type %T", src)
+	}
+	out := GlossaryTranslations{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	*t = out
+	return nil
+}
+
+// GlossaryTerm is a per-novel term (name, cultivation rank, place) that must be translated consistently.
+type GlossaryTerm struct {
+	ID           uuid.UUID            `json:"id" db:"id"`
+	NovelID      uuid.UUID            `json:"novelId" db:"novel_id"`
+	SourceTerm   string               `json:"sourceTerm" db:"source_term"`
+	Translations GlossaryTranslations `json:"translations" db:"translations"`
+	Notes        *string              `json:"notes,omitempty" db:"notes"`
+	CreatedBy    *uuid.UUID           `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt    time.Time            `json:"createdAt" db:"created_at"`
+	UpdatedAt    time.Time            `json:"updatedAt" db:"updated_at"`
+}
+
+// GlossaryTermInput is the body of glossary create/update requests and the JSON
+// payload of "glossary" wiki edit changes.
+type GlossaryTermInput struct {
+	SourceTerm   string               `json:"sourceTerm"`
+	Translations GlossaryTranslations `json:"translations"`
+	Notes        *string              `json:"notes,omitempty"`
+}
+
+// GlossaryViolation is a chapter translation in which a source term leaked through untranslated.
+type GlossaryViolation struct {
+	TermID        uuid.UUID `json:"termId" db:"term_id"`
+	SourceTerm    string    `json:"sourceTerm" db:"source_term"`
+	ChapterID     uuid.UUID `json:"chapterId" db:"chapter_id"`
+	ChapterNumber float64   `json:"chapterNumber" db:"chapter_number"`
+	Lang          string    `json:"lang" db:"lang"`
+	ContentSource string    `json:"contentSource" db:"content_source"`
+	Occurrences   int       `json:"occurrences" db:"occurrences"`
+}
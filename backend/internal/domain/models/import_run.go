@@ -10,6 +10,7 @@ import (
 type ImportRunStatus string
 
 const (
+	ImportRunStatusQueued        ImportRunStatus = "queued"
 	ImportRunStatusRunning       ImportRunStatus = "running"
 	ImportRunStatusPauseRequested ImportRunStatus = "pause_requested"
 	ImportRunStatusPaused        ImportRunStatus = "paused"
@@ -18,11 +19,33 @@ const (
 	ImportRunStatusCancelled     ImportRunStatus = "cancelled"
 )
 
+type ImportRunKind string
+
+const (
+	// ImportRunKindImport is the initial import of a proposal into a new novel.
+	ImportRunKindImport ImportRunKind = "import"
+	// ImportRunKindSync fetches chapters published at the source after the initial import.
+	ImportRunKindSync ImportRunKind = "sync"
+)
+
+type ImportRunOrigin string
+
+const (
+	// ImportRunOriginQueue runs are enqueued by the API and executed by import queue workers.
+	ImportRunOriginQueue ImportRunOrigin = "queue"
+	// ImportRunOriginCLI runs are executed in-process by cmd/novelctl; they may have no proposal.
+	ImportRunOriginCLI ImportRunOrigin = "cli"
+)
+
 type ImportRun struct {
 	ID        uuid.UUID       `json:"id" db:"id"`
-	ProposalID uuid.UUID      `json:"proposalId" db:"proposal_id"`
+	ProposalID *uuid.UUID     `json:"proposalId,omitempty" db:"proposal_id"`
 	NovelID   *uuid.UUID      `json:"novelId,omitempty" db:"novel_id"`
 	Importer  string          `json:"importer" db:"importer"`
+	Kind      ImportRunKind   `json:"kind" db:"kind"`
+	Origin    ImportRunOrigin `json:"origin" db:"origin"`
+	// SourceURL is the imported link of runs without a proposal.
+	SourceURL *string `json:"sourceUrl,omitempty" db:"source_url"`
 	Status    ImportRunStatus `json:"status" db:"status"`
 	Error     *string         `json:"error,omitempty" db:"error"`
 
@@ -43,4 +66,37 @@ type ImportRunCookie struct {
 	CookieHeader string   `json:"cookieHeader" db:"cookie_header"`
 	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
 	UpdatedAt    time.Time `json:"updatedAt" db:"updated_at"`
-}
\ No newline at end of file
+}
+
+type ImportJobStatus string
+
+const (
+	ImportJobStatusQueued ImportJobStatus = "queued"
+	ImportJobStatusLeased ImportJobStatus = "leased"
+	ImportJobStatusDone   ImportJobStatus = "done"
+)
+
+// ImportJob is the durable queue entry backing an ImportRun.
+// A worker owns the job while its lease is valid and extends it with heartbeats.
+type ImportJob struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	RunID      uuid.UUID       `json:"runId" db:"run_id"`
+	ProposalID uuid.UUID       `json:"proposalId" db:"proposal_id"`
+	Importer   string          `json:"importer" db:"importer"`
+	Status     ImportJobStatus `json:"status" db:"status"`
+
+	Attempts    int `json:"attempts" db:"attempts"`
+	MaxAttempts int `json:"maxAttempts" db:"max_attempts"`
+
+	LeaseOwner      *string    `json:"leaseOwner,omitempty" db:"lease_owner"`
+	LeaseExpiresAt  *time.Time `json:"leaseExpiresAt,omitempty" db:"lease_expires_at"`
+	HeartbeatAt     *time.Time `json:"heartbeatAt,omitempty" db:"heartbeat_at"`
+	CancelRequested bool       `json:"cancelRequested" db:"cancel_requested"`
+	LastError       *string    `json:"lastError,omitempty" db:"last_error"`
+	// TraceParent is the trace context of the request that enqueued the job.
+	TraceParent *string `json:"traceParent,omitempty" db:"trace_parent"`
+
+	AvailableAt time.Time `json:"availableAt" db:"available_at"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NovelSource links an imported novel to the page it was imported from,
+// so new chapters published at the source can be synced later.
+type NovelSource struct {
+	NovelID    uuid.UUID `json:"novelId" db:"novel_id"`
+	ProposalID uuid.UUID `json:"proposalId" db:"proposal_id"`
+	Importer   string    `json:"importer" db:"importer"`
+	SourceURL  string    `json:"sourceUrl" db:"source_url"`
+
+	SyncEnabled         bool      `json:"syncEnabled" db:"sync_enabled"`
+	SyncIntervalMinutes int       `json:"syncIntervalMinutes" db:"sync_interval_minutes"`
+	NextSyncAt          time.Time `json:"nextSyncAt" db:"next_sync_at"`
+
+	LastSyncedAt        *time.Time       `json:"lastSyncedAt,omitempty" db:"last_synced_at"`
+	LastSyncStatus      *ImportRunStatus `json:"lastSyncStatus,omitempty" db:"last_sync_status"`
+	LastSyncError       *string          `json:"lastSyncError,omitempty" db:"last_sync_error"`
+	LastSyncNewChapters int              `json:"lastSyncNewChapters" db:"last_sync_new_chapters"`
+	LastSyncRunID       *uuid.UUID       `json:"lastSyncRunId,omitempty" db:"last_sync_run_id"`
+
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// NovelSourceListItem is a NovelSource with the novel slug for ops listings.
+type NovelSourceListItem struct {
+	NovelSource
+	Slug string `json:"slug" db:"slug"`
+}
+
+type UpdateNovelSourceRequest struct {
+	SyncEnabled         *bool `json:"syncEnabled,omitempty"`
+	SyncIntervalMinutes *int  `json:"syncIntervalMinutes,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxEventStatus string
+
+const (
+	OutboxEventStatusPending   OutboxEventStatus = "pending"
+	OutboxEventStatusRunning   OutboxEventStatus = "running"
+	OutboxEventStatusDelivered OutboxEventStatus = "delivered"
+	// OutboxEventStatusDead: a handler kept failing until max_attempts; the event waits for an operator.
+	OutboxEventStatusDead OutboxEventStatus = "dead"
+)
+
+// OutboxEvent is a domain event stored in event_outbox until every subscriber has handled it.
+type OutboxEvent struct {
+	ID          uuid.UUID         `json:"id" db:"id"`
+	EventName   string            `json:"eventName" db:"event_name"`
+	Payload     json.RawMessage   `json:"payload" db:"payload"`
+	Status      OutboxEventStatus `json:"status" db:"status"`
+	Attempts    int               `json:"attempts" db:"attempts"`
+	MaxAttempts int               `json:"maxAttempts" db:"max_attempts"`
+	Error       *string           `json:"error,omitempty" db:"error"`
+
+	LockedBy    *string    `json:"lockedBy,omitempty" db:"locked_by"`
+	LockedUntil *time.Time `json:"lockedUntil,omitempty" db:"locked_until"`
+	AvailableAt time.Time  `json:"availableAt" db:"available_at"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty" db:"delivered_at"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// OutboxStats summarizes the outbox for the ops API.
+type OutboxStats struct {
+	Pending   int `json:"pending" db:"pending"`
+	Running   int `json:"running" db:"running"`
+	Delivered int `json:"delivered" db:"delivered"`
+	Dead      int `json:"dead" db:"dead"`
+	// OldestPendingAt is when the oldest undelivered event was written; LagSeconds is its age.
+	OldestPendingAt *time.Time `json:"oldestPendingAt,omitempty" db:"oldest_pending_at"`
+	LagSeconds      float64    `json:"lagSeconds" db:"lag_seconds"`
+}
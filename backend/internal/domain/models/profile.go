@@ -0,0 +1,153 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfileSection is a part of the public profile the user can hide from others.
+type ProfileSection string
+
+const (
+	ProfileSectionAchievements ProfileSection = "achievements"
+	ProfileSectionCollections  ProfileSection = "collections"
+	ProfileSectionBookmarks    ProfileSection = "bookmarks"
+	ProfileSectionComments     ProfileSection = "comments"
+	ProfileSectionActivity     ProfileSection = "activity"
+	ProfileSectionStats        ProfileSection = "stats"
+)
+
+// ProfilePrivacy is which sections of the profile other users see. Stats is the reading
+// statistics toggle (see ReadingStats).
+type ProfilePrivacy struct {
+	Achievements bool `json:"achievements" db:"achievements_public"`
+	Collections  bool `json:"collections" db:"collections_public"`
+	Bookmarks    bool `json:"bookmarks" db:"bookmarks_public"`
+	Comments     bool `json:"comments" db:"comments_public"`
+	Activity     bool `json:"activity" db:"activity_public"`
+	Stats        bool `json:"stats" db:"stats_public"`
+}
+
+// Public reports whether the section is shown to other users.
+func (p ProfilePrivacy) Public(section ProfileSection) bool {
+	switch section {
+	case ProfileSectionAchievements:
+		return p.Achievements
+	case ProfileSectionCollections:
+		return p.Collections
+	case ProfileSectionBookmarks:
+		return p.Bookmarks
+	case ProfileSectionComments:
+		return p.Comments
+	case ProfileSectionActivity:
+		return p.Activity
+	case ProfileSectionStats:
+		return p.Stats
+	}
+	return false
+}
+
+// ProfileOwner is the stored profile of a user with the XP level, as the public profile is
+// built from.
+type ProfileOwner struct {
+	UserID      uuid.UUID `db:"user_id"`
+	DisplayName string    `db:"display_name"`
+	AvatarKey   *string   `db:"avatar_key"`
+	Bio         *string   `db:"bio"`
+	IsBanned    bool      `db:"is_banned"`
+	Level       int       `db:"level"`
+	XPTotal     int64     `db:"xp_total"`
+	JoinedAt    time.Time `db:"created_at"`
+	ProfilePrivacy
+}
+
+// AchievementBadge is an achievement unlocked by the user.
+type AchievementBadge struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Code        string    `json:"code" db:"code"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	IconKey     string    `json:"iconKey" db:"icon_key"`
+	UnlockedAt  time.Time `json:"unlockedAt" db:"unlocked_at"`
+}
+
+// ProfileComment is a recent comment of the user with what it was left on.
+type ProfileComment struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TargetType  TargetType `json:"targetType" db:"target_type"`
+	TargetID    uuid.UUID  `json:"targetId" db:"target_id"`
+	TargetTitle *string    `json:"targetTitle,omitempty" db:"target_title"`
+	// TargetSlug is the novel slug for novel and chapter comments, the news slug for news.
+	TargetSlug *string   `json:"targetSlug,omitempty" db:"target_slug"`
+	Body       string    `json:"body" db:"body"`
+	IsSpoiler  bool      `json:"isSpoiler" db:"is_spoiler"`
+	LikesCount int       `json:"likesCount" db:"likes_count"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ProfileActivityType is the kind of a profile activity feed item.
+type ProfileActivityType string
+
+const (
+	ProfileActivityCollectionCreated   ProfileActivityType = "collection_created"
+	ProfileActivityProposalSubmitted   ProfileActivityType = "proposal_submitted"
+	ProfileActivityAchievementUnlocked ProfileActivityType = "achievement_unlocked"
+	ProfileActivityWikiEditApproved    ProfileActivityType = "wiki_edit_approved"
+)
+
+// ProfileActivity is an item of the profile activity feed. RefID is the collection, proposal,
+// achievement or edit request; Slug is the novel slug of an approved wiki edit.
+type ProfileActivity struct {
+	Type      ProfileActivityType `json:"type" db:"type"`
+	RefID     uuid.UUID           `json:"refId" db:"ref_id"`
+	Title     string              `json:"title" db:"title"`
+	Slug      *string             `json:"slug,omitempty" db:"slug"`
+	IconKey   *string             `json:"iconKey,omitempty" db:"icon_key"`
+	CreatedAt time.Time           `json:"createdAt" db:"created_at"`
+}
+
+// ProfileActivityPage is a page of the activity feed, newest first.
+type ProfileActivityPage struct {
+	Items   []ProfileActivity `json:"items"`
+	Page    int               `json:"page"`
+	Limit   int               `json:"limit"`
+	HasMore bool              `json:"hasMore"`
+}
+
+// PublicProfile is the profile of a user as seen by the viewer. Sections the user hid from the
+// viewer are listed in Hidden and left empty; Privacy is returned to the owner only.
+type PublicProfile struct {
+	ID             uuid.UUID           `json:"id"`
+	DisplayName    string              `json:"displayName"`
+	AvatarURL      *string             `json:"avatarUrl,omitempty"`
+	Bio            *string             `json:"bio,omitempty"`
+	Level          int                 `json:"level"`
+	XPTotal        int64               `json:"xpTotal"`
+	JoinedAt       time.Time           `json:"joinedAt"`
+	IsOwner        bool                `json:"isOwner"`
+	Privacy        *ProfilePrivacy     `json:"privacy,omitempty"`
+	Hidden         []ProfileSection    `json:"hidden"`
+	Achievements   []AchievementBadge  `json:"achievements"`
+	Collections    []CollectionCard    `json:"collections"`
+	BookmarkStats  []BookmarkListStats `json:"bookmarkStats"`
+	RecentComments []ProfileComment    `json:"recentComments"`
+	Activity       []ProfileActivity   `json:"activity"`
+}
+
+// UpdateProfileRequest edits the profile; nil fields are left unchanged, an empty bio clears it.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"displayName,omitempty"`
+	Bio         *string `json:"bio,omitempty"`
+}
+
+// UpdateProfilePrivacyRequest changes the visibility of profile sections; nil fields are left
+// unchanged.
+type UpdateProfilePrivacyRequest struct {
+	Achievements *bool `json:"achievements,omitempty"`
+	Collections  *bool `json:"collections,omitempty"`
+	Bookmarks    *bool `json:"bookmarks,omitempty"`
+	Comments     *bool `json:"comments,omitempty"`
+	Activity     *bool `json:"activity,omitempty"`
+	Stats        *bool `json:"stats,omitempty"`
+}
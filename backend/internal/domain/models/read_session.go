@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadSession is a continuous read of a chapter by a user, fed by progress saves. It decides
+// when the read counts for read_chapter XP.
+type ReadSession struct {
+	UserID        uuid.UUID  `json:"userId" db:"user_id"`
+	ChapterID     uuid.UUID  `json:"chapterId" db:"chapter_id"`
+	NovelID       uuid.UUID  `json:"novelId" db:"novel_id"`
+	Words         int        `json:"words" db:"words"`
+	StartedAt     time.Time  `json:"startedAt" db:"started_at"`
+	LastSeenAt    time.Time  `json:"lastSeenAt" db:"last_seen_at"`
+	StartPosition int        `json:"startPosition" db:"start_position"`
+	MaxPosition   int        `json:"maxPosition" db:"max_position"`
+	LastPosition  int        `json:"lastPosition" db:"last_position"`
+	Updates       int        `json:"updates" db:"updates"`
+	Regressions   int        `json:"regressions" db:"regressions"`
+	CappedAt      *time.Time `json:"cappedAt,omitempty" db:"capped_at"`
+	AwardedAt     *time.Time `json:"awardedAt,omitempty" db:"awarded_at"`
+}
+
+// Dwell is how long the chapter has been open in this session.
+func (s *ReadSession) Dwell() time.Duration {
+	return s.LastSeenAt.Sub(s.StartedAt)
+}
+
+// Progressed reports whether the position moved forward during the session.
+func (s *ReadSession) Progressed() bool {
+	return s.MaxPosition > s.StartPosition
+}
+
+// ReadActivityReport sums up the chapter reads of a user over a window for moderators.
+// Score is 0-100; Reasons explain what raised it.
+type ReadActivityReport struct {
+	WindowHours int `json:"windowHours"`
+	Sessions    int `json:"sessions" db:"sessions"`
+	Awarded     int `json:"awarded" db:"awarded"`
+	// Skimmed are finished reads that were too short for the chapter length.
+	Skimmed     int `json:"skimmed" db:"skimmed"`
+	Regressions int `json:"regressions" db:"regressions"`
+	// Capped are reads that hit a daily cap.
+	Capped int `json:"capped" db:"capped"`
+	// PeakPerHour is the most chapters opened within one hour.
+	PeakPerHour int      `json:"peakPerHour" db:"peak_per_hour"`
+	Score       int      `json:"score"`
+	Reasons     []string `json:"reasons"`
+}
+
+// AdminUserView is a user as shown in the admin panel.
+type AdminUserView struct {
+	UserWithProfile
+	ReadActivity *ReadActivityReport `json:"readActivity,omitempty"`
+}
+
+// ClawbackXPRequest revokes XP events of a user.
+type ClawbackXPRequest struct {
+	EventIDs []uuid.UUID `json:"eventIds"`
+	Reason   string      `json:"reason"`
+}
+
+// ClawbackXPResult is the outcome of a clawback.
+type ClawbackXPResult struct {
+	Revoked  int   `json:"revoked"`
+	XPTaken  int64 `json:"xpTaken"`
+	XPTotal  int64 `json:"xpTotal"`
+	NewLevel int   `json:"level"`
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingStatsPeriod is the bucket of ReadingStats.Periods.
+type ReadingStatsPeriod string
+
+const (
+	ReadingStatsWeek  ReadingStatsPeriod = "week"
+	ReadingStatsMonth ReadingStatsPeriod = "month"
+)
+
+// ReadingTotals sums up reading over a range of days.
+type ReadingTotals struct {
+	Seconds    int64 `json:"seconds" db:"seconds"`
+	Chapters   int   `json:"chapters" db:"chapters"`
+	Words      int64 `json:"words" db:"words"`
+	DaysRead   int   `json:"daysRead" db:"days_read"`
+	NovelsRead int   `json:"novelsRead" db:"novels_read"`
+}
+
+// ReadingPeriodStats is the reading of one week or month; Start is its first day.
+type ReadingPeriodStats struct {
+	Start    string `json:"start" db:"start"`
+	Seconds  int64  `json:"seconds" db:"seconds"`
+	Chapters int    `json:"chapters" db:"chapters"`
+	Words    int64  `json:"words" db:"words"`
+}
+
+// ReadingCategoryStats is the reading of novels with a genre or tag.
+type ReadingCategoryStats struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	Slug     string    `json:"slug" db:"slug"`
+	Name     string    `json:"name" db:"name"`
+	Seconds  int64     `json:"seconds" db:"seconds"`
+	Chapters int       `json:"chapters" db:"chapters"`
+}
+
+// NovelReadingStats is the reading of one novel.
+type NovelReadingStats struct {
+	NovelID  uuid.UUID `json:"novelId" db:"novel_id"`
+	Slug     string    `json:"slug" db:"slug"`
+	Title    string    `json:"title" db:"title"`
+	CoverURL *string   `json:"coverUrl,omitempty" db:"cover_url"`
+	Seconds  int64     `json:"seconds" db:"seconds"`
+	Chapters int       `json:"chapters" db:"chapters"`
+}
+
+// ReadingBinge is the longest uninterrupted reading.
+type ReadingBinge struct {
+	StartedAt time.Time `json:"startedAt" db:"started_at"`
+	Seconds   int64     `json:"seconds" db:"seconds"`
+	Chapters  int       `json:"chapters" db:"chapters"`
+}
+
+// BookmarkCompletion is how many started novels (reading, completed or dropped) were completed.
+type BookmarkCompletion struct {
+	Total          int     `json:"total" db:"total"`
+	Started        int     `json:"started" db:"started"`
+	Completed      int     `json:"completed" db:"completed"`
+	Dropped        int     `json:"dropped" db:"dropped"`
+	CompletionRate float64 `json:"completionRate"`
+}
+
+// ReadingStats is the personal reading statistics of a user.
+type ReadingStats struct {
+	UserID         uuid.UUID              `json:"userId"`
+	Public         bool                   `json:"public"`
+	Totals         ReadingTotals          `json:"totals"`
+	Period         ReadingStatsPeriod     `json:"period"`
+	Periods        []ReadingPeriodStats   `json:"periods"`
+	FavoriteGenres []ReadingCategoryStats `json:"favoriteGenres"`
+	FavoriteTags   []ReadingCategoryStats `json:"favoriteTags"`
+	Bookmarks      BookmarkCompletion     `json:"bookmarks"`
+	LongestBinge   *ReadingBinge          `json:"longestBinge,omitempty"`
+}
+
+// ReadingMonth is the reading of a calendar month, "2006-01".
+type ReadingMonth struct {
+	Month    string `json:"month" db:"month"`
+	Seconds  int64  `json:"seconds" db:"seconds"`
+	Chapters int    `json:"chapters" db:"chapters"`
+}
+
+// YearInReview is the annual summary of a user's reading, generated by the year_review job.
+// Names of novels, genres and tags are filled in the requested language when it is served.
+type YearInReview struct {
+	UserID          uuid.UUID              `json:"userId"`
+	Year            int                    `json:"year"`
+	Totals          ReadingTotals          `json:"totals"`
+	TopNovels       []NovelReadingStats    `json:"topNovels"`
+	TopGenres       []ReadingCategoryStats `json:"topGenres"`
+	TopTags         []ReadingCategoryStats `json:"topTags"`
+	BusiestMonth    *ReadingMonth          `json:"busiestMonth,omitempty"`
+	LongestBinge    *ReadingBinge          `json:"longestBinge,omitempty"`
+	LongestStreak   int                    `json:"longestStreak"`
+	NovelsCompleted int                    `json:"novelsCompleted"`
+	GeneratedAt     time.Time              `json:"generatedAt"`
+}
+
+// UpdateStatsPrivacyRequest makes the reading statistics public or private.
+type UpdateStatsPrivacyRequest struct {
+	Public bool `json:"public"`
+}
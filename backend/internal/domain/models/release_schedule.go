@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ReleaseSchedule is the release cadence of a novel: new chapters wait in a queue and are
+// released ChaptersPerRelease at a time at each of ReleaseTimes (local to Timezone).
+type ReleaseSchedule struct {
+	NovelID            uuid.UUID      `json:"novelId" db:"novel_id"`
+	Enabled            bool           `json:"enabled" db:"enabled"`
+	ChaptersPerRelease int            `json:"chaptersPerRelease" db:"chapters_per_release"`
+	ReleaseTimes       pq.StringArray `json:"releaseTimes" db:"release_times"` // "HH:MM"
+	Weekdays           pq.Int64Array  `json:"weekdays" db:"weekdays"`          // 0 = Sunday; empty = every day
+	Timezone           string         `json:"timezone" db:"timezone"`
+	LastReleaseAt      *time.Time     `json:"lastReleaseAt,omitempty" db:"last_release_at"`
+	UpdatedBy          *uuid.UUID     `json:"updatedBy,omitempty" db:"updated_by"`
+	CreatedAt          time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updatedAt" db:"updated_at"`
+}
+
+// ReleaseScheduleInput creates or replaces a release schedule.
+type ReleaseScheduleInput struct {
+	Enabled            *bool    `json:"enabled,omitempty"`
+	ChaptersPerRelease int      `json:"chaptersPerRelease"`
+	ReleaseTimes       []string `json:"releaseTimes"`
+	Weekdays           []int    `json:"weekdays,omitempty"`
+	Timezone           string   `json:"timezone,omitempty"`
+}
+
+// ReleaseQueueItem is a chapter that is queued or scheduled but not live yet.
+type ReleaseQueueItem struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Number      float64    `json:"number" db:"number"`
+	Title       *string    `json:"title,omitempty" db:"title"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty" db:"published_at"` // nil = waiting for a slot
+}
+
+// ReleaseScheduleView is a schedule with its upcoming releases for the admin UI.
+type ReleaseScheduleView struct {
+	Schedule *ReleaseSchedule   `json:"schedule"`
+	Queued   int                `json:"queued"`
+	Upcoming []ReleaseQueueItem `json:"upcoming"`
+	// NextSlots are the next release times; queued chapters will take them in number order.
+	NextSlots []time.Time `json:"nextSlots"`
+}
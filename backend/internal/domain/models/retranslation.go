@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RetranslationRequestStatus string
+
+const (
+	RetranslationStatusPending   RetranslationRequestStatus = "pending"
+	RetranslationStatusAccepted  RetranslationRequestStatus = "accepted"
+	RetranslationStatusRejected  RetranslationRequestStatus = "rejected"
+	RetranslationStatusCompleted RetranslationRequestStatus = "completed"
+	RetranslationStatusFailed    RetranslationRequestStatus = "failed"
+)
+
+// RetranslationRequest is a user's report that a chapter translation (or a paragraph range of it)
+// should be machine-translated again.
+type RetranslationRequest struct {
+	ID            uuid.UUID                  `json:"id" db:"id"`
+	UserID        uuid.UUID                  `json:"userId" db:"user_id"`
+	ChapterID     uuid.UUID                  `json:"chapterId" db:"chapter_id"`
+	NovelID       uuid.UUID                  `json:"novelId" db:"novel_id"`
+	Lang          string                     `json:"lang" db:"lang"`
+	ParagraphFrom *int                       `json:"paragraphFrom,omitempty" db:"paragraph_from"`
+	ParagraphTo   *int                       `json:"paragraphTo,omitempty" db:"paragraph_to"`
+	Reason        string                     `json:"reason" db:"reason"`
+	Status        RetranslationRequestStatus `json:"status" db:"status"`
+
+	ModeratorID      *uuid.UUID `json:"moderatorId,omitempty" db:"moderator_id"`
+	ModeratorComment *string    `json:"moderatorComment,omitempty" db:"moderator_comment"`
+	ReviewedAt       *time.Time `json:"reviewedAt,omitempty" db:"reviewed_at"`
+
+	TranslationJobID *uuid.UUID `json:"translationJobId,omitempty" db:"translation_job_id"`
+	PreviousContent  *string    `json:"previousContent,omitempty" db:"previous_content"`
+	PreviousSource   *string    `json:"previousSource,omitempty" db:"previous_source"`
+	Error            *string    `json:"error,omitempty" db:"error"`
+	CompletedAt      *time.Time `json:"completedAt,omitempty" db:"completed_at"`
+	CreatedAt        time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updatedAt" db:"updated_at"`
+
+	// Populated in moderation lists
+	ChapterNumber *float64 `json:"chapterNumber,omitempty" db:"chapter_number"`
+	NovelSlug     *string  `json:"novelSlug,omitempty" db:"novel_slug"`
+}
+
+// CreateRetranslationRequest is the body of POST /chapters/{id}/retranslation-requests
+type CreateRetranslationRequest struct {
+	Lang          string `json:"lang" validate:"required,min=2,max=10"`
+	ParagraphFrom *int   `json:"paragraphFrom"`
+	ParagraphTo   *int   `json:"paragraphTo"`
+	Reason        string `json:"reason" validate:"required,max=1000"`
+}
+
+// ReviewRetranslationRequest is the body of moderation accept/reject calls
+type ReviewRetranslationRequest struct {
+	Comment string `json:"comment" validate:"max=500"`
+}
+
+// RetranslationQuotaTransaction is a ledger entry of the retranslation allowance.
+type RetranslationQuotaTransaction struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"userId" db:"user_id"`
+	SubscriptionID *uuid.UUID `json:"subscriptionId,omitempty" db:"subscription_id"`
+	PeriodStart    time.Time  `json:"periodStart" db:"period_start"`
+	Delta          int        `json:"delta" db:"delta"`
+	Reason         string     `json:"reason" db:"reason"`
+	RefType        *string    `json:"refType,omitempty" db:"ref_type"`
+	RefID          *uuid.UUID `json:"refId,omitempty" db:"ref_id"`
+	IdempotencyKey *string    `json:"idempotencyKey,omitempty" db:"idempotency_key"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// RetranslationQuota is the allowance of the current subscription period.
+type RetranslationQuota struct {
+	Limit       int       `json:"limit"`
+	Used        int       `json:"used"`
+	Remaining   int       `json:"remaining"`
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+}
+
+// RetranslationRequestListResponse paginated response
+type RetranslationRequestListResponse struct {
+	Requests   []RetranslationRequest `json:"requests"`
+	Total      int                    `json:"total"`
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalPages int                    `json:"totalPages"`
+}
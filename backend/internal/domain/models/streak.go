@@ -0,0 +1,173 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ActivityKind is what a user did on an activity day.
+type ActivityKind string
+
+const (
+	ActivityLogin ActivityKind = "login"
+	ActivityRead  ActivityKind = "read"
+)
+
+// DateLayout is the format of streak days in the API.
+const DateLayout = "2006-01-02"
+
+// UserStreak is the run of consecutive days (local to the user's timezone) with activity.
+// Days are stored as midnight UTC of the local date.
+type UserStreak struct {
+	UserID       uuid.UUID  `db:"user_id"`
+	Current      int        `db:"current_streak"`
+	Longest      int        `db:"longest_streak"`
+	StartedOn    *time.Time `db:"started_on"`
+	LastActiveOn *time.Time `db:"last_active_on"`
+	Freezes      int        `db:"freezes"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+// ActivityState is what is needed to tell whether an activity was already recorded today.
+type ActivityState struct {
+	Timezone     string         `db:"timezone"`
+	LastActiveOn *time.Time     `db:"last_active_on"`
+	Kinds        pq.StringArray `db:"kinds"` // kinds recorded on LastActiveOn
+}
+
+// Recorded reports whether kind was recorded on day.
+func (s *ActivityState) Recorded(day time.Time, kind ActivityKind) bool {
+	if s.LastActiveOn == nil || !s.LastActiveOn.Equal(day) {
+		return false
+	}
+	for _, k := range s.Kinds {
+		if ActivityKind(k) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// StreakMilestone is the bonus for reaching a streak length.
+type StreakMilestone struct {
+	Days       int   `json:"days"`
+	XP         int64 `json:"xp"`
+	DailyVotes int   `json:"dailyVotes"`
+}
+
+// StreakMilestones are the milestone bonuses, shortest first. Each is granted once per streak.
+var StreakMilestones = []StreakMilestone{
+	{Days: 3, XP: 30, DailyVotes: 1},
+	{Days: 7, XP: 70, DailyVotes: 2},
+	{Days: 14, XP: 150, DailyVotes: 3},
+	{Days: 30, XP: 300, DailyVotes: 5},
+	{Days: 60, XP: 600, DailyVotes: 7},
+	{Days: 100, XP: 1000, DailyVotes: 10},
+	{Days: 365, XP: 3650, DailyVotes: 20},
+}
+
+// StreakMilestoneFor returns the milestone reached at a streak of days, if any.
+func StreakMilestoneFor(days int) *StreakMilestone {
+	for i := range StreakMilestones {
+		if StreakMilestones[i].Days == days {
+			return &StreakMilestones[i]
+		}
+	}
+	return nil
+}
+
+// NextStreakMilestone returns the first milestone longer than days, or nil after the last one.
+func NextStreakMilestone(days int) *StreakMilestone {
+	for i := range StreakMilestones {
+		if StreakMilestones[i].Days > days {
+			return &StreakMilestones[i]
+		}
+	}
+	return nil
+}
+
+// StreakAdvance is the result of recording a day of activity.
+type StreakAdvance struct {
+	// NewDay is false if the day was already recorded (or is before the last recorded day).
+	NewDay bool
+	// Frozen are the missed days covered by freezes.
+	Frozen    []time.Time
+	Milestone *StreakMilestone
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// Advance records activity on day. Missed days since the last active day are covered by
+// freezes if there are enough of them; otherwise the streak starts over and the freezes are
+// kept. Days before the last active day (after a timezone change) do not count.
+func (s *UserStreak) Advance(day time.Time) StreakAdvance {
+	if s.LastActiveOn != nil && !day.After(*s.LastActiveOn) {
+		return StreakAdvance{}
+	}
+
+	adv := StreakAdvance{NewDay: true}
+	missed := 0
+	if s.LastActiveOn != nil {
+		missed = daysBetween(*s.LastActiveOn, day) - 1
+	}
+	if s.LastActiveOn == nil || s.Current == 0 || missed > s.Freezes {
+		s.Current = 1
+		started := day
+		s.StartedOn = &started
+	} else {
+		for i := 1; i <= missed; i++ {
+			adv.Frozen = append(adv.Frozen, s.LastActiveOn.AddDate(0, 0, i))
+		}
+		s.Freezes -= missed
+		s.Current++
+	}
+	s.Longest = max(s.Longest, s.Current)
+	last := day
+	s.LastActiveOn = &last
+	adv.Milestone = StreakMilestoneFor(s.Current)
+	return adv
+}
+
+// CurrentOn returns the streak as it stands on day: 0 if it is already broken, and how many
+// freezes the next activity will use up.
+func (s *UserStreak) CurrentOn(day time.Time) (current, freezesNeeded int) {
+	if s.LastActiveOn == nil {
+		return 0, 0
+	}
+	missed := daysBetween(*s.LastActiveOn, day) - 1
+	switch {
+	case missed <= 0:
+		return s.Current, 0
+	case missed <= s.Freezes:
+		return s.Current, missed
+	default:
+		return 0, 0
+	}
+}
+
+// StreakInfo is the streak of the current user.
+type StreakInfo struct {
+	Current      int     `json:"current"`
+	Longest      int     `json:"longest"`
+	StartedOn    *string `json:"startedOn,omitempty"`
+	LastActiveOn *string `json:"lastActiveOn,omitempty"`
+	ActiveToday  bool    `json:"activeToday"`
+	Today        string  `json:"today"`
+	Timezone     string  `json:"timezone"`
+	// FreezesNeeded is how many freezes the next activity uses up to keep the streak.
+	FreezesNeeded int              `json:"freezesNeeded"`
+	Freezes       int              `json:"freezes"`
+	MaxFreezes    int              `json:"maxFreezes"`
+	FreezePrice   int              `json:"freezePrice"`
+	FreezeTicket  TicketType       `json:"freezeTicketType"`
+	NextMilestone *StreakMilestone `json:"nextMilestone,omitempty"`
+}
+
+// UpdateTimezoneRequest sets the timezone streak days are counted in.
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone"`
+}
@@ -56,6 +56,9 @@ type PlanFeatures struct {
 	AdFree                   bool `json:"adFree"`                   // no ads
 	CanEditDescriptions      bool `json:"canEditDescriptions"`      // can edit novel descriptions
 	CanRequestRetranslation  bool `json:"canRequestRetranslation"`  // can request chapter retranslation
+	RetranslationRequests    int  `json:"retranslationRequests"`    // retranslation requests per subscription period (0 = default)
+	CanExportFull            bool `json:"canExportFull"`            // can export large chapter ranges to EPUB
+	EarlyAccessHours         int  `json:"earlyAccessHours"`         // scheduled chapters open this many hours early
 	PrioritySupport          bool `json:"prioritySupport"`          // priority support
 	ExclusiveBadge           bool `json:"exclusiveBadge"`           // exclusive profile badge
 }
@@ -45,6 +45,7 @@ const (
 	ReasonSubscriptionGrant  = "subscription_grant"
 	ReasonAdminAdjustment    = "admin_adjustment"
 	ReasonLevelReward        = "level_reward"
+	ReasonStreakFreeze       = "streak_freeze"
 )
 
 // WalletInfo represents user's wallet with all ticket balances
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TranslationJobStatus string
+
+const (
+	TranslationJobStatusQueued  TranslationJobStatus = "queued"
+	TranslationJobStatusRunning TranslationJobStatus = "running"
+	TranslationJobStatusDone    TranslationJobStatus = "done"
+	TranslationJobStatusFailed  TranslationJobStatus = "failed"
+	// TranslationJobStatusSkipped: nothing to translate, or a manual translation already exists.
+	TranslationJobStatusSkipped TranslationJobStatus = "skipped"
+)
+
+// TranslationJob is a queued machine translation of one chapter into one language.
+type TranslationJob struct {
+	ID         uuid.UUID            `json:"id" db:"id"`
+	ChapterID  uuid.UUID            `json:"chapterId" db:"chapter_id"`
+	NovelID    uuid.UUID            `json:"novelId" db:"novel_id"`
+	SourceLang string               `json:"sourceLang" db:"source_lang"`
+	TargetLang string               `json:"targetLang" db:"target_lang"`
+	Status     TranslationJobStatus `json:"status" db:"status"`
+	Provider   *string              `json:"provider,omitempty" db:"provider"`
+
+	// Retranslation of a paragraph range (1-based, inclusive); Overwrite allows replacing manual text.
+	ParagraphFrom *int `json:"paragraphFrom,omitempty" db:"paragraph_from"`
+	ParagraphTo   *int `json:"paragraphTo,omitempty" db:"paragraph_to"`
+	Overwrite     bool `json:"overwrite" db:"overwrite"`
+
+	Attempts    int     `json:"attempts" db:"attempts"`
+	MaxAttempts int     `json:"maxAttempts" db:"max_attempts"`
+	Error       *string `json:"error,omitempty" db:"error"`
+
+	LockedBy    *string    `json:"lockedBy,omitempty" db:"locked_by"`
+	LockedUntil *time.Time `json:"lockedUntil,omitempty" db:"locked_until"`
+	AvailableAt time.Time  `json:"availableAt" db:"available_at"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty" db:"finished_at"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// TranslationJobStats is a per-novel/per-language summary of the translation queue.
+type TranslationJobStats struct {
+	NovelID    uuid.UUID `json:"novelId" db:"novel_id"`
+	TargetLang string    `json:"targetLang" db:"target_lang"`
+	Queued     int       `json:"queued" db:"queued"`
+	Running    int       `json:"running" db:"running"`
+	Done       int       `json:"done" db:"done"`
+	Failed     int       `json:"failed" db:"failed"`
+	Skipped    int       `json:"skipped" db:"skipped"`
+}
@@ -101,6 +101,55 @@ type CreateProposalRequest struct {
 	CoverURL     *string  `json:"coverUrl,omitempty" validate:"omitempty,url"`
 	Genres       []string `json:"genres" validate:"required,min=1"`
 	Tags         []string `json:"tags,omitempty"`
+	// OnDuplicate is what to do if the book is already proposed: "reject" (default) or "merge"
+	// into the existing proposal instead of creating a new one.
+	OnDuplicate string `json:"onDuplicate,omitempty" validate:"omitempty,oneof=reject merge"`
+	// IgnoreSimilarTitles creates the proposal even if proposals or novels with similar titles exist.
+	IgnoreSimilarTitles bool `json:"ignoreSimilarTitles,omitempty"`
+}
+
+// Duplicate handling modes of CreateProposalRequest.OnDuplicate
+const (
+	OnDuplicateReject = "reject"
+	OnDuplicateMerge  = "merge"
+)
+
+// DuplicateMatch points to an existing proposal or imported novel of the same book
+type DuplicateMatch struct {
+	// Kind is "proposal" or "novel"
+	Kind string `json:"kind" db:"kind"`
+	// MatchedBy is "url" for the same canonical source URL, "title" for a similar title
+	MatchedBy  string    `json:"matchedBy" db:"matched_by"`
+	ID         uuid.UUID `json:"id" db:"id"`
+	Slug       *string   `json:"slug,omitempty" db:"slug"`
+	Title      string    `json:"title" db:"title"`
+	Status     string    `json:"status,omitempty" db:"status"`
+	Similarity float64   `json:"similarity" db:"similarity"`
+}
+
+// DuplicateMatch kinds and match types
+const (
+	DuplicateKindProposal = "proposal"
+	DuplicateKindNovel    = "novel"
+
+	DuplicateMatchedByURL   = "url"
+	DuplicateMatchedByTitle = "title"
+)
+
+// PreviewProposalRequest represents a request to prefill a proposal from its original link
+type PreviewProposalRequest struct {
+	OriginalLink string `json:"originalLink" validate:"required,url"`
+}
+
+// ProposalPreview is a proposal draft prefilled from the source site.
+// Genres are left empty: sites use their own categories, the user picks ours.
+type ProposalPreview struct {
+	// Site is the importer that will import the book, e.g. "tadu" or "101kks"
+	Site          string                `json:"site"`
+	ChaptersCount int                   `json:"chaptersCount"`
+	Category      string                `json:"category,omitempty"`
+	Draft         CreateProposalRequest `json:"draft"`
+	FetchedAt     time.Time             `json:"fetchedAt"`
 }
 
 // UpdateProposalRequest represents a request to update a proposal
@@ -30,6 +30,7 @@ const (
 	EditFieldGenres                EditFieldType = "genres"
 	EditFieldTags                  EditFieldType = "tags"
 	EditFieldTranslationStatus     EditFieldType = "translation_status"
+	EditFieldGlossary              EditFieldType = "glossary"
 )
 
 // NovelEditRequest represents a request to edit a novel
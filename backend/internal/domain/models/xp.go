@@ -1,6 +1,10 @@
 package models
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +20,12 @@ const (
 	XPEventVote        XPEventType = "vote"
 	XPEventProposal    XPEventType = "proposal"
 	XPEventBookmark    XPEventType = "bookmark"
+	// XPEventAchievement is the reward of an unlocked achievement (amount from Achievement.XPReward)
+	XPEventAchievement XPEventType = "achievement"
+	// XPEventStreakMilestone is the bonus of a streak milestone (amount from StreakMilestone.XP)
+	XPEventStreakMilestone XPEventType = "streak_milestone"
+	// XPEventClawback takes back the XP of a revoked event (negative delta, ref to the event)
+	XPEventClawback XPEventType = "clawback"
 )
 
 // XP rewards for each event type
@@ -45,6 +55,9 @@ type XPEvent struct {
 	RefType   string      `json:"refType" db:"ref_type"`
 	RefID     uuid.UUID   `json:"refId" db:"ref_id"`
 	CreatedAt time.Time   `json:"createdAt" db:"created_at"`
+	// RevokedAt is set when a moderator took the XP back (see XPEventClawback)
+	RevokedAt    *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+	RevokeReason *string    `json:"revokeReason,omitempty" db:"revoke_reason"`
 }
 
 // LevelInfo provides info about a level
@@ -59,14 +72,77 @@ type LevelInfo struct {
 
 // Achievement represents an achievement/badge
 type Achievement struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Code        string    `json:"code" db:"code"`
-	Title       string    `json:"title" db:"title"`
-	Description string    `json:"description" db:"description"`
-	IconKey     string    `json:"iconKey" db:"icon_key"`
-	Condition   string    `json:"condition" db:"condition"` // JSON condition
-	XPReward    int64     `json:"xpReward" db:"xp_reward"`
-	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	ID          uuid.UUID            `json:"id" db:"id"`
+	Code        string               `json:"code" db:"code"`
+	Title       string               `json:"title" db:"title"`
+	Description string               `json:"description" db:"description"`
+	IconKey     string               `json:"iconKey" db:"icon_key"`
+	Condition   AchievementCondition `json:"condition,omitempty" db:"condition"`
+	XPReward    int64                `json:"xpReward" db:"xp_reward"`
+	CreatedAt   time.Time            `json:"createdAt" db:"created_at"`
+}
+
+// AchievementCondition is the JSON rule of an achievement (see package achievements); stored as JSONB.
+type AchievementCondition json.RawMessage
+
+func (c AchievementCondition) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return nil, nil
+	}
+	return []byte(c), nil
+}
+
+// Scan copies the value: the driver reuses its buffer for the next row.
+func (c *AchievementCondition) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*c = nil
+	case []byte:
+		*c = bytes.Clone(v)
+	case string:
+		*c = AchievementCondition(v)
+	default:
+		return fmt.Errorf("achievement condition: unsupported type %T", src)
+	}
+	return nil
+}
+
+func (c AchievementCondition) MarshalJSON() ([]byte, error) {
+	if len(c) == 0 {
+		return []byte("null"), nil
+	}
+	return []byte(c), nil
+}
+
+func (c *AchievementCondition) UnmarshalJSON(data []byte) error {
+	*c = bytes.Clone(data)
+	return nil
+}
+
+// AchievementStatus is an achievement with a user's progress towards it
+type AchievementStatus struct {
+	Achievement
+	UnlockedAt *time.Time `json:"unlockedAt,omitempty" db:"unlocked_at"`
+	Current    int64      `json:"current" db:"current"`
+	Target     int64      `json:"target" db:"target"`
+}
+
+// AchievementProgress is the stored progress of a user towards a locked achievement
+type AchievementProgress struct {
+	UserID        uuid.UUID `db:"user_id"`
+	AchievementID uuid.UUID `db:"achievement_id"`
+	Current       int64     `db:"current"`
+	Target        int64     `db:"target"`
+}
+
+// SaveAchievementRequest creates or updates an achievement (admin)
+type SaveAchievementRequest struct {
+	Code        string          `json:"code"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	IconKey     string          `json:"iconKey"`
+	Condition   json.RawMessage `json:"condition"`
+	XPReward    int64           `json:"xpReward"`
 }
 
 // UserAchievement represents an unlocked achievement
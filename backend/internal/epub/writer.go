@@ -0,0 +1,292 @@
+// Package epub writes EPUB 3 books as a stream: chapters go straight to the zip
+// archive and only their titles are kept for the navigation documents.
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Metadata describes the book in the package document.
+type Metadata struct {
+	// Identifier is a stable unique id of the book, e.g. "urn:uuid:<novel id>".
+	Identifier  string
+	Title       string
+	Language    string
+	Description string
+	Authors     []string
+	Subjects    []string
+	Publisher   string
+	Modified    time.Time
+}
+
+type chapterEntry struct {
+	id    string
+	file  string
+	title string
+}
+
+// Writer builds an EPUB. Call SetCover (optional) and AddChapter in reading order, then Close.
+type Writer struct {
+	zw       *zip.Writer
+	meta     Metadata
+	coverExt string
+	coverMT  string
+	chapters []chapterEntry
+}
+
+// NewWriter starts a book on w, writing the mimetype entry and the container document.
+func NewWriter(w io.Writer, meta Metadata) (*Writer, error) {
+	zw := zip.NewWriter(w)
+
+	// The mimetype entry must come first and be stored uncompressed.
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return nil, fmt.Errorf("write mimetype: %w", err)
+	}
+	if _, err := io.WriteString(mw, "application/epub+zip"); err != nil {
+		return nil, fmt.Errorf("write mimetype: %w", err)
+	}
+
+	ew := &Writer{zw: zw, meta: meta}
+	if err := ew.writeFile("META-INF/container.xml", containerXML); err != nil {
+		return nil, err
+	}
+	return ew, nil
+}
+
+// SetCover adds the cover image and a cover page. mediaType must be image/jpeg, image/png, image/gif or image/webp.
+func (w *Writer) SetCover(mediaType string, r io.Reader) error {
+	ext, ok := coverExtensions[mediaType]
+	if !ok {
+		return fmt.Errorf("unsupported cover media type %q", mediaType)
+	}
+	fw, err := w.zw.Create("OEBPS/images/cover" + ext)
+	if err != nil {
+		return fmt.Errorf("write cover: %w", err)
+	}
+	if _, err := io.Copy(fw, r); err != nil {
+		return fmt.Errorf("write cover: %w", err)
+	}
+	w.coverExt, w.coverMT = ext, mediaType
+
+	var b bytes.Buffer
+	w.xhtmlHeader(&b, w.meta.Title, "style.css")
+	fmt.Fprintf(&b, "<section epub:type=\"cover\" class=\"cover\"><img src=\"images/cover%s\" alt=\"%s\"/></section>\n", ext, escape(w.meta.Title))
+	b.WriteString(xhtmlFooter)
+	return w.writeFile("OEBPS/cover.xhtml", b.String())
+}
+
+// AddChapter writes one chapter. text is plain text: paragraphs are separated by blank lines,
+// single line breaks are kept.
+func (w *Writer) AddChapter(title, text string) error {
+	n := len(w.chapters) + 1
+	entry := chapterEntry{
+		id:    fmt.Sprintf("ch%05d", n),
+		file:  fmt.Sprintf("text/ch%05d.xhtml", n),
+		title: title,
+	}
+
+	fw, err := w.zw.Create("OEBPS/" + entry.file)
+	if err != nil {
+		return fmt.Errorf("write chapter %d: %w", n, err)
+	}
+	var b bytes.Buffer
+	w.xhtmlHeader(&b, title, "../style.css")
+	fmt.Fprintf(&b, "<section epub:type=\"chapter\">\n<h2>%s</h2>\n", escape(title))
+	for _, p := range paragraphs(text) {
+		b.WriteString("<p>")
+		for i, line := range strings.Split(p, "\n") {
+			if i > 0 {
+				b.WriteString("<br/>")
+			}
+			b.WriteString(escape(strings.TrimSpace(line)))
+		}
+		b.WriteString("</p>\n")
+	}
+	b.WriteString("</section>\n")
+	b.WriteString(xhtmlFooter)
+	if _, err := fw.Write(b.Bytes()); err != nil {
+		return fmt.Errorf("write chapter %d: %w", n, err)
+	}
+
+	w.chapters = append(w.chapters, entry)
+	return nil
+}
+
+// Close writes the stylesheet, navigation documents and package document and finishes the archive.
+func (w *Writer) Close() error {
+	if err := w.writeFile("OEBPS/style.css", styleCSS); err != nil {
+		return err
+	}
+	if err := w.writeFile("OEBPS/nav.xhtml", w.navXHTML()); err != nil {
+		return err
+	}
+	if err := w.writeFile("OEBPS/toc.ncx", w.tocNCX()); err != nil {
+		return err
+	}
+	if err := w.writeFile("OEBPS/content.opf", w.packageOPF()); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+func (w *Writer) writeFile(name, content string) error {
+	fw, err := w.zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := io.WriteString(fw, content); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (w *Writer) xhtmlHeader(b *bytes.Buffer, title, cssHref string) {
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString("<!DOCTYPE html>\n")
+	fmt.Fprintf(b, "<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" xml:lang=\"%s\" lang=\"%s\">\n", escape(w.meta.Language), escape(w.meta.Language))
+	fmt.Fprintf(b, "<head>\n<meta charset=\"UTF-8\"/>\n<title>%s</title>\n", escape(title))
+	fmt.Fprintf(b, "<link rel=\"stylesheet\" type=\"text/css\" href=\"%s\"/>\n</head>\n<body>\n", cssHref)
+}
+
+func (w *Writer) navXHTML() string {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n<!DOCTYPE html>\n")
+	fmt.Fprintf(&b, "<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" xml:lang=\"%s\" lang=\"%s\">\n", escape(w.meta.Language), escape(w.meta.Language))
+	fmt.Fprintf(&b, "<head>\n<meta charset=\"UTF-8\"/>\n<title>%s</title>\n</head>\n<body>\n", escape(w.meta.Title))
+	fmt.Fprintf(&b, "<nav epub:type=\"toc\" id=\"toc\">\n<h1>%s</h1>\n<ol>\n", escape(w.meta.Title))
+	for _, ch := range w.chapters {
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a></li>\n", ch.file, escape(ch.title))
+	}
+	b.WriteString("</ol>\n</nav>\n")
+	if w.coverExt != "" {
+		b.WriteString("<nav epub:type=\"landmarks\" hidden=\"\">\n<ol>\n")
+		b.WriteString("<li><a epub:type=\"cover\" href=\"cover.xhtml\">Cover</a></li>\n")
+		if len(w.chapters) > 0 {
+			fmt.Fprintf(&b, "<li><a epub:type=\"bodymatter\" href=\"%s\">Start</a></li>\n", w.chapters[0].file)
+		}
+		b.WriteString("</ol>\n</nav>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// tocNCX is the EPUB 2 table of contents, still used by many older reading systems.
+func (w *Writer) tocNCX() string {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">` + "\n")
+	fmt.Fprintf(&b, "<head>\n<meta name=\"dtb:uid\" content=\"%s\"/>\n<meta name=\"dtb:depth\" content=\"1\"/>\n</head>\n", escape(w.meta.Identifier))
+	fmt.Fprintf(&b, "<docTitle><text>%s</text></docTitle>\n<navMap>\n", escape(w.meta.Title))
+	for i, ch := range w.chapters {
+		fmt.Fprintf(&b, "<navPoint id=\"%s\" playOrder=\"%d\"><navLabel><text>%s</text></navLabel><content src=\"%s\"/></navPoint>\n",
+			ch.id, i+1, escape(ch.title), ch.file)
+	}
+	b.WriteString("</navMap>\n</ncx>\n")
+	return b.String()
+}
+
+func (w *Writer) packageOPF() string {
+	m := w.meta
+	modified := m.Modified
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid">` + "\n")
+	b.WriteString(`<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">` + "\n")
+	fmt.Fprintf(&b, "<dc:identifier id=\"bookid\">%s</dc:identifier>\n", escape(m.Identifier))
+	fmt.Fprintf(&b, "<dc:title>%s</dc:title>\n", escape(m.Title))
+	fmt.Fprintf(&b, "<dc:language>%s</dc:language>\n", escape(m.Language))
+	for i, a := range m.Authors {
+		fmt.Fprintf(&b, "<dc:creator id=\"creator%d\">%s</dc:creator>\n", i+1, escape(a))
+	}
+	for _, s := range m.Subjects {
+		fmt.Fprintf(&b, "<dc:subject>%s</dc:subject>\n", escape(s))
+	}
+	if m.Description != "" {
+		fmt.Fprintf(&b, "<dc:description>%s</dc:description>\n", escape(m.Description))
+	}
+	if m.Publisher != "" {
+		fmt.Fprintf(&b, "<dc:publisher>%s</dc:publisher>\n", escape(m.Publisher))
+	}
+	fmt.Fprintf(&b, "<meta property=\"dcterms:modified\">%s</meta>\n", modified.UTC().Format("2006-01-02T15:04:05Z"))
+	if w.coverExt != "" {
+		b.WriteString("<meta name=\"cover\" content=\"cover-image\"/>\n")
+	}
+	b.WriteString("</metadata>\n<manifest>\n")
+	b.WriteString("<item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
+	b.WriteString("<item id=\"ncx\" href=\"toc.ncx\" media-type=\"application/x-dtbncx+xml\"/>\n")
+	b.WriteString("<item id=\"css\" href=\"style.css\" media-type=\"text/css\"/>\n")
+	if w.coverExt != "" {
+		fmt.Fprintf(&b, "<item id=\"cover-image\" href=\"images/cover%s\" media-type=\"%s\" properties=\"cover-image\"/>\n", w.coverExt, w.coverMT)
+		b.WriteString("<item id=\"cover\" href=\"cover.xhtml\" media-type=\"application/xhtml+xml\"/>\n")
+	}
+	for _, ch := range w.chapters {
+		fmt.Fprintf(&b, "<item id=\"%s\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", ch.id, ch.file)
+	}
+	b.WriteString("</manifest>\n<spine toc=\"ncx\">\n")
+	if w.coverExt != "" {
+		b.WriteString("<itemref idref=\"cover\" linear=\"no\"/>\n")
+	}
+	b.WriteString("<itemref idref=\"nav\"/>\n")
+	for _, ch := range w.chapters {
+		fmt.Fprintf(&b, "<itemref idref=\"%s\"/>\n", ch.id)
+	}
+	b.WriteString("</spine>\n</package>\n")
+	return b.String()
+}
+
+var paragraphSplit = regexp.MustCompile(`\n\s*\n`)
+
+// paragraphs splits chapter text the same way the reader UI does (blank line = new paragraph).
+func paragraphs(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	out := []string{}
+	for _, p := range paragraphSplit.Split(text, -1) {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// escape makes s safe for XML text and attribute values; characters invalid in XML are replaced.
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+var coverExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+const xhtmlFooter = "</body>\n</html>\n"
+
+const styleCSS = `body { margin: 0 5%; line-height: 1.5; }
+h2 { text-align: center; margin: 1.5em 0 1em; }
+p { text-indent: 1.5em; margin: 0 0 0.6em; text-align: justify; }
+.cover { text-align: center; margin: 0; padding: 0; }
+.cover img { max-width: 100%; max-height: 100%; }
+`
@@ -2,7 +2,16 @@ package events
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/repository"
+	"novels-backend/internal/telemetry"
 )
 
 // Event is a domain event marker.
@@ -14,32 +23,161 @@ type Event interface {
 type Handler func(context.Context, Event) error
 
 // Bus is an in-process pub/sub event bus.
-// It is intentionally simple: best-effort delivery to handlers in registration order.
+// Sync handlers are called in registration order, async handlers run in their own goroutines;
+// a failing or panicking handler does not keep the others from running (see subscriber.go).
+// Without an outbox delivery is best-effort; with an outbox (see UseOutbox) events are stored and
+// delivered by the Dispatcher at least once, so handlers must tolerate seeing the same event again.
 type Bus struct {
 	mu       sync.RWMutex
-	handlers map[string][]Handler
+	handlers map[string][]subscription
+	outbox   *repository.OutboxRepository
+	wake     chan struct{}
+
+	stats  *busStats
+	async  sync.WaitGroup
+	logger zerolog.Logger
+}
+
+func NewBus(logger zerolog.Logger) *Bus {
+	return &Bus{
+		handlers: map[string][]subscription{},
+		wake:     make(chan struct{}, 1),
+		stats:    newBusStats(),
+		logger:   logger.With().Str("component", "event_bus").Logger(),
+	}
 }
 
-func NewBus() *Bus {
-	return &Bus{handlers: map[string][]Handler{}}
+// UseOutbox makes Publish store events in the outbox instead of calling handlers directly.
+// Must be called before events are published.
+func (b *Bus) UseOutbox(outbox *repository.OutboxRepository) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.outbox = outbox
 }
 
-func (b *Bus) Subscribe(eventName string, h Handler) {
+// Subscribe registers h for eventName. The key identifies the handler in delivery records, logs
+// and stats and must be stable across releases and unique per event: a handler that already
+// processed an event is not called again when the event is retried.
+func (b *Bus) Subscribe(eventName, key string, h Handler, opts ...SubscribeOption) {
+	o := subscribeOptions{attempts: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.handlers[eventName] = append(b.handlers[eventName], h)
+	for _, s := range b.handlers[eventName] {
+		if s.key == key {
+			panic(fmt.Sprintf("events: duplicate handler key %q for %s", key, eventName))
+		}
+	}
+	b.handlers[eventName] = append(b.handlers[eventName], subscription{key: key, h: h, opts: o})
+	b.stats.register(eventName, key, o.async)
 }
 
+// Publish delivers evt to its subscribers, or stores it in the outbox if one is configured.
+// Without an outbox the errors of sync handlers are returned (joined, as *HandlerError);
+// async handlers only log their errors.
 func (b *Bus) Publish(ctx context.Context, evt Event) error {
+	telemetry.EventPublished(evt.Name())
 	b.mu.RLock()
-	hs := append([]Handler(nil), b.handlers[evt.Name()]...)
+	outbox := b.outbox
 	b.mu.RUnlock()
 
-	for _, h := range hs {
-		if err := h(ctx, evt); err != nil {
-			return err
-		}
+	if outbox == nil {
+		return b.deliver(ctx, evt, nil, nil, false)
 	}
+	if err := b.store(ctx, outbox, nil, evt); err != nil {
+		return err
+	}
+	b.notify()
 	return nil
 }
 
+// PublishTx stores evt in the outbox within tx: the event is delivered only if tx commits.
+// Without an outbox (e.g. in tests) the handlers are called right away, as by Publish.
+func (b *Bus) PublishTx(ctx context.Context, tx *sqlx.Tx, evt Event) error {
+	telemetry.EventPublished(evt.Name())
+	b.mu.RLock()
+	outbox := b.outbox
+	b.mu.RUnlock()
+
+	if outbox == nil {
+		return b.deliver(ctx, evt, nil, nil, false)
+	}
+	return b.store(ctx, outbox, tx, evt)
+}
+
+// Wait blocks until async handlers started by Publish have returned.
+func (b *Bus) Wait() {
+	b.async.Wait()
+}
+
+func (b *Bus) store(ctx context.Context, outbox *repository.OutboxRepository, q sqlx.QueryerContext, evt Event) error {
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", evt.Name(), err)
+	}
+	_, err = outbox.Insert(ctx, q, evt.Name(), payload)
+	return err
+}
+
+// notify wakes the dispatcher up so a published event does not wait for the next poll.
+func (b *Bus) notify() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver calls every handler of evt except those in done; handled is called after each
+// successful handler. With waitAsync async handlers run concurrently and deliver waits for them
+// (outbox delivery needs their results); otherwise they run in the background.
+// Returns the joined errors of the handlers that failed.
+func (b *Bus) deliver(ctx context.Context, evt Event, done map[string]bool, handled func(key string) error, waitAsync bool) error {
+	b.mu.RLock()
+	subs := append([]subscription(nil), b.handlers[evt.Name()]...)
+	b.mu.RUnlock()
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	record := func(s subscription, err error) {
+		if err == nil && handled != nil {
+			err = handled(s.key)
+		}
+		if err != nil {
+			mu.Lock()
+			errs = append(errs, &HandlerError{Event: evt.Name(), Key: s.key, Err: err})
+			mu.Unlock()
+		}
+	}
+
+	for _, s := range subs {
+		if done[s.key] {
+			continue
+		}
+		s := s
+		switch {
+		case !s.opts.async:
+			record(s, b.call(ctx, s, evt))
+		case waitAsync:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				record(s, b.call(ctx, s, evt))
+			}()
+		default:
+			b.async.Add(1)
+			go func() {
+				defer b.async.Done()
+				// The publisher may return (and cancel its context) before the handler is done.
+				_ = b.call(context.WithoutCancel(ctx), s, evt)
+			}()
+		}
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,246 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type testEvent struct{ ID int }
+
+func (testEvent) Name() string { return "test_event" }
+
+func newTestBus() *Bus {
+	return NewBus(zerolog.Nop())
+}
+
+func TestPublishCallsSyncHandlersInOrder(t *testing.T) {
+	bus := newTestBus()
+	var calls []string
+	for _, key := range []string{"a", "b", "c"} {
+		key := key
+		bus.Subscribe("test_event", key, func(context.Context, Event) error {
+			calls = append(calls, key)
+			return nil
+		})
+	}
+
+	if err := bus.Publish(context.Background(), testEvent{}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(calls) != 3 || calls[0] != "a" || calls[1] != "b" || calls[2] != "c" {
+		t.Fatalf("calls = %v, want [a b c]", calls)
+	}
+}
+
+func TestFailingAndPanickingHandlersDoNotStopOthers(t *testing.T) {
+	bus := newTestBus()
+	failure := errors.New("boom")
+	var reached []string
+	bus.Subscribe("test_event", "fails", func(context.Context, Event) error { return failure })
+	bus.Subscribe("test_event", "panics", func(context.Context, Event) error { panic("handler bug") })
+	bus.Subscribe("test_event", "ok", func(context.Context, Event) error {
+		reached = append(reached, "ok")
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), testEvent{})
+	if len(reached) != 1 {
+		t.Fatal("handler after the failing ones was not called")
+	}
+	if !errors.Is(err, failure) {
+		t.Errorf("error %v does not wrap the handler error", err)
+	}
+
+	var keys []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var herr *HandlerError
+		if !errors.As(e, &herr) {
+			t.Fatalf("error %v is not a *HandlerError", e)
+		}
+		keys = append(keys, herr.Key)
+	}
+	if len(keys) != 2 || keys[0] != "fails" || keys[1] != "panics" {
+		t.Errorf("failed handlers = %v, want [fails panics]", keys)
+	}
+
+	if h := findHandlerStats(bus, "test_event", "panics"); h == nil || h.Panics != 1 {
+		t.Errorf("panic was not counted: %+v", h)
+	}
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	bus := newTestBus()
+	released := make(chan struct{})
+	bus.Subscribe("test_event", "slow", func(ctx context.Context, _ Event) error {
+		<-ctx.Done()
+		close(released)
+		return ctx.Err()
+	}, WithTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	err := bus.Publish(context.Background(), testEvent{})
+	if !errors.Is(err, ErrHandlerTimeout) {
+		t.Fatalf("error = %v, want ErrHandlerTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Publish took %s, the timeout did not apply", elapsed)
+	}
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Error("handler context was not cancelled")
+	}
+}
+
+func TestHandlerRetry(t *testing.T) {
+	bus := newTestBus()
+	var attempts int
+	bus.Subscribe("test_event", "flaky", func(context.Context, Event) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, WithRetry(3, time.Millisecond))
+
+	if err := bus.Publish(context.Background(), testEvent{}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if h := findHandlerStats(bus, "test_event", "flaky"); h == nil || h.Retries != 2 {
+		t.Errorf("retries were not counted: %+v", h)
+	}
+}
+
+func TestHandlerRetryGivesUp(t *testing.T) {
+	bus := newTestBus()
+	var attempts int
+	failure := errors.New("permanent")
+	bus.Subscribe("test_event", "broken", func(context.Context, Event) error {
+		attempts++
+		return failure
+	}, WithRetry(2, time.Millisecond))
+
+	if err := bus.Publish(context.Background(), testEvent{}); !errors.Is(err, failure) {
+		t.Fatalf("error = %v, want %v", err, failure)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestRetryStopsWhenContextIsCancelled(t *testing.T) {
+	bus := newTestBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts int
+	bus.Subscribe("test_event", "flaky", func(context.Context, Event) error {
+		attempts++
+		cancel()
+		return errors.New("temporary")
+	}, WithRetry(5, time.Hour))
+
+	done := make(chan struct{})
+	go func() {
+		_ = bus.Publish(ctx, testEvent{})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish kept waiting for the backoff after the context was cancelled")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestAsyncHandlersRunInBackground(t *testing.T) {
+	bus := newTestBus()
+	release := make(chan struct{})
+	var handled atomic.Int32
+	bus.Subscribe("test_event", "async", func(ctx context.Context, _ Event) error {
+		<-release
+		if ctx.Err() != nil {
+			t.Error("async handler got the cancelled publisher context")
+		}
+		handled.Add(1)
+		return errors.New("async errors are only logged")
+	}, Async())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := bus.Publish(ctx, testEvent{}); err != nil {
+		t.Fatalf("Publish returned the async handler error: %v", err)
+	}
+	cancel()
+	close(release)
+	bus.Wait()
+	if handled.Load() != 1 {
+		t.Error("async handler did not run")
+	}
+}
+
+func TestDeliverSkipsDoneHandlersAndWaitsForAsync(t *testing.T) {
+	bus := newTestBus()
+	var mu sync.Mutex
+	called := map[string]bool{}
+	record := func(key string) Handler {
+		return func(context.Context, Event) error {
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			called[key] = true
+			mu.Unlock()
+			return nil
+		}
+	}
+	bus.Subscribe("test_event", "done", record("done"))
+	bus.Subscribe("test_event", "sync", record("sync"))
+	bus.Subscribe("test_event", "async", record("async"), Async())
+
+	var handled []string
+	err := bus.deliver(context.Background(), testEvent{}, map[string]bool{"done": true}, func(key string) error {
+		mu.Lock()
+		handled = append(handled, key)
+		mu.Unlock()
+		return nil
+	}, true)
+	if err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if called["done"] {
+		t.Error("handler that already processed the event was called again")
+	}
+	if !called["sync"] || !called["async"] {
+		t.Errorf("called = %v, want sync and async", called)
+	}
+	if len(handled) != 2 {
+		t.Errorf("handled = %v, want two keys", handled)
+	}
+}
+
+func TestSubscribeRejectsDuplicateKey(t *testing.T) {
+	bus := newTestBus()
+	bus.Subscribe("test_event", "same", func(context.Context, Event) error { return nil })
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate handler key did not panic")
+		}
+	}()
+	bus.Subscribe("test_event", "same", func(context.Context, Event) error { return nil })
+}
+
+func findHandlerStats(bus *Bus, event, key string) *HandlerStats {
+	for _, h := range bus.Stats() {
+		if h.Event == event && h.Handler == key {
+			return &h
+		}
+	}
+	return nil
+}
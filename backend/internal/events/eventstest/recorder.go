@@ -0,0 +1,139 @@
+// Package eventstest lets unit tests assert which domain events were published.
+//
+//	bus, rec := eventstest.NewBus()
+//	svc := service.NewVotingService(votingRepo, ticketRepo, nil, nil, bus, logger)
+//	...
+//	evt, ok := eventstest.Last[events.DailyVoteWinnerSelected](rec)
+package eventstest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/events"
+)
+
+// RecorderKey is the subscription key of the recorder on the bus.
+const RecorderKey = "eventstest.recorder"
+
+// Recorder collects the events published on a bus, in publish order.
+type Recorder struct {
+	mu      sync.Mutex
+	events  []events.Event
+	changed chan struct{}
+}
+
+// NewBus returns a bus without an outbox (handlers are called on Publish) with a recorder
+// subscribed to every known event.
+func NewBus() (*events.Bus, *Recorder) {
+	bus := events.NewBus(zerolog.Nop())
+	return bus, Attach(bus)
+}
+
+// Attach subscribes a recorder to the given events (all known events if none are given).
+// The recorder is a sync handler, so it sees events in the order they were published.
+func Attach(bus *events.Bus, names ...string) *Recorder {
+	if len(names) == 0 {
+		names = events.Names()
+	}
+	r := &Recorder{changed: make(chan struct{})}
+	for _, name := range names {
+		bus.Subscribe(name, RecorderKey, func(_ context.Context, evt events.Event) error {
+			r.record(evt)
+			return nil
+		})
+	}
+	return r
+}
+
+func (r *Recorder) record(evt events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, evt)
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// Events returns every recorded event.
+func (r *Recorder) Events() []events.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]events.Event(nil), r.events...)
+}
+
+// Named returns the recorded events with the given name.
+func (r *Recorder) Named(name string) []events.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []events.Event{}
+	for _, evt := range r.events {
+		if evt.Name() == name {
+			out = append(out, evt)
+		}
+	}
+	return out
+}
+
+// Count returns how many events with the given name were recorded.
+func (r *Recorder) Count(name string) int {
+	return len(r.Named(name))
+}
+
+// Reset forgets the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// WaitFor waits until at least n events with the given name were recorded, for code that
+// publishes from a goroutine. Reports whether they arrived before the timeout.
+func (r *Recorder) WaitFor(name string, n int, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		r.mu.Lock()
+		count := 0
+		for _, evt := range r.events {
+			if evt.Name() == name {
+				count++
+			}
+		}
+		changed := r.changed
+		r.mu.Unlock()
+
+		if count >= n {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+// Last returns the most recent recorded event of type T.
+func Last[T events.Event](r *Recorder) (T, bool) {
+	all := r.Events()
+	for i := len(all) - 1; i >= 0; i-- {
+		if evt, ok := all[i].(T); ok {
+			return evt, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// All returns the recorded events of type T, in publish order.
+func All[T events.Event](r *Recorder) []T {
+	out := []T{}
+	for _, evt := range r.Events() {
+		if e, ok := evt.(T); ok {
+			out = append(out, e)
+		}
+	}
+	return out
+}
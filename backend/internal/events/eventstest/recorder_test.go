@@ -0,0 +1,100 @@
+package eventstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/events"
+)
+
+func TestRecorderRecordsInPublishOrder(t *testing.T) {
+	bus, rec := NewBus()
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+
+	for _, evt := range []events.Event{
+		events.DailyVoteWinnerSelected{ProposalID: first},
+		events.CommentLiked{CommentID: uuid.New()},
+		events.DailyVoteWinnerSelected{ProposalID: second},
+	} {
+		if err := bus.Publish(ctx, evt); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	if got := len(rec.Events()); got != 3 {
+		t.Fatalf("recorded %d events, want 3", got)
+	}
+	if got := rec.Count(events.EventDailyVoteWinnerSelected); got != 2 {
+		t.Errorf("Count = %d, want 2", got)
+	}
+	if got := rec.Named(events.EventCommentLiked); len(got) != 1 {
+		t.Errorf("Named = %v, want one comment like", got)
+	}
+
+	last, ok := Last[events.DailyVoteWinnerSelected](rec)
+	if !ok || last.ProposalID != second {
+		t.Errorf("Last = %+v, %v; want proposal %s", last, ok, second)
+	}
+	all := All[events.DailyVoteWinnerSelected](rec)
+	if len(all) != 2 || all[0].ProposalID != first || all[1].ProposalID != second {
+		t.Errorf("All = %+v, want both winners in publish order", all)
+	}
+	if _, ok := Last[events.ProposalReleased](rec); ok {
+		t.Error("Last found an event that was not published")
+	}
+
+	rec.Reset()
+	if got := len(rec.Events()); got != 0 {
+		t.Errorf("recorded %d events after Reset, want 0", got)
+	}
+}
+
+func TestAttachOnlyRecordsGivenEvents(t *testing.T) {
+	bus := events.NewBus(zerolog.Nop())
+	rec := Attach(bus, events.EventCommentLiked)
+
+	_ = bus.Publish(context.Background(), events.DailyVoteWinnerSelected{})
+	_ = bus.Publish(context.Background(), events.CommentLiked{})
+
+	if got := rec.Events(); len(got) != 1 || got[0].Name() != events.EventCommentLiked {
+		t.Errorf("recorded %v, want only the comment like", got)
+	}
+}
+
+func TestRecorderRunsAfterEarlierHandlers(t *testing.T) {
+	bus := events.NewBus(zerolog.Nop())
+	seen := false
+	bus.Subscribe(events.EventCommentLiked, "first", func(context.Context, events.Event) error {
+		seen = true
+		return nil
+	})
+	rec := Attach(bus)
+
+	_ = bus.Publish(context.Background(), events.CommentLiked{})
+	if !seen || rec.Count(events.EventCommentLiked) != 1 {
+		t.Errorf("seen = %v, recorded = %d", seen, rec.Count(events.EventCommentLiked))
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	bus, rec := NewBus()
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			time.Sleep(5 * time.Millisecond)
+			_ = bus.Publish(context.Background(), events.CommentLiked{})
+		}
+	}()
+
+	if !rec.WaitFor(events.EventCommentLiked, 2, time.Second) {
+		t.Fatal("WaitFor timed out before both events were published")
+	}
+	if rec.WaitFor(events.EventProposalReleased, 1, 20*time.Millisecond) {
+		t.Error("WaitFor reported an event that was never published")
+	}
+}
@@ -0,0 +1,194 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+)
+
+// DispatcherOptions configures outbox delivery.
+type DispatcherOptions struct {
+	PollInterval time.Duration
+	// LockDuration is how long a claimed event is reserved; after it expires another dispatcher
+	// (or this one after a restart) picks the event up again.
+	LockDuration time.Duration
+	// Retention is how long delivered events are kept for inspection.
+	Retention time.Duration
+	WorkerID  string
+}
+
+func (o DispatcherOptions) withDefaults() DispatcherOptions {
+	if o.PollInterval <= 0 {
+		o.PollInterval = 2 * time.Second
+	}
+	if o.LockDuration <= 0 {
+		o.LockDuration = 2 * time.Minute
+	}
+	if o.Retention <= 0 {
+		o.Retention = 7 * 24 * time.Hour
+	}
+	if o.WorkerID == "" {
+		host, _ := os.Hostname()
+		o.WorkerID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return o
+}
+
+const (
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = time.Hour
+	outboxPruneEvery  = time.Hour
+)
+
+// Dispatcher delivers events from the outbox to the subscribers of the bus.
+// Delivery is at least once: a handler that fails makes the event retry with exponential
+// backoff (handlers that already succeeded are skipped), and after max_attempts the event is
+// dead-lettered until an operator retries it.
+type Dispatcher struct {
+	bus    *Bus
+	repo   *repository.OutboxRepository
+	opts   DispatcherOptions
+	logger zerolog.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates an outbox dispatcher and switches the bus to the outbox.
+func NewDispatcher(bus *Bus, repo *repository.OutboxRepository, opts DispatcherOptions, logger zerolog.Logger) *Dispatcher {
+	bus.UseOutbox(repo)
+	return &Dispatcher{
+		bus:    bus,
+		repo:   repo,
+		opts:   opts.withDefaults(),
+		logger: logger.With().Str("component", "event_outbox").Logger(),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start implements jobs.Worker.
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.wg.Add(1)
+	go d.run(ctx)
+}
+
+// Stop implements jobs.Worker. The event being delivered and async handlers started by
+// direct publishing are finished first.
+func (d *Dispatcher) Stop() {
+	close(d.stopCh)
+	d.wg.Wait()
+	d.bus.Wait()
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		if _, err := d.DispatchPending(ctx); err != nil {
+			d.logger.Error().Err(err).Msg("Failed to claim outbox event")
+		}
+		if time.Since(lastPrune) >= outboxPruneEvery {
+			lastPrune = time.Now()
+			if n, err := d.repo.PruneDelivered(ctx, lastPrune.Add(-d.opts.Retention)); err != nil {
+				d.logger.Error().Err(err).Msg("Failed to prune outbox")
+			} else if n > 0 {
+				d.logger.Info().Int64("events", n).Msg("Pruned delivered outbox events")
+			}
+		}
+
+		select {
+		case <-d.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.bus.wake:
+		}
+	}
+}
+
+// DispatchPending delivers every event that is due and returns how many were handled.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	n := 0
+	for {
+		select {
+		case <-d.stopCh:
+			return n, nil
+		default:
+		}
+		evt, err := d.repo.Claim(ctx, d.opts.WorkerID, d.opts.LockDuration)
+		if err != nil {
+			return n, err
+		}
+		if evt == nil {
+			return n, nil
+		}
+		d.dispatch(ctx, evt)
+		n++
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, row *models.OutboxEvent) {
+	log := d.logger.With().
+		Str("event_id", row.ID.String()).
+		Str("event", row.EventName).
+		Int("attempt", row.Attempts).
+		Logger()
+
+	evt, err := Decode(row.EventName, row.Payload)
+	if err != nil {
+		log.Error().Err(err).Msg("Outbox event cannot be decoded, dead-lettered")
+		if err := d.repo.Bury(context.Background(), row.ID, err.Error()); err != nil {
+			log.Error().Err(err).Msg("Failed to dead-letter outbox event")
+		}
+		return
+	}
+
+	// Handlers are isolated from each other: the event is retried only for those that failed.
+	done, err := d.repo.DeliveredKeys(ctx, row.ID)
+	if err == nil {
+		err = d.bus.deliver(ctx, evt, done, func(key string) error {
+			return d.repo.MarkHandled(context.Background(), row.ID, key)
+		}, true)
+	}
+	if err == nil {
+		if err := d.repo.Complete(context.Background(), row.ID); err != nil {
+			log.Error().Err(err).Msg("Failed to complete outbox event")
+		}
+		return
+	}
+
+	status, ferr := d.repo.Fail(context.Background(), row.ID, err.Error(), outboxBackoff(row.Attempts))
+	if ferr != nil {
+		log.Error().Err(ferr).Msg("Failed to record outbox delivery failure")
+		return
+	}
+	if status == models.OutboxEventStatusDead {
+		log.Error().Err(err).Msg("Outbox event dead-lettered")
+		return
+	}
+	log.Warn().Err(err).Msg("Outbox event delivery failed, will retry")
+}
+
+// outboxBackoff is 5s, 10s, 20s, ... capped at an hour.
+func outboxBackoff(attempt int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempt && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,239 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/config"
+	"novels-backend/internal/database"
+	"novels-backend/internal/repository"
+)
+
+// outboxDriver is a database/sql driver that only understands the outbox INSERT. Rows written
+// in a transaction become visible when it commits and are dropped when it rolls back.
+type outboxDriver struct {
+	mu        sync.Mutex
+	committed []string
+}
+
+func (d *outboxDriver) Open(string) (driver.Conn, error) { return &outboxConn{d: d}, nil }
+
+func (d *outboxDriver) events() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.committed...)
+}
+
+type outboxConn struct {
+	d       *outboxDriver
+	pending []string
+	inTx    bool
+}
+
+func (c *outboxConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *outboxConn) Close() error { return nil }
+
+func (c *outboxConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	return c, nil
+}
+
+func (c *outboxConn) Commit() error {
+	c.d.mu.Lock()
+	c.d.committed = append(c.d.committed, c.pending...)
+	c.d.mu.Unlock()
+	c.pending, c.inTx = nil, false
+	return nil
+}
+
+func (c *outboxConn) Rollback() error {
+	c.pending, c.inTx = nil, false
+	return nil
+}
+
+func (c *outboxConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "INSERT INTO event_outbox") {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	name, _ := args[0].Value.(string)
+	if c.inTx {
+		c.pending = append(c.pending, name)
+	} else {
+		c.d.mu.Lock()
+		c.d.committed = append(c.d.committed, name)
+		c.d.mu.Unlock()
+	}
+	return &idRows{id: uuid.New()}, nil
+}
+
+type idRows struct {
+	id   uuid.UUID
+	done bool
+}
+
+func (r *idRows) Columns() []string { return []string{"id"} }
+func (r *idRows) Close() error      { return nil }
+func (r *idRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.id.String()
+	return nil
+}
+
+func newFakeOutboxDB(t *testing.T) (*sqlx.DB, *outboxDriver) {
+	t.Helper()
+	d := &outboxDriver{}
+	name := "outboxtest-" + uuid.NewString()
+	sql.Register(name, d)
+	db, err := sqlx.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestPublishTxWithOutbox(t *testing.T) {
+	ctx := context.Background()
+	db, store := newFakeOutboxDB(t)
+	bus := newTestBus()
+	bus.UseOutbox(repository.NewOutboxRepository(db, 3))
+
+	called := 0
+	bus.Subscribe(EventCommentLiked, "counter", func(context.Context, Event) error {
+		called++
+		return nil
+	})
+
+	rolledBack, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.PublishTx(ctx, rolledBack, CommentLiked{}); err != nil {
+		t.Fatalf("PublishTx: %v", err)
+	}
+	if err := rolledBack.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.events(); len(got) != 0 {
+		t.Fatalf("rolled back event was stored: %v", got)
+	}
+
+	committed, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.PublishTx(ctx, committed, CommentLiked{}); err != nil {
+		t.Fatalf("PublishTx: %v", err)
+	}
+	if got := store.events(); len(got) != 0 {
+		t.Fatalf("event was stored before commit: %v", got)
+	}
+	if err := committed.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.events(); len(got) != 1 || got[0] != EventCommentLiked {
+		t.Fatalf("stored events = %v, want [%s]", got, EventCommentLiked)
+	}
+
+	if called != 0 {
+		t.Errorf("handler was called %d times by PublishTx; with an outbox only the dispatcher delivers", called)
+	}
+}
+
+func TestPublishTxWithoutOutboxDeliversRightAway(t *testing.T) {
+	bus := newTestBus()
+	called := 0
+	bus.Subscribe(EventCommentLiked, "counter", func(context.Context, Event) error {
+		called++
+		return nil
+	})
+
+	if err := bus.PublishTx(context.Background(), nil, CommentLiked{}); err != nil {
+		t.Fatalf("PublishTx: %v", err)
+	}
+	if called != 1 {
+		t.Errorf("called = %d, want 1", called)
+	}
+}
+
+// TestDispatcherSkipsRolledBackEvents runs against a real database: set TEST_DATABASE_URL to a
+// disposable PostgreSQL database (migrations are applied to it).
+func TestDispatcherSkipsRolledBackEvents(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	db, err := database.Connect(config.DatabaseConfig{URL: url, MaxOpenConns: 5, MaxIdleConns: 5, ConnMaxLifetime: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := database.RunMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+
+	bus := newTestBus()
+	dispatcher := NewDispatcher(bus, repository.NewOutboxRepository(db, 3), DispatcherOptions{WorkerID: "test"}, zerolog.Nop())
+	// Events left by earlier runs are delivered before this test counts anything.
+	if _, err := dispatcher.DispatchPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var delivered []uuid.UUID
+	bus.Subscribe(EventCommentLiked, "test.rollback", func(_ context.Context, evt Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, evt.(CommentLiked).AuthorID)
+		return nil
+	})
+
+	rollbackID, commitID := uuid.New(), uuid.New()
+	for _, c := range []struct {
+		author uuid.UUID
+		commit bool
+	}{{rollbackID, false}, {commitID, true}} {
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bus.PublishTx(ctx, tx, CommentLiked{AuthorID: c.author}); err != nil {
+			t.Fatal(err)
+		}
+		if c.commit {
+			err = tx.Commit()
+		} else {
+			err = tx.Rollback()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := dispatcher.DispatchPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 1 || delivered[0] != commitID {
+		t.Errorf("delivered = %v, want only the committed event %s", delivered, commitID)
+	}
+}
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"novels-backend/internal/telemetry"
+)
+
+// HandlerStats are the delivery counters of one handler of one event since the process started.
+type HandlerStats struct {
+	Event       string     `json:"event"`
+	Handler     string     `json:"handler"`
+	Async       bool       `json:"async"`
+	Calls       int64      `json:"calls"`
+	Failures    int64      `json:"failures"`
+	Timeouts    int64      `json:"timeouts"`
+	Panics      int64      `json:"panics"`
+	Retries     int64      `json:"retries"`
+	AvgMillis   float64    `json:"avgMillis"`
+	MaxMillis   float64    `json:"maxMillis"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+type handlerStats struct {
+	HandlerStats
+	total time.Duration
+}
+
+type busStats struct {
+	mu       sync.Mutex
+	handlers map[string]*handlerStats
+}
+
+func newBusStats() *busStats {
+	return &busStats{handlers: map[string]*handlerStats{}}
+}
+
+func (s *busStats) get(event, key string) *handlerStats {
+	h, ok := s.handlers[event+"/"+key]
+	if !ok {
+		h = &handlerStats{HandlerStats: HandlerStats{Event: event, Handler: key}}
+		s.handlers[event+"/"+key] = h
+	}
+	return h
+}
+
+func (s *busStats) register(event, key string, async bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(event, key).Async = async
+}
+
+func (s *busStats) observe(event, key string, d time.Duration, err error) {
+	telemetry.ObserveEventHandler(event, key, d, err, errors.Is(err, ErrHandlerTimeout))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.get(event, key)
+	h.Calls++
+	h.total += d
+	if ms := float64(d) / float64(time.Millisecond); ms > h.MaxMillis {
+		h.MaxMillis = ms
+	}
+	if err != nil {
+		now := time.Now()
+		h.Failures++
+		h.LastError = err.Error()
+		h.LastErrorAt = &now
+		if errors.Is(err, ErrHandlerTimeout) {
+			h.Timeouts++
+		}
+	}
+}
+
+func (s *busStats) retried(event, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(event, key).Retries++
+}
+
+func (s *busStats) panicked(event, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(event, key).Panics++
+}
+
+// Stats returns per-handler delivery counters, ordered by event and handler key.
+func (b *Bus) Stats() []HandlerStats {
+	b.stats.mu.Lock()
+	defer b.stats.mu.Unlock()
+
+	out := make([]HandlerStats, 0, len(b.stats.handlers))
+	for _, h := range b.stats.handlers {
+		st := h.HandlerStats
+		if st.Calls > 0 {
+			st.AvgMillis = float64(h.total) / float64(st.Calls) / float64(time.Millisecond)
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Event != out[j].Event {
+			return out[i].Event < out[j].Event
+		}
+		return out[i].Handler < out[j].Handler
+	})
+	return out
+}
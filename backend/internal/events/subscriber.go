@@ -0,0 +1,152 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"novels-backend/internal/telemetry"
+)
+
+// ErrHandlerTimeout is returned for a handler that did not finish within its timeout.
+var ErrHandlerTimeout = errors.New("event handler timed out")
+
+type subscription struct {
+	key  string
+	h    Handler
+	opts subscribeOptions
+}
+
+type subscribeOptions struct {
+	async    bool
+	timeout  time.Duration
+	attempts int
+	backoff  time.Duration
+}
+
+// SubscribeOption configures how a handler is called.
+type SubscribeOption func(*subscribeOptions)
+
+// Async runs the handler in its own goroutine so it does not hold up the publisher or the
+// handlers registered after it. Use it for handlers that do not depend on the order of delivery.
+func Async() SubscribeOption {
+	return func(o *subscribeOptions) { o.async = true }
+}
+
+// WithTimeout gives up on a handler call after d. The handler gets a context with that deadline;
+// a handler that ignores it keeps running in the background, but the delivery moves on.
+func WithTimeout(d time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) { o.timeout = d }
+}
+
+// WithRetry calls a failing handler up to attempts times in total, waiting backoff, 2*backoff, ...
+// between calls. Outbox delivery retries the event on top of that.
+func WithRetry(attempts int, backoff time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if attempts > 1 {
+			o.attempts = attempts
+		}
+		o.backoff = backoff
+	}
+}
+
+// HandlerError is the error of one handler for one event.
+type HandlerError struct {
+	Event string
+	Key   string
+	Err   error
+}
+
+func (e *HandlerError) Error() string { return e.Key + ": " + e.Err.Error() }
+
+func (e *HandlerError) Unwrap() error { return e.Err }
+
+// call runs the handler with its retry policy, recording stats and logging failures.
+func (b *Bus) call(ctx context.Context, s subscription, evt Event) error {
+	name := evt.Name()
+	backoff := s.opts.backoff
+
+	var err error
+	for attempt := 1; attempt <= s.opts.attempts; attempt++ {
+		if attempt > 1 {
+			b.stats.retried(name, s.key)
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		spanCtx, span := telemetry.StartSpan(ctx, "event "+name,
+			attribute.String("event.name", name),
+			attribute.String("event.handler", s.key),
+			attribute.Int("event.attempt", attempt),
+		)
+		start := time.Now()
+		err = b.callOnce(spanCtx, s, evt)
+		elapsed := time.Since(start)
+		telemetry.EndSpan(span, err)
+		b.stats.observe(name, s.key, elapsed, err)
+		if err == nil {
+			b.logger.Debug().
+				Str("event", name).
+				Str("handler", s.key).
+				Dur("duration", elapsed).
+				Msg("Event handled")
+			return nil
+		}
+
+		b.logger.Error().
+			Err(err).
+			Str("event", name).
+			Str("handler", s.key).
+			Int("attempt", attempt).
+			Int("max_attempts", s.opts.attempts).
+			Dur("duration", elapsed).
+			Msg("Event handler failed")
+	}
+	return err
+}
+
+// callOnce calls the handler once, turning a panic into an error and enforcing the timeout.
+func (b *Bus) callOnce(ctx context.Context, s subscription, evt Event) error {
+	if s.opts.timeout <= 0 {
+		return b.safeCall(ctx, s, evt)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.opts.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- b.safeCall(ctx, s, evt) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s", ErrHandlerTimeout, s.opts.timeout)
+		}
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) safeCall(ctx context.Context, s subscription, evt Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			b.stats.panicked(evt.Name(), s.key)
+			b.logger.Error().
+				Str("event", evt.Name()).
+				Str("handler", s.key).
+				Str("panic", fmt.Sprint(r)).
+				Str("stack", string(debug.Stack())).
+				Msg("Event handler panicked")
+		}
+	}()
+	return s.h(ctx, evt)
+}
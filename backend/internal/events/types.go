@@ -1,11 +1,21 @@
 package events
 
-import "github.com/google/uuid"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
 
 const (
 	EventDailyVoteWinnerSelected      = "daily_vote_winner_selected"
 	EventTranslationVoteWinnerSelected = "translation_vote_winner_selected"
 	EventProposalReleased             = "proposal_released"
+	EventTranslationJobFinished       = "translation_job_finished"
+	EventCatalogChanged               = "catalog_changed"
+	EventXPAwarded                    = "xp_awarded"
+	EventCommentLiked                 = "comment_liked"
 )
 
 type DailyVoteWinnerSelected struct {
@@ -30,3 +40,94 @@ type ProposalReleased struct {
 
 func (ProposalReleased) Name() string { return EventProposalReleased }
 
+
+// TranslationJobFinished is fired when a chapter translation job reaches a final status
+// (done, skipped or failed after its last attempt).
+type TranslationJobFinished struct {
+	JobID      uuid.UUID
+	ChapterID  uuid.UUID
+	NovelID    uuid.UUID
+	TargetLang string
+	Status     string
+	Error      string
+}
+
+func (TranslationJobFinished) Name() string { return EventTranslationJobFinished }
+
+// CatalogChanged is fired when something shown in catalog lists changes: a novel, its chapters,
+// genres, tags or wiki data. NovelID is nil for changes not tied to one novel.
+type CatalogChanged struct {
+	NovelID *uuid.UUID
+	Reason  string
+}
+
+func (CatalogChanged) Name() string { return EventCatalogChanged }
+
+// CatalogChanged reasons.
+const (
+	CatalogReasonNovel    = "novel"
+	CatalogReasonChapter  = "chapter"
+	CatalogReasonTaxonomy = "taxonomy"
+	CatalogReasonWiki     = "wiki"
+)
+
+// XPAwarded is fired after XP was awarded for a user action (see models.XPEventType).
+type XPAwarded struct {
+	UserID  uuid.UUID
+	Type    string
+	Delta   int64
+	RefType string
+	RefID   uuid.UUID
+}
+
+func (XPAwarded) Name() string { return EventXPAwarded }
+
+// CommentLiked is fired when a user likes another user's comment.
+type CommentLiked struct {
+	CommentID uuid.UUID
+	AuthorID  uuid.UUID
+}
+
+func (CommentLiked) Name() string { return EventCommentLiked }
+
+// decoders restore events stored in the outbox. Every event type must be listed here.
+var decoders = map[string]func([]byte) (Event, error){
+	EventDailyVoteWinnerSelected:       decodeAs[DailyVoteWinnerSelected],
+	EventTranslationVoteWinnerSelected: decodeAs[TranslationVoteWinnerSelected],
+	EventProposalReleased:              decodeAs[ProposalReleased],
+	EventTranslationJobFinished:        decodeAs[TranslationJobFinished],
+	EventCatalogChanged:                decodeAs[CatalogChanged],
+	EventXPAwarded:                     decodeAs[XPAwarded],
+	EventCommentLiked:                  decodeAs[CommentLiked],
+}
+
+func decodeAs[T Event](payload []byte) (Event, error) {
+	var evt T
+	if err := json.Unmarshal(payload, &evt); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// Names returns the names of all known events, sorted.
+func Names() []string {
+	names := make([]string, 0, len(decoders))
+	for name := range decoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decode restores an event from its name and JSON payload.
+func Decode(name string, payload []byte) (Event, error) {
+	decode, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	evt, err := decode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", name, err)
+	}
+	return evt, nil
+}
@@ -0,0 +1,156 @@
+// Package health runs the dependency checks behind the readiness endpoint.
+//
+// Checks run concurrently, each with its own timeout. A failing critical check makes the
+// instance unready (it should get no traffic); a failing non-critical check only marks it
+// degraded, so an outage of e.g. parser-service does not take the whole site out of rotation.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Statuses of a check and of a report.
+const (
+	StatusOK          = "ok"
+	StatusFailed      = "failed"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// ErrDraining is returned by a check of a component that is shutting down.
+var ErrDraining = errors.New("shutting down")
+
+// CheckFunc reports whether a dependency is usable.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Critical   bool    `json:"critical"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"durationMs"`
+}
+
+// Report is the outcome of all checks.
+type Report struct {
+	Status    string    `json:"status"`
+	Checks    []Result  `json:"checks"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Ready reports whether the instance should receive traffic.
+func (r Report) Ready() bool {
+	return r.Status == StatusOK || r.Status == StatusDegraded
+}
+
+// Checker holds the registered checks.
+type Checker struct {
+	timeout time.Duration
+	checks  []check
+}
+
+// New creates a checker; each check gets at most timeout.
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a check. Must be called before Run.
+func (c *Checker) Add(name string, critical bool, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, critical: critical, fn: fn})
+}
+
+// Run runs every check and summarizes them: unavailable if a critical check failed
+// (draining if it failed with ErrDraining), degraded if only non-critical ones did.
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, ch := range c.checks {
+		i, ch := i, ch
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, ch)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results, CheckedAt: time.Now().UTC()}
+	for _, res := range results {
+		switch {
+		case res.Status == StatusOK:
+		case res.Critical && res.Status == StatusDraining:
+			report.Status = StatusDraining
+		case res.Critical && report.Status != StatusDraining:
+			report.Status = StatusUnavailable
+		case !res.Critical && report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, ch check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- ch.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+
+	res := Result{
+		Name:       ch.name,
+		Status:     StatusOK,
+		Critical:   ch.critical,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	switch {
+	case errors.Is(err, ErrDraining):
+		res.Status = StatusDraining
+		res.Error = err.Error()
+	case err != nil:
+		res.Status = StatusFailed
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// DirWritable checks that a file can be created in dir.
+func DirWritable(dir string) CheckFunc {
+	return func(context.Context) error {
+		f, err := os.CreateTemp(dir, ".healthcheck-*")
+		if err != nil {
+			return err
+		}
+		name := f.Name()
+		f.Close()
+		return os.Remove(filepath.Clean(name))
+	}
+}
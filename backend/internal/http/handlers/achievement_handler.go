@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/jobs"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// AchievementHandler обработчик ачивок: публичный список, прогресс пользователей и админка
+type AchievementHandler struct {
+	achievementService *service.AchievementService
+	profileService     *service.ProfileService
+	backfillJob        *jobs.AchievementBackfillJob
+	logger             zerolog.Logger
+}
+
+// NewAchievementHandler создает новый AchievementHandler
+func NewAchievementHandler(achievementService *service.AchievementService, profileService *service.ProfileService, backfillJob *jobs.AchievementBackfillJob, logger zerolog.Logger) *AchievementHandler {
+	return &AchievementHandler{
+		achievementService: achievementService,
+		profileService:     profileService,
+		backfillJob:        backfillJob,
+		logger:             logger,
+	}
+}
+
+// List возвращает все ачивки с условиями получения
+// GET /api/v1/achievements
+func (h *AchievementHandler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.achievementService.List(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, list)
+}
+
+// GetUserAchievements возвращает ачивки пользователя: полученные и прогресс по остальным.
+// Если пользователь скрыл ачивки в профиле — 403, заблокированный пользователь — 404
+// GET /api/v1/users/{id}/achievements
+func (h *AchievementHandler) GetUserAchievements(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid user id")
+		return
+	}
+
+	// Гость — uuid.Nil
+	viewerID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.profileService.CanView(r.Context(), userID, viewerID, models.ProfileSectionAchievements); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeUserAchievements(w, r, userID)
+}
+
+// GetMyAchievements возвращает ачивки текущего пользователя
+// GET /api/v1/me/achievements
+func (h *AchievementHandler) GetMyAchievements(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	h.writeUserAchievements(w, r, userID)
+}
+
+func (h *AchievementHandler) writeUserAchievements(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	list, err := h.achievementService.ListForUser(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	unlocked := 0
+	for _, a := range list {
+		if a.UnlockedAt != nil {
+			unlocked++
+		}
+	}
+	response.OK(w, map[string]any{"achievements": list, "unlocked": unlocked, "total": len(list)})
+}
+
+// ListMetrics возвращает метрики, доступные в условиях ачивок
+// GET /api/v1/admin/achievements/metrics
+func (h *AchievementHandler) ListMetrics(w http.ResponseWriter, r *http.Request) {
+	response.OK(w, h.achievementService.Metrics())
+}
+
+// Create создает ачивку; пользователи, уже выполнившие условие, получат ее фоновой задачей
+// POST /api/v1/admin/achievements
+func (h *AchievementHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.SaveAchievementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	achievement, err := h.achievementService.Create(r.Context(), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.Created(w, achievement)
+}
+
+// Update обновляет ачивку; при изменении условия она заново выдается по всем пользователям
+// PUT /api/v1/admin/achievements/{id}
+func (h *AchievementHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid achievement id")
+		return
+	}
+
+	var req models.SaveAchievementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	achievement, err := h.achievementService.Update(r.Context(), id, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, achievement)
+}
+
+// RunBackfillNow выдает новые и измененные ачивки немедленно
+// POST /api/v1/admin/ops/jobs/achievement-backfill/run
+func (h *AchievementHandler) RunBackfillNow(w http.ResponseWriter, r *http.Request) {
+	unlocked, err := h.backfillJob.Run(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Achievement backfill job failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to run achievement backfill job")
+		return
+	}
+
+	response.OK(w, map[string]any{"message": "achievement backfill job executed", "unlocked": unlocked})
+}
+
+func (h *AchievementHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrAchievementNotFound):
+		response.NotFound(w, "achievement not found")
+	case errors.Is(err, service.ErrInvalidAchievement):
+		response.BadRequest(w, err.Error())
+	case errors.Is(err, service.ErrAchievementCodeTaken):
+		response.Conflict(w, err.Error())
+	case errors.Is(err, service.ErrUserNotFound):
+		response.NotFound(w, "user not found")
+	case errors.Is(err, service.ErrProfileSectionHidden):
+		response.Forbidden(w, err.Error())
+	default:
+		h.logger.Error().Err(err).Msg("Achievement request failed")
+		response.InternalError(w)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
 	"novels-backend/internal/service"
 	"novels-backend/pkg/response"
 
@@ -36,6 +37,15 @@ func NewAdminHandler(
 	}
 }
 
+// adminUserID возвращает ID текущего пользователя (автор ревизий текста глав)
+func adminUserID(r *http.Request) *uuid.UUID {
+	id, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
 // ========================
 // NOVELS CRUD
 // ========================
@@ -161,7 +171,7 @@ func (h *AdminHandler) CreateChapter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chapter, err := h.chapterService.Create(r.Context(), &req)
+	chapter, err := h.chapterService.Create(r.Context(), &req, adminUserID(r))
 	if err != nil {
 		if errors.Is(err, service.ErrNovelNotFound) {
 			response.NotFound(w, "novel not found")
@@ -190,7 +200,7 @@ func (h *AdminHandler) UpdateChapter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.chapterService.Update(r.Context(), id, &req); err != nil {
+	if err := h.chapterService.Update(r.Context(), id, &req, adminUserID(r)); err != nil {
 		if errors.Is(err, service.ErrChapterNotFound) {
 			response.NotFound(w, "chapter not found")
 			return
@@ -387,7 +397,7 @@ func (h *AdminHandler) BulkCreateChapters(w http.ResponseWriter, r *http.Request
 
 	for _, chapterReq := range req.Chapters {
 		chapterReq.NovelID = novelID
-		_, err := h.chapterService.Create(r.Context(), &chapterReq)
+		_, err := h.chapterService.Create(r.Context(), &chapterReq, adminUserID(r))
 		if err != nil {
 			lastError = err
 			continue
@@ -89,6 +89,10 @@ func (h *AdminSystemHandler) UpdateSetting(w http.ResponseWriter, r *http.Reques
 			response.NotFound(w, "setting not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidSetting) {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalError(w)
 		return
 	}
@@ -33,7 +33,13 @@ func (h *ChapterHandler) ListByNovel(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 	params := h.parseListParams(r)
 
-	result, err := h.chapterService.ListByNovel(r.Context(), slug, params)
+	// Подписчикам с ранним доступом видны запланированные главы
+	var userID *uuid.UUID
+	if uid, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		userID = &uid
+	}
+
+	result, err := h.chapterService.ListByNovel(r.Context(), slug, params, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrNovelNotFound) {
 			response.NotFound(w, "novel not found")
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ChapterRevisionHandler обработчик истории ревизий текста глав (админка)
+type ChapterRevisionHandler struct {
+	revisionService *service.ChapterRevisionService
+}
+
+// NewChapterRevisionHandler создает новый ChapterRevisionHandler
+func NewChapterRevisionHandler(revisionService *service.ChapterRevisionService) *ChapterRevisionHandler {
+	return &ChapterRevisionHandler{
+		revisionService: revisionService,
+	}
+}
+
+// ListRevisions получает список ревизий главы (без текста)
+// GET /api/v1/admin/chapters/{id}/revisions?lang=ru
+func (h *ChapterRevisionHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid chapter id")
+		return
+	}
+
+	revisions, err := h.revisionService.List(r.Context(), chapterID, r.URL.Query().Get("lang"), parseIntQuery(r, "limit", 100))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, map[string]any{"revisions": revisions})
+}
+
+// GetRevision получает ревизию с текстом
+// GET /api/v1/admin/chapters/{id}/revisions/{revisionId}
+func (h *ChapterRevisionHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid chapter id")
+		return
+	}
+	revisionID, err := uuid.Parse(chi.URLParam(r, "revisionId"))
+	if err != nil {
+		response.BadRequest(w, "invalid revision id")
+		return
+	}
+
+	rev, err := h.revisionService.Get(r.Context(), chapterID, revisionID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, rev)
+}
+
+// DiffRevisions сравнивает две ревизии по абзацам
+// GET /api/v1/admin/chapters/{id}/revisions/diff?from={revisionId}&to={revisionId}
+func (h *ChapterRevisionHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid chapter id")
+		return
+	}
+	fromID, err := uuid.Parse(r.URL.Query().Get("from"))
+	if err != nil {
+		response.BadRequest(w, "invalid from revision id")
+		return
+	}
+	toID, err := uuid.Parse(r.URL.Query().Get("to"))
+	if err != nil {
+		response.BadRequest(w, "invalid to revision id")
+		return
+	}
+
+	diff, err := h.revisionService.Diff(r.Context(), chapterID, fromID, toID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, diff)
+}
+
+// RollbackRevision восстанавливает текст главы из ревизии (создается новая ревизия)
+// POST /api/v1/admin/chapters/{id}/revisions/{revisionId}/rollback
+func (h *ChapterRevisionHandler) RollbackRevision(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid chapter id")
+		return
+	}
+	revisionID, err := uuid.Parse(chi.URLParam(r, "revisionId"))
+	if err != nil {
+		response.BadRequest(w, "invalid revision id")
+		return
+	}
+
+	if err := h.revisionService.Rollback(r.Context(), chapterID, revisionID, adminUserID(r)); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, map[string]string{"message": "chapter content restored"})
+}
+
+func (h *ChapterRevisionHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrChapterNotFound):
+		response.NotFound(w, "chapter not found")
+	case errors.Is(err, service.ErrNotFound):
+		response.NotFound(w, "revision not found")
+	case errors.Is(err, service.ErrRevisionMismatch):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalError(w)
+	}
+}
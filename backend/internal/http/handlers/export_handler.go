@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// exportWriteTimeout заменяет WRITE_TIMEOUT сервера на время отдачи файла
+const exportWriteTimeout = 10 * time.Minute
+
+// ExportHandler обработчик выгрузки новелл для офлайн-чтения
+type ExportHandler struct {
+	exportService *service.ExportService
+	logger        zerolog.Logger
+}
+
+// NewExportHandler создает новый ExportHandler
+func NewExportHandler(exportService *service.ExportService, logger zerolog.Logger) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// ExportEPUB выгружает новеллу (или диапазон глав) в EPUB 3
+// GET /api/v1/novels/{slug}/export.epub?lang=ru&from=1&to=200
+func (h *ExportHandler) ExportEPUB(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	q := r.URL.Query()
+
+	from, err := parseChapterNumber(q.Get("from"))
+	if err != nil {
+		response.BadRequest(w, "invalid from")
+		return
+	}
+	to, err := parseChapterNumber(q.Get("to"))
+	if err != nil {
+		response.BadRequest(w, "invalid to")
+		return
+	}
+	if to > 0 && to < from {
+		response.BadRequest(w, "to must not be less than from")
+		return
+	}
+
+	var userID *uuid.UUID
+	if id, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		userID = &id
+	}
+
+	exp, err := h.exportService.PrepareEPUB(r.Context(), slug, q.Get("lang"), from, to, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNovelNotFound):
+			response.NotFound(w, "novel not found")
+		case errors.Is(err, service.ErrExportEmpty):
+			response.NotFound(w, "no chapters in this language and range")
+		case errors.Is(err, service.ErrExportTooLarge):
+			response.BadRequest(w, err.Error())
+		case errors.Is(err, service.ErrExportRequiresSubscription):
+			response.Forbidden(w, err.Error())
+		default:
+			h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to prepare EPUB export")
+			response.InternalError(w)
+		}
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+
+	w.Header().Set("Content-Type", "application/epub+zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+exp.FileName+`"`)
+	w.Header().Set("ETag", `"`+exp.Key+`"`)
+
+	cached, err := h.exportService.OpenCached(exp)
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to open cached EPUB export")
+		response.InternalError(w)
+		return
+	}
+	if cached != nil {
+		defer cached.Close()
+		info, err := cached.Stat()
+		if err != nil {
+			response.InternalError(w)
+			return
+		}
+		http.ServeContent(w, r, exp.FileName, info.ModTime(), cached)
+		return
+	}
+
+	if match := r.Header.Get("If-None-Match"); match == `"`+exp.Key+`"` {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// Файл пишется прямо в ответ; после начала отдачи ошибку можно только залогировать.
+	if err := h.exportService.WriteEPUB(r.Context(), exp, w); err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Str("lang", exp.Lang).Msg("EPUB export failed")
+	}
+}
+
+// parseChapterNumber разбирает номер главы (допускаются дробные); пусто — 0
+func parseChapterNumber(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, errors.New("invalid chapter number")
+	}
+	return n, nil
+}
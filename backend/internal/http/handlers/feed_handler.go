@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"novels-backend/internal/atom"
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// feedMaxAge сколько читалки и прокси могут не перезапрашивать ленту
+const feedMaxAge = 5 * time.Minute
+
+// FeedHandler обработчик Atom-лент обновлений
+type FeedHandler struct {
+	feedService *service.FeedService
+	logger      zerolog.Logger
+}
+
+// NewFeedHandler создает новый FeedHandler
+func NewFeedHandler(feedService *service.FeedService, logger zerolog.Logger) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		logger:      logger,
+	}
+}
+
+// NovelChapters лента новых глав новеллы
+// GET /api/v1/novels/{slug}/feed.atom?lang=ru
+func (h *FeedHandler) NovelChapters(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	lang := feedLang(r)
+
+	novel, chapters, err := h.feedService.NovelChapters(r.Context(), slug, lang)
+	if err != nil {
+		if errors.Is(err, service.ErrNovelNotFound) {
+			response.NotFound(w, "novel not found")
+			return
+		}
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to build novel feed")
+		response.InternalError(w)
+		return
+	}
+
+	feed := h.newFeed(r, "urn:uuid:"+novel.ID.String()+":"+lang, novel.Title,
+		"/"+lang+"/novel/"+novel.Slug)
+	if novel.Description != nil {
+		feed.Subtitle = *novel.Description
+	}
+	if novel.CoverImageKey != nil && *novel.CoverImageKey != "" {
+		feed.Icon = absoluteURL(r, "/uploads/"+*novel.CoverImageKey)
+	}
+	for _, ch := range chapters {
+		feed.Entries = append(feed.Entries, h.chapterEntry(r, lang, ch, false))
+	}
+
+	h.write(w, r, feed, lastUpdated(feed, novel.UpdatedAt), false)
+}
+
+// News лента новостей сайта на языке lang
+// GET /api/v1/news/feed.atom?lang=ru
+func (h *FeedHandler) News(w http.ResponseWriter, r *http.Request) {
+	lang := feedLang(r)
+
+	news, err := h.feedService.News(r.Context(), lang)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to build news feed")
+		response.InternalError(w)
+		return
+	}
+
+	title := "News"
+	if lang == "ru" {
+		title = "Новости"
+	}
+	feed := h.newFeed(r, "urn:novels:news:"+lang, title, "/"+lang+"/news")
+	for _, n := range news {
+		entry := atom.Entry{
+			ID:         "urn:uuid:" + n.ID.String(),
+			Title:      n.Title,
+			Categories: []atom.Category{{Term: string(n.Category)}},
+			Links:      []atom.Link{{Rel: "alternate", Href: absoluteURL(r, "/"+lang+"/news/"+n.Slug), Type: "text/html"}},
+		}
+		if n.PublishedAt != nil {
+			entry.Updated = atom.FormatTime(*n.PublishedAt)
+			entry.Published = entry.Updated
+		} else {
+			entry.Updated = atom.FormatTime(time.Time{})
+		}
+		if n.Author != nil {
+			entry.Authors = []atom.Person{{Name: n.Author.DisplayName}}
+		}
+		if n.Summary != "" {
+			entry.Summary = &atom.Text{Body: n.Summary}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	h.write(w, r, feed, lastUpdated(feed, time.Time{}), false)
+}
+
+// ReadingList личная лента новых глав новелл из списка «Читаю»; доступ по токену ленты
+// GET /api/v1/me/reading.atom?token=...&lang=ru
+func (h *FeedHandler) ReadingList(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="feeds"`)
+		response.Unauthorized(w, "feed token is required")
+		return
+	}
+	lang := feedLang(r)
+
+	chapters, err := h.feedService.ReadingList(r.Context(), userID, lang)
+	if err != nil {
+		h.logger.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to build reading list feed")
+		response.InternalError(w)
+		return
+	}
+
+	title := "Reading: new chapters"
+	if lang == "ru" {
+		title = "Читаю: новые главы"
+	}
+	feed := h.newFeed(r, "urn:novels:reading:"+userID.String()+":"+lang, title, "/"+lang+"/bookmarks")
+	for _, ch := range chapters {
+		feed.Entries = append(feed.Entries, h.chapterEntry(r, lang, ch, true))
+	}
+
+	h.write(w, r, feed, lastUpdated(feed, time.Time{}), true)
+}
+
+// newFeed создает ленту со ссылками на себя (без токена) и на страницу сайта
+func (h *FeedHandler) newFeed(r *http.Request, id, title, sitePath string) *atom.Feed {
+	self := r.URL.Path
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		self += "?lang=" + lang
+	}
+	feed := atom.NewFeed(id, title, time.Time{})
+	feed.Author = &atom.Person{Name: "Novels", URI: absoluteURL(r, "/")}
+	feed.Links = []atom.Link{
+		{Rel: "self", Href: absoluteURL(r, self), Type: "application/atom+xml"},
+		{Rel: "alternate", Href: absoluteURL(r, sitePath), Type: "text/html"},
+	}
+	return feed
+}
+
+// chapterEntry запись о главе; withNovel добавляет название новеллы (для сводных лент)
+func (h *FeedHandler) chapterEntry(r *http.Request, lang string, ch models.FeedChapter, withNovel bool) atom.Entry {
+	title := feedChapterTitle(lang, ch)
+	if withNovel {
+		title = ch.NovelTitle + " — " + title
+	}
+	published := atom.FormatTime(ch.PublishedAt)
+	return atom.Entry{
+		ID:        "urn:uuid:" + ch.ID.String(),
+		Title:     title,
+		Updated:   published,
+		Published: published,
+		Links: []atom.Link{{
+			Rel:  "alternate",
+			Href: absoluteURL(r, "/"+lang+"/novel/"+ch.NovelSlug+"/chapter/"+ch.ID.String()),
+			Type: "text/html",
+		}},
+	}
+}
+
+// write отдает ленту с ETag и Last-Modified; условные запросы (If-None-Match,
+// If-Modified-Since) обрабатывает http.ServeContent и отвечает 304
+func (h *FeedHandler) write(w http.ResponseWriter, r *http.Request, feed *atom.Feed, modified time.Time, private bool) {
+	feed.Updated = atom.FormatTime(modified)
+
+	var body bytes.Buffer
+	if err := atom.Encode(&body, feed); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode Atom feed")
+		response.InternalError(w)
+		return
+	}
+	sum := sha256.Sum256(body.Bytes())
+
+	scope := "public"
+	if private {
+		scope = "private"
+	}
+	w.Header().Set("Content-Type", atom.ContentType)
+	w.Header().Set("Cache-Control", scope+", max-age="+strconv.Itoa(int(feedMaxAge.Seconds())))
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	http.ServeContent(w, r, "", modified, bytes.NewReader(body.Bytes()))
+}
+
+// lastUpdated время самой свежей записи ленты; для пустой ленты — empty
+func lastUpdated(feed *atom.Feed, empty time.Time) time.Time {
+	if len(feed.Entries) == 0 {
+		return empty
+	}
+	var latest time.Time
+	for _, e := range feed.Entries {
+		if t, err := time.Parse(time.RFC3339, e.Updated); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+func feedLang(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+	return "ru"
+}
+
+func feedChapterTitle(lang string, ch models.FeedChapter) string {
+	number := strconv.FormatFloat(ch.Number, 'f', -1, 64)
+	prefix := "Chapter " + number
+	if lang == "ru" {
+		prefix = "Глава " + number
+	}
+	if ch.Title != nil && strings.TrimSpace(*ch.Title) != "" {
+		return prefix + ". " + strings.TrimSpace(*ch.Title)
+	}
+	return prefix
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// FeedTokenHandler управление токенами лент (OPDS, Atom) текущего пользователя
+type FeedTokenHandler struct {
+	feedTokenService *service.FeedTokenService
+}
+
+// NewFeedTokenHandler создает новый FeedTokenHandler
+func NewFeedTokenHandler(feedTokenService *service.FeedTokenService) *FeedTokenHandler {
+	return &FeedTokenHandler{
+		feedTokenService: feedTokenService,
+	}
+}
+
+// ListTokens получает токены лент пользователя
+// GET /api/v1/me/feed-tokens
+func (h *FeedTokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "invalid user id")
+		return
+	}
+
+	tokens, err := h.feedTokenService.List(r.Context(), userID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]any{"tokens": tokens})
+}
+
+// CreateToken выпускает новый токен; сам токен возвращается только в этом ответе
+// POST /api/v1/me/feed-tokens
+func (h *FeedTokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "invalid user id")
+		return
+	}
+
+	var req models.CreateFeedTokenRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+	}
+
+	token, err := h.feedTokenService.Create(r.Context(), userID, req.Name)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.Created(w, token)
+}
+
+// RevokeToken отзывает токен
+// DELETE /api/v1/me/feed-tokens/{id}
+func (h *FeedTokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "invalid user id")
+		return
+	}
+	tokenID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid token id")
+		return
+	}
+
+	if err := h.feedTokenService.Revoke(r.Context(), userID, tokenID); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			response.NotFound(w, "feed token not found")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]string{"message": "feed token revoked"})
+}
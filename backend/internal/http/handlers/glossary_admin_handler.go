@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GlossaryAdminHandler обработчик админских эндпоинтов глоссария новеллы
+type GlossaryAdminHandler struct {
+	glossaryService *service.GlossaryService
+}
+
+// NewGlossaryAdminHandler создает новый GlossaryAdminHandler
+func NewGlossaryAdminHandler(glossaryService *service.GlossaryService) *GlossaryAdminHandler {
+	return &GlossaryAdminHandler{
+		glossaryService: glossaryService,
+	}
+}
+
+// ListTerms получает глоссарий новеллы
+// GET /api/v1/admin/novels/{id}/glossary
+func (h *GlossaryAdminHandler) ListTerms(w http.ResponseWriter, r *http.Request) {
+	novelID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+
+	terms, err := h.glossaryService.List(r.Context(), novelID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, map[string]any{"terms": terms})
+}
+
+// CreateTerm добавляет термин в глоссарий
+// POST /api/v1/admin/novels/{id}/glossary
+func (h *GlossaryAdminHandler) CreateTerm(w http.ResponseWriter, r *http.Request) {
+	novelID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+
+	var req models.GlossaryTermInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if userID, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		createdBy = &userID
+	}
+
+	term, err := h.glossaryService.Create(r.Context(), novelID, req, createdBy)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.Created(w, term)
+}
+
+// UpdateTerm обновляет термин глоссария
+// PUT /api/v1/admin/novels/{id}/glossary/{termId}
+func (h *GlossaryAdminHandler) UpdateTerm(w http.ResponseWriter, r *http.Request) {
+	novelID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+	termID, err := uuid.Parse(chi.URLParam(r, "termId"))
+	if err != nil {
+		response.BadRequest(w, "invalid term id")
+		return
+	}
+
+	var req models.GlossaryTermInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	term, err := h.glossaryService.Update(r.Context(), novelID, termID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, term)
+}
+
+// DeleteTerm удаляет термин глоссария
+// DELETE /api/v1/admin/novels/{id}/glossary/{termId}
+func (h *GlossaryAdminHandler) DeleteTerm(w http.ResponseWriter, r *http.Request) {
+	novelID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+	termID, err := uuid.Parse(chi.URLParam(r, "termId"))
+	if err != nil {
+		response.BadRequest(w, "invalid term id")
+		return
+	}
+
+	if err := h.glossaryService.Delete(r.Context(), novelID, termID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, map[string]string{"message": "glossary term deleted"})
+}
+
+// GetViolations отчет о терминах, оставшихся непереведенными в главах
+// GET /api/v1/admin/novels/{id}/glossary/violations?lang=ru&limit=200
+func (h *GlossaryAdminHandler) GetViolations(w http.ResponseWriter, r *http.Request) {
+	novelID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+
+	violations, err := h.glossaryService.Violations(r.Context(), novelID, r.URL.Query().Get("lang"), parseIntQuery(r, "limit", 200))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, map[string]any{"violations": violations})
+}
+
+func (h *GlossaryAdminHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrNovelNotFound):
+		response.NotFound(w, "novel not found")
+	case errors.Is(err, service.ErrNotFound):
+		response.NotFound(w, "glossary term not found")
+	case errors.Is(err, service.ErrGlossaryTermExists):
+		response.Conflict(w, err.Error())
+	case errors.Is(err, service.ErrInvalidGlossaryTerm):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalError(w)
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"novels-backend/internal/health"
+)
+
+// HealthHandler обработчик проверок живости и готовности (для оркестратора/балансировщика)
+type HealthHandler struct {
+	checker *health.Checker
+	timeout time.Duration
+}
+
+// NewHealthHandler создает обработчик; timeout ограничивает всю проверку готовности
+func NewHealthHandler(checker *health.Checker, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{checker: checker, timeout: timeout}
+}
+
+// Healthz — процесс жив и обслуживает запросы. Зависимости не проверяются, чтобы сбой базы
+// не приводил к перезапуску всех инстансов
+// GET /healthz
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, map[string]string{
+		"status":    health.StatusOK,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Readyz — инстанс готов принимать трафик: 200 (ok/degraded) или 503 (unavailable/draining)
+// GET /readyz
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	report := h.checker.Run(ctx)
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	writeHealth(w, status, report)
+}
+
+// writeHealth пишет ответ без обёртки pkg/response: формат проб должен быть плоским
+func writeHealth(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,466 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"novels-backend/internal/atom"
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	opdsNavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	opdsAcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	openSearchType      = "application/opensearchdescription+xml"
+	opdsPageSize        = 20
+)
+
+// opdsSorts сортировки каталога OPDS -> сортировки NovelService.List
+var opdsSorts = []struct {
+	Code  string
+	Sort  string
+	Title string
+}{
+	{"popular", "views_total", "Популярное"},
+	{"trending", "views_daily", "В тренде"},
+	{"latest", "updated_at", "Последние обновления"},
+	{"new", "created_at", "Новинки"},
+	{"rating", "rating", "Лучшие по рейтингу"},
+}
+
+// OPDSHandler каталог OPDS 1.2 для читалок (KOReader, Moon+ Reader и т.п.)
+type OPDSHandler struct {
+	novelService    *service.NovelService
+	bookmarkService *service.BookmarkService
+	// freeExportChapters — сколько глав отдается в EPUB без подписки (ссылка на начало книги)
+	freeExportChapters int
+	logger             zerolog.Logger
+}
+
+// NewOPDSHandler создает новый OPDSHandler
+func NewOPDSHandler(novelService *service.NovelService, bookmarkService *service.BookmarkService, freeExportChapters int, logger zerolog.Logger) *OPDSHandler {
+	return &OPDSHandler{
+		novelService:       novelService,
+		bookmarkService:    bookmarkService,
+		freeExportChapters: freeExportChapters,
+		logger:             logger,
+	}
+}
+
+// Root корневой навигационный каталог
+// GET /opds
+func (h *OPDSHandler) Root(w http.ResponseWriter, r *http.Request) {
+	feed := h.newFeed(r, "urn:novels:opds:root", "Новеллы", "/opds", opdsNavigationType)
+
+	for _, s := range opdsSorts {
+		feed.Entries = append(feed.Entries, h.navEntry(r, "urn:novels:opds:"+s.Code, s.Title,
+			h.href(r, "/opds/novels", url.Values{"sort": {s.Code}}), opdsAcquisitionType))
+	}
+	feed.Entries = append(feed.Entries,
+		h.navEntry(r, "urn:novels:opds:genres", "Жанры", h.href(r, "/opds/genres", nil), opdsNavigationType),
+		h.navEntry(r, "urn:novels:opds:tags", "Теги", h.href(r, "/opds/tags", nil), opdsNavigationType),
+	)
+	if middleware.GetUserID(r.Context()) != "" {
+		feed.Entries = append(feed.Entries,
+			h.navEntry(r, "urn:novels:opds:shelves", "Мои закладки", h.href(r, "/opds/shelves", nil), opdsNavigationType))
+	}
+
+	h.write(w, feed, opdsNavigationType)
+}
+
+// ListNovels каталог новелл с сортировкой и фильтром по жанру/тегу
+// GET /opds/novels?sort=popular&genre={slug}&tag={slug}&page=1
+func (h *OPDSHandler) ListNovels(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	sortCode := q.Get("sort")
+	sortField, sortTitle := "views_total", opdsSorts[0].Title
+	for _, s := range opdsSorts {
+		if s.Code == sortCode {
+			sortField, sortTitle = s.Sort, s.Title
+		}
+	}
+
+	params := models.NovelListParams{
+		Lang:  h.lang(r),
+		Page:  parseIntQuery(r, "page", 1),
+		Limit: opdsPageSize,
+		Sort:  sortField,
+		Order: "desc",
+	}
+	title := sortTitle
+	if genre := q.Get("genre"); genre != "" {
+		params.Genres = []string{genre}
+		title = "Жанр: " + genre
+	}
+	if tag := q.Get("tag"); tag != "" {
+		params.Tags = []string{tag}
+		title = "Тег: " + tag
+	}
+
+	result, err := h.novelService.List(r.Context(), params)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("OPDS: failed to list novels")
+		response.InternalError(w)
+		return
+	}
+
+	feed := h.newFeed(r, "urn:novels:opds:novels:"+h.pageQuery(r).Encode(), title, "/opds/novels", opdsAcquisitionType)
+	for _, s := range opdsSorts {
+		feed.Links = append(feed.Links, atom.Link{
+			Rel:         "http://opds-spec.org/facet",
+			Href:        h.href(r, "/opds/novels", url.Values{"sort": {s.Code}, "genre": {q.Get("genre")}, "tag": {q.Get("tag")}}),
+			Type:        opdsAcquisitionType,
+			Title:       s.Title,
+			FacetGroup:  "Сортировка",
+			ActiveFacet: s.Sort == sortField,
+		})
+	}
+	h.addPagination(r, feed, "/opds/novels", result.Pagination)
+	for _, n := range result.Novels {
+		feed.Entries = append(feed.Entries, h.novelEntry(r, n))
+	}
+
+	h.write(w, feed, opdsAcquisitionType)
+}
+
+// Search поиск по каталогу (OpenSearch)
+// GET /opds/search?q=...&page=1
+func (h *OPDSHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	feed := h.newFeed(r, "urn:novels:opds:search:"+query, "Поиск: "+query, "/opds/search", opdsAcquisitionType)
+	if query == "" {
+		h.write(w, feed, opdsAcquisitionType)
+		return
+	}
+
+	result, err := h.novelService.Search(r.Context(), query, models.NovelListParams{
+		Lang:  h.lang(r),
+		Page:  parseIntQuery(r, "page", 1),
+		Limit: opdsPageSize,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("OPDS: search failed")
+		response.InternalError(w)
+		return
+	}
+
+	h.addPagination(r, feed, "/opds/search", result.Pagination)
+	for _, n := range result.Novels {
+		feed.Entries = append(feed.Entries, h.novelEntry(r, n))
+	}
+
+	h.write(w, feed, opdsAcquisitionType)
+}
+
+// OpenSearchDescription описание поиска для читалок
+// GET /opds/opensearch.xml
+func (h *OPDSHandler) OpenSearchDescription(w http.ResponseWriter, r *http.Request) {
+	template := absoluteURL(r, h.href(r, "/opds/search", nil))
+	sep := "?"
+	if strings.Contains(template, "?") {
+		sep = "&"
+	}
+	template += sep + "q={searchTerms}"
+
+	w.Header().Set("Content-Type", openSearchType+"; charset=utf-8")
+	fmt.Fprintf(w, `%s<OpenSearchDescription xmlns="%s">
+  <ShortName>Novels</ShortName>
+  <Description>Поиск новелл</Description>
+  <InputEncoding>UTF-8</InputEncoding>
+  <OutputEncoding>UTF-8</OutputEncoding>
+  <Url type="%s" template="%s"/>
+</OpenSearchDescription>
+`, `<?xml version="1.0" encoding="UTF-8"?>`+"\n", atom.NSOpenSearch, opdsAcquisitionType, xmlAttr(template))
+}
+
+// ListGenres навигация по жанрам
+// GET /opds/genres
+func (h *OPDSHandler) ListGenres(w http.ResponseWriter, r *http.Request) {
+	genres, err := h.novelService.GetAllGenres(r.Context(), h.lang(r))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("OPDS: failed to list genres")
+		response.InternalError(w)
+		return
+	}
+
+	feed := h.newFeed(r, "urn:novels:opds:genres", "Жанры", "/opds/genres", opdsNavigationType)
+	for _, g := range genres {
+		feed.Entries = append(feed.Entries, h.navEntry(r, "urn:novels:opds:genre:"+g.Slug, g.Name,
+			h.href(r, "/opds/novels", url.Values{"genre": {g.Slug}}), opdsAcquisitionType))
+	}
+	h.write(w, feed, opdsNavigationType)
+}
+
+// ListTags навигация по тегам
+// GET /opds/tags
+func (h *OPDSHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.novelService.GetAllTags(r.Context(), h.lang(r))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("OPDS: failed to list tags")
+		response.InternalError(w)
+		return
+	}
+
+	feed := h.newFeed(r, "urn:novels:opds:tags", "Теги", "/opds/tags", opdsNavigationType)
+	for _, t := range tags {
+		feed.Entries = append(feed.Entries, h.navEntry(r, "urn:novels:opds:tag:"+t.Slug, t.Name,
+			h.href(r, "/opds/novels", url.Values{"tag": {t.Slug}}), opdsAcquisitionType))
+	}
+	h.write(w, feed, opdsNavigationType)
+}
+
+// ListShelves списки закладок пользователя (нужен токен ленты)
+// GET /opds/shelves?token=...
+func (h *OPDSHandler) ListShelves(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	lists, err := h.bookmarkService.GetLists(r.Context(), userID)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("OPDS: failed to get bookmark lists")
+		response.InternalError(w)
+		return
+	}
+
+	feed := h.newFeed(r, "urn:novels:opds:shelves:"+userID.String(), "Мои закладки", "/opds/shelves", opdsNavigationType)
+	for _, l := range lists {
+		title := l.Title
+		if title == "" {
+			title = string(l.Code)
+		}
+		entry := h.navEntry(r, "urn:novels:opds:shelf:"+l.ID.String(), fmt.Sprintf("%s (%d)", title, l.Count),
+			h.href(r, "/opds/shelves/"+url.PathEscape(string(l.Code)), nil), opdsAcquisitionType)
+		feed.Entries = append(feed.Entries, entry)
+	}
+	h.write(w, feed, opdsNavigationType)
+}
+
+// GetShelf новеллы из списка закладок
+// GET /opds/shelves/{list}?token=...&page=1
+func (h *OPDSHandler) GetShelf(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	code := models.BookmarkListCode(chi.URLParam(r, "list"))
+	page := parseIntQuery(r, "page", 1)
+	result, err := h.bookmarkService.List(r.Context(), userID, models.BookmarksFilter{
+		ListCode: &code,
+		Sort:     "latest_update",
+		Page:     page,
+		Limit:    opdsPageSize,
+	}, h.lang(r))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("OPDS: failed to list bookmarks")
+		response.InternalError(w)
+		return
+	}
+
+	title := string(code)
+	for _, l := range result.Lists {
+		if l.Code == code && l.Title != "" {
+			title = l.Title
+		}
+	}
+
+	path := "/opds/shelves/" + url.PathEscape(string(code))
+	feed := h.newFeed(r, "urn:novels:opds:shelf:"+userID.String()+":"+string(code), title, path, opdsAcquisitionType)
+	totalPages := (result.TotalCount + opdsPageSize - 1) / opdsPageSize
+	h.addPagination(r, feed, path, models.Pagination{Page: page, Limit: opdsPageSize, Total: result.TotalCount, TotalPages: totalPages})
+	for _, b := range result.Bookmarks {
+		if b.Novel == nil {
+			continue
+		}
+		n := models.NovelWithLocalization{
+			Novel: models.Novel{
+				ID:                b.Novel.ID,
+				Slug:              b.Novel.Slug,
+				CoverImageKey:     b.Novel.CoverImageKey,
+				TranslationStatus: b.Novel.TranslationStatus,
+				UpdatedAt:         b.UpdatedAt,
+			},
+			Title: b.Novel.Title,
+		}
+		feed.Entries = append(feed.Entries, h.novelEntry(r, n))
+	}
+	h.write(w, feed, opdsAcquisitionType)
+}
+
+func (h *OPDSHandler) requireUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="feeds"`)
+		response.Unauthorized(w, "feed token is required")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// novelEntry карточка новеллы со ссылками на EPUB и обложку
+func (h *OPDSHandler) novelEntry(r *http.Request, n models.NovelWithLocalization) atom.Entry {
+	lang := h.lang(r)
+	entry := atom.Entry{
+		ID:         "urn:uuid:" + n.ID.String(),
+		Title:      n.Title,
+		Updated:    atom.FormatTime(n.UpdatedAt),
+		DCLanguage: lang,
+	}
+	if n.Author != nil && *n.Author != "" {
+		entry.Authors = append(entry.Authors, atom.Person{Name: *n.Author})
+	}
+	if n.ReleaseYear != nil {
+		entry.DCIssued = strconv.Itoa(*n.ReleaseYear)
+	}
+	for _, g := range n.Genres {
+		entry.Categories = append(entry.Categories, atom.Category{Term: g.Slug, Label: g.Name})
+	}
+	if n.Description != nil && *n.Description != "" {
+		entry.Summary = &atom.Text{Type: "text", Body: *n.Description}
+	}
+
+	exportPath := "/opds/novels/" + url.PathEscape(n.Slug) + "/export.epub"
+	entry.Links = append(entry.Links, atom.Link{
+		Rel:   "http://opds-spec.org/acquisition",
+		Href:  h.href(r, exportPath, nil),
+		Type:  "application/epub+zip",
+		Title: "EPUB",
+	})
+	if h.freeExportChapters > 0 {
+		entry.Links = append(entry.Links, atom.Link{
+			Rel:   "http://opds-spec.org/acquisition/sample",
+			Href:  h.href(r, exportPath, url.Values{"from": {"1"}, "to": {strconv.Itoa(h.freeExportChapters)}}),
+			Type:  "application/epub+zip",
+			Title: fmt.Sprintf("EPUB, главы 1–%d", h.freeExportChapters),
+		})
+	}
+	if n.CoverImageKey != nil && *n.CoverImageKey != "" {
+		cover := "/uploads/" + *n.CoverImageKey
+		entry.Links = append(entry.Links,
+			atom.Link{Rel: "http://opds-spec.org/image", Href: cover},
+			atom.Link{Rel: "http://opds-spec.org/image/thumbnail", Href: cover},
+		)
+	}
+	entry.Links = append(entry.Links, atom.Link{
+		Rel:  "alternate",
+		Href: "/" + lang + "/novel/" + url.PathEscape(n.Slug),
+		Type: "text/html",
+	})
+	return entry
+}
+
+func (h *OPDSHandler) newFeed(r *http.Request, id, title, path, kind string) *atom.Feed {
+	feed := atom.NewFeed(id, title, time.Now())
+	feed.XMLNSDC = atom.NSDublinCore
+	feed.XMLNSOPDS = atom.NSOPDS
+	feed.XMLNSOS = atom.NSOpenSearch
+	feed.Links = []atom.Link{
+		{Rel: "self", Href: h.href(r, path, h.pageQuery(r)), Type: kind},
+		{Rel: "start", Href: h.href(r, "/opds", nil), Type: opdsNavigationType},
+		{Rel: "search", Href: h.href(r, "/opds/opensearch.xml", nil), Type: openSearchType},
+	}
+	return feed
+}
+
+func (h *OPDSHandler) navEntry(r *http.Request, id, title, href, kind string) atom.Entry {
+	return atom.Entry{
+		ID:      id,
+		Title:   title,
+		Updated: atom.FormatTime(time.Now()),
+		Links:   []atom.Link{{Rel: "subsection", Href: href, Type: kind}},
+	}
+}
+
+func (h *OPDSHandler) addPagination(r *http.Request, feed *atom.Feed, path string, p models.Pagination) {
+	feed.TotalResults = p.Total
+	feed.ItemsPerPage = p.Limit
+	feed.StartIndex = (p.Page-1)*p.Limit + 1
+
+	link := func(rel string, page int) atom.Link {
+		q := h.pageQuery(r)
+		q.Set("page", strconv.Itoa(page))
+		return atom.Link{Rel: rel, Href: h.href(r, path, q), Type: opdsAcquisitionType}
+	}
+	if p.TotalPages > 1 {
+		feed.Links = append(feed.Links, link("first", 1), link("last", p.TotalPages))
+	}
+	if p.Page > 1 {
+		feed.Links = append(feed.Links, link("previous", p.Page-1))
+	}
+	if p.Page < p.TotalPages {
+		feed.Links = append(feed.Links, link("next", p.Page+1))
+	}
+}
+
+// pageQuery параметры текущего запроса, влияющие на выборку (без lang/token — их добавляет href)
+func (h *OPDSHandler) pageQuery(r *http.Request) url.Values {
+	q := url.Values{}
+	for _, key := range []string{"sort", "genre", "tag", "q", "page"} {
+		if v := r.URL.Query().Get(key); v != "" {
+			q.Set(key, v)
+		}
+	}
+	return q
+}
+
+// href строит ссылку каталога, сохраняя язык и токен ленты текущего запроса
+func (h *OPDSHandler) href(r *http.Request, path string, params url.Values) string {
+	q := url.Values{}
+	for k, vs := range params {
+		for _, v := range vs {
+			if v != "" {
+				q.Add(k, v)
+			}
+		}
+	}
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		q.Set("lang", lang)
+	}
+	if token := r.URL.Query().Get("token"); token != "" {
+		q.Set("token", token)
+	}
+	if len(q) == 0 {
+		return path
+	}
+	return path + "?" + q.Encode()
+}
+
+// absoluteURL превращает путь в абсолютную ссылку на текущий хост (с учетом https за прокси)
+func absoluteURL(r *http.Request, href string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + href
+}
+
+func (h *OPDSHandler) lang(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+	return "ru"
+}
+
+func (h *OPDSHandler) write(w http.ResponseWriter, feed *atom.Feed, kind string) {
+	if err := atom.Write(w, feed, kind+";charset=utf-8"); err != nil {
+		h.logger.Debug().Err(err).Msg("OPDS: failed to write feed")
+	}
+}
+
+func xmlAttr(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	return r.Replace(s)
+}
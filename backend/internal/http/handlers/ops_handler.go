@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"net/http"
@@ -10,10 +12,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
+	"novels-backend/internal/cache"
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
 	"novels-backend/internal/jobs"
 	"novels-backend/internal/orchestrator"
+	"novels-backend/internal/ratelimit"
 	"novels-backend/internal/repository"
+	"novels-backend/internal/translation"
 	"novels-backend/pkg/response"
 )
 
@@ -22,9 +28,18 @@ type OpsHandler struct {
 	scheduler        *jobs.Scheduler
 	orchestrator     *orchestrator.ImportOrchestrator
 	importRunsRepo   *repository.ImportRunsRepository
+	importJobsRepo   *repository.ImportJobsRepository
+	novelSourcesRepo *repository.NovelSourcesRepository
+	novelSyncJob     *jobs.NovelSyncJob
 	cookiesRepo      *repository.ImportRunCookiesRepository
 	translationRepo  *repository.TranslationVotingRepository
 	votingRepo       *repository.VotingRepository
+	translation      *translation.Pipeline
+	translationJobs  *repository.TranslationJobsRepository
+	outbox           *repository.OutboxRepository
+	eventBus         *events.Bus
+	cache            *cache.Store
+	rateLimiter      *ratelimit.Limiter
 	logger           zerolog.Logger
 }
 
@@ -32,18 +47,36 @@ func NewOpsHandler(
 	scheduler *jobs.Scheduler,
 	orchestrator *orchestrator.ImportOrchestrator,
 	importRunsRepo *repository.ImportRunsRepository,
+	importJobsRepo *repository.ImportJobsRepository,
+	novelSourcesRepo *repository.NovelSourcesRepository,
+	novelSyncJob *jobs.NovelSyncJob,
 	cookiesRepo *repository.ImportRunCookiesRepository,
 	translationRepo *repository.TranslationVotingRepository,
 	votingRepo *repository.VotingRepository,
+	translationPipeline *translation.Pipeline,
+	translationJobs *repository.TranslationJobsRepository,
+	outbox *repository.OutboxRepository,
+	eventBus *events.Bus,
+	cacheStore *cache.Store,
+	rateLimiter *ratelimit.Limiter,
 	logger zerolog.Logger,
 ) *OpsHandler {
 	return &OpsHandler{
 		scheduler:       scheduler,
 		orchestrator:    orchestrator,
 		importRunsRepo:  importRunsRepo,
+		importJobsRepo:  importJobsRepo,
+		novelSourcesRepo: novelSourcesRepo,
+		novelSyncJob:    novelSyncJob,
 		cookiesRepo:     cookiesRepo,
 		translationRepo: translationRepo,
 		votingRepo:      votingRepo,
+		translation:     translationPipeline,
+		translationJobs: translationJobs,
+		outbox:          outbox,
+		eventBus:        eventBus,
+		cache:           cacheStore,
+		rateLimiter:     rateLimiter,
 		logger:          logger.With().Str("handler", "ops").Logger(),
 	}
 }
@@ -119,6 +152,22 @@ func (h *OpsHandler) ListImportRuns(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, map[string]any{"runs": runs})
 }
 
+// GET /api/v1/admin/ops/import-jobs?limit=50
+// Queued and leased import jobs (the durable queue behind import runs).
+func (h *OpsHandler) ListImportJobs(w http.ResponseWriter, r *http.Request) {
+	if h.importJobsRepo == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "import jobs repo is not configured")
+		return
+	}
+	jobs, err := h.importJobsRepo.ListActive(r.Context(), parseIntQuery(r, "limit", 50))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("List import jobs failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list import jobs")
+		return
+	}
+	response.OK(w, map[string]any{"jobs": jobs})
+}
+
 // POST /api/v1/admin/ops/import-runs/{id}/cancel
 func (h *OpsHandler) CancelImportRun(w http.ResponseWriter, r *http.Request) {
 	if h.orchestrator == nil {
@@ -132,7 +181,7 @@ func (h *OpsHandler) CancelImportRun(w http.ResponseWriter, r *http.Request) {
 	}
 	ok := h.orchestrator.CancelImport(runID)
 	if !ok {
-		response.Error(w, http.StatusNotFound, "NOT_FOUND", "run is not active")
+		response.Error(w, http.StatusNotFound, "NOT_FOUND", "run is not queued or active")
 		return
 	}
 	response.OK(w, map[string]string{"message": "cancel requested"})
@@ -151,7 +200,7 @@ func (h *OpsHandler) PauseImportRun(w http.ResponseWriter, r *http.Request) {
 	}
 	ok := h.orchestrator.PauseImport(runID)
 	if !ok {
-		response.Error(w, http.StatusNotFound, "NOT_FOUND", "run is not active")
+		response.Error(w, http.StatusNotFound, "NOT_FOUND", "run is not queued or active")
 		return
 	}
 	response.OK(w, map[string]string{"message": "pause requested"})
@@ -178,7 +227,12 @@ func (h *OpsHandler) ResumeImportRun(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "run is not paused")
 		return
 	}
-	h.orchestrator.ResumeImportAsync(runID)
+	// novelctl runs execute outside the queue, only novelctl can continue them
+	if run.Origin == models.ImportRunOriginCLI {
+		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "run was started by novelctl, resume it with: novelctl import --resume "+runID.String())
+		return
+	}
+	h.orchestrator.ResumeImportAsync(r.Context(), runID)
 	response.OK(w, map[string]string{"message": "resume requested"})
 }
 
@@ -234,7 +288,7 @@ func (h *OpsHandler) RunImportNow(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	runID := h.orchestrator.StartImportAsync(pid)
+	runID := h.orchestrator.StartImportAsync(r.Context(), pid)
 	h.logger.Info().Str("proposalId", pid.String()).Str("runId", runID.String()).Msg("RunImportNow: import started")
 	response.OK(w, map[string]string{"runId": runID.String()})
 }
@@ -387,6 +441,10 @@ func (h *OpsHandler) RetryImportRun(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "run was not blocked by Cloudflare")
 		return
 	}
+	if run.ProposalID == nil {
+		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "run has no proposal, retry it with novelctl")
+		return
+	}
 	// Get cookies for this run
 	cookie, err := h.cookiesRepo.GetByRunID(r.Context(), runID)
 	if err != nil || cookie == nil {
@@ -394,6 +452,231 @@ func (h *OpsHandler) RetryImportRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Start a new import run with the same proposal
-	newRunID := h.orchestrator.StartImportAsyncWithCookies(run.ProposalID, cookie.CookieHeader)
+	newRunID := h.orchestrator.StartImportAsyncWithCookies(r.Context(), *run.ProposalID, cookie.CookieHeader)
 	response.OK(w, map[string]string{"runId": newRunID.String(), "message": "retry started"})
 }
+
+// GET /api/v1/admin/ops/novel-sources?limit=50
+// Imported novels followed at their source, with sync cadence and last sync status.
+func (h *OpsHandler) ListNovelSources(w http.ResponseWriter, r *http.Request) {
+	if h.novelSourcesRepo == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "novel sources repo is not configured")
+		return
+	}
+	sources, err := h.novelSourcesRepo.List(r.Context(), parseIntQuery(r, "limit", 50))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("List novel sources failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list novel sources")
+		return
+	}
+	response.OK(w, map[string]any{"sources": sources})
+}
+
+// PUT /api/v1/admin/ops/novel-sources/{novelId}
+func (h *OpsHandler) UpdateNovelSource(w http.ResponseWriter, r *http.Request) {
+	if h.novelSourcesRepo == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "novel sources repo is not configured")
+		return
+	}
+	novelID, err := uuid.Parse(chi.URLParam(r, "novelId"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+	var req models.UpdateNovelSourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	if req.SyncIntervalMinutes != nil && *req.SyncIntervalMinutes < 1 {
+		response.BadRequest(w, "syncIntervalMinutes must be positive")
+		return
+	}
+	if err := h.novelSourcesRepo.Update(r.Context(), novelID, req); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(w, http.StatusNotFound, "NOT_FOUND", "novel source not found")
+			return
+		}
+		h.logger.Error().Err(err).Str("novel_id", novelID.String()).Msg("Update novel source failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update novel source")
+		return
+	}
+	src, err := h.novelSourcesRepo.GetByNovelID(r.Context(), novelID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load novel source")
+		return
+	}
+	response.OK(w, map[string]any{"source": src})
+}
+
+// POST /api/v1/admin/ops/novel-sources/{novelId}/sync
+func (h *OpsHandler) SyncNovelNow(w http.ResponseWriter, r *http.Request) {
+	if h.orchestrator == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "orchestrator is not configured")
+		return
+	}
+	novelID, err := uuid.Parse(chi.URLParam(r, "novelId"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+	runID, err := h.orchestrator.StartSyncAsync(r.Context(), novelID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(w, http.StatusNotFound, "NOT_FOUND", "novel source not found")
+			return
+		}
+		if errors.Is(err, orchestrator.ErrSyncNotSupported) {
+			response.Conflict(w, err.Error())
+			return
+		}
+		h.logger.Error().Err(err).Str("novel_id", novelID.String()).Msg("SyncNovelNow failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to start sync")
+		return
+	}
+	response.OK(w, map[string]string{"runId": runID.String(), "message": "sync enqueued"})
+}
+
+// POST /api/v1/admin/ops/jobs/novel-sync/run
+func (h *OpsHandler) RunNovelSyncNow(w http.ResponseWriter, r *http.Request) {
+	if h.novelSyncJob == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "novel sync job is not configured")
+		return
+	}
+	enqueued, err := h.novelSyncJob.Run(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("RunNovelSyncNow failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to run novel sync job")
+		return
+	}
+	response.OK(w, map[string]any{"message": "novel sync job executed", "enqueued": enqueued})
+}
+
+// GET /api/v1/admin/ops/translation-jobs?novelId=...
+// Machine translation queue: per-novel/per-language counters and recent failures.
+func (h *OpsHandler) ListTranslationJobs(w http.ResponseWriter, r *http.Request) {
+	if h.translationJobs == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "translation jobs repo is not configured")
+		return
+	}
+	var novelID *uuid.UUID
+	if s := r.URL.Query().Get("novelId"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			response.BadRequest(w, "invalid novel id")
+			return
+		}
+		novelID = &id
+	}
+	stats, err := h.translationJobs.Stats(r.Context(), novelID)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Translation job stats failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load translation jobs")
+		return
+	}
+	failed, err := h.translationJobs.ListFailed(r.Context(), parseIntQuery(r, "limit", 50))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("List failed translation jobs failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load translation jobs")
+		return
+	}
+	response.OK(w, map[string]any{"stats": stats, "failed": failed})
+}
+
+// POST /api/v1/admin/ops/translation-jobs/novels/{novelId}/enqueue
+func (h *OpsHandler) EnqueueNovelTranslation(w http.ResponseWriter, r *http.Request) {
+	if h.translation == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "translation pipeline is not configured")
+		return
+	}
+	novelID, err := uuid.Parse(chi.URLParam(r, "novelId"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+	n, err := h.translation.EnqueueNovel(r.Context(), novelID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("novel_id", novelID.String()).Msg("EnqueueNovelTranslation failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to enqueue translation")
+		return
+	}
+	response.OK(w, map[string]any{"message": "translation enqueued", "jobs": n})
+}
+
+// GET /api/v1/admin/ops/outbox?limit=50
+// Returns outbox counters, delivery lag and dead-lettered events.
+func (h *OpsHandler) GetOutbox(w http.ResponseWriter, r *http.Request) {
+	if h.outbox == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "event outbox is not configured")
+		return
+	}
+	stats, err := h.outbox.Stats(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Outbox stats failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load outbox")
+		return
+	}
+	dead, err := h.outbox.ListDead(r.Context(), parseIntQuery(r, "limit", 50))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("List dead outbox events failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load outbox")
+		return
+	}
+	response.OK(w, map[string]any{"stats": stats, "dead": dead})
+}
+
+// POST /api/v1/admin/ops/outbox/{id}/retry
+// Puts a dead-lettered event back into the queue; handlers that already processed it are skipped.
+func (h *OpsHandler) RetryOutboxEvent(w http.ResponseWriter, r *http.Request) {
+	if h.outbox == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "event outbox is not configured")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid event id")
+		return
+	}
+	ok, err := h.outbox.Retry(r.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("event_id", id.String()).Msg("Retry outbox event failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to retry event")
+		return
+	}
+	if !ok {
+		response.NotFound(w, "dead-lettered event not found")
+		return
+	}
+	response.OK(w, map[string]any{"message": "event requeued"})
+}
+
+// GET /api/v1/admin/ops/events/handlers
+// Per-handler delivery counters (calls, failures, timeouts, panics, retries, latency) since startup.
+func (h *OpsHandler) ListEventHandlers(w http.ResponseWriter, r *http.Request) {
+	if h.eventBus == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "event bus is not configured")
+		return
+	}
+	response.OK(w, map[string]any{"handlers": h.eventBus.Stats()})
+}
+
+// GET /api/v1/admin/ops/cache
+// Returns per-namespace hit/miss counters of the catalog cache since the process started.
+func (h *OpsHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	backend := "none"
+	if h.cache != nil {
+		backend = h.cache.Backend().Name()
+	}
+	response.OK(w, map[string]any{"backend": backend, "namespaces": h.cache.Stats()})
+}
+
+// GET /api/v1/admin/ops/rate-limits
+// Returns the effective rate limit policies with allowed/limited counters since the process started.
+// Limits are changed via PUT /api/v1/admin/settings/rate_limits.
+func (h *OpsHandler) GetRateLimits(w http.ResponseWriter, r *http.Request) {
+	if h.rateLimiter == nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "rate limiter is not configured")
+		return
+	}
+	response.OK(w, map[string]any{"backend": h.rateLimiter.Backend(), "policies": h.rateLimiter.Stats()})
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ProfileHandler обработчик публичных профилей пользователей и редактирования своего профиля
+type ProfileHandler struct {
+	profileService *service.ProfileService
+	logger         zerolog.Logger
+}
+
+// NewProfileHandler создает новый ProfileHandler
+func NewProfileHandler(profileService *service.ProfileService, logger zerolog.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+		logger:         logger,
+	}
+}
+
+// GetProfile возвращает публичный профиль пользователя; скрытые им разделы перечислены в hidden
+// GET /api/v1/users/{id}?lang=ru
+func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid user id")
+		return
+	}
+
+	// Гость — uuid.Nil
+	viewerID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	profile, err := h.profileService.GetProfile(r.Context(), userID, viewerID, statsLang(r))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, profile)
+}
+
+// GetActivity возвращает ленту активности пользователя
+// GET /api/v1/users/{id}/activity?page=1&limit=20
+func (h *ProfileHandler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid user id")
+		return
+	}
+
+	viewerID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	page := parseIntQuery(r, "page", 1)
+	limit := parseIntQuery(r, "limit", 20)
+
+	activity, err := h.profileService.GetActivity(r.Context(), userID, viewerID, statsLang(r), page, limit)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, activity)
+}
+
+// UpdateProfile изменяет отображаемое имя и описание текущего пользователя
+// PUT /api/v1/me/profile
+func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	var req models.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.profileService.UpdateProfile(r.Context(), userID, req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeOwnProfile(w, r, userID)
+}
+
+// UpdatePrivacy открывает или скрывает разделы профиля
+// PUT /api/v1/me/profile/privacy
+func (h *ProfileHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	var req models.UpdateProfilePrivacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	privacy, err := h.profileService.UpdatePrivacy(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, privacy)
+}
+
+// UploadAvatar загружает аватар текущего пользователя (jpg, png, webp, до 2 МБ)
+// POST /api/v1/me/profile/avatar (multipart/form-data, поле "file")
+func (h *ProfileHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	// Запас на заголовки multipart; размер самого файла проверяет сервис
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxAvatarSize+(1<<20))
+	if err := r.ParseMultipartForm(service.MaxAvatarSize); err != nil {
+		response.BadRequest(w, "file too large")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, "file is required")
+		return
+	}
+	defer file.Close()
+
+	avatarURL, err := h.profileService.SetAvatar(r.Context(), userID, file)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, map[string]string{"avatarUrl": avatarURL})
+}
+
+// DeleteAvatar удаляет аватар текущего пользователя
+// DELETE /api/v1/me/profile/avatar
+func (h *ProfileHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	if err := h.profileService.RemoveAvatar(r.Context(), userID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *ProfileHandler) writeOwnProfile(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	profile, err := h.profileService.GetProfile(r.Context(), userID, userID, statsLang(r))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, profile)
+}
+
+func (h *ProfileHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidProfile), errors.Is(err, service.ErrInvalidAvatar):
+		response.BadRequest(w, err.Error())
+	case errors.Is(err, service.ErrUserNotFound):
+		response.NotFound(w, "user not found")
+	case errors.Is(err, service.ErrProfileSectionHidden):
+		response.Forbidden(w, err.Error())
+	default:
+		h.logger.Error().Err(err).Msg("Profile request failed")
+		response.InternalError(w)
+	}
+}
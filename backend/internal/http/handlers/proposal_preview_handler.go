@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/rs/zerolog"
+)
+
+// ProposalPreviewHandler обработчик предпросмотра заявки по ссылке на источник
+type ProposalPreviewHandler struct {
+	previewService *service.ProposalPreviewService
+	logger         zerolog.Logger
+}
+
+// NewProposalPreviewHandler создает новый ProposalPreviewHandler
+func NewProposalPreviewHandler(previewService *service.ProposalPreviewService, logger zerolog.Logger) *ProposalPreviewHandler {
+	return &ProposalPreviewHandler{
+		previewService: previewService,
+		logger:         logger,
+	}
+}
+
+// PreviewProposal определяет сайт по ссылке, загружает метаданные книги (без глав)
+// и возвращает черновик заявки с числом глав. Ничего не сохраняет и не списывает билеты
+// POST /api/v1/proposals/preview
+func (h *ProposalPreviewHandler) PreviewProposal(w http.ResponseWriter, r *http.Request) {
+	var req models.PreviewProposalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.OriginalLink) == "" {
+		response.BadRequest(w, "originalLink is required")
+		return
+	}
+
+	preview, err := h.previewService.Preview(r.Context(), req.OriginalLink)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidOriginalLink):
+			response.BadRequest(w, err.Error())
+		case errors.Is(err, service.ErrPreviewTimeout):
+			response.Error(w, http.StatusGatewayTimeout, "PREVIEW_TIMEOUT", err.Error())
+		case errors.Is(err, service.ErrPreviewUnavailable):
+			response.Error(w, http.StatusBadGateway, "PREVIEW_UNAVAILABLE", service.ErrPreviewUnavailable.Error())
+		default:
+			h.logger.Error().Err(err).Msg("Failed to preview proposal")
+			response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to preview proposal")
+		}
+		return
+	}
+
+	response.OK(w, preview)
+}
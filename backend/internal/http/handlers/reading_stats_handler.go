@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ReadingStatsHandler обработчик статистики чтения и итогов года
+type ReadingStatsHandler struct {
+	statsService *service.ReadingStatsService
+	logger       zerolog.Logger
+}
+
+// NewReadingStatsHandler создает новый ReadingStatsHandler
+func NewReadingStatsHandler(statsService *service.ReadingStatsService, logger zerolog.Logger) *ReadingStatsHandler {
+	return &ReadingStatsHandler{
+		statsService: statsService,
+		logger:       logger,
+	}
+}
+
+// GetMyStats возвращает статистику чтения текущего пользователя
+// GET /api/v1/me/stats?period=week|month&count=12
+func (h *ReadingStatsHandler) GetMyStats(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	h.writeStats(w, r, userID)
+}
+
+// GetUserStats возвращает статистику чтения пользователя, если он ее открыл
+// GET /api/v1/users/{id}/stats?period=week|month&count=12
+func (h *ReadingStatsHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.viewableUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeStats(w, r, userID)
+}
+
+// UpdatePrivacy открывает или скрывает статистику чтения в профиле
+// PUT /api/v1/me/stats/privacy
+func (h *ReadingStatsHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	var req models.UpdateStatsPrivacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.statsService.SetPublic(r.Context(), userID, req.Public); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, map[string]bool{"public": req.Public})
+}
+
+// GetMyYearInReview возвращает итоги года текущего пользователя
+// GET /api/v1/me/year-in-review/{year}
+func (h *ReadingStatsHandler) GetMyYearInReview(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	h.writeYearInReview(w, r, userID)
+}
+
+// GetUserYearInReview возвращает итоги года пользователя, если он открыл статистику
+// GET /api/v1/users/{id}/year-in-review/{year}
+func (h *ReadingStatsHandler) GetUserYearInReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.viewableUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeYearInReview(w, r, userID)
+}
+
+// viewableUser разбирает {id} и проверяет, что статистика пользователя доступна текущему
+func (h *ReadingStatsHandler) viewableUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid user id")
+		return uuid.Nil, false
+	}
+
+	// Гость — uuid.Nil
+	viewerID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.statsService.CanView(r.Context(), userID, viewerID); err != nil {
+		h.writeError(w, err)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (h *ReadingStatsHandler) writeStats(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	period := models.ReadingStatsPeriod(r.URL.Query().Get("period"))
+	count := parseIntQuery(r, "count", 12)
+
+	stats, err := h.statsService.GetStats(r.Context(), userID, period, count, statsLang(r))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, stats)
+}
+
+func (h *ReadingStatsHandler) writeYearInReview(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil || year < 2000 || year > 9999 {
+		response.BadRequest(w, "invalid year")
+		return
+	}
+
+	review, err := h.statsService.GetYearInReview(r.Context(), userID, year, statsLang(r))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, review)
+}
+
+func (h *ReadingStatsHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidStatsPeriod):
+		response.BadRequest(w, err.Error())
+	case errors.Is(err, service.ErrUserNotFound):
+		response.NotFound(w, "user not found")
+	case errors.Is(err, service.ErrStatsPrivate):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, service.ErrYearReviewNotReady):
+		response.NotFound(w, err.Error())
+	default:
+		h.logger.Error().Err(err).Msg("Reading stats request failed")
+		response.InternalError(w)
+	}
+}
+
+// statsLang язык названий новелл, жанров и тегов
+func statsLang(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+	return "ru"
+}
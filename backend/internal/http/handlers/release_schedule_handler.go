@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/jobs"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ReleaseScheduleHandler обработчик админских эндпоинтов расписания выпуска глав
+type ReleaseScheduleHandler struct {
+	releaseService *service.ReleaseScheduleService
+	releaseJob     *jobs.ChapterReleaseJob
+	logger         zerolog.Logger
+}
+
+// NewReleaseScheduleHandler создает новый ReleaseScheduleHandler
+func NewReleaseScheduleHandler(releaseService *service.ReleaseScheduleService, releaseJob *jobs.ChapterReleaseJob, logger zerolog.Logger) *ReleaseScheduleHandler {
+	return &ReleaseScheduleHandler{
+		releaseService: releaseService,
+		releaseJob:     releaseJob,
+		logger:         logger,
+	}
+}
+
+// GetSchedule получает расписание новеллы, очередь и ближайшие выпуски
+// GET /api/v1/admin/novels/{id}/release-schedule
+func (h *ReleaseScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	novelID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+
+	view, err := h.releaseService.Get(r.Context(), novelID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, view)
+}
+
+// SaveSchedule создает или заменяет расписание выпуска
+// PUT /api/v1/admin/novels/{id}/release-schedule
+func (h *ReleaseScheduleHandler) SaveSchedule(w http.ResponseWriter, r *http.Request) {
+	novelID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+
+	var req models.ReleaseScheduleInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	view, err := h.releaseService.Save(r.Context(), novelID, req, adminUserID(r))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, view)
+}
+
+// DeleteSchedule удаляет расписание; главы из очереди публикуются сразу
+// DELETE /api/v1/admin/novels/{id}/release-schedule
+func (h *ReleaseScheduleHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	novelID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid novel id")
+		return
+	}
+
+	released, err := h.releaseService.Delete(r.Context(), novelID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, map[string]any{"message": "release schedule deleted", "published": released})
+}
+
+// RunNow запускает выпуск глав из очередей немедленно
+// POST /api/v1/admin/ops/jobs/chapter-release/run
+func (h *ReleaseScheduleHandler) RunNow(w http.ResponseWriter, r *http.Request) {
+	released, err := h.releaseJob.Run(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Chapter release job failed")
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to run chapter release job")
+		return
+	}
+
+	response.OK(w, map[string]any{"message": "chapter release job executed", "scheduled": released})
+}
+
+func (h *ReleaseScheduleHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrNovelNotFound):
+		response.NotFound(w, "novel not found")
+	case errors.Is(err, service.ErrNotFound):
+		response.NotFound(w, "release schedule not found")
+	case errors.Is(err, service.ErrInvalidReleaseSchedule):
+		response.BadRequest(w, err.Error())
+	default:
+		h.logger.Error().Err(err).Msg("Release schedule request failed")
+		response.InternalError(w)
+	}
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// RetranslationHandler handles chapter retranslation requests
+type RetranslationHandler struct {
+	retranslationService *service.RetranslationService
+}
+
+// NewRetranslationHandler creates a new retranslation handler
+func NewRetranslationHandler(retranslationService *service.RetranslationService) *RetranslationHandler {
+	return &RetranslationHandler{
+		retranslationService: retranslationService,
+	}
+}
+
+// CreateRequest flags a chapter for retranslation (subscription feature)
+// POST /chapters/{id}/retranslation-requests
+func (h *RetranslationHandler) CreateRequest(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "Not authenticated")
+		return
+	}
+
+	chapterID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid chapter ID")
+		return
+	}
+
+	var req models.CreateRetranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body")
+		return
+	}
+
+	created, err := h.retranslationService.CreateRequest(r.Context(), userID, chapterID, req)
+	if err != nil {
+		writeRetranslationError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, created)
+}
+
+// GetMyRequests returns the current user's requests and remaining allowance
+// GET /me/retranslation-requests
+func (h *RetranslationHandler) GetMyRequests(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "Not authenticated")
+		return
+	}
+
+	list, err := h.retranslationService.ListRequests(r.Context(), nil, &userID, parseIntQuery(r, "page", 1), parseIntQuery(r, "limit", 20))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get retranslation requests")
+		return
+	}
+
+	// No quota without a subscription that has the feature; the list is still returned.
+	quota, err := h.retranslationService.GetQuota(r.Context(), userID)
+	if err != nil && !errors.Is(err, service.ErrRetranslationNotAllowed) {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get retranslation quota")
+		return
+	}
+
+	response.OK(w, map[string]any{
+		"requests":   list.Requests,
+		"total":      list.Total,
+		"page":       list.Page,
+		"limit":      list.Limit,
+		"totalPages": list.TotalPages,
+		"quota":      quota,
+	})
+}
+
+// GetMyTransactions returns the current user's retranslation quota ledger
+// GET /me/retranslation-requests/transactions
+func (h *RetranslationHandler) GetMyTransactions(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "Not authenticated")
+		return
+	}
+
+	transactions, err := h.retranslationService.ListTransactions(r.Context(), userID, parseIntQuery(r, "limit", 50))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get transactions")
+		return
+	}
+
+	response.OK(w, map[string]any{"transactions": transactions})
+}
+
+// GetPendingRequests returns the moderation queue
+// GET /moderation/retranslation-requests?status=pending
+func (h *RetranslationHandler) GetPendingRequests(w http.ResponseWriter, r *http.Request) {
+	status := models.RetranslationStatusPending
+	if s := r.URL.Query().Get("status"); s != "" {
+		status = models.RetranslationRequestStatus(s)
+	}
+
+	list, err := h.retranslationService.ListRequests(r.Context(), &status, nil, parseIntQuery(r, "page", 1), parseIntQuery(r, "limit", 20))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get retranslation requests")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, list)
+}
+
+// AcceptRequest accepts a request and re-runs translation of the chapter (moderator/admin)
+// POST /moderation/retranslation-requests/{id}/accept
+func (h *RetranslationHandler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "Not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid retranslation request ID")
+		return
+	}
+
+	var req models.ReviewRetranslationRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	accepted, err := h.retranslationService.Accept(r.Context(), id, userID, req.Comment)
+	if err != nil {
+		writeRetranslationError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, accepted)
+}
+
+// RejectRequest rejects a request (moderator/admin)
+// POST /moderation/retranslation-requests/{id}/reject
+func (h *RetranslationHandler) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "Not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid retranslation request ID")
+		return
+	}
+
+	var req models.ReviewRetranslationRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	if err := h.retranslationService.Reject(r.Context(), id, userID, req.Comment); err != nil {
+		writeRetranslationError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "rejected"})
+}
+
+func writeRetranslationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrRetranslationNotAllowed):
+		response.Error(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, service.ErrRetranslationQuotaExceeded):
+		response.Error(w, http.StatusTooManyRequests, "QUOTA_EXCEEDED", err.Error())
+	case errors.Is(err, service.ErrRetranslationExists):
+		response.Error(w, http.StatusConflict, "CONFLICT", err.Error())
+	case errors.Is(err, service.ErrInvalidRetranslation):
+		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+	case errors.Is(err, service.ErrChapterNotFound):
+		response.Error(w, http.StatusNotFound, "NOT_FOUND", "Chapter not found")
+	case errors.Is(err, service.ErrNotFound):
+		response.Error(w, http.StatusNotFound, "NOT_FOUND", "Retranslation request not found")
+	case errors.Is(err, service.ErrInvalidAction):
+		response.Error(w, http.StatusConflict, "CONFLICT", "Retranslation request is not pending")
+	default:
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to process retranslation request")
+	}
+}
@@ -159,10 +159,25 @@ func (h *SitemapHandler) SitemapChapters(w http.ResponseWriter, r *http.Request)
 		URLs:  []SitemapURL{},
 	}
 
-	// TODO: Implement GetAllForSitemap in ChapterRepository
-	// For now, return empty sitemap
-	// This method will be implemented later when needed
-	h.logger.Warn().Msg("SitemapChapters: GetAllForSitemap not implemented, returning empty sitemap")
+	// Only released chapters: queued and scheduled ones must not leak through the sitemap.
+	// A sitemap holds at most 50000 URLs.
+	chapters, err := h.chapterRepo.ListForSitemap(r.Context(), 50000/len(h.languages))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get chapters for sitemap")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	for _, ch := range chapters {
+		for _, lang := range h.languages {
+			sitemap.URLs = append(sitemap.URLs, SitemapURL{
+				Loc:        fmt.Sprintf("%s/%s/novel/%s/chapter/%s", h.baseURL, lang, ch.NovelSlug, ch.ID),
+				LastMod:    ch.PublishedAt.Format("2006-01-02"),
+				ChangeFreq: "monthly",
+				Priority:   "0.6",
+			})
+		}
+	}
 
 	h.writeXML(w, sitemap)
 }
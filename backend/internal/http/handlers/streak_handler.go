@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
+	"novels-backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// StreakHandler обработчик серий ежедневной активности текущего пользователя
+type StreakHandler struct {
+	streakService *service.StreakService
+	logger        zerolog.Logger
+}
+
+// NewStreakHandler создает новый StreakHandler
+func NewStreakHandler(streakService *service.StreakService, logger zerolog.Logger) *StreakHandler {
+	return &StreakHandler{
+		streakService: streakService,
+		logger:        logger,
+	}
+}
+
+// GetMyStreak возвращает текущую и лучшую серию, заморозки и следующую награду
+// GET /api/v1/me/streak
+func (h *StreakHandler) GetMyStreak(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	info, err := h.streakService.GetStreak(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, info)
+}
+
+// PurchaseFreeze покупает заморозку серии за тикеты
+// POST /api/v1/me/streak/freezes
+func (h *StreakHandler) PurchaseFreeze(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	info, err := h.streakService.PurchaseFreeze(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, info)
+}
+
+// UpdateTimezone задает часовой пояс, по которому считаются дни серии
+// PUT /api/v1/me/timezone
+func (h *StreakHandler) UpdateTimezone(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	var req models.UpdateTimezoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.streakService.SetTimezone(r.Context(), userID, req.Timezone); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	info, err := h.streakService.GetStreak(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	response.OK(w, info)
+}
+
+func (h *StreakHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTimezone):
+		response.BadRequest(w, err.Error())
+	case errors.Is(err, service.ErrInsufficientTickets):
+		response.Error(w, http.StatusPaymentRequired, "PAYMENT_REQUIRED", "Insufficient tickets for a streak freeze")
+	case errors.Is(err, service.ErrStreakFreezeLimit):
+		response.Conflict(w, err.Error())
+	default:
+		h.logger.Error().Err(err).Msg("Streak request failed")
+		response.InternalError(w)
+	}
+}
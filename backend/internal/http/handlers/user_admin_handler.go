@@ -3,9 +3,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/http/middleware"
+	"novels-backend/internal/service"
 	"novels-backend/pkg/response"
 
 	"github.com/go-chi/chi/v5"
@@ -23,12 +26,16 @@ type UserRepository interface {
 
 // UserAdminHandler обработчик админских эндпоинтов для пользователей
 type UserAdminHandler struct {
-	userRepo UserRepository
+	userRepo         UserRepository
+	xpService        *service.XPService
+	readVerification *service.ReadVerificationService
 }
 
-func NewUserAdminHandler(userRepo UserRepository) *UserAdminHandler {
+func NewUserAdminHandler(userRepo UserRepository, xpService *service.XPService, readVerification *service.ReadVerificationService) *UserAdminHandler {
 	return &UserAdminHandler{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		xpService:        xpService,
+		readVerification: readVerification,
 	}
 }
 
@@ -63,7 +70,7 @@ func (h *UserAdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetUser получает пользователя по ID
+// GetUser получает пользователя по ID вместе с оценкой подозрительной активности чтения
 // GET /api/v1/admin/users/{id}
 func (h *UserAdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -83,7 +90,74 @@ func (h *UserAdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.OK(w, user)
+	view := models.AdminUserView{UserWithProfile: *user}
+	if h.readVerification != nil {
+		report, err := h.readVerification.Report(r.Context(), id)
+		if err != nil {
+			response.InternalError(w)
+			return
+		}
+		view.ReadActivity = report
+	}
+
+	response.OK(w, view)
+}
+
+// ListXPEvents получает историю XP пользователя (включая отозванные события)
+// GET /api/v1/admin/users/{id}/xp-events
+func (h *UserAdminHandler) ListXPEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid user id")
+		return
+	}
+
+	page := parseIntQuery(r, "page", 1)
+	limit := parseIntQuery(r, "limit", 50)
+	events, err := h.xpService.GetXPEvents(r.Context(), userID, page, limit)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"events": events,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+// ClawbackXP отзывает XP-события пользователя (например, накрученные прочтения глав)
+// POST /api/v1/admin/users/{id}/xp-events/clawback
+func (h *UserAdminHandler) ClawbackXP(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid user id")
+		return
+	}
+	adminID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "unauthorized")
+		return
+	}
+
+	var req models.ClawbackXPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	result, err := h.xpService.Clawback(r.Context(), userID, adminID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidClawback) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, result)
 }
 
 // BanUser блокирует пользователя
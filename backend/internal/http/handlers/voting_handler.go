@@ -70,12 +70,20 @@ func (h *VotingHandler) CreateProposal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	proposal, err := h.votingService.CreateProposal(r.Context(), userID, req)
+	if req.OnDuplicate != "" && req.OnDuplicate != models.OnDuplicateReject && req.OnDuplicate != models.OnDuplicateMerge {
+		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", "onDuplicate must be reject or merge")
+		return
+	}
+	
+	proposal, merged, err := h.votingService.CreateProposal(r.Context(), userID, req)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidOriginalLink) {
 			response.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
 			return
 		}
+		if writeDuplicateError(w, err) {
+			return
+		}
 		if err == service.ErrInsufficientTickets {
 			response.Error(w, http.StatusPaymentRequired, "PAYMENT_REQUIRED", "Insufficient novel request tickets")
 			return
@@ -85,9 +93,33 @@ func (h *VotingHandler) CreateProposal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	// Merged into an existing proposal: nothing was created and no ticket was spent
+	if merged {
+		response.JSON(w, http.StatusOK, proposal)
+		return
+	}
 	response.JSON(w, http.StatusCreated, proposal)
 }
 
+// writeDuplicateError writes 409 with pointers to the existing proposals and novels
+// if err is a duplicate error
+func writeDuplicateError(w http.ResponseWriter, err error) bool {
+	var dup *service.DuplicateError
+	switch {
+	case errors.As(err, &dup) && errors.Is(err, service.ErrNovelAlreadyExists):
+		response.ErrorWithData(w, http.StatusConflict, "NOVEL_EXISTS", err.Error(), dup.Matches)
+	case errors.As(err, &dup) && errors.Is(err, service.ErrSimilarTitlesExist):
+		response.ErrorWithData(w, http.StatusConflict, "SIMILAR_TITLES", err.Error(), dup.Matches)
+	case errors.As(err, &dup):
+		response.ErrorWithData(w, http.StatusConflict, "DUPLICATE_PROPOSAL", err.Error(), dup.Matches)
+	case errors.Is(err, service.ErrDuplicateProposal):
+		response.Error(w, http.StatusConflict, "DUPLICATE_PROPOSAL", err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
 // GetProposal returns a proposal by ID
 // GET /api/v1/proposals/{id}
 func (h *VotingHandler) GetProposal(w http.ResponseWriter, r *http.Request) {
@@ -223,6 +255,9 @@ func (h *VotingHandler) UpdateProposal(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
 			return
 		}
+		if writeDuplicateError(w, err) {
+			return
+		}
 		h.logger.Error().Err(err).Msg("Failed to update proposal")
 		response.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
 		return
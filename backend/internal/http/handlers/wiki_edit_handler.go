@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -60,6 +61,10 @@ func (h *WikiEditHandler) CreateEditRequest(w http.ResponseWriter, r *http.Reque
 			response.Error(w, http.StatusForbidden, "FORBIDDEN", "Premium subscription required")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidGlossaryTerm) {
+			response.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create edit request")
 		return
 	}
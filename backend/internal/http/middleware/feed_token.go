@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"novels-backend/pkg/response"
+)
+
+// FeedTokenResolver находит владельца токена ленты (nil — токен неизвестен или отозван)
+type FeedTokenResolver interface {
+	Resolve(ctx context.Context, token string) (*uuid.UUID, error)
+}
+
+// FeedToken аутентифицирует по токену ленты вместо JWT: читалки (OPDS, Atom) не умеют Bearer.
+// Токен берется из параметра ?token= или из пароля HTTP Basic. Без токена запрос идет анонимно,
+// с неверным или отозванным токеном — 401, чтобы пользователь увидел, что ссылка больше не работает.
+func FeedToken(resolver FeedTokenResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get("token")
+			if token == "" {
+				if _, password, ok := r.BasicAuth(); ok {
+					token = password
+				}
+			}
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := resolver.Resolve(r.Context(), token)
+			if err != nil {
+				response.InternalError(w)
+				return
+			}
+			if userID == nil {
+				w.Header().Set("WWW-Authenticate", `Basic realm="feeds"`)
+				response.Error(w, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or revoked feed token")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserIDKey, userID.String())
+			ctx = context.WithValue(ctx, UserRolesKey, []string{"user"})
+			ctx = context.WithValue(ctx, UserRoleKey, "user")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"novels-backend/internal/ratelimit"
+	"novels-backend/pkg/response"
+)
+
+// RateLimit ограничивает частоту запросов по политике policy (token bucket).
+// Ключ — IP клиента или ID пользователя (анонимные запросы считаются по IP), поэтому для политик
+// by=user middleware ставится после OptionalAuth/Authenticate.
+// Ответ несет заголовки RateLimit-Limit/-Remaining/-Reset, при превышении — 429 и Retry-After.
+// Если хранилище лимитов недоступно, запрос пропускается.
+func RateLimit(limiter *ratelimit.Limiter, policy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := limiter.Policy(policy)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			subject := "ip:" + clientIP(r)
+			if p.By == ratelimit.KeyByUser {
+				if userID := GetUserID(r.Context()); userID != "" {
+					subject = "user:" + userID
+				}
+			}
+
+			res, applied := limiter.Allow(r.Context(), policy, subject)
+			if !applied {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", p.Limit, int(p.Period.Seconds())))
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				response.TooManyRequests(w, "Too many requests, try again later")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP возвращает IP клиента; за доверенным прокси RemoteAddr уже подменен RealIP
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
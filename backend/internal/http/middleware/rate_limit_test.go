@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/ratelimit"
+)
+
+func newLoginLimiter(t *testing.T, trusted ...string) http.Handler {
+	t.Helper()
+	nets, err := ParseTrustedProxies(trusted)
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+	limiter := ratelimit.New(ratelimit.NewMemory(), map[string]ratelimit.Policy{
+		"auth_login": {Limit: 2, Period: time.Hour, By: ratelimit.KeyByIP},
+	}, zerolog.Nop())
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	return RealIP(nets)(RateLimit(limiter, "auth_login")(ok))
+}
+
+func login(h http.Handler, remoteAddr string, headers map[string]string) int {
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRateLimitIgnoresSpoofedHeadersFromUntrustedPeer(t *testing.T) {
+	h := newLoginLimiter(t)
+
+	for i := 0; i < 3; i++ {
+		code := login(h, "203.0.113.5:40000", map[string]string{
+			"X-Forwarded-For": fmt.Sprintf("198.51.100.%d", i),
+			"X-Real-IP":       fmt.Sprintf("198.51.100.%d", 100+i),
+			"True-Client-IP":  fmt.Sprintf("198.51.100.%d", 200+i),
+		})
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if code != want {
+			t.Fatalf("request %d: status %d, want %d", i, code, want)
+		}
+	}
+}
+
+func TestRateLimitUsesRightmostUntrustedHopBehindProxy(t *testing.T) {
+	h := newLoginLimiter(t, "10.0.0.0/8")
+
+	// The client prepends a fresh fake address each time; the proxy appends the real one.
+	for i := 0; i < 3; i++ {
+		code := login(h, "10.0.0.2:50000", map[string]string{
+			"X-Forwarded-For": fmt.Sprintf("198.51.100.%d, 203.0.113.5", i),
+		})
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if code != want {
+			t.Fatalf("request %d: status %d, want %d", i, code, want)
+		}
+	}
+
+	// Another client behind the same proxy has its own bucket.
+	if code := login(h, "10.0.0.2:50000", map[string]string{"X-Forwarded-For": "203.0.113.9"}); code != http.StatusOK {
+		t.Fatalf("other client: status %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestForwardedIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{"untrusted peer", "203.0.113.5:1", map[string]string{"X-Forwarded-For": "198.51.100.1"}, ""},
+		{"trusted peer, single hop", "10.1.2.3:1", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "198.51.100.1"},
+		{"skips trusted hops", "10.1.2.3:1", map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.1, 192.0.2.1, 10.0.0.7"}, "198.51.100.1"},
+		{"all hops trusted", "10.1.2.3:1", map[string]string{"X-Forwarded-For": "10.0.0.9, 10.0.0.7"}, "10.0.0.9"},
+		{"garbage stops the walk", "10.1.2.3:1", map[string]string{"X-Forwarded-For": "198.51.100.1, junk"}, ""},
+		{"falls back to X-Real-IP", "192.0.2.1:1", map[string]string{"X-Real-IP": "198.51.100.2"}, "198.51.100.2"},
+		{"no headers", "10.1.2.3:1", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if got := forwardedIP(req, trusted); got != tt.want {
+				t.Errorf("forwardedIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsInvalid(t *testing.T) {
+	if _, err := ParseTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected error for invalid CIDR")
+	}
+	if _, err := ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("expected error for hostname")
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseTrustedProxies разбирает список доверенных прокси: CIDR (10.0.0.0/8) или отдельные IP
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", v)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", v, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// RealIP подставляет в RemoteAddr адрес клиента из X-Forwarded-For/X-Real-IP, но только если
+// запрос пришел от доверенного прокси (TCP-адрес в trusted). Из X-Forwarded-For берется самый
+// правый недоверенный адрес: левые элементы клиент может подделать, правые дописали наши прокси.
+// Запросы не от доверенных прокси остаются с адресом TCP-соединения, их заголовки игнорируются.
+func RealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := forwardedIP(r, trusted); ip != "" {
+				r.RemoteAddr = ip
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP возвращает адрес клиента из заголовков прокси или "", если им нельзя верить
+func forwardedIP(r *http.Request, trusted []*net.IPNet) string {
+	if !isTrusted(net.ParseIP(clientIP(r)), trusted) {
+		return ""
+	}
+
+	var hops []string
+	for _, h := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(h, ",")...)
+	}
+	first := ""
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			// Мусор в цепочке — дальше нее доверять нечему
+			break
+		}
+		if !isTrusted(ip, trusted) {
+			return ip.String()
+		}
+		first = ip.String()
+	}
+	if first != "" {
+		// Вся цепочка из наших прокси — клиент внутри сети
+		return first
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+func isTrusted(ip net.IP, trusted []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
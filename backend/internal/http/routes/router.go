@@ -2,33 +2,61 @@ package routes
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
+	"novels-backend/internal/cache"
 	"novels-backend/internal/config"
+	"novels-backend/internal/database"
 	"novels-backend/internal/events"
+	"novels-backend/internal/health"
 	"novels-backend/internal/http/handlers"
 	"novels-backend/internal/http/middleware"
 	"novels-backend/internal/jobs"
 	"novels-backend/internal/orchestrator"
 	"novels-backend/internal/orchestrator/importers"
+	"novels-backend/internal/parserclient"
+	"novels-backend/internal/ratelimit"
 	"novels-backend/internal/repository"
 	"novels-backend/internal/service"
+	"novels-backend/internal/telemetry"
+	"novels-backend/internal/translation"
 
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 )
 
+// defaultRateLimits лимиты по умолчанию; переопределяются настройкой rate_limits в app_settings
+var defaultRateLimits = map[string]ratelimit.Policy{
+	"auth_login":       {Limit: 10, Period: time.Minute, By: ratelimit.KeyByIP},
+	"auth_register":    {Limit: 5, Period: time.Hour, By: ratelimit.KeyByIP},
+	"comments_create":  {Limit: 10, Period: time.Minute, By: ratelimit.KeyByUser},
+	"votes":            {Limit: 30, Period: time.Minute, By: ratelimit.KeyByUser},
+	"proposal_preview": {Limit: 10, Period: time.Minute, By: ratelimit.KeyByUser},
+	"upload":           {Limit: 20, Period: time.Hour, By: ratelimit.KeyByUser},
+}
+
 // NewRouter создает новый роутер с настроенными маршрутами
 func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handler, *jobs.Scheduler) {
 	r := chi.NewRouter()
 
+	// Адрес клиента из X-Forwarded-For берется только от доверенных прокси, иначе его можно
+	// подменить и обойти лимиты по IP
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid TRUSTED_PROXIES")
+	}
+
 	// Глобальные middleware
 	r.Use(chiMiddleware.RequestID)
-	r.Use(chiMiddleware.RealIP)
+	r.Use(middleware.RealIP(trustedProxies))
+	r.Use(middleware.Telemetry())
 	r.Use(middleware.Logger(log))
 	r.Use(chiMiddleware.Recoverer)
 	r.Use(chiMiddleware.Timeout(60 * time.Second))
@@ -37,8 +65,8 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   cfg.CORS.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "Accept-Language"},
-		ExposedHeaders:   []string{"Link", "X-Request-ID"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "Accept-Language", "traceparent", "tracestate"},
+		ExposedHeaders:   []string{"Link", "X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -51,6 +79,7 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 	commentRepo := repository.NewCommentRepository(db)
 	bookmarkRepo := repository.NewBookmarkRepository(db)
 	xpRepo := repository.NewXPRepository(db)
+	achievementRepo := repository.NewAchievementRepository(db)
 	ticketRepo := repository.NewTicketRepository(db)
 	votingRepo := repository.NewVotingRepository(db)
 	translationVotingRepo := repository.NewTranslationVotingRepository(db)
@@ -62,26 +91,75 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 	genreRepo := repository.NewGenreRepository(db)
 	tagRepo := repository.NewTagRepository(db)
 	adminRepo := repository.NewAdminRepository(db)
+	glossaryRepo := repository.NewGlossaryRepository(db)
+	chapterRevisionsRepo := repository.NewChapterRevisionsRepository(db)
+	feedTokenRepo := repository.NewFeedTokenRepository(db)
+	releaseScheduleRepo := repository.NewReleaseScheduleRepository(db)
+	sourceLinksRepo := repository.NewSourceLinksRepository(db)
+	streakRepo := repository.NewStreakRepository(db)
+	readSessionRepo := repository.NewReadSessionRepository(db)
+	readingStatsRepo := repository.NewReadingStatsRepository(db)
+	profileRepo := repository.NewProfileRepository(db)
+	// Импортёры сайтов: очередь импорта и поиск дубликатов заявок по каноническому URL
+	sourceImporters := orchestrator.Importers(importers.All())
 
 	// Инициализация сервисов
-	authService := service.NewAuthService(userRepo, cfg)
-	xpService := service.NewXPService(xpRepo)
-	novelService := service.NewNovelService(novelRepo)
-	chapterService := service.NewChapterService(chapterRepo, novelRepo, progressRepo)
-	commentService := service.NewCommentService(commentRepo, xpService)
-	bookmarkService := service.NewBookmarkService(bookmarkRepo, novelRepo, xpService)
+	eventBus := events.NewBus(log)
+	xpService := service.NewXPService(xpRepo, eventBus)
 	ticketService := service.NewTicketService(ticketRepo, subscriptionRepo, log)
-	eventBus := events.NewBus()
-	votingService := service.NewVotingService(votingRepo, ticketRepo, eventBus, log)
+	// Серии активности: вход и чтение глав, XP за ежедневный вход, награды и заморозки
+	streakService := service.NewStreakService(streakRepo, xpService, ticketService, cfg.Streaks.DefaultTimezone,
+		cfg.Streaks.FreezeTicketType, cfg.Streaks.FreezePrice, cfg.Streaks.MaxFreezes, log)
+	// Проверка прочтения глав: XP за главу только после времени на главе, с дневными лимитами
+	readVerificationService := service.NewReadVerificationService(readSessionRepo, xpService, service.ReadVerificationOptions{
+		DwellRatio:      cfg.ReadXP.DwellRatio,
+		MinDwell:        cfg.ReadXP.MinDwell,
+		NovelDailyCap:   cfg.ReadXP.NovelDailyCap,
+		DailyCap:        cfg.ReadXP.DailyCap,
+		SuspicionWindow: cfg.ReadXP.SuspicionWindow,
+	}, log)
+	// Статистика чтения: время по дням из сохранений прогресса, итоги года
+	readingStatsService := service.NewReadingStatsService(readingStatsRepo, streakService, cfg.ReadingStats.IdleGap, log)
+	authService := service.NewAuthService(userRepo, streakService, cfg)
+	// Ачивки: условия из achievements.condition проверяются по событиям XP
+	achievementService := service.NewAchievementService(achievementRepo, xpService, log)
+	achievementService.Register(eventBus)
+	commentService := service.NewCommentService(commentRepo, xpService, eventBus)
+	bookmarkService := service.NewBookmarkService(bookmarkRepo, novelRepo, xpService)
+	outboxRepo := repository.NewOutboxRepository(db, cfg.Events.OutboxMaxAttempts)
+	outboxDispatcher := events.NewDispatcher(eventBus, outboxRepo, events.DispatcherOptions{
+		PollInterval: cfg.Events.OutboxPollInterval,
+		Retention:    cfg.Events.OutboxRetention,
+		WorkerID:     cfg.Imports.WorkerID,
+	}, log)
+	// Кэш витрин каталога; сбрасывается событиями catalog_changed и proposal_released
+	redisClient := connectRedis(cfg, log)
+	cacheStore := newCacheStore(cfg.Cache, redisClient, log)
+	catalogCache := service.NewCatalogCache(cacheStore, eventBus, cfg.Cache.CatalogTTL, cfg.Cache.StatsTTL, log)
+	catalogCache.Register(eventBus)
+	rateLimiter := newRateLimiter(cfg.RateLimit, redisClient, log)
+	novelService := service.NewNovelService(novelRepo, catalogCache)
+	votingService := service.NewVotingService(votingRepo, ticketRepo, sourceLinksRepo, sourceImporters, eventBus, log)
 	translationVotingService := service.NewTranslationVotingService(translationVotingRepo, votingRepo, ticketRepo, eventBus, log)
 	subscriptionService := service.NewSubscriptionService(subscriptionRepo, ticketRepo, log)
+	chapterService := service.NewChapterService(chapterRepo, novelRepo, progressRepo, readVerificationService, streakService, readingStatsService, subscriptionService, catalogCache)
 	collectionService := service.NewCollectionService(collectionRepo, novelRepo, userRepo)
 	newsService := service.NewNewsService(newsRepo, userRepo)
-	wikiEditService := service.NewWikiEditService(wikiEditRepo, novelRepo, userRepo, subscriptionService)
+	wikiEditService := service.NewWikiEditService(wikiEditRepo, novelRepo, userRepo, glossaryRepo, subscriptionService, catalogCache)
 	authorService := service.NewAuthorService(authorRepo)
-	genreService := service.NewGenreService(genreRepo)
-	tagService := service.NewTagService(tagRepo)
+	genreService := service.NewGenreService(genreRepo, catalogCache)
+	tagService := service.NewTagService(tagRepo, catalogCache)
 	adminService := service.NewAdminService(adminRepo)
+	adminService.ValidateSetting(ratelimit.SettingKey, func(value json.RawMessage) error {
+		_, err := ratelimit.ParsePolicies(value)
+		return err
+	})
+	glossaryService := service.NewGlossaryService(glossaryRepo, novelRepo, cfg.Translation.SourceLang)
+	chapterRevisionService := service.NewChapterRevisionService(chapterRevisionsRepo, chapterRepo)
+	feedTokenService := service.NewFeedTokenService(feedTokenRepo)
+	exportService := service.NewExportService(novelRepo, chapterRepo, authorRepo, subscriptionService, cfg.UploadsDir,
+		cfg.Export.FreeMaxChapters, cfg.Export.MaxChapters, cfg.Export.CacheTTL)
+	releaseScheduleService := service.NewReleaseScheduleService(releaseScheduleRepo, novelRepo, cfg.Releases.PlanAhead)
 
 	// Инициализация обработчиков
 	authHandler := handlers.NewAuthHandler(authService)
@@ -99,16 +177,31 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 	wikiEditHandler := handlers.NewWikiEditHandler(wikiEditService)
 	authorHandler := handlers.NewAuthorAdminHandler(authorService)
 	genreTagHandler := handlers.NewGenreTagAdminHandler(genreService, tagService)
-	userAdminHandler := handlers.NewUserAdminHandler(userRepo)
+	userAdminHandler := handlers.NewUserAdminHandler(userRepo, xpService, readVerificationService)
 	commentAdminHandler := handlers.NewCommentAdminHandler(commentRepo)
 	adminSystemHandler := handlers.NewAdminSystemHandler(adminService)
+	glossaryHandler := handlers.NewGlossaryAdminHandler(glossaryService)
+	chapterRevisionHandler := handlers.NewChapterRevisionHandler(chapterRevisionService)
+	exportHandler := handlers.NewExportHandler(exportService, log)
+	feedTokenHandler := handlers.NewFeedTokenHandler(feedTokenService)
+	feedService := service.NewFeedService(novelRepo, chapterRepo, newsService)
+	feedHandler := handlers.NewFeedHandler(feedService, log)
+	opdsHandler := handlers.NewOPDSHandler(novelService, bookmarkService, cfg.Export.FreeMaxChapters, log)
 	uploadHandler := handlers.NewUploadHandler(cfg.UploadsDir)
 	importRunsRepo := repository.NewImportRunsRepository(db)
 	cookiesRepo := repository.NewImportRunCookiesRepository(db)
+	importJobsRepo := repository.NewImportJobsRepository(db)
+	novelSourcesRepo := repository.NewNovelSourcesRepository(db)
+	translationJobsRepo := repository.NewTranslationJobsRepository(db)
+	retranslationRepo := repository.NewRetranslationRepository(db)
 
 	// Job scheduler (daily grants, etc.)
 	scheduler := jobs.NewScheduler(db, ticketService, votingService, translationVotingService, subscriptionService, log)
 	jobsHandler := handlers.NewJobsHandler(scheduler, log)
+	// Доставка событий из event_outbox подписчикам (at least once)
+	scheduler.AddWorker(outboxDispatcher)
+	// Лимиты запросов из app_settings (rate_limits)
+	scheduler.AddWorker(ratelimit.NewReloader(rateLimiter, adminRepo, cfg.RateLimit.ReloadInterval, log))
 
 	// ============================================
 	// Orchestration: parent (voting) -> events -> child (importers/parsers)
@@ -117,33 +210,99 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 		db,
 		votingRepo,
 		importRunsRepo,
+		importJobsRepo,
 		cookiesRepo,
+		novelSourcesRepo,
+		sourceLinksRepo,
 		eventBus,
 		cfg.UploadsDir,
-		[]orchestrator.ProposalImporter{
-			importers.Shuba69Importer{},
-			importers.Kks101Importer{},
-			importers.TaduImporter{},
+		sourceImporters,
+		orchestrator.QueueOptions{
+			WorkerID:           cfg.Imports.WorkerID,
+			PollInterval:       cfg.Imports.PollInterval,
+			LeaseDuration:      cfg.Imports.LeaseDuration,
+			HeartbeatInterval:  cfg.Imports.HeartbeatInterval,
+			DefaultConcurrency: cfg.Imports.DefaultConcurrency,
+			Concurrency:        cfg.Imports.Concurrency,
 		},
 		log,
 	)
 	impOrch.Register()
-
-	opsHandler := handlers.NewOpsHandler(scheduler, impOrch, importRunsRepo, cookiesRepo, translationVotingRepo, votingRepo, log)
+	scheduler.AddWorker(impOrch)
+	// Предпросмотр заявки: метаданные книги из parser-service без глав, кэш по ссылке
+	proposalPreviewService := service.NewProposalPreviewService(impOrch, cacheStore, cfg.Imports.PreviewCacheTTL, cfg.Imports.PreviewTimeout, log)
+	proposalPreviewHandler := handlers.NewProposalPreviewHandler(proposalPreviewService, log)
+	novelSyncJob := jobs.NewNovelSyncJob(novelSourcesRepo, impOrch, cfg.Imports.SyncCheckInterval, log)
+	scheduler.AddWorker(novelSyncJob)
+	chapterReleaseJob := jobs.NewChapterReleaseJob(releaseScheduleService, cfg.Releases.CheckInterval, log)
+	scheduler.AddWorker(chapterReleaseJob)
+	releaseScheduleHandler := handlers.NewReleaseScheduleHandler(releaseScheduleService, chapterReleaseJob, log)
+	achievementBackfillJob := jobs.NewAchievementBackfillJob(achievementService, cfg.Achievements.BackfillInterval, log)
+	scheduler.AddWorker(achievementBackfillJob)
+	profileService := service.NewProfileService(profileRepo, collectionRepo, bookmarkRepo, cfg.UploadsDir, log)
+	achievementHandler := handlers.NewAchievementHandler(achievementService, profileService, achievementBackfillJob, log)
+	streakHandler := handlers.NewStreakHandler(streakService, log)
+	scheduler.AddWorker(jobs.NewYearReviewJob(readingStatsService, cfg.ReadingStats.YearReviewInterval, log))
+	readingStatsHandler := handlers.NewReadingStatsHandler(readingStatsService, log)
+	profileHandler := handlers.NewProfileHandler(profileService, log)
 
 	// When proposal is released into a novel, translation voting should immediately
 	// move waiting_release -> translating (if it already won translation voting).
-	eventBus.Subscribe(events.EventProposalReleased, func(ctx context.Context, evt events.Event) error {
+	eventBus.Subscribe(events.EventProposalReleased, "translation_voting.bind_released", func(ctx context.Context, evt events.Event) error {
 		e := evt.(events.ProposalReleased)
 		return translationVotingService.OnProposalReleased(ctx, e.ProposalID, e.NovelID)
+	}, events.WithRetry(3, time.Second))
+
+	// Machine translation: translating targets -> translation_jobs -> chapter_contents (source=auto).
+	// Registered after OnProposalReleased so a released novel is already in `translating`.
+	translator, err := translation.NewTranslator(translation.ProviderOptions{
+		Provider: cfg.Translation.Provider,
+		HTTPURL:  cfg.Translation.HTTPURL,
+		APIKey:   cfg.Translation.APIKey,
+		Timeout:  cfg.Translation.Timeout,
 	})
+	if err != nil {
+		log.Error().Err(err).Msg("Translation provider is misconfigured, machine translation disabled")
+	}
+	translationPipeline := translation.NewPipeline(
+		translationJobsRepo,
+		chapterRepo,
+		translationVotingRepo,
+		glossaryRepo,
+		translator,
+		eventBus,
+		translation.Options{
+			SourceLang:  cfg.Translation.SourceLang,
+			TargetLangs: cfg.Translation.TargetLangs,
+			Workers:     cfg.Translation.Workers,
+			BatchSize:   cfg.Translation.BatchSize,
+		},
+		log,
+	)
+	translationPipeline.Register()
+	scheduler.AddWorker(translationPipeline)
+
+	// Retranslation requests (subscription feature): moderator accepts -> pipeline re-runs the chapter.
+	retranslationService := service.NewRetranslationService(retranslationRepo, chapterRepo, subscriptionRepo, translationPipeline, cfg.Translation.RetranslationRequests, log)
+	retranslationService.Register(eventBus)
+	retranslationHandler := handlers.NewRetranslationHandler(retranslationService)
+
+	opsHandler := handlers.NewOpsHandler(scheduler, impOrch, importRunsRepo, importJobsRepo, novelSourcesRepo, novelSyncJob, cookiesRepo, translationVotingRepo, votingRepo, translationPipeline, translationJobsRepo, outboxRepo, eventBus, cacheStore, rateLimiter, log)
 
 	// Auth middleware
 	authMiddleware := middleware.NewAuthMiddleware(authService, cfg.JWT)
 
+	// Пробы живости/готовности для оркестратора и балансировщика
+	healthHandler := handlers.NewHealthHandler(newHealthChecker(db, redisClient, scheduler, cfg), cfg.Server.HealthCheckTimeout+time.Second)
+	r.Get("/healthz", healthHandler.Healthz)
+	r.Get("/readyz", healthHandler.Readyz)
+
+	// Метрики Prometheus — для системы мониторинга, поэтому вне /api/v1
+	r.With(middleware.MetricsAuth(cfg.Telemetry.MetricsToken)).Handle("/metrics", telemetry.Handler())
+
 	// Маршруты
 	r.Route("/api/v1", func(r chi.Router) {
-		// Health check
+		// Health check (только живость процесса; готовность с зависимостями — /readyz)
 		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
@@ -156,8 +315,8 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 			r.Use(authMiddleware.OptionalAuth)
 
 			// Аутентификация
-			r.Post("/auth/register", authHandler.Register)
-			r.Post("/auth/login", authHandler.Login)
+			r.With(middleware.RateLimit(rateLimiter, "auth_register")).Post("/auth/register", authHandler.Register)
+			r.With(middleware.RateLimit(rateLimiter, "auth_login")).Post("/auth/login", authHandler.Login)
 			r.Post("/auth/refresh", authHandler.Refresh)
 
 			// Каталог новелл
@@ -170,6 +329,8 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 			r.Get("/novels/trending", novelHandler.GetTrending)
 			r.Get("/novels/top-rated", novelHandler.GetTopRated)
 			r.Get("/novels/{slug}/chapters", chapterHandler.ListByNovel)
+			r.Get("/novels/{slug}/export.epub", exportHandler.ExportEPUB)
+			r.Get("/novels/{slug}/feed.atom", feedHandler.NovelChapters)
 			
 			// Главы
 			r.Get("/chapters/{id}", chapterHandler.GetByID)
@@ -204,6 +365,7 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 
 			// Новости (публичные)
 			r.Get("/news", newsHandler.List)
+			r.Get("/news/feed.atom", feedHandler.News)
 			r.Get("/news/latest", newsHandler.GetLatest)
 			r.Get("/news/pinned", newsHandler.GetPinned)
 			r.Get("/news/{slug}", newsHandler.GetBySlug)
@@ -211,6 +373,18 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 			// Платформенная статистика
 			r.Get("/stats/platform", wikiEditHandler.GetPlatformStats)
 
+			// Публичные профили (разделы, скрытые пользователем, не отдаются)
+			r.Get("/users/{id}", profileHandler.GetProfile)
+			r.Get("/users/{id}/activity", profileHandler.GetActivity)
+
+			// Ачивки и прогресс пользователей
+			r.Get("/achievements", achievementHandler.List)
+			r.Get("/users/{id}/achievements", achievementHandler.GetUserAchievements)
+
+			// Статистика чтения и итоги года (если пользователь их открыл)
+			r.Get("/users/{id}/stats", readingStatsHandler.GetUserStats)
+			r.Get("/users/{id}/year-in-review/{year}", readingStatsHandler.GetUserYearInReview)
+
 			// История правок для новеллы (публичная)
 			r.Get("/novels/{id}/edit-history", wikiEditHandler.GetNovelEditHistory)
 
@@ -221,6 +395,13 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 			r.Post("/jobs/weekly-tickets/run", jobsHandler.RunWeeklyTicketsNow)
 		})
 
+		// Личные Atom-ленты: читалки не умеют JWT, пользователь определяется по токену ленты
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.FeedToken(feedTokenService))
+
+			r.Get("/me/reading.atom", feedHandler.ReadingList)
+		})
+
 		// Защищенные маршруты (требуют аутентификации)
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware.Authenticate)
@@ -234,7 +415,7 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 			r.Post("/chapters/{id}/progress", chapterHandler.SaveProgress)
 
 			// Комментарии (защищенные операции)
-			r.Post("/comments", commentHandler.Create)
+			r.With(middleware.RateLimit(rateLimiter, "comments_create")).Post("/comments", commentHandler.Create)
 			r.Put("/comments/{id}", commentHandler.Update)
 			r.Delete("/comments/{id}", commentHandler.Delete)
 			r.Post("/comments/{id}/vote", commentHandler.Vote)
@@ -259,16 +440,17 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 			r.Get("/proposals/my", votingHandler.GetMyProposals)
 			r.Get("/proposals/{id}", votingHandler.GetProposal)
 			r.Post("/proposals", votingHandler.CreateProposal)
+			r.With(middleware.RateLimit(rateLimiter, "proposal_preview")).Post("/proposals/preview", proposalPreviewHandler.PreviewProposal)
 			r.Put("/proposals/{id}", votingHandler.UpdateProposal)
 			r.Post("/proposals/{id}/submit", votingHandler.SubmitProposal)
 			r.Delete("/proposals/{id}", votingHandler.DeleteProposal)
 
 			// Голосование
-			r.Post("/votes", votingHandler.CastVote)
-			r.Post("/translation-votes", translationVotingHandler.CastTranslationVote)
+			r.With(middleware.RateLimit(rateLimiter, "votes")).Post("/votes", votingHandler.CastVote)
+			r.With(middleware.RateLimit(rateLimiter, "votes")).Post("/translation-votes", translationVotingHandler.CastTranslationVote)
 
 			// User uploads (e.g. proposal cover image)
-			r.Post("/upload", uploadHandler.Upload)
+			r.With(middleware.RateLimit(rateLimiter, "upload")).Post("/upload", uploadHandler.Upload)
 
 			// Подписки пользователя
 			r.Get("/subscriptions/me", subscriptionHandler.GetMySubscription)
@@ -294,6 +476,35 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 			r.Get("/edit-requests/{id}", wikiEditHandler.GetEditRequest)
 			r.Post("/edit-requests/{id}/cancel", wikiEditHandler.CancelEditRequest)
 			r.Get("/me/edit-requests", wikiEditHandler.GetUserEditRequests)
+
+			// Запросы на перевод заново (подписка)
+			r.Post("/chapters/{id}/retranslation-requests", retranslationHandler.CreateRequest)
+			r.Get("/me/retranslation-requests", retranslationHandler.GetMyRequests)
+			r.Get("/me/retranslation-requests/transactions", retranslationHandler.GetMyTransactions)
+
+			// Ачивки текущего пользователя
+			r.Get("/me/achievements", achievementHandler.GetMyAchievements)
+
+			// Серии ежедневной активности
+			r.Get("/me/streak", streakHandler.GetMyStreak)
+			r.Post("/me/streak/freezes", streakHandler.PurchaseFreeze)
+			r.Put("/me/timezone", streakHandler.UpdateTimezone)
+
+			// Редактирование своего профиля
+			r.Put("/me/profile", profileHandler.UpdateProfile)
+			r.Put("/me/profile/privacy", profileHandler.UpdatePrivacy)
+			r.With(middleware.RateLimit(rateLimiter, "upload")).Post("/me/profile/avatar", profileHandler.UploadAvatar)
+			r.Delete("/me/profile/avatar", profileHandler.DeleteAvatar)
+
+			// Статистика чтения
+			r.Get("/me/stats", readingStatsHandler.GetMyStats)
+			r.Put("/me/stats/privacy", readingStatsHandler.UpdatePrivacy)
+			r.Get("/me/year-in-review/{year}", readingStatsHandler.GetMyYearInReview)
+
+			// Токены лент (OPDS, Atom)
+			r.Get("/me/feed-tokens", feedTokenHandler.ListTokens)
+			r.Post("/me/feed-tokens", feedTokenHandler.CreateToken)
+			r.Delete("/me/feed-tokens/{id}", feedTokenHandler.RevokeToken)
 		})
 
 		// Маршруты модерации
@@ -311,6 +522,11 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 				r.Get("/edit-requests", wikiEditHandler.GetPendingEditRequests)
 				r.Post("/edit-requests/{id}/approve", wikiEditHandler.ApproveEditRequest)
 				r.Post("/edit-requests/{id}/reject", wikiEditHandler.RejectEditRequest)
+
+				// Запросы на перевод заново
+				r.Get("/retranslation-requests", retranslationHandler.GetPendingRequests)
+				r.Post("/retranslation-requests/{id}/accept", retranslationHandler.AcceptRequest)
+				r.Post("/retranslation-requests/{id}/reject", retranslationHandler.RejectRequest)
 			})
 		})
 
@@ -332,6 +548,17 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 				r.Put("/chapters/{id}", adminHandler.UpdateChapter)
 				r.Delete("/chapters/{id}", adminHandler.DeleteChapter)
 
+				// История ревизий текста глав
+				r.Get("/chapters/{id}/revisions", chapterRevisionHandler.ListRevisions)
+				r.Get("/chapters/{id}/revisions/diff", chapterRevisionHandler.DiffRevisions)
+				r.Get("/chapters/{id}/revisions/{revisionId}", chapterRevisionHandler.GetRevision)
+				r.Post("/chapters/{id}/revisions/{revisionId}/rollback", chapterRevisionHandler.RollbackRevision)
+
+				// Расписание выпуска глав
+				r.Get("/novels/{id}/release-schedule", releaseScheduleHandler.GetSchedule)
+				r.Put("/novels/{id}/release-schedule", releaseScheduleHandler.SaveSchedule)
+				r.Delete("/novels/{id}/release-schedule", releaseScheduleHandler.DeleteSchedule)
+
 				// Загрузка файлов
 				r.Post("/upload", adminHandler.Upload)
 
@@ -367,6 +594,13 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 				r.Get("/novels/{id}/authors", authorHandler.GetNovelAuthors)
 				r.Put("/novels/{id}/authors", authorHandler.UpdateNovelAuthors)
 
+				// Глоссарий новеллы
+				r.Get("/novels/{id}/glossary", glossaryHandler.ListTerms)
+				r.Post("/novels/{id}/glossary", glossaryHandler.CreateTerm)
+				r.Get("/novels/{id}/glossary/violations", glossaryHandler.GetViolations)
+				r.Put("/novels/{id}/glossary/{termId}", glossaryHandler.UpdateTerm)
+				r.Delete("/novels/{id}/glossary/{termId}", glossaryHandler.DeleteTerm)
+
 				// Управление жанрами
 				r.Get("/genres", genreTagHandler.ListGenres)
 				r.Post("/genres", genreTagHandler.CreateGenre)
@@ -387,6 +621,8 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 				r.Post("/users/{id}/ban", userAdminHandler.BanUser)
 				r.Post("/users/{id}/unban", userAdminHandler.UnbanUser)
 				r.Put("/users/{id}/roles", userAdminHandler.UpdateUserRoles)
+				r.Get("/users/{id}/xp-events", userAdminHandler.ListXPEvents)
+				r.Post("/users/{id}/xp-events/clawback", userAdminHandler.ClawbackXP)
 
 				// Управление комментариями и жалобами
 				r.Get("/comments", commentAdminHandler.ListComments)
@@ -395,6 +631,11 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 				r.Get("/reports", commentAdminHandler.ListReports)
 				r.Post("/reports/{id}/resolve", commentAdminHandler.ResolveReport)
 
+				// Управление ачивками
+				r.Get("/achievements/metrics", achievementHandler.ListMetrics)
+				r.Post("/achievements", achievementHandler.Create)
+				r.Put("/achievements/{id}", achievementHandler.Update)
+
 				// Системные функции
 				r.Get("/settings", adminSystemHandler.GetSettings)
 				r.Get("/settings/{key}", adminSystemHandler.GetSetting)
@@ -419,14 +660,43 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 					r.Get("/import-runs/{id}/cookies", opsHandler.GetImportRunCookies)
 					r.Put("/import-runs/{id}/cookies", opsHandler.UpdateImportRunCookies)
 					r.Post("/import-runs/{id}/retry", opsHandler.RetryImportRun)
+					r.Get("/import-jobs", opsHandler.ListImportJobs)
+					r.Get("/novel-sources", opsHandler.ListNovelSources)
+					r.Put("/novel-sources/{novelId}", opsHandler.UpdateNovelSource)
+					r.Post("/novel-sources/{novelId}/sync", opsHandler.SyncNovelNow)
+					r.Post("/jobs/novel-sync/run", opsHandler.RunNovelSyncNow)
+					r.Post("/jobs/chapter-release/run", releaseScheduleHandler.RunNow)
+					r.Post("/jobs/achievement-backfill/run", achievementHandler.RunBackfillNow)
 					r.Post("/imports/run", opsHandler.RunImportNow)
+					r.Get("/translation-jobs", opsHandler.ListTranslationJobs)
+					r.Post("/translation-jobs/novels/{novelId}/enqueue", opsHandler.EnqueueNovelTranslation)
 					r.Get("/translation-targets", opsHandler.ListTranslationTargets)
 					r.Post("/translation-targets/{id}/status", opsHandler.SetTranslationTargetStatus)
+					r.Get("/outbox", opsHandler.GetOutbox)
+					r.Post("/outbox/{id}/retry", opsHandler.RetryOutboxEvent)
+					r.Get("/events/handlers", opsHandler.ListEventHandlers)
+					r.Get("/cache", opsHandler.GetCacheStats)
+					r.Get("/rate-limits", opsHandler.GetRateLimits)
 				})
 			})
 		})
 	})
 
+	// OPDS-каталог для читалок; пользователь определяется по токену ленты, а не JWT
+	r.Route("/opds", func(r chi.Router) {
+		r.Use(middleware.FeedToken(feedTokenService))
+
+		r.Get("/", opdsHandler.Root)
+		r.Get("/opensearch.xml", opdsHandler.OpenSearchDescription)
+		r.Get("/search", opdsHandler.Search)
+		r.Get("/novels", opdsHandler.ListNovels)
+		r.Get("/novels/{slug}/export.epub", exportHandler.ExportEPUB)
+		r.Get("/genres", opdsHandler.ListGenres)
+		r.Get("/tags", opdsHandler.ListTags)
+		r.Get("/shelves", opdsHandler.ListShelves)
+		r.Get("/shelves/{list}", opdsHandler.GetShelf)
+	})
+
 	// Статические файлы (загруженные изображения)
 	r.Handle("/uploads/*", http.StripPrefix("/uploads/", http.FileServer(http.Dir(cfg.UploadsDir))))
 
@@ -434,3 +704,72 @@ func NewRouter(db *sqlx.DB, cfg *config.Config, log zerolog.Logger) (http.Handle
 	_ = context.Background()
 	return r, scheduler
 }
+
+// newHealthChecker собирает проверки /readyz. Критичные (база, схема, планировщик) выводят
+// инстанс из балансировки; остальные только помечают его degraded: без parser-service или
+// загрузок сайт продолжает отдавать чтение
+func newHealthChecker(db *sqlx.DB, redisClient *redis.Client, scheduler *jobs.Scheduler, cfg *config.Config) *health.Checker {
+	checker := health.New(cfg.Server.HealthCheckTimeout)
+	checker.Add("database", true, func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	})
+	checker.Add("migrations", true, func(ctx context.Context) error {
+		pending, err := database.PendingMigrations(ctx, db)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migrations, first %s", len(pending), pending[0])
+		}
+		return nil
+	})
+	checker.Add("scheduler", true, scheduler.Health)
+	checker.Add("parser_service", false, parserclient.New().Health)
+	checker.Add("uploads_dir", false, health.DirWritable(cfg.UploadsDir))
+	if redisClient != nil {
+		checker.Add("redis", false, func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		})
+	}
+	return checker
+}
+
+// connectRedis подключается к Redis, если он нужен кэшу или лимитам запросов.
+// Недоступный Redis не мешает старту: возвращается nil, и они работают в памяти процесса
+func connectRedis(cfg *config.Config, log zerolog.Logger) *redis.Client {
+	if cfg.Cache.Backend != "redis" && cfg.RateLimit.Backend != "redis" {
+		return nil
+	}
+	client, err := database.ConnectRedis(context.Background(), cfg.Redis)
+	if err != nil {
+		log.Warn().Err(err).Msg("Redis is unavailable, falling back to in-memory cache and rate limits")
+		return nil
+	}
+	return client
+}
+
+// newCacheStore создает кэш по настройкам (nil-стор при CACHE_BACKEND=none отключает кэширование)
+func newCacheStore(cfg config.CacheConfig, redisClient *redis.Client, log zerolog.Logger) *cache.Store {
+	switch {
+	case cfg.Backend == "none":
+		log.Info().Msg("Catalog cache disabled")
+		return nil
+	case cfg.Backend == "redis" && redisClient != nil:
+		return cache.New(cache.NewRedis(redisClient), "novels", log)
+	default:
+		return cache.New(cache.NewMemory(cfg.MaxEntries), "novels", log)
+	}
+}
+
+// newRateLimiter создает ограничитель запросов по настройкам (RATE_LIMIT_BACKEND=none отключает лимиты)
+func newRateLimiter(cfg config.RateLimitConfig, redisClient *redis.Client, log zerolog.Logger) *ratelimit.Limiter {
+	switch {
+	case cfg.Backend == "none":
+		log.Info().Msg("Rate limiting disabled")
+		return ratelimit.New(nil, defaultRateLimits, log)
+	case cfg.Backend == "redis" && redisClient != nil:
+		return ratelimit.New(ratelimit.NewRedis(redisClient), defaultRateLimits, log)
+	default:
+		return ratelimit.New(ratelimit.NewMemory(), defaultRateLimits, log)
+	}
+}
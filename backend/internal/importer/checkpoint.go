@@ -9,5 +9,19 @@ type Checkpoint struct {
 	Slug        string    `json:"slug"`
 	NextIndex   int       `json:"nextIndex"`
 	TotalChapters int     `json:"totalChapters"`
+	// Sync marks a follow-up run for an already imported novel: chapters whose
+	// number already exists are skipped and original_chapters_count is refreshed.
+	Sync bool `json:"sync,omitempty"`
+	// EndIndex stops the import before this 0-based index (0 = up to the last chapter).
+	// With NextIndex it selects a chapter range, e.g. for `novelctl import --from/--to`.
+	EndIndex int `json:"endIndex,omitempty"`
 }
 
+// ChaptersLimit is the chapters limit to ask the parser for, so chapters past EndIndex
+// are not fetched at all. A nil checkpoint imports everything.
+func (c *Checkpoint) ChaptersLimit() int {
+	if c == nil || c.EndIndex < 0 {
+		return 0
+	}
+	return c.EndIndex
+}
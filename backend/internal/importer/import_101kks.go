@@ -39,30 +39,13 @@ type Import101KksResult struct {
 	CoverKey      *string
 }
 
-func Import101KksResumable(
-	ctx context.Context,
-	db *sqlx.DB,
-	opts Import101KksOptions,
-	checkpoint *Checkpoint,
-	onChapter func(cp *Checkpoint, chaptersSaved int) error,
-) (*Import101KksResult, *Checkpoint, error) {
-	if db == nil {
-		return nil, nil, fmt.Errorf("db is nil")
-	}
-	if strings.TrimSpace(opts.PageURL) == "" {
-		return nil, nil, fmt.Errorf("page url is required")
-	}
-	if strings.TrimSpace(opts.UploadDir) == "" {
-		return nil, nil, fmt.Errorf("upload dir is required")
-	}
-
-	pc := parserclient.New()
-
+// parseRequest is the parser-service request for the book page of opts.
+func (opts Import101KksOptions) parseRequest() parserclient.ParseRequest {
 	storagePath := ""
 	if strings.TrimSpace(opts.StorageStatePath) != "" {
 		storagePath = "/data/" + filepath.Base(strings.TrimSpace(opts.StorageStatePath))
 	}
-	resp, err := pc.Parse(ctx, parserclient.ParseRequest{
+	return parserclient.ParseRequest{
 		URL:                 opts.PageURL,
 		Site:                "101kks",
 		ChaptersLimit:       opts.ChaptersLimit,
@@ -79,7 +62,35 @@ func Import101KksResumable(
 		HumanDelayMSMin:     220,
 		HumanDelayMSMax:     950,
 		CloudflareWaitMS:    12_000,
-	})
+	}
+}
+
+// Preview101Kks fetches book metadata and the chapter list without importing anything.
+func Preview101Kks(ctx context.Context, opts Import101KksOptions) (*BookPreview, error) {
+	if strings.TrimSpace(opts.PageURL) == "" {
+		return nil, fmt.Errorf("page url is required")
+	}
+	return previewBook(ctx, opts.parseRequest())
+}
+
+func Import101KksResumable(
+	ctx context.Context,
+	db *sqlx.DB,
+	opts Import101KksOptions,
+	checkpoint *Checkpoint,
+	onChapter func(cp *Checkpoint, chaptersSaved int) error,
+) (*Import101KksResult, *Checkpoint, error) {
+	if db == nil {
+		return nil, nil, fmt.Errorf("db is nil")
+	}
+	if strings.TrimSpace(opts.PageURL) == "" {
+		return nil, nil, fmt.Errorf("page url is required")
+	}
+	if strings.TrimSpace(opts.UploadDir) == "" {
+		return nil, nil, fmt.Errorf("upload dir is required")
+	}
+
+	resp, err := parserclient.New().Parse(ctx, opts.parseRequest())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -171,6 +182,18 @@ func Import101KksResumable(
 
 	novelID := checkpoint.NovelID
 	chaptersSaved := 0
+
+	// Sync mode: the novel already exists, only chapters missing in DB are imported.
+	var known map[float64]bool
+	if checkpoint.Sync {
+		known, err = existingChapterNumbers(ctx, db, novelID)
+		if err != nil {
+			return nil, checkpoint, err
+		}
+		if err := updateOriginalChaptersCount(ctx, db, novelID, total); err != nil {
+			return nil, checkpoint, err
+		}
+	}
 	now := time.Now().UTC()
 
 	for i := checkpoint.NextIndex; i < total; i++ {
@@ -197,6 +220,11 @@ func Import101KksResumable(
 			titlePtr = &title
 		}
 
+		if known[number] {
+			checkpoint.NextIndex = i + 1
+			continue
+		}
+
 		tx, err := db.BeginTxx(ctx, nil)
 		if err != nil {
 			return nil, checkpoint, fmt.Errorf("begin chapter tx: %w", err)
@@ -215,7 +243,7 @@ func Import101KksResumable(
 			VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT (novel_id, number) DO UPDATE SET
 				title = EXCLUDED.title,
-				published_at = EXCLUDED.published_at,
+				published_at = COALESCE(chapters.published_at, EXCLUDED.published_at),
 				updated_at = NOW()
 			RETURNING id
 		`, chapterID, novelID, number, titlePtr, now).Scan(&chapterID)
@@ -225,6 +253,11 @@ func Import101KksResumable(
 		}
 
 		content := strings.TrimSpace(ch.Content)
+		if err = tagRevision(ctx, tx, "import:101kks"); err != nil {
+			rollback()
+			return nil, checkpoint, fmt.Errorf("tag chapter content revision #%d: %w", i+1, err)
+		}
+
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO chapter_contents (chapter_id, lang, content, word_count, source)
 			VALUES
@@ -402,6 +435,10 @@ func Import101Kks(ctx context.Context, db *sqlx.DB, opts Import101KksOptions) (*
 		}
 
 		content := strings.TrimSpace(ch.Content)
+		if err = tagRevision(ctx, tx, "import:101kks"); err != nil {
+			return nil, fmt.Errorf("tag chapter content revision #%d: %w", i+1, err)
+		}
+
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO chapter_contents (chapter_id, lang, content, word_count, source)
 			VALUES
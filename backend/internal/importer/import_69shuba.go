@@ -38,6 +38,31 @@ type Import69ShubaResult struct {
 	CoverKey      *string
 }
 
+// parseRequest is the parser-service request for the book page of opts.
+func (opts Import69ShubaOptions) parseRequest() parserclient.ParseRequest {
+	storagePath := ""
+	if strings.TrimSpace(opts.StorageStatePath) != "" {
+		storagePath = "/data/" + filepath.Base(strings.TrimSpace(opts.StorageStatePath))
+	}
+	return parserclient.ParseRequest{
+		URL:                 opts.PageURL,
+		Site:                "69shuba",
+		ChaptersLimit:       opts.ChaptersLimit,
+		UserAgent:           opts.UserAgent,
+		CookieHeader:        opts.Cookie,
+		StorageStatePath:    storagePath,
+		NavigationTimeoutMS: 300_000,
+	}
+}
+
+// Preview69Shuba fetches book metadata and the chapter list without importing anything.
+func Preview69Shuba(ctx context.Context, opts Import69ShubaOptions) (*BookPreview, error) {
+	if strings.TrimSpace(opts.PageURL) == "" {
+		return nil, fmt.Errorf("page url is required")
+	}
+	return previewBook(ctx, opts.parseRequest())
+}
+
 func Import69ShubaResumable(
 	ctx context.Context,
 	db *sqlx.DB,
@@ -55,21 +80,7 @@ func Import69ShubaResumable(
 		return nil, nil, fmt.Errorf("upload dir is required")
 	}
 
-	pc := parserclient.New()
-
-	storagePath := ""
-	if strings.TrimSpace(opts.StorageStatePath) != "" {
-		storagePath = "/data/" + filepath.Base(strings.TrimSpace(opts.StorageStatePath))
-	}
-	resp, err := pc.Parse(ctx, parserclient.ParseRequest{
-		URL:                 opts.PageURL,
-		Site:                "69shuba",
-		ChaptersLimit:       opts.ChaptersLimit,
-		UserAgent:           opts.UserAgent,
-		CookieHeader:        opts.Cookie,
-		StorageStatePath:    storagePath,
-		NavigationTimeoutMS: 300_000,
-	})
+	resp, err := parserclient.New().Parse(ctx, opts.parseRequest())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -160,6 +171,18 @@ func Import69ShubaResumable(
 
 	novelID := checkpoint.NovelID
 	chaptersSaved := 0
+
+	// Sync mode: the novel already exists, only chapters missing in DB are imported.
+	var known map[float64]bool
+	if checkpoint.Sync {
+		known, err = existingChapterNumbers(ctx, db, novelID)
+		if err != nil {
+			return nil, checkpoint, err
+		}
+		if err := updateOriginalChaptersCount(ctx, db, novelID, total); err != nil {
+			return nil, checkpoint, err
+		}
+	}
 	now := time.Now().UTC()
 
 	for i := checkpoint.NextIndex; i < total; i++ {
@@ -186,6 +209,11 @@ func Import69ShubaResumable(
 			titlePtr = &title
 		}
 
+		if known[number] {
+			checkpoint.NextIndex = i + 1
+			continue
+		}
+
 		tx, err := db.BeginTxx(ctx, nil)
 		if err != nil {
 			return nil, checkpoint, fmt.Errorf("begin chapter tx: %w", err)
@@ -204,7 +232,7 @@ func Import69ShubaResumable(
 			VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT (novel_id, number) DO UPDATE SET
 				title = EXCLUDED.title,
-				published_at = EXCLUDED.published_at,
+				published_at = COALESCE(chapters.published_at, EXCLUDED.published_at),
 				updated_at = NOW()
 			RETURNING id
 		`, chapterID, novelID, number, titlePtr, now).Scan(&chapterID)
@@ -214,6 +242,11 @@ func Import69ShubaResumable(
 		}
 
 		content := strings.TrimSpace(ch.Content)
+		if err = tagRevision(ctx, tx, "import:69shuba"); err != nil {
+			rollback()
+			return nil, checkpoint, fmt.Errorf("tag chapter content revision #%d: %w", i+1, err)
+		}
+
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO chapter_contents (chapter_id, lang, content, word_count, source)
 			VALUES
@@ -376,6 +409,10 @@ func Import69Shuba(ctx context.Context, db *sqlx.DB, opts Import69ShubaOptions)
 		}
 
 		content := strings.TrimSpace(ch.Content)
+		if err = tagRevision(ctx, tx, "import:69shuba"); err != nil {
+			return nil, fmt.Errorf("tag chapter content revision #%d: %w", i+1, err)
+		}
+
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO chapter_contents (chapter_id, lang, content, word_count, source)
 			VALUES
@@ -146,6 +146,10 @@ func ImportFanqie(ctx context.Context, db *sqlx.DB, opts ImportFanqieOptions) (*
 		}
 
 		content := strings.TrimSpace(ch.Content)
+		if err = tagRevision(ctx, tx, "import:fanqie"); err != nil {
+			return nil, fmt.Errorf("tag chapter content revision #%d: %w", i+1, err)
+		}
+
 		// Important: UI requests chapters in ru locale (lang=ru). For imported originals we store content both as "zh"
 		// and as "ru" fallback so chapter pages work immediately after import.
 		_, err = tx.ExecContext(ctx, `
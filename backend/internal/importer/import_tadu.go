@@ -38,6 +38,31 @@ type ImportTaduResult struct {
 	CoverKey      *string
 }
 
+// parseRequest is the parser-service request for the book page of opts.
+func (opts ImportTaduOptions) parseRequest() parserclient.ParseRequest {
+	storagePath := ""
+	if strings.TrimSpace(opts.StorageStatePath) != "" {
+		storagePath = "/data/" + filepath.Base(strings.TrimSpace(opts.StorageStatePath))
+	}
+	return parserclient.ParseRequest{
+		URL:                 opts.PageURL,
+		Site:                "tadu",
+		ChaptersLimit:       opts.ChaptersLimit,
+		UserAgent:           opts.UserAgent,
+		CookieHeader:        opts.Cookie,
+		StorageStatePath:    storagePath,
+		NavigationTimeoutMS: 300_000,
+	}
+}
+
+// PreviewTadu fetches book metadata and the chapter list without importing anything.
+func PreviewTadu(ctx context.Context, opts ImportTaduOptions) (*BookPreview, error) {
+	if strings.TrimSpace(opts.PageURL) == "" {
+		return nil, fmt.Errorf("page url is required")
+	}
+	return previewBook(ctx, opts.parseRequest())
+}
+
 // ImportTaduResumable is a pause/resume-friendly version of ImportTadu:
 // - Creates novel+localizations once (checkpoint.NovelID).
 // - Imports chapters in per-chapter transactions.
@@ -61,22 +86,7 @@ func ImportTaduResumable(
 		return nil, nil, fmt.Errorf("upload dir is required")
 	}
 
-	pc := parserclient.New()
-
-	storagePath := ""
-	if strings.TrimSpace(opts.StorageStatePath) != "" {
-		storagePath = "/data/" + filepath.Base(strings.TrimSpace(opts.StorageStatePath))
-	}
-
-	resp, err := pc.Parse(ctx, parserclient.ParseRequest{
-		URL:                 opts.PageURL,
-		Site:                "tadu",
-		ChaptersLimit:       opts.ChaptersLimit,
-		UserAgent:           opts.UserAgent,
-		CookieHeader:        opts.Cookie,
-		StorageStatePath:    storagePath,
-		NavigationTimeoutMS: 300_000,
-	})
+	resp, err := parserclient.New().Parse(ctx, opts.parseRequest())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -173,6 +183,18 @@ func ImportTaduResumable(
 
 	novelID := checkpoint.NovelID
 	chaptersSaved := 0
+
+	// Sync mode: the novel already exists, only chapters missing in DB are imported.
+	var known map[float64]bool
+	if checkpoint.Sync {
+		known, err = existingChapterNumbers(ctx, db, novelID)
+		if err != nil {
+			return nil, checkpoint, err
+		}
+		if err := updateOriginalChaptersCount(ctx, db, novelID, total); err != nil {
+			return nil, checkpoint, err
+		}
+	}
 	now := time.Now().UTC()
 
 	for i := checkpoint.NextIndex; i < total; i++ {
@@ -199,6 +221,11 @@ func ImportTaduResumable(
 			titlePtr = &title
 		}
 
+		if known[number] {
+			checkpoint.NextIndex = i + 1
+			continue
+		}
+
 		tx, err := db.BeginTxx(ctx, nil)
 		if err != nil {
 			return nil, checkpoint, fmt.Errorf("begin chapter tx: %w", err)
@@ -217,7 +244,7 @@ func ImportTaduResumable(
 			VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT (novel_id, number) DO UPDATE SET
 				title = EXCLUDED.title,
-				published_at = EXCLUDED.published_at,
+				published_at = COALESCE(chapters.published_at, EXCLUDED.published_at),
 				updated_at = NOW()
 			RETURNING id
 		`, chapterID, novelID, number, titlePtr, now).Scan(&chapterID)
@@ -227,6 +254,11 @@ func ImportTaduResumable(
 		}
 
 		content := strings.TrimSpace(ch.Content)
+		if err = tagRevision(ctx, tx, "import:tadu"); err != nil {
+			rollback()
+			return nil, checkpoint, fmt.Errorf("tag chapter content revision #%d: %w", i+1, err)
+		}
+
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO chapter_contents (chapter_id, lang, content, word_count, source)
 			VALUES
@@ -387,6 +419,10 @@ func ImportTadu(ctx context.Context, db *sqlx.DB, opts ImportTaduOptions) (*Impo
 		}
 
 		content := strings.TrimSpace(ch.Content)
+		if err = tagRevision(ctx, tx, "import:tadu"); err != nil {
+			return nil, fmt.Errorf("tag chapter content revision #%d: %w", i+1, err)
+		}
+
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO chapter_contents (chapter_id, lang, content, word_count, source)
 			VALUES
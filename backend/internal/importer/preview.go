@@ -0,0 +1,100 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"novels-backend/internal/parserclient"
+	"novels-backend/internal/parsers/fanqie"
+)
+
+// BookPreview is what a source says about a book before anything is imported:
+// metadata from the book page and the chapter list from its catalog.
+type BookPreview struct {
+	Site        string           `json:"site"`
+	URL         string           `json:"url"`
+	Title       string           `json:"title"`
+	Author      string           `json:"author,omitempty"`
+	Description string           `json:"description,omitempty"`
+	CoverURL    string           `json:"coverUrl,omitempty"`
+	Category    string           `json:"category,omitempty"`
+	Tags        []string         `json:"tags,omitempty"`
+	Chapters    []PreviewChapter `json:"chapters"`
+}
+
+// PreviewChapter is one entry of the source catalog. Index is 1-based, in catalog order;
+// Number is the chapter number the import would store.
+type PreviewChapter struct {
+	Index  int     `json:"index"`
+	Number float64 `json:"number"`
+	Title  string  `json:"title"`
+	URL    string  `json:"url"`
+}
+
+// previewBook asks parser-service for the book page and catalog only (no chapter contents).
+func previewBook(ctx context.Context, req parserclient.ParseRequest) (*BookPreview, error) {
+	req.MetadataOnly = true
+	req.ChaptersLimit = 0
+	resp, err := parserclient.New().Parse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	book := resp.Book
+	out := &BookPreview{
+		Site:        resp.Site,
+		URL:         req.URL,
+		Title:       strings.TrimSpace(book.Title),
+		Author:      strings.TrimSpace(book.Author),
+		Description: strings.TrimSpace(book.Description),
+		CoverURL:    book.CoverURL,
+		Category:    strings.TrimSpace(book.Category),
+		Tags:        book.Tags,
+		Chapters:    make([]PreviewChapter, 0, len(book.Chapters)),
+	}
+	for i, ref := range book.Chapters {
+		// Same numbering as the resumable importers.
+		number := float64(i + 1)
+		if ref.Number != nil && *ref.Number > 0 {
+			number = float64(*ref.Number)
+		}
+		out.Chapters = append(out.Chapters, PreviewChapter{
+			Index:  i + 1,
+			Number: number,
+			Title:  strings.TrimSpace(ref.Title),
+			URL:    ref.URL,
+		})
+	}
+	return out, nil
+}
+
+// PreviewFanqie fetches book metadata and the chapter list from fanqienovel without importing anything.
+func PreviewFanqie(ctx context.Context, opts ImportFanqieOptions) (*BookPreview, error) {
+	if strings.TrimSpace(opts.PageURL) == "" {
+		return nil, fmt.Errorf("page url is required")
+	}
+	s := fanqie.NewScraper(fanqie.NewFetcherWithOptions(opts.Cookie, opts.UserAgent))
+	book, err := s.ScrapeBook(ctx, opts.PageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &BookPreview{
+		Site:        "fanqie",
+		URL:         opts.PageURL,
+		Title:       strings.TrimSpace(book.Title),
+		Description: strings.TrimSpace(book.Description),
+		CoverURL:    book.CoverURL,
+		Chapters:    make([]PreviewChapter, 0, len(book.Chapters)),
+	}
+	for i, ref := range book.Chapters {
+		out.Chapters = append(out.Chapters, PreviewChapter{
+			Index:  i + 1,
+			Number: float64(i + 1),
+			Title:  strings.TrimSpace(ref.Title),
+			URL:    ref.URL,
+		})
+	}
+	return out, nil
+}
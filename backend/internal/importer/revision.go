@@ -0,0 +1,14 @@
+package importer
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/repository"
+)
+
+// tagRevision labels the chapter content revisions written by tx with the importer name.
+func tagRevision(ctx context.Context, tx *sqlx.Tx, note string) error {
+	return repository.SetRevisionContext(ctx, tx, nil, note)
+}
@@ -0,0 +1,34 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// existingChapterNumbers returns the chapter numbers already stored for a novel.
+func existingChapterNumbers(ctx context.Context, db *sqlx.DB, novelID uuid.UUID) (map[float64]bool, error) {
+	var numbers []float64
+	if err := db.SelectContext(ctx, &numbers, `SELECT number FROM chapters WHERE novel_id = $1`, novelID); err != nil {
+		return nil, fmt.Errorf("load existing chapters: %w", err)
+	}
+	known := make(map[float64]bool, len(numbers))
+	for _, n := range numbers {
+		known[n] = true
+	}
+	return known, nil
+}
+
+// updateOriginalChaptersCount stores the chapter count reported by the source catalog.
+func updateOriginalChaptersCount(ctx context.Context, db *sqlx.DB, novelID uuid.UUID, total int) error {
+	_, err := db.ExecContext(ctx, `
+		UPDATE novels SET original_chapters_count = $2, updated_at = NOW()
+		WHERE id = $1
+	`, novelID, total)
+	if err != nil {
+		return fmt.Errorf("update original_chapters_count: %w", err)
+	}
+	return nil
+}
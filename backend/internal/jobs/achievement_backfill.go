@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/service"
+	"novels-backend/internal/telemetry"
+)
+
+// AchievementBackfillJob evaluates new achievements and achievements whose condition changed for
+// all users, so users who already meet a condition get the achievement retroactively. Achievements
+// are evaluated once; afterwards the events of each user keep them up to date.
+type AchievementBackfillJob struct {
+	achievements *service.AchievementService
+	interval     time.Duration
+	logger       zerolog.Logger
+
+	cancel context.CancelFunc
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewAchievementBackfillJob(achievements *service.AchievementService, interval time.Duration, logger zerolog.Logger) *AchievementBackfillJob {
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	return &AchievementBackfillJob{
+		achievements: achievements,
+		interval:     interval,
+		logger:       logger.With().Str("job", "achievement_backfill").Logger(),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Start implements Worker. The first run happens right away, so achievements added by a
+// migration are backfilled on deploy.
+func (j *AchievementBackfillJob) Start(ctx context.Context) {
+	// A backfill over all users can take a while; Stop interrupts it and the next start
+	// evaluates the achievement again (unlocks are idempotent).
+	ctx, j.cancel = context.WithCancel(ctx)
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		j.logger.Info().Dur("interval", j.interval).Msg("Achievement backfill job scheduled")
+		for {
+			if _, err := j.Run(ctx); err != nil && ctx.Err() == nil {
+				j.logger.Error().Err(err).Msg("Achievement backfill job failed")
+			}
+			select {
+			case <-j.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop implements Worker.
+func (j *AchievementBackfillJob) Stop() {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	close(j.stopCh)
+	j.wg.Wait()
+}
+
+// Run backfills the pending achievements and returns how many were unlocked.
+func (j *AchievementBackfillJob) Run(ctx context.Context) (int, error) {
+	unlocked := 0
+	err := telemetry.RunJob(ctx, "achievement_backfill", func(ctx context.Context) error {
+		var err error
+		unlocked, err = j.achievements.BackfillPending(ctx)
+		return err
+	})
+	return unlocked, err
+}
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/service"
+	"novels-backend/internal/telemetry"
+)
+
+// ChapterReleaseJob periodically takes chapters out of the per-novel release queues and gives
+// them release times according to each novel's schedule. Chapters become visible to readers
+// once their published_at has passed (earlier for subscribers with early access).
+type ChapterReleaseJob struct {
+	releases *service.ReleaseScheduleService
+	interval time.Duration
+	logger   zerolog.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewChapterReleaseJob(releases *service.ReleaseScheduleService, interval time.Duration, logger zerolog.Logger) *ChapterReleaseJob {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &ChapterReleaseJob{
+		releases: releases,
+		interval: interval,
+		logger:   logger.With().Str("job", "chapter_release").Logger(),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start implements Worker.
+func (j *ChapterReleaseJob) Start(ctx context.Context) {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		j.logger.Info().Dur("interval", j.interval).Msg("Chapter release job scheduled")
+		for {
+			select {
+			case <-j.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := j.Run(ctx); err != nil {
+					j.logger.Error().Err(err).Msg("Chapter release job failed")
+				}
+			}
+		}
+	}()
+}
+
+// Stop implements Worker.
+func (j *ChapterReleaseJob) Stop() {
+	close(j.stopCh)
+	j.wg.Wait()
+}
+
+// Run schedules queued chapters for all due release slots and returns how many were scheduled.
+func (j *ChapterReleaseJob) Run(ctx context.Context) (int, error) {
+	released := 0
+	err := telemetry.RunJob(ctx, "chapter_release", func(ctx context.Context) error {
+		var err error
+		released, err = j.releases.ReleaseDue(ctx, time.Now())
+		return err
+	})
+	if released > 0 {
+		j.logger.Info().Int("chapters", released).Msg("Queued chapters scheduled for release")
+	}
+	return released, err
+}
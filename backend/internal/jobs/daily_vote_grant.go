@@ -10,6 +10,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"novels-backend/internal/domain/models"
 	"novels-backend/internal/service"
+	"novels-backend/internal/telemetry"
 	"github.com/rs/zerolog"
 )
 
@@ -35,6 +36,10 @@ func NewDailyVoteGrantJob(
 // Run executes the daily vote grant job
 // Should be triggered at 00:00 UTC (3:00 MSK)
 func (j *DailyVoteGrantJob) Run(ctx context.Context) error {
+	return telemetry.RunJob(ctx, "daily_vote_grant", j.run)
+}
+
+func (j *DailyVoteGrantJob) run(ctx context.Context) error {
 	j.logger.Info().Msg("Starting daily vote grant job")
 	
 	startTime := time.Now()
@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/repository"
+	"novels-backend/internal/telemetry"
+)
+
+// NovelSyncEnqueuer starts a sync run for an imported novel (implemented by the import orchestrator).
+type NovelSyncEnqueuer interface {
+	StartSyncAsync(ctx context.Context, novelID uuid.UUID) (uuid.UUID, error)
+}
+
+// NovelSyncJob periodically enqueues "follow the source" runs for imported novels whose
+// per-novel sync cadence is due. The runs themselves go through the import queue.
+type NovelSyncJob struct {
+	sources  *repository.NovelSourcesRepository
+	enqueuer NovelSyncEnqueuer
+	interval time.Duration
+	logger   zerolog.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewNovelSyncJob(
+	sources *repository.NovelSourcesRepository,
+	enqueuer NovelSyncEnqueuer,
+	interval time.Duration,
+	logger zerolog.Logger,
+) *NovelSyncJob {
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	return &NovelSyncJob{
+		sources:  sources,
+		enqueuer: enqueuer,
+		interval: interval,
+		logger:   logger.With().Str("job", "novel_sync").Logger(),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start implements Worker.
+func (j *NovelSyncJob) Start(ctx context.Context) {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		j.logger.Info().Dur("interval", j.interval).Msg("Novel sync job scheduled")
+		for {
+			select {
+			case <-j.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := j.Run(ctx); err != nil {
+					j.logger.Error().Err(err).Msg("Novel sync job failed")
+				}
+			}
+		}
+	}()
+}
+
+// Stop implements Worker.
+func (j *NovelSyncJob) Stop() {
+	close(j.stopCh)
+	j.wg.Wait()
+}
+
+// Run enqueues sync runs for all due novels and returns how many were enqueued.
+func (j *NovelSyncJob) Run(ctx context.Context) (int, error) {
+	enqueued := 0
+	err := telemetry.RunJob(ctx, "novel_sync", func(ctx context.Context) error {
+		var err error
+		enqueued, err = j.run(ctx)
+		return err
+	})
+	return enqueued, err
+}
+
+func (j *NovelSyncJob) run(ctx context.Context) (int, error) {
+	due, err := j.sources.ListDue(ctx, 50)
+	if err != nil {
+		return 0, err
+	}
+	enqueued := 0
+	for _, src := range due {
+		if _, err := j.enqueuer.StartSyncAsync(ctx, src.NovelID); err != nil {
+			j.logger.Error().Err(err).Str("novel_id", src.NovelID.String()).Msg("Failed to enqueue novel sync")
+			continue
+		}
+		enqueued++
+	}
+	if enqueued > 0 {
+		j.logger.Info().Int("enqueued", enqueued).Msg("Novel sync runs enqueued")
+	}
+	return enqueued, nil
+}
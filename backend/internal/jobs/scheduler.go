@@ -2,15 +2,36 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"novels-backend/internal/repository"
+	"novels-backend/internal/health"
 	"novels-backend/internal/service"
+	"novels-backend/internal/telemetry"
 	"github.com/rs/zerolog"
 )
 
+// Worker is a long-running background component whose lifecycle follows the scheduler
+// (e.g. import queue workers).
+type Worker interface {
+	Start(ctx context.Context)
+	Stop()
+}
+
+// Drainer is a worker that can stop taking new work before the scheduler stops it
+// (e.g. the import queue stops claiming jobs and hands its active runs back).
+// Drain returns once the work in progress is finished or handed off, or ctx is done.
+type Drainer interface {
+	Drain(ctx context.Context)
+}
+
+// jobLoops is the number of job runner goroutines started by Start.
+const jobLoops = 6
+
 // Scheduler manages background jobs
 type Scheduler struct {
 	db                *sqlx.DB
@@ -22,7 +43,14 @@ type Scheduler struct {
 	
 	dailyVoteJob      *DailyVoteGrantJob
 	weeklyTicketJob   *WeeklyTicketGrantJob
+	workers           []Worker
 	
+	cancel            context.CancelFunc
+	started           atomic.Bool
+	draining          atomic.Bool
+	loops             atomic.Int32
+	stopOnce          sync.Once
+	drainWg           sync.WaitGroup
 	stopCh            chan struct{}
 	wg                sync.WaitGroup
 }
@@ -46,30 +74,118 @@ func NewScheduler(
 	}
 }
 
+// AddWorker registers a worker started and stopped together with the scheduler.
+// Must be called before Start.
+func (s *Scheduler) AddWorker(w Worker) {
+	s.workers = append(s.workers, w)
+}
+
 // Start starts all scheduled jobs
 func (s *Scheduler) Start(ctx context.Context) {
 	s.logger.Info().Msg("Starting job scheduler")
+	// Jobs get their own context: Shutdown cancels it only if they do not finish in time.
+	ctx, s.cancel = context.WithCancel(ctx)
 	
 	// Initialize jobs
 	s.dailyVoteJob = NewDailyVoteGrantJob(s.db, s.ticketService, s.logger)
 	// weekly job initialized lazily in runner
 	
 	// Start job runners
-	s.wg.Add(6)
-	go s.runDailyVoteJob(ctx)
-	go s.runWeeklyTicketJob(ctx)
-	go s.runVotingWinnerJob(ctx)
-	go s.runTranslationWinnerJob(ctx)
-	go s.runSubscriptionExpiryJob(ctx)
-	go s.runCleanupJob(ctx)
+	s.wg.Add(jobLoops)
+	for _, run := range []func(context.Context){
+		s.runDailyVoteJob,
+		s.runWeeklyTicketJob,
+		s.runVotingWinnerJob,
+		s.runTranslationWinnerJob,
+		s.runSubscriptionExpiryJob,
+		s.runCleanupJob,
+	} {
+		run := run
+		s.loops.Add(1)
+		go func() {
+			defer s.loops.Add(-1)
+			run(ctx)
+		}()
+	}
+	s.started.Store(true)
+
+	for _, w := range s.workers {
+		w.Start(ctx)
+	}
 }
 
-// Stop stops all scheduled jobs
+// Stop stops all scheduled jobs, waiting for the ones in progress to finish.
 func (s *Scheduler) Stop() {
-	s.logger.Info().Msg("Stopping job scheduler")
-	close(s.stopCh)
-	s.wg.Wait()
-	s.logger.Info().Msg("Job scheduler stopped")
+	s.stopOnce.Do(func() {
+		s.logger.Info().Msg("Stopping job scheduler")
+		s.draining.Store(true)
+		close(s.stopCh)
+		for _, w := range s.workers {
+			w.Stop()
+		}
+		s.wg.Wait()
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.logger.Info().Msg("Job scheduler stopped")
+	})
+}
+
+// Drain marks the scheduler as shutting down (Health starts failing, so the instance leaves
+// the load balancer) and lets workers that support it stop taking new work. It does not wait:
+// the workers drain in the background while the HTTP server finishes its requests.
+func (s *Scheduler) Drain(ctx context.Context) {
+	if s.draining.Swap(true) {
+		return
+	}
+	s.logger.Info().Msg("Draining job scheduler")
+	for _, w := range s.workers {
+		if d, ok := w.(Drainer); ok {
+			s.drainWg.Add(1)
+			go func() {
+				defer s.drainWg.Done()
+				d.Drain(ctx)
+			}()
+		}
+	}
+}
+
+// Shutdown drains and stops the scheduler, waiting for jobs in progress until ctx is done;
+// then their context is cancelled and Shutdown waits for them to return.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.Drain(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		s.drainWg.Wait()
+		s.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.logger.Warn().Msg("Jobs did not finish in time, cancelling them")
+		if s.cancel != nil {
+			s.cancel()
+		}
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Health reports whether the job loops are running; health.ErrDraining once shutdown began.
+func (s *Scheduler) Health(context.Context) error {
+	if s.draining.Load() {
+		return health.ErrDraining
+	}
+	if !s.started.Load() {
+		return fmt.Errorf("scheduler not started")
+	}
+	if n := s.loops.Load(); n < jobLoops {
+		return fmt.Errorf("%d of %d job loops running", n, jobLoops)
+	}
+	return nil
 }
 
 // runDailyVoteJob runs daily at 00:00 UTC (3:00 MSK)
@@ -163,7 +279,7 @@ func (s *Scheduler) runVotingWinnerJob(ctx context.Context) {
 		case <-ticker.C:
 			s.logger.Info().Msg("Running voting winner selection job")
 			
-			if err := s.votingService.ProcessVotingWinner(ctx); err != nil {
+			if err := telemetry.RunJob(ctx, "voting_winner", s.votingService.ProcessVotingWinner); err != nil {
 				s.logger.Error().Err(err).Msg("Voting winner job failed")
 			}
 		}
@@ -189,7 +305,7 @@ func (s *Scheduler) runTranslationWinnerJob(ctx context.Context) {
 				s.logger.Error().Msg("TranslationVotingService is not configured")
 				continue
 			}
-			if err := s.translationVotingService.ProcessTranslationWinner(ctx); err != nil {
+			if err := telemetry.RunJob(ctx, "translation_winner", s.translationVotingService.ProcessTranslationWinner); err != nil {
 				s.logger.Error().Err(err).Msg("Translation winner job failed")
 			}
 		}
@@ -206,7 +322,7 @@ func (s *Scheduler) runSubscriptionExpiryJob(ctx context.Context) {
 	s.logger.Info().Msg("Subscription expiry job started (every hour)")
 	
 	// Run immediately on start
-	s.subscriptionService.ExpireSubscriptions(ctx)
+	telemetry.RunJob(ctx, "subscription_expiry", s.subscriptionService.ExpireSubscriptions)
 	
 	for {
 		select {
@@ -215,7 +331,7 @@ func (s *Scheduler) runSubscriptionExpiryJob(ctx context.Context) {
 		case <-ticker.C:
 			s.logger.Debug().Msg("Running subscription expiry job")
 			
-			if err := s.subscriptionService.ExpireSubscriptions(ctx); err != nil {
+			if err := telemetry.RunJob(ctx, "subscription_expiry", s.subscriptionService.ExpireSubscriptions); err != nil {
 				s.logger.Error().Err(err).Msg("Subscription expiry job failed")
 			}
 		}
@@ -237,7 +353,10 @@ func (s *Scheduler) runCleanupJob(ctx context.Context) {
 			return
 		case <-ticker.C:
 			s.logger.Info().Msg("Running cleanup job")
-			s.runCleanupTasks(ctx)
+			telemetry.RunJob(ctx, "cleanup", func(ctx context.Context) error {
+				s.runCleanupTasks(ctx)
+				return nil
+			})
 		}
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"novels-backend/internal/domain/models"
 	"novels-backend/internal/repository"
+	"novels-backend/internal/telemetry"
 	"github.com/rs/zerolog"
 )
 
@@ -41,6 +42,10 @@ func NewWeeklyTicketGrantJob(
 
 // Run executes the weekly ticket grant.
 func (j *WeeklyTicketGrantJob) Run(ctx context.Context) error {
+	return telemetry.RunJob(ctx, "weekly_ticket_grant", j.run)
+}
+
+func (j *WeeklyTicketGrantJob) run(ctx context.Context) error {
 	j.logger.Info().Msg("Starting weekly ticket grant job")
 
 	startTime := time.Now()
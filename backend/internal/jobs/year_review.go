@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/service"
+	"novels-backend/internal/telemetry"
+)
+
+// YearReviewJob generates and caches the year in review of readers: the past year once it
+// ended and, during December, the running one (see ReadingStatsService.GenerateYearReviews).
+type YearReviewJob struct {
+	stats    *service.ReadingStatsService
+	interval time.Duration
+	logger   zerolog.Logger
+
+	cancel context.CancelFunc
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewYearReviewJob(stats *service.ReadingStatsService, interval time.Duration, logger zerolog.Logger) *YearReviewJob {
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+	return &YearReviewJob{
+		stats:    stats,
+		interval: interval,
+		logger:   logger.With().Str("job", "year_review").Logger(),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start implements Worker. The first run happens right away.
+func (j *YearReviewJob) Start(ctx context.Context) {
+	// Generation over all readers can take a while; Stop interrupts it and the next start
+	// continues with the reviews still missing.
+	ctx, j.cancel = context.WithCancel(ctx)
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		j.logger.Info().Dur("interval", j.interval).Msg("Year review job scheduled")
+		for {
+			if _, err := j.Run(ctx); err != nil && ctx.Err() == nil {
+				j.logger.Error().Err(err).Msg("Year review job failed")
+			}
+			select {
+			case <-j.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop implements Worker.
+func (j *YearReviewJob) Stop() {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	close(j.stopCh)
+	j.wg.Wait()
+}
+
+// Run generates the due reviews and returns how many were generated.
+func (j *YearReviewJob) Run(ctx context.Context) (int, error) {
+	generated := 0
+	err := telemetry.RunJob(ctx, "year_review", func(ctx context.Context) error {
+		var err error
+		generated, err = j.stats.GenerateYearReviews(ctx, time.Now())
+		return err
+	})
+	return generated, err
+}
@@ -0,0 +1,263 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/importer"
+	"novels-backend/internal/telemetry"
+)
+
+var (
+	// ErrNoImporter is returned for links no registered importer can import.
+	ErrNoImporter = errors.New("no importer registered for original link")
+	// ErrSyncNotSupported is returned when syncing a novel whose importer is one-shot.
+	ErrSyncNotSupported = errors.New("importer does not support sync")
+)
+
+// ImporterFor returns the importer that handles originalLink, or nil.
+func (o *ImportOrchestrator) ImporterFor(originalLink string) ProposalImporter {
+	return o.pickImporter(originalLink)
+}
+
+// Preview resolves the importer of originalLink and fetches the book metadata and chapter list
+// without importing anything. It returns the name of the importer and the preview.
+func (o *ImportOrchestrator) Preview(ctx context.Context, originalLink string, cookieHeader string) (string, *importer.BookPreview, error) {
+	imp := o.pickImporter(originalLink)
+	if imp == nil {
+		return "", nil, ErrNoImporter
+	}
+	preview, err := imp.Preview(ctx, originalLink, cookieHeader)
+	if err != nil {
+		return imp.Name(), nil, err
+	}
+	return imp.Name(), preview, nil
+}
+
+// LocalImport is an import executed by the calling process instead of a queue worker
+// (cmd/novelctl). It is recorded in import_runs with origin "cli", so it shows up in the
+// ops dashboard next to queued runs.
+type LocalImport struct {
+	// RunID resumes a CLI run from its checkpoint; uuid.Nil starts a new run.
+	RunID uuid.UUID
+	// URL is the book page. With ProposalID set it defaults to the proposal's original link.
+	URL string
+	// ProposalID links the imported novel to a proposal, as a queued import would.
+	ProposalID *uuid.UUID
+	Cookie     string
+	// From and To select a 1-based inclusive chapter range of a new run (0 = open end).
+	From, To int
+	// OnChapter is called after each saved chapter.
+	OnChapter func(cp *importer.Checkpoint, chaptersSaved int)
+}
+
+// RunLocal runs an import in the calling process and returns the finished run.
+// If ctx is cancelled the run is left paused and can be continued with LocalImport.RunID.
+func (o *ImportOrchestrator) RunLocal(ctx context.Context, req LocalImport) (*models.ImportRun, error) {
+	if o.importRuns == nil {
+		return nil, fmt.Errorf("import runs repository is not configured")
+	}
+
+	var (
+		run *models.ImportRun
+		p   *models.NovelProposal
+		err error
+	)
+	if req.RunID != uuid.Nil {
+		run, p, err = o.resumeLocalRun(ctx, req)
+	} else {
+		run, p, err = o.createLocalRun(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	runID := run.ID
+
+	imp := o.pickImporter(p.OriginalLink)
+	if imp == nil {
+		return run, ErrNoImporter
+	}
+	var cp *importer.Checkpoint
+	if len(run.Checkpoint) > 0 {
+		var tmp importer.Checkpoint
+		if err := json.Unmarshal(run.Checkpoint, &tmp); err != nil {
+			return run, fmt.Errorf("decode checkpoint: %w", err)
+		}
+		cp = &tmp
+	}
+
+	cookieHeader := strings.TrimSpace(req.Cookie)
+	if o.cookiesRepo != nil {
+		if cookieHeader != "" {
+			if err := o.cookiesRepo.Upsert(ctx, runID, cookieHeader); err != nil {
+				o.logger.Error().Err(err).Str("run_id", runID.String()).Msg("Failed to save cookies for import run")
+			}
+		} else if cookie, err := o.cookiesRepo.GetByRunID(ctx, runID); err == nil && cookie != nil {
+			cookieHeader = cookie.CookieHeader
+		}
+	}
+
+	if err := o.importRuns.SetStatus(ctx, runID, models.ImportRunStatusRunning); err != nil {
+		return run, err
+	}
+	o.logger.Info().
+		Str("run_id", runID.String()).
+		Str("importer", imp.Name()).
+		Str("url", p.OriginalLink).
+		Msg("Starting local import")
+
+	onChapter := func(c *importer.Checkpoint, saved int) error {
+		if c == nil {
+			return nil
+		}
+		if c.NovelID != uuid.Nil {
+			_ = o.importRuns.SetNovelID(ctx, runID, c.NovelID)
+		}
+		telemetry.ImportProgress(runID.String(), imp.Name(), c.NextIndex, c.TotalChapters)
+		if req.OnChapter != nil {
+			req.OnChapter(c, saved)
+		}
+		return o.importRuns.UpdateProgress(ctx, runID, c.NextIndex, c.TotalChapters, c)
+	}
+
+	spanCtx, span := telemetry.StartSpan(ctx, "import.run",
+		attribute.String("import.run_id", runID.String()),
+		attribute.String("import.importer", imp.Name()),
+		attribute.String("import.origin", string(models.ImportRunOriginCLI)),
+	)
+	novelID, _, err := imp.Import(spanCtx, o.db, p, o.uploadsDir, cp, onChapter, cookieHeader)
+	telemetry.EndSpan(span, err)
+	if err != nil {
+		errMsg := err.Error()
+		if ctx.Err() != nil {
+			// Interrupted (Ctrl+C): keep the checkpoint for --resume.
+			_ = o.importRuns.SetStatus(context.Background(), runID, models.ImportRunStatusPaused)
+			telemetry.ImportFinished(runID.String(), imp.Name(), string(models.ImportRunStatusPaused))
+			return o.reloadRun(run), fmt.Errorf("import interrupted, run %s paused: %w", runID, err)
+		}
+		cloudflareBlocked := isCloudflareError(errMsg)
+		_ = o.importRuns.SetResult(context.Background(), runID, models.ImportRunStatusFailed, nil, &errMsg, &cloudflareBlocked)
+		telemetry.ImportFinished(runID.String(), imp.Name(), string(models.ImportRunStatusFailed))
+		return o.reloadRun(run), err
+	}
+
+	if run.ProposalID != nil {
+		if errMsg := o.releaseProposal(ctx, runID, p, imp, novelID); errMsg != nil {
+			telemetry.ImportFinished(runID.String(), imp.Name(), string(models.ImportRunStatusFailed))
+			return o.reloadRun(run), errors.New(*errMsg)
+		}
+	} else {
+		o.linkSourceNovel(ctx, imp, p.OriginalLink, novelID, nil)
+		cloudflareBlocked := false
+		_ = o.importRuns.SetResult(context.Background(), runID, models.ImportRunStatusSucceeded, &novelID, nil, &cloudflareBlocked)
+	}
+	telemetry.ImportFinished(runID.String(), imp.Name(), string(models.ImportRunStatusSucceeded))
+	return o.reloadRun(run), nil
+}
+
+func (o *ImportOrchestrator) createLocalRun(ctx context.Context, req LocalImport) (*models.ImportRun, *models.NovelProposal, error) {
+	if req.From < 0 || req.To < 0 || (req.To > 0 && req.To < req.From) {
+		return nil, nil, fmt.Errorf("invalid chapter range %d..%d", req.From, req.To)
+	}
+
+	link := strings.TrimSpace(req.URL)
+	p := &models.NovelProposal{OriginalLink: link}
+	if req.ProposalID != nil {
+		loaded, err := o.votingRepo.GetProposalByID(ctx, *req.ProposalID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load proposal: %w", err)
+		}
+		if loaded == nil {
+			return nil, nil, fmt.Errorf("proposal %s not found", req.ProposalID)
+		}
+		p = loaded
+		if link == "" {
+			link = p.OriginalLink
+		} else {
+			withLink := *p
+			withLink.OriginalLink = link
+			p = &withLink
+		}
+	}
+	if link == "" {
+		return nil, nil, fmt.Errorf("url or proposal is required")
+	}
+	imp := o.pickImporter(link)
+	if imp == nil {
+		return nil, nil, ErrNoImporter
+	}
+
+	cp := importer.Checkpoint{EndIndex: req.To}
+	if req.From > 0 {
+		cp.NextIndex = req.From - 1
+	}
+	cpJSON, _ := json.Marshal(cp)
+	run := &models.ImportRun{
+		ID:         uuid.New(),
+		ProposalID: req.ProposalID,
+		Importer:   imp.Name(),
+		Origin:     models.ImportRunOriginCLI,
+		SourceURL:  &link,
+		Status:     models.ImportRunStatusQueued,
+		Checkpoint: cpJSON,
+	}
+	if err := o.importRuns.Create(ctx, run); err != nil {
+		return nil, nil, err
+	}
+	return run, p, nil
+}
+
+func (o *ImportOrchestrator) resumeLocalRun(ctx context.Context, req LocalImport) (*models.ImportRun, *models.NovelProposal, error) {
+	if req.From != 0 || req.To != 0 {
+		return nil, nil, fmt.Errorf("chapter range cannot be changed when resuming, the run continues from its checkpoint")
+	}
+	run, err := o.importRuns.GetByID(ctx, req.RunID)
+	if err != nil || run == nil {
+		return nil, nil, fmt.Errorf("import run %s not found", req.RunID)
+	}
+	if run.Origin != models.ImportRunOriginCLI {
+		return nil, nil, fmt.Errorf("run %s belongs to the import queue, resume it from the ops dashboard", run.ID)
+	}
+	if run.Kind != models.ImportRunKindImport {
+		return nil, nil, fmt.Errorf("run %s is a %s run and cannot be resumed", run.ID, run.Kind)
+	}
+	if run.Status == models.ImportRunStatusSucceeded {
+		return nil, nil, fmt.Errorf("run %s has already succeeded", run.ID)
+	}
+
+	var p *models.NovelProposal
+	switch {
+	case run.ProposalID != nil:
+		p, err = o.votingRepo.GetProposalByID(ctx, *run.ProposalID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load proposal: %w", err)
+		}
+		if p == nil {
+			return nil, nil, fmt.Errorf("proposal %s not found", run.ProposalID)
+		}
+		if run.SourceURL != nil {
+			withLink := *p
+			withLink.OriginalLink = *run.SourceURL
+			p = &withLink
+		}
+	case run.SourceURL != nil:
+		p = &models.NovelProposal{OriginalLink: *run.SourceURL}
+	default:
+		return nil, nil, fmt.Errorf("run %s has neither a proposal nor a source url", run.ID)
+	}
+	return run, p, nil
+}
+
+func (o *ImportOrchestrator) reloadRun(run *models.ImportRun) *models.ImportRun {
+	if fresh, err := o.importRuns.GetByID(context.Background(), run.ID); err == nil && fresh != nil {
+		return fresh
+	}
+	return run
+}
@@ -3,7 +3,7 @@ package orchestrator
 import (
 	"context"
 	"encoding/json"
-	"net/url"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +16,7 @@ import (
 	"novels-backend/internal/events"
 	"novels-backend/internal/importer"
 	"novels-backend/internal/repository"
+	"novels-backend/internal/telemetry"
 )
 
 // isCloudflareError checks if an error message indicates Cloudflare blocking
@@ -43,43 +44,79 @@ func isCloudflareError(errMsg string) bool {
 type ProposalImporter interface {
 	Name() string
 	CanImport(originalLink string) bool
+	// CanonicalURL returns the book page URL in one fixed form, so links to one book compare equal
+	// (used for duplicate detection, see Importers.CanonicalURL).
+	CanonicalURL(originalLink string) string
+	// Preview fetches book metadata and the chapter list without importing anything.
+	Preview(ctx context.Context, originalLink string, cookieHeader string) (*importer.BookPreview, error)
 	Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string, checkpoint *importer.Checkpoint, onChapter func(cp *importer.Checkpoint, chaptersSaved int) error, cookieHeader string) (uuid.UUID, *importer.Checkpoint, error)
 }
 
+// oneShotImporter is implemented by importers that import a book in one go (FanqieImporter):
+// their runs cannot resume from a checkpoint and their novels cannot be synced.
+type oneShotImporter interface {
+	OneShot()
+}
+
 type ImportOrchestrator struct {
 	db         *sqlx.DB
 	votingRepo *repository.VotingRepository
 	importRuns *repository.ImportRunsRepository
+	importJobs *repository.ImportJobsRepository
 	cookiesRepo *repository.ImportRunCookiesRepository
+	novelSources *repository.NovelSourcesRepository
+	sourceLinks *repository.SourceLinksRepository
 	bus        *events.Bus
 	uploadsDir string
 	importers  []ProposalImporter
+	queue      QueueOptions
 	logger     zerolog.Logger
 
 	mu            sync.Mutex
-	activeCancels map[uuid.UUID]context.CancelFunc // runID -> cancel
+	activeCancels map[uuid.UUID]context.CancelCauseFunc // runID -> cancel (runs leased by this process)
+	wake          map[string]chan struct{}             // importer -> dispatcher wake-up
+	draining      bool                                 // no new jobs are claimed
+	runs          sync.WaitGroup                       // runs in progress
+	stopCh        chan struct{}
+	wg            sync.WaitGroup
 }
 
 func NewImportOrchestrator(
 	db *sqlx.DB,
 	votingRepo *repository.VotingRepository,
 	importRuns *repository.ImportRunsRepository,
+	importJobs *repository.ImportJobsRepository,
 	cookiesRepo *repository.ImportRunCookiesRepository,
+	novelSources *repository.NovelSourcesRepository,
+	sourceLinks *repository.SourceLinksRepository,
 	bus *events.Bus,
 	uploadsDir string,
 	importers []ProposalImporter,
+	queue QueueOptions,
 	logger zerolog.Logger,
 ) *ImportOrchestrator {
+	wake := map[string]chan struct{}{}
+	for _, imp := range importers {
+		if imp != nil {
+			wake[imp.Name()] = make(chan struct{}, 1)
+		}
+	}
 	return &ImportOrchestrator{
 		db:         db,
 		votingRepo: votingRepo,
 		importRuns: importRuns,
+		importJobs: importJobs,
 		cookiesRepo: cookiesRepo,
+		novelSources: novelSources,
+		sourceLinks: sourceLinks,
 		bus:        bus,
 		uploadsDir: uploadsDir,
 		importers:  importers,
+		queue:      queue.withDefaults(),
 		logger:     logger.With().Str("component", "import_orchestrator").Logger(),
-		activeCancels: map[uuid.UUID]context.CancelFunc{},
+		activeCancels: map[uuid.UUID]context.CancelCauseFunc{},
+		wake:          wake,
+		stopCh:        make(chan struct{}),
 	}
 }
 
@@ -88,87 +125,288 @@ func (o *ImportOrchestrator) Register() {
 		return
 	}
 
-	o.bus.Subscribe(events.EventDailyVoteWinnerSelected, func(_ context.Context, evt events.Event) error {
+	o.bus.Subscribe(events.EventDailyVoteWinnerSelected, "import_orchestrator.enqueue_import", func(ctx context.Context, evt events.Event) error {
 		e := evt.(events.DailyVoteWinnerSelected)
 
-		// Run import async: winner job should stay fast and deterministic.
-		o.StartImportAsync(e.ProposalID)
+		// The outbox may deliver the event again; a winner is imported only once.
+		if o.importRuns != nil {
+			exists, err := o.importRuns.HasImportForProposal(ctx, e.ProposalID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return nil
+			}
+		}
+
+		// Only enqueue here: winner job should stay fast and deterministic.
+		o.StartImportAsync(ctx, e.ProposalID)
 		return nil
-	})
+	}, events.WithTimeout(30*time.Second), events.WithRetry(3, time.Second))
+}
+
+// StartImportAsync enqueues an import of a proposal and returns the run ID.
+// The run is picked up by a queue worker (see import_queue.go), possibly on another instance;
+// ctx only carries the trace the run continues, its cancellation does not affect the run.
+func (o *ImportOrchestrator) StartImportAsync(ctx context.Context, proposalID uuid.UUID) uuid.UUID {
+	return o.enqueueNewRun(ctx, proposalID, "")
+}
+
+// StartImportAsyncWithCookies enqueues an import of a proposal with custom cookies and returns the run ID.
+func (o *ImportOrchestrator) StartImportAsyncWithCookies(ctx context.Context, proposalID uuid.UUID, cookieHeader string) uuid.UUID {
+	return o.enqueueNewRun(ctx, proposalID, cookieHeader)
 }
 
-// StartImportAsync starts importing a proposal in background and returns the run ID.
-func (o *ImportOrchestrator) StartImportAsync(proposalID uuid.UUID) uuid.UUID {
+func (o *ImportOrchestrator) enqueueNewRun(ctx context.Context, proposalID uuid.UUID, cookieHeader string) uuid.UUID {
+	ctx = context.WithoutCancel(ctx)
 	runID := uuid.New()
-	go o.handleImportRun(runID, proposalID, "")
+	if o.importRuns == nil || o.importJobs == nil {
+		o.logger.Error().Str("proposal_id", proposalID.String()).Msg("Import queue is not configured")
+		return runID
+	}
+
+	importerName := ""
+	var failMsg string
+	p, err := o.votingRepo.GetProposalByID(ctx, proposalID)
+	switch {
+	case err != nil:
+		failMsg = "failed to load proposal: " + err.Error()
+	case p == nil:
+		failMsg = "proposal not found"
+	default:
+		if imp := o.pickImporter(p.OriginalLink); imp != nil {
+			importerName = imp.Name()
+		} else {
+			failMsg = "no importer registered for original link"
+		}
+	}
+
+	if err := o.importRuns.Create(ctx, &models.ImportRun{
+		ID:         runID,
+		ProposalID: &proposalID,
+		Importer:   importerName,
+		Status:     models.ImportRunStatusQueued,
+	}); err != nil {
+		o.logger.Error().Err(err).Str("proposal_id", proposalID.String()).Msg("Failed to create import run")
+		return runID
+	}
+	if failMsg != "" {
+		cloudflareBlocked := false
+		_ = o.importRuns.SetResult(ctx, runID, models.ImportRunStatusFailed, nil, &failMsg, &cloudflareBlocked)
+		o.logger.Error().Str("run_id", runID.String()).Str("proposal_id", proposalID.String()).Msg("Import not enqueued: " + failMsg)
+		return runID
+	}
+
+	if cookieHeader != "" && o.cookiesRepo != nil {
+		if err := o.cookiesRepo.Upsert(ctx, runID, cookieHeader); err != nil {
+			o.logger.Error().Err(err).Str("run_id", runID.String()).Msg("Failed to save cookies for import run")
+		}
+	}
+
+	if err := o.importJobs.Enqueue(ctx, runID, proposalID, importerName); err != nil {
+		errMsg := err.Error()
+		cloudflareBlocked := false
+		_ = o.importRuns.SetResult(ctx, runID, models.ImportRunStatusFailed, nil, &errMsg, &cloudflareBlocked)
+		o.logger.Error().Err(err).Str("run_id", runID.String()).Msg("Failed to enqueue import run")
+		return runID
+	}
+	o.notify(importerName)
+
+	o.logger.Info().
+		Str("run_id", runID.String()).
+		Str("proposal_id", proposalID.String()).
+		Str("importer", importerName).
+		Msg("Import run enqueued")
 	return runID
 }
 
-// StartImportAsyncWithCookies starts importing a proposal with custom cookies in background and returns the run ID.
-func (o *ImportOrchestrator) StartImportAsyncWithCookies(proposalID uuid.UUID, cookieHeader string) uuid.UUID {
+// StartSyncAsync enqueues a sync run that imports chapters published at the source
+// since the novel was imported (or last synced).
+func (o *ImportOrchestrator) StartSyncAsync(ctx context.Context, novelID uuid.UUID) (uuid.UUID, error) {
+	if o.importRuns == nil || o.importJobs == nil || o.novelSources == nil {
+		return uuid.Nil, fmt.Errorf("import queue is not configured")
+	}
+	src, err := o.novelSources.GetByNovelID(ctx, novelID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("load novel source: %w", err)
+	}
+	imp := o.pickImporter(src.SourceURL)
+	if imp == nil {
+		return uuid.Nil, fmt.Errorf("no importer registered for %s", src.SourceURL)
+	}
+	if _, oneShot := imp.(oneShotImporter); oneShot {
+		// Every run would be rejected by the importer; stop the schedule from retrying it.
+		syncEnabled := false
+		if err := o.novelSources.Update(ctx, novelID, models.UpdateNovelSourceRequest{SyncEnabled: &syncEnabled}); err != nil {
+			o.logger.Error().Err(err).Str("novel_id", novelID.String()).Msg("Failed to disable sync of one-shot novel source")
+		}
+		return uuid.Nil, fmt.Errorf("%w: %s", ErrSyncNotSupported, imp.Name())
+	}
+
+	cp, _ := json.Marshal(importer.Checkpoint{NovelID: novelID, Sync: true})
 	runID := uuid.New()
-	go o.handleImportRun(runID, proposalID, cookieHeader)
-	return runID
+	if err := o.importRuns.Create(ctx, &models.ImportRun{
+		ID:         runID,
+		ProposalID: &src.ProposalID,
+		NovelID:    &novelID,
+		Importer:   src.Importer,
+		Kind:       models.ImportRunKindSync,
+		Status:     models.ImportRunStatusQueued,
+		Checkpoint: cp,
+	}); err != nil {
+		return uuid.Nil, err
+	}
+	if err := o.importJobs.Enqueue(ctx, runID, src.ProposalID, src.Importer); err != nil {
+		errMsg := err.Error()
+		cloudflareBlocked := false
+		_ = o.importRuns.SetResult(ctx, runID, models.ImportRunStatusFailed, nil, &errMsg, &cloudflareBlocked)
+		return uuid.Nil, err
+	}
+	if err := o.novelSources.MarkQueued(ctx, novelID, runID); err != nil {
+		o.logger.Error().Err(err).Str("novel_id", novelID.String()).Msg("Failed to mark novel source queued")
+	}
+	o.notify(src.Importer)
+
+	o.logger.Info().
+		Str("run_id", runID.String()).
+		Str("novel_id", novelID.String()).
+		Str("importer", src.Importer).
+		Msg("Novel sync enqueued")
+	return runID, nil
 }
 
-// ResumeImportAsync resumes a paused import run by its run ID.
-func (o *ImportOrchestrator) ResumeImportAsync(runID uuid.UUID) {
-	if o.importRuns == nil {
+// ResumeImportAsync re-enqueues a paused import run; it continues from the saved checkpoint.
+func (o *ImportOrchestrator) ResumeImportAsync(ctx context.Context, runID uuid.UUID) {
+	if o.importRuns == nil || o.importJobs == nil {
 		return
 	}
-	run, err := o.importRuns.GetByID(context.Background(), runID)
+	ctx = context.WithoutCancel(ctx)
+	run, err := o.importRuns.GetByID(ctx, runID)
 	if err != nil || run == nil {
 		return
 	}
-	// Only resume paused/pause_requested runs.
+	// Only resume paused/pause_requested runs; runs of cmd/novelctl are resumed by novelctl.
 	if run.Status != models.ImportRunStatusPaused && run.Status != models.ImportRunStatusPauseRequested {
 		return
 	}
-	go o.handleImportRun(runID, run.ProposalID, "")
+	if run.Origin == models.ImportRunOriginCLI || run.ProposalID == nil {
+		return
+	}
+	if err := o.importRuns.SetStatus(ctx, runID, models.ImportRunStatusQueued); err != nil {
+		o.logger.Error().Err(err).Str("run_id", runID.String()).Msg("Failed to mark import run queued")
+		return
+	}
+	if err := o.importJobs.Enqueue(ctx, runID, *run.ProposalID, run.Importer); err != nil {
+		o.logger.Error().Err(err).Str("run_id", runID.String()).Msg("Failed to enqueue resumed import run")
+		return
+	}
+	o.notify(run.Importer)
 }
 
-// CancelImport requests cancellation for an active import run.
+// CancelImport requests cancellation of a queued or active import run.
+// Runs leased by another instance are stopped by their owner on its next heartbeat.
 func (o *ImportOrchestrator) CancelImport(runID uuid.UUID) bool {
 	o.mu.Lock()
 	cancel := o.activeCancels[runID]
 	o.mu.Unlock()
-	if cancel == nil {
+	if cancel != nil {
+		cancel(nil)
+		return true
+	}
+	if o.importJobs == nil {
 		return false
 	}
-	cancel()
-	return true
+	ctx := context.Background()
+	found, dequeued, err := o.importJobs.RequestCancel(ctx, runID)
+	if err != nil {
+		o.logger.Error().Err(err).Str("run_id", runID.String()).Msg("Failed to request import cancel")
+		return false
+	}
+	if dequeued && o.importRuns != nil {
+		errMsg := "cancelled before start"
+		cloudflareBlocked := false
+		_ = o.importRuns.SetResult(ctx, runID, models.ImportRunStatusCancelled, nil, &errMsg, &cloudflareBlocked)
+	}
+	return found
 }
 
-// PauseImport requests pause for an active import run (cooperative via ctx cancellation).
+// PauseImport requests pause for a queued or active import run (cooperative via ctx cancellation).
 func (o *ImportOrchestrator) PauseImport(runID uuid.UUID) bool {
-	if o.importRuns != nil {
-		_ = o.importRuns.SetStatus(context.Background(), runID, models.ImportRunStatusPauseRequested)
+	if o.importRuns == nil || o.importJobs == nil {
+		return false
+	}
+	ctx := context.Background()
+	if dequeued, err := o.importJobs.Dequeue(ctx, runID); err == nil && dequeued {
+		_ = o.importRuns.SetStatus(ctx, runID, models.ImportRunStatusPaused)
+		return true
+	}
+
+	o.mu.Lock()
+	cancel := o.activeCancels[runID]
+	o.mu.Unlock()
+	if cancel == nil {
+		job, err := o.importJobs.GetByRunID(ctx, runID)
+		if err != nil || job == nil || job.Status != models.ImportJobStatusLeased {
+			return false
+		}
 	}
-	return o.CancelImport(runID)
+	_ = o.importRuns.SetStatus(ctx, runID, models.ImportRunStatusPauseRequested)
+	if cancel != nil {
+		cancel(errPauseRequested)
+	}
+	return true
 }
 
-func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UUID, cookieHeader string) {
+// handleImportRun executes a leased run. It returns the error message recorded on the run, if any.
+// Runs interrupted by shutdown or a lost lease keep their status: the queue hands them to the next worker.
+func (o *ImportOrchestrator) handleImportRun(parent context.Context, runID uuid.UUID, proposalID uuid.UUID) *string {
 	// Imports can be very long for large books (1000+ chapters) because parser-service uses real browser.
 	// We allow a larger budget and rely on pause/cancel for control.
-	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Hour)
-	o.mu.Lock()
-	o.activeCancels[runID] = cancel
-	o.mu.Unlock()
-	defer func() {
-		cancel()
-		o.mu.Lock()
-		delete(o.activeCancels, runID)
-		o.mu.Unlock()
-	}()
+	ctx, cancel := context.WithTimeout(parent, 6*time.Hour)
+	defer cancel()
+
+	// Sync runs fetch new chapters of an already imported novel; their outcome goes to novel_sources.
+	var syncSource *models.NovelSource
+	if o.importRuns != nil && o.novelSources != nil {
+		if run, err := o.importRuns.GetByID(ctx, runID); err == nil && run != nil && run.Kind == models.ImportRunKindSync && run.NovelID != nil {
+			if src, err := o.novelSources.GetByNovelID(ctx, *run.NovelID); err == nil {
+				syncSource = src
+			}
+		}
+	}
+	newChapters := 0
+	recordSync := func(status models.ImportRunStatus, errMsg *string) {
+		if syncSource != nil {
+			_ = o.novelSources.SetSyncResult(context.Background(), syncSource.NovelID, runID, status, newChapters, errMsg)
+		}
+	}
+
+	fail := func(errMsg string) *string {
+		if interruptedByQueue(ctx) {
+			return nil
+		}
+		if o.importRuns != nil {
+			cloudflareBlocked := false
+			_ = o.importRuns.SetResult(context.Background(), runID, models.ImportRunStatusFailed, nil, &errMsg, &cloudflareBlocked)
+		}
+		recordSync(models.ImportRunStatusFailed, &errMsg)
+		return &errMsg
+	}
 
 	p, err := o.votingRepo.GetProposalByID(ctx, proposalID)
 	if err != nil {
 		o.logger.Error().Err(err).Str("proposal_id", proposalID.String()).Msg("Failed to load proposal for import")
-		return
+		return fail("failed to load proposal: " + err.Error())
 	}
 	if p == nil {
 		o.logger.Error().Str("proposal_id", proposalID.String()).Msg("Proposal not found for import")
-		return
+		return fail("proposal not found")
+	}
+	if syncSource != nil {
+		// The source link may have been corrected after the initial import.
+		synced := *p
+		synced.OriginalLink = syncSource.SourceURL
+		p = &synced
 	}
 
 	imp := o.pickImporter(p.OriginalLink)
@@ -177,7 +415,7 @@ func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UU
 			Str("proposal_id", proposalID.String()).
 			Str("original_link", p.OriginalLink).
 			Msg("No importer registered for proposal original_link")
-		return
+		return fail("no importer registered for original link")
 	}
 
 	o.logger.Info().
@@ -186,22 +424,11 @@ func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UU
 		Str("importer", imp.Name()).
 		Msg("Starting import for daily vote winner")
 
-	// Record run
 	if o.importRuns != nil {
-		// Create only if missing.
-		if existing, err := o.importRuns.GetByID(ctx, runID); err != nil || existing == nil {
-			_ = o.importRuns.Create(ctx, &models.ImportRun{
-				ID:         runID,
-				ProposalID: proposalID,
-				Importer:   imp.Name(),
-				Status:     models.ImportRunStatusRunning,
-			})
-		} else {
-			_ = o.importRuns.SetStatus(ctx, runID, models.ImportRunStatusRunning)
-		}
+		_ = o.importRuns.SetStatus(ctx, runID, models.ImportRunStatusRunning)
 	}
 
-	// Load checkpoint if any.
+	// Load checkpoint if any (set by a previous attempt, a pause or a crashed worker).
 	var cp *importer.Checkpoint
 	totalFromDB := 0
 	if o.importRuns != nil {
@@ -215,8 +442,8 @@ func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UU
 		}
 	}
 
-	// Get cookies if not provided and run exists
-	if cookieHeader == "" && o.cookiesRepo != nil {
+	cookieHeader := ""
+	if o.cookiesRepo != nil {
 		if cookie, err := o.cookiesRepo.GetByRunID(ctx, runID); err == nil && cookie != nil {
 			cookieHeader = cookie.CookieHeader
 			o.logger.Info().Str("run_id", runID.String()).Msg("Loaded cookies from database for import run")
@@ -229,6 +456,7 @@ func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UU
 	}
 
 	onChapter := func(c *importer.Checkpoint, saved int) error {
+		newChapters = saved
 		if o.importRuns == nil || c == nil {
 			return nil
 		}
@@ -240,6 +468,7 @@ func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UU
 		if total == 0 {
 			total = totalFromDB
 		}
+		telemetry.ImportProgress(runID.String(), imp.Name(), c.NextIndex, total)
 		return o.importRuns.UpdateProgress(ctx, runID, c.NextIndex, total, c)
 	}
 
@@ -247,34 +476,67 @@ func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UU
 	if err != nil {
 		errMsg := err.Error()
 		if ctx.Err() == context.Canceled {
+			if interruptedByQueue(ctx) {
+				o.logger.Warn().Err(context.Cause(ctx)).Str("run_id", runID.String()).Str("proposal_id", proposalID.String()).Msg("Import interrupted, leaving run to the queue")
+				return nil
+			}
 			// Decide: pause vs cancel, based on persisted status.
 			if o.importRuns != nil {
 				if run, e := o.importRuns.GetByID(context.Background(), runID); e == nil && run != nil && run.Status == models.ImportRunStatusPauseRequested {
 					_ = o.importRuns.SetStatus(context.Background(), runID, models.ImportRunStatusPaused)
+					recordSync(models.ImportRunStatusPaused, nil)
 					o.logger.Warn().Str("run_id", runID.String()).Str("proposal_id", proposalID.String()).Msg("Import paused")
-					return
+					return nil
 				}
 			}
 			if o.importRuns != nil {
 				cloudflareBlocked := isCloudflareError(errMsg)
 				_ = o.importRuns.SetResult(context.Background(), runID, models.ImportRunStatusCancelled, nil, &errMsg, &cloudflareBlocked)
 			}
+			recordSync(models.ImportRunStatusCancelled, &errMsg)
 			o.logger.Warn().Err(err).Str("run_id", runID.String()).Str("proposal_id", proposalID.String()).Msg("Import cancelled")
-			return
+			return &errMsg
 		}
 		if o.importRuns != nil {
 			cloudflareBlocked := isCloudflareError(errMsg)
 			_ = o.importRuns.SetResult(context.Background(), runID, models.ImportRunStatusFailed, nil, &errMsg, &cloudflareBlocked)
 		}
+		recordSync(models.ImportRunStatusFailed, &errMsg)
 		o.logger.Error().
 			Err(err).
 			Str("run_id", runID.String()).
 			Str("proposal_id", proposalID.String()).
 			Str("importer", imp.Name()).
 			Msg("Import failed for daily vote winner")
-		return
+		return &errMsg
+	}
+
+	if syncSource != nil {
+		if o.importRuns != nil {
+			cloudflareBlocked := false
+			_ = o.importRuns.SetResult(context.Background(), runID, models.ImportRunStatusSucceeded, &novelID, nil, &cloudflareBlocked)
+		}
+		recordSync(models.ImportRunStatusSucceeded, nil)
+		o.logger.Info().
+			Str("run_id", runID.String()).
+			Str("novel_id", novelID.String()).
+			Int("new_chapters", newChapters).
+			Msg("Novel synced with source")
+		if o.bus != nil && newChapters > 0 {
+			if err := o.bus.Publish(ctx, events.CatalogChanged{NovelID: &novelID, Reason: events.CatalogReasonChapter}); err != nil {
+				o.logger.Error().Err(err).Str("novel_id", novelID.String()).Msg("Failed to publish catalog changed event")
+			}
+		}
+		return nil
 	}
 
+	return o.releaseProposal(ctx, runID, p, imp, novelID)
+}
+
+// releaseProposal links an imported novel to its proposal, records where it came from and
+// finishes the run. It returns the error message recorded on the run, if any.
+func (o *ImportOrchestrator) releaseProposal(ctx context.Context, runID uuid.UUID, p *models.NovelProposal, imp ProposalImporter, novelID uuid.UUID) *string {
+	proposalID := p.ID
 	if err := o.votingRepo.SetProposalNovelID(ctx, proposalID, novelID); err != nil {
 		errMsg := err.Error()
 		if o.importRuns != nil {
@@ -282,7 +544,7 @@ func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UU
 			_ = o.importRuns.SetResult(context.Background(), runID, models.ImportRunStatusFailed, &novelID, &errMsg, &cloudflareBlocked)
 		}
 		o.logger.Error().Err(err).Str("proposal_id", proposalID.String()).Str("novel_id", novelID.String()).Msg("Failed to link proposal->novel")
-		return
+		return &errMsg
 	}
 
 	o.logger.Info().
@@ -291,26 +553,40 @@ func (o *ImportOrchestrator) handleImportRun(runID uuid.UUID, proposalID uuid.UU
 		Str("novel_id", novelID.String()).
 		Msg("Proposal released into novel")
 
+	o.linkSourceNovel(ctx, imp, p.OriginalLink, novelID, &proposalID)
+
+	// Novels of one-shot importers cannot be synced, so they are not followed at the source.
+	if _, oneShot := imp.(oneShotImporter); o.novelSources != nil && !oneShot {
+		if err := o.novelSources.Upsert(ctx, novelID, proposalID, imp.Name(), p.OriginalLink); err != nil {
+			o.logger.Error().Err(err).Str("novel_id", novelID.String()).Msg("Failed to record novel source")
+		}
+	}
+
 	if o.importRuns != nil {
 		cloudflareBlocked := false
 		_ = o.importRuns.SetResult(context.Background(), runID, models.ImportRunStatusSucceeded, &novelID, nil, &cloudflareBlocked)
 	}
 
 	if o.bus != nil {
-		_ = o.bus.Publish(ctx, events.ProposalReleased{ProposalID: proposalID, NovelID: novelID})
+		if err := o.bus.Publish(ctx, events.ProposalReleased{ProposalID: proposalID, NovelID: novelID}); err != nil {
+			o.logger.Error().Err(err).Str("proposal_id", proposalID.String()).Msg("Failed to publish proposal released event")
+		}
 	}
+	return nil
 }
 
-func (o *ImportOrchestrator) pickImporter(originalLink string) ProposalImporter {
-	// normalize url (some users paste without scheme)
-	if _, err := url.ParseRequestURI(originalLink); err != nil {
-		// still allow CanImport() implementations to decide
+// linkSourceNovel registers the novel under the canonical URL of its source, so the book
+// cannot be proposed again.
+func (o *ImportOrchestrator) linkSourceNovel(ctx context.Context, imp ProposalImporter, originalLink string, novelID uuid.UUID, proposalID *uuid.UUID) {
+	if o.sourceLinks == nil {
+		return
 	}
-	for _, imp := range o.importers {
-		if imp != nil && imp.CanImport(originalLink) {
-			return imp
-		}
+	if err := o.sourceLinks.LinkNovel(ctx, imp.CanonicalURL(originalLink), imp.Name(), novelID, proposalID); err != nil {
+		o.logger.Error().Err(err).Str("novel_id", novelID.String()).Msg("Failed to record source link")
 	}
-	return nil
+}
+
+func (o *ImportOrchestrator) pickImporter(originalLink string) ProposalImporter {
+	return Importers(o.importers).For(originalLink)
 }
 
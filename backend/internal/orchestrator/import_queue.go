@@ -0,0 +1,360 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/telemetry"
+)
+
+// Cancellation causes for leased runs. Shutdown and lease loss leave the run to the queue;
+// pause/cancel requests go through the regular pause-vs-cancel handling in handleImportRun.
+var (
+	errShuttingDown    = errors.New("import worker is shutting down")
+	errLeaseLost       = errors.New("import job lease lost")
+	errPauseRequested  = errors.New("import pause requested")
+	errCancelRequested = errors.New("import cancel requested")
+)
+
+// QueueOptions configures the import queue workers.
+type QueueOptions struct {
+	// WorkerID identifies this process as lease owner; defaults to hostname-pid.
+	WorkerID          string
+	PollInterval      time.Duration
+	LeaseDuration     time.Duration
+	HeartbeatInterval time.Duration
+	// DefaultConcurrency caps leased jobs per importer across all instances;
+	// Concurrency overrides it per importer name.
+	DefaultConcurrency int
+	Concurrency        map[string]int
+}
+
+func (q QueueOptions) withDefaults() QueueOptions {
+	if q.WorkerID == "" {
+		host, _ := os.Hostname()
+		q.WorkerID = fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+	}
+	if q.PollInterval <= 0 {
+		q.PollInterval = 5 * time.Second
+	}
+	if q.LeaseDuration <= 0 {
+		q.LeaseDuration = 2 * time.Minute
+	}
+	if q.HeartbeatInterval <= 0 || q.HeartbeatInterval >= q.LeaseDuration {
+		q.HeartbeatInterval = q.LeaseDuration / 4
+	}
+	if q.DefaultConcurrency < 1 {
+		q.DefaultConcurrency = 1
+	}
+	return q
+}
+
+func (q QueueOptions) concurrencyFor(importer string) int {
+	if n, ok := q.Concurrency[importer]; ok && n > 0 {
+		return n
+	}
+	return q.DefaultConcurrency
+}
+
+// Start recovers runs left over by a previous process and starts one dispatcher per importer.
+// Run contexts are not derived from ctx: the scheduler cancels ctx when shutdown takes too long,
+// and a cancelled run would otherwise be recorded as cancelled instead of being handed back to the queue.
+func (o *ImportOrchestrator) Start(ctx context.Context) {
+	if o.importJobs == nil {
+		o.logger.Warn().Msg("Import queue is not configured, workers not started")
+		return
+	}
+	o.logger.Info().Str("worker_id", o.queue.WorkerID).Msg("Starting import queue workers")
+
+	o.recoverStale(ctx)
+
+	o.wg.Add(1)
+	go o.runReaper(ctx)
+
+	for name := range o.wake {
+		o.wg.Add(1)
+		go o.runDispatcher(ctx, name)
+	}
+}
+
+// Drain stops claiming jobs and hands the runs leased by this process back to the queue, as
+// on Stop but before the rest of the process shuts down: the runs stop cooperatively (their
+// checkpoint is saved after every chapter) and another instance resumes them from it.
+// Waits for the runs to be released until ctx is done. Imports can still be enqueued: the
+// queue is durable and other instances pick them up.
+func (o *ImportOrchestrator) Drain(ctx context.Context) {
+	if o.importJobs == nil {
+		return
+	}
+	o.mu.Lock()
+	o.draining = true
+	active := len(o.activeCancels)
+	for _, cancel := range o.activeCancels {
+		cancel(errShuttingDown)
+	}
+	o.mu.Unlock()
+	o.logger.Info().Int("active_runs", active).Msg("Draining import queue workers")
+
+	done := make(chan struct{})
+	go func() {
+		o.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		o.logger.Warn().Msg("Import runs were not released in time")
+	}
+}
+
+// Stop interrupts runs leased by this process, returns them to the queue and waits for workers.
+func (o *ImportOrchestrator) Stop() {
+	o.logger.Info().Msg("Stopping import queue workers")
+	close(o.stopCh)
+
+	o.mu.Lock()
+	for _, cancel := range o.activeCancels {
+		cancel(errShuttingDown)
+	}
+	o.mu.Unlock()
+
+	o.wg.Wait()
+	o.logger.Info().Msg("Import queue workers stopped")
+}
+
+// interruptedByQueue reports whether ctx was cancelled because the run goes back to the queue.
+func interruptedByQueue(ctx context.Context) bool {
+	cause := context.Cause(ctx)
+	return errors.Is(cause, errShuttingDown) || errors.Is(cause, errLeaseLost)
+}
+
+func (o *ImportOrchestrator) notify(importer string) {
+	if ch, ok := o.wake[importer]; ok {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (o *ImportOrchestrator) recoverStale(ctx context.Context) {
+	rec, err := o.importJobs.RecoverStale(ctx)
+	if err != nil {
+		o.logger.Error().Err(err).Msg("Import queue recovery failed")
+		return
+	}
+	if rec.Enqueued+rec.Paused+rec.Cancelled+rec.Exhausted > 0 {
+		o.logger.Info().
+			Int64("enqueued", rec.Enqueued).
+			Int64("paused", rec.Paused).
+			Int64("cancelled", rec.Cancelled).
+			Int64("exhausted", rec.Exhausted).
+			Msg("Recovered stale import runs")
+	}
+}
+
+// runReaper periodically reconciles runs whose worker died without a restart of this process.
+func (o *ImportOrchestrator) runReaper(ctx context.Context) {
+	defer o.wg.Done()
+
+	ticker := time.NewTicker(o.queue.LeaseDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.recoverStale(ctx)
+		}
+	}
+}
+
+// reserveRun counts a run about to be claimed unless the queue is draining.
+func (o *ImportOrchestrator) reserveRun() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.draining {
+		return false
+	}
+	o.runs.Add(1)
+	return true
+}
+
+// runDispatcher claims jobs for one importer until its concurrency cap is reached.
+func (o *ImportOrchestrator) runDispatcher(ctx context.Context, importerName string) {
+	defer o.wg.Done()
+
+	ticker := time.NewTicker(o.queue.PollInterval)
+	defer ticker.Stop()
+
+	limit := o.queue.concurrencyFor(importerName)
+	for {
+		for {
+			select {
+			case <-o.stopCh:
+				return
+			default:
+			}
+			if !o.reserveRun() {
+				break
+			}
+			job, err := o.importJobs.Claim(ctx, o.queue.WorkerID, importerName, limit, o.queue.LeaseDuration)
+			if err != nil {
+				o.runs.Done()
+				o.logger.Error().Err(err).Str("importer", importerName).Msg("Failed to claim import job")
+				break
+			}
+			if job == nil {
+				o.runs.Done()
+				break
+			}
+			o.wg.Add(1)
+			go o.runJob(job)
+		}
+
+		select {
+		case <-o.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake[importerName]:
+		}
+	}
+}
+
+func (o *ImportOrchestrator) runJob(job *models.ImportJob) {
+	defer o.wg.Done()
+	defer o.runs.Done()
+
+	// The run continues the trace of the request that enqueued it (if any).
+	traceCtx := context.Background()
+	if job.TraceParent != nil {
+		traceCtx = telemetry.WithTraceParent(traceCtx, *job.TraceParent)
+	}
+	traceCtx, span := telemetry.StartSpan(traceCtx, "import.run",
+		attribute.String("import.run_id", job.RunID.String()),
+		attribute.String("import.importer", job.Importer),
+		attribute.Int("import.attempt", job.Attempts),
+	)
+	defer span.End()
+
+	runCtx, cancel := context.WithCancelCause(traceCtx)
+	o.mu.Lock()
+	o.activeCancels[job.RunID] = cancel
+	draining := o.draining
+	o.mu.Unlock()
+	defer func() {
+		cancel(nil)
+		o.mu.Lock()
+		delete(o.activeCancels, job.RunID)
+		o.mu.Unlock()
+	}()
+
+	// Stop or Drain may have run between Claim and registering the cancel above.
+	select {
+	case <-o.stopCh:
+		_ = o.importJobs.Release(context.Background(), job.ID, o.queue.WorkerID)
+		return
+	default:
+	}
+	if draining {
+		_ = o.importJobs.Release(context.Background(), job.ID, o.queue.WorkerID)
+		return
+	}
+
+	o.logger.Info().
+		Str("run_id", job.RunID.String()).
+		Str("job_id", job.ID.String()).
+		Int("attempt", job.Attempts).
+		Msg("Import job leased")
+
+	hbDone := make(chan struct{})
+	go o.heartbeat(runCtx, job, cancel, hbDone)
+
+	errMsg := o.handleImportRun(runCtx, job.RunID, job.ProposalID)
+	close(hbDone)
+	if errMsg != nil {
+		span.SetStatus(codes.Error, *errMsg)
+	}
+
+	ctx := context.Background()
+	switch cause := context.Cause(runCtx); {
+	case errors.Is(cause, errLeaseLost):
+		// Another worker owns the job now.
+		telemetry.ImportFinished(job.RunID.String(), job.Importer, "interrupted")
+	case errors.Is(cause, errShuttingDown):
+		telemetry.ImportFinished(job.RunID.String(), job.Importer, "interrupted")
+		if err := o.importJobs.Release(ctx, job.ID, o.queue.WorkerID); err != nil {
+			o.logger.Error().Err(err).Str("run_id", job.RunID.String()).Msg("Failed to release import job")
+			return
+		}
+		if o.importRuns != nil {
+			_ = o.importRuns.SetStatus(ctx, job.RunID, models.ImportRunStatusQueued)
+		}
+	default:
+		telemetry.ImportFinished(job.RunID.String(), job.Importer, o.runOutcome(ctx, job.RunID, errMsg))
+		if err := o.importJobs.Finish(ctx, job.ID, o.queue.WorkerID, errMsg); err != nil {
+			o.logger.Error().Err(err).Str("run_id", job.RunID.String()).Msg("Failed to finish import job")
+		}
+	}
+}
+
+// runOutcome is the final status of a run for metrics (succeeded, failed, cancelled, paused).
+func (o *ImportOrchestrator) runOutcome(ctx context.Context, runID uuid.UUID, errMsg *string) string {
+	if o.importRuns != nil {
+		if run, err := o.importRuns.GetByID(ctx, runID); err == nil && run != nil {
+			return string(run.Status)
+		}
+	}
+	if errMsg != nil {
+		return string(models.ImportRunStatusFailed)
+	}
+	return string(models.ImportRunStatusSucceeded)
+}
+
+// heartbeat extends the lease while the run is in progress and relays pause/cancel
+// requests made through other instances.
+func (o *ImportOrchestrator) heartbeat(ctx context.Context, job *models.ImportJob, cancel context.CancelCauseFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(o.queue.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cancelRequested, runStatus, ok, err := o.importJobs.Heartbeat(ctx, job.ID, o.queue.WorkerID, o.queue.LeaseDuration)
+		if err != nil {
+			o.logger.Warn().Err(err).Str("run_id", job.RunID.String()).Msg("Import job heartbeat failed")
+			continue
+		}
+		switch {
+		case !ok:
+			o.logger.Warn().Str("run_id", job.RunID.String()).Msg("Import job lease lost")
+			cancel(errLeaseLost)
+			return
+		case cancelRequested:
+			cancel(errCancelRequested)
+			return
+		case runStatus == models.ImportRunStatusPauseRequested:
+			cancel(errPauseRequested)
+			return
+		}
+	}
+}
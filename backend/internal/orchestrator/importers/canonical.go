@@ -0,0 +1,35 @@
+package importers
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"novels-backend/internal/orchestrator"
+)
+
+// Book ids in the paths of any page of a book (book page, catalog, chapters).
+var (
+	taduBookPath    = regexp.MustCompile(`^/book/(?:catalogue/)?(\d+)`)
+	shuba69BookPath = regexp.MustCompile(`^/(?:book|txt)/(\d+)`)
+	kks101BookPath  = regexp.MustCompile(`^/(?:book|txt)/(\d+)`)
+	fanqieBookPath  = regexp.MustCompile(`^/page/(\d+)`)
+)
+
+// canonicalBookURL formats the book id found in the path of originalLink with format.
+// Links without a book id are only normalized.
+func canonicalBookURL(originalLink string, bookPath *regexp.Regexp, format string) string {
+	normalized, err := orchestrator.NormalizeURL(originalLink)
+	if err != nil {
+		return strings.TrimSpace(originalLink)
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return normalized
+	}
+	if m := bookPath.FindStringSubmatch(u.Path); m != nil {
+		return fmt.Sprintf(format, m[1])
+	}
+	return normalized
+}
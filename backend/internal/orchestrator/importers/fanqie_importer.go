@@ -2,6 +2,7 @@ package importers
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strings"
 
@@ -25,15 +26,48 @@ func (FanqieImporter) CanImport(originalLink string) bool {
 	return host == "fanqienovel.com" || strings.HasSuffix(host, ".fanqienovel.com")
 }
 
-func (FanqieImporter) Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string) (uuid.UUID, error) {
+func (FanqieImporter) CanonicalURL(originalLink string) string {
+	return canonicalBookURL(originalLink, fanqieBookPath, "https://fanqienovel.com/page/%s")
+}
+
+// OneShot marks fanqie imports as not resumable and not syncable.
+func (FanqieImporter) OneShot() {}
+
+func (FanqieImporter) Preview(ctx context.Context, originalLink string, cookieHeader string) (*importer.BookPreview, error) {
+	return importer.PreviewFanqie(ctx, importer.ImportFanqieOptions{
+		PageURL: originalLink,
+		Cookie:  strings.TrimSpace(cookieHeader),
+	})
+}
+
+// Import runs the fanqie importer, which saves the whole book in one transaction:
+// there is no partial progress to resume, so a run can only start from the first chapter.
+func (FanqieImporter) Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string, checkpoint *importer.Checkpoint, onChapter func(cp *importer.Checkpoint, chaptersSaved int) error, cookieHeader string) (uuid.UUID, *importer.Checkpoint, error) {
+	if checkpoint != nil && (checkpoint.NextIndex > 0 || checkpoint.Sync) {
+		return uuid.Nil, checkpoint, fmt.Errorf("fanqie import cannot resume or sync, start a new run")
+	}
 	res, err := importer.ImportFanqie(ctx, db, importer.ImportFanqieOptions{
 		PageURL:       proposal.OriginalLink,
-		ChaptersLimit: 0,
+		ChaptersLimit: checkpoint.ChaptersLimit(),
 		UploadDir:     uploadsDir,
+		Cookie:        strings.TrimSpace(cookieHeader),
 	})
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, checkpoint, err
+	}
+	cp := &importer.Checkpoint{
+		NovelID:       res.NovelID,
+		Slug:          res.Slug,
+		NextIndex:     res.ChaptersSaved,
+		TotalChapters: res.ChaptersTotal,
+	}
+	if checkpoint != nil {
+		cp.EndIndex = checkpoint.EndIndex
 	}
-	return res.NovelID, nil
+	if onChapter != nil {
+		if err := onChapter(cp, res.ChaptersSaved); err != nil {
+			return res.NovelID, cp, err
+		}
+	}
+	return res.NovelID, cp, nil
 }
-
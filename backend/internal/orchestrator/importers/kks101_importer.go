@@ -26,21 +26,32 @@ func (Kks101Importer) CanImport(originalLink string) bool {
 	return host == "101kks.com" || strings.HasSuffix(host, ".101kks.com")
 }
 
-func (Kks101Importer) Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string, checkpoint *importer.Checkpoint, onChapter func(cp *importer.Checkpoint, chaptersSaved int) error, cookieHeader string) (uuid.UUID, *importer.Checkpoint, error) {
+func (Kks101Importer) CanonicalURL(originalLink string) string {
+	return canonicalBookURL(originalLink, kks101BookPath, "https://101kks.com/book/%s.html")
+}
+
+func (Kks101Importer) options(originalLink, uploadsDir, cookieHeader string) importer.Import101KksOptions {
 	storageState := strings.TrimSpace(os.Getenv("KKS101_STORAGE_STATE"))
 	if storageState == "" {
 		storageState = "/app/cookies/101kks_storage.json"
 	}
-	referer := strings.TrimSpace(os.Getenv("KKS101_REFERER"))
-
-	res, cp, err := importer.Import101KksResumable(ctx, db, importer.Import101KksOptions{
-		PageURL:          proposal.OriginalLink,
-		ChaptersLimit:    0,
+	return importer.Import101KksOptions{
+		PageURL:          originalLink,
 		UploadDir:        uploadsDir,
 		StorageStatePath: storageState,
-		Referer:          referer,
+		Referer:          strings.TrimSpace(os.Getenv("KKS101_REFERER")),
 		Cookie:           strings.TrimSpace(cookieHeader),
-	}, checkpoint, onChapter)
+	}
+}
+
+func (i Kks101Importer) Preview(ctx context.Context, originalLink string, cookieHeader string) (*importer.BookPreview, error) {
+	return importer.Preview101Kks(ctx, i.options(originalLink, "", cookieHeader))
+}
+
+func (i Kks101Importer) Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string, checkpoint *importer.Checkpoint, onChapter func(cp *importer.Checkpoint, chaptersSaved int) error, cookieHeader string) (uuid.UUID, *importer.Checkpoint, error) {
+	opts := i.options(proposal.OriginalLink, uploadsDir, cookieHeader)
+	opts.ChaptersLimit = checkpoint.ChaptersLimit()
+	res, cp, err := importer.Import101KksResumable(ctx, db, opts, checkpoint, onChapter)
 	if err != nil {
 		return uuid.Nil, cp, err
 	}
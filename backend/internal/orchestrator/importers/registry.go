@@ -0,0 +1,14 @@
+package importers
+
+import "novels-backend/internal/orchestrator"
+
+// All returns the importers of every supported site. The import queue and cmd/novelctl
+// share this list, so a link importable from one is importable from the other.
+func All() []orchestrator.ProposalImporter {
+	return []orchestrator.ProposalImporter{
+		Shuba69Importer{},
+		Kks101Importer{},
+		TaduImporter{},
+		FanqieImporter{},
+	}
+}
@@ -26,20 +26,32 @@ func (Shuba69Importer) CanImport(originalLink string) bool {
 	return host == "www.69shuba.com" || host == "69shuba.com" || strings.HasSuffix(host, ".69shuba.com")
 }
 
-func (Shuba69Importer) Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string, checkpoint *importer.Checkpoint, onChapter func(cp *importer.Checkpoint, chaptersSaved int) error, cookieHeader string) (uuid.UUID, *importer.Checkpoint, error) {
+func (Shuba69Importer) CanonicalURL(originalLink string) string {
+	return canonicalBookURL(originalLink, shuba69BookPath, "https://www.69shuba.com/book/%s.htm")
+}
+
+func (Shuba69Importer) options(originalLink, uploadsDir, cookieHeader string) importer.Import69ShubaOptions {
 	// If present, reuse user-provided interactive session cookies exported via tools/shuba-browser.
 	storageState := strings.TrimSpace(os.Getenv("SHUBA_STORAGE_STATE"))
 	if storageState == "" {
 		storageState = "/app/cookies/69shuba_storage.json"
 	}
-
-	res, cp, err := importer.Import69ShubaResumable(ctx, db, importer.Import69ShubaOptions{
-		PageURL:          proposal.OriginalLink,
-		ChaptersLimit:    0,
+	return importer.Import69ShubaOptions{
+		PageURL:          originalLink,
 		UploadDir:        uploadsDir,
 		StorageStatePath: storageState,
 		Cookie:           strings.TrimSpace(cookieHeader),
-	}, checkpoint, onChapter)
+	}
+}
+
+func (i Shuba69Importer) Preview(ctx context.Context, originalLink string, cookieHeader string) (*importer.BookPreview, error) {
+	return importer.Preview69Shuba(ctx, i.options(originalLink, "", cookieHeader))
+}
+
+func (i Shuba69Importer) Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string, checkpoint *importer.Checkpoint, onChapter func(cp *importer.Checkpoint, chaptersSaved int) error, cookieHeader string) (uuid.UUID, *importer.Checkpoint, error) {
+	opts := i.options(proposal.OriginalLink, uploadsDir, cookieHeader)
+	opts.ChaptersLimit = checkpoint.ChaptersLimit()
+	res, cp, err := importer.Import69ShubaResumable(ctx, db, opts, checkpoint, onChapter)
 	if err != nil {
 		return uuid.Nil, cp, err
 	}
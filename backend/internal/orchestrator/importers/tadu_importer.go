@@ -27,19 +27,31 @@ func (TaduImporter) CanImport(originalLink string) bool {
 	return host == "tadu.com" || host == "www.tadu.com" || host == "m.tadu.com" || strings.HasSuffix(host, ".tadu.com")
 }
 
-func (TaduImporter) Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string, checkpoint *importer.Checkpoint, onChapter func(cp *importer.Checkpoint, chaptersSaved int) error, cookieHeader string) (uuid.UUID, *importer.Checkpoint, error) {
+func (TaduImporter) CanonicalURL(originalLink string) string {
+	return canonicalBookURL(originalLink, taduBookPath, "https://www.tadu.com/book/%s/")
+}
+
+func (TaduImporter) options(originalLink, uploadsDir, cookieHeader string) importer.ImportTaduOptions {
 	storageState := strings.TrimSpace(os.Getenv("TADU_STORAGE_STATE"))
 	if storageState == "" {
 		storageState = "/app/cookies/tadu_storage.json"
 	}
-
-	res, cp, err := importer.ImportTaduResumable(ctx, db, importer.ImportTaduOptions{
-		PageURL:          proposal.OriginalLink,
-		ChaptersLimit:    0,
+	return importer.ImportTaduOptions{
+		PageURL:          originalLink,
 		UploadDir:        uploadsDir,
 		StorageStatePath: storageState,
 		Cookie:           strings.TrimSpace(cookieHeader),
-	}, checkpoint, onChapter)
+	}
+}
+
+func (i TaduImporter) Preview(ctx context.Context, originalLink string, cookieHeader string) (*importer.BookPreview, error) {
+	return importer.PreviewTadu(ctx, i.options(originalLink, "", cookieHeader))
+}
+
+func (i TaduImporter) Import(ctx context.Context, db *sqlx.DB, proposal *models.NovelProposal, uploadsDir string, checkpoint *importer.Checkpoint, onChapter func(cp *importer.Checkpoint, chaptersSaved int) error, cookieHeader string) (uuid.UUID, *importer.Checkpoint, error) {
+	opts := i.options(proposal.OriginalLink, uploadsDir, cookieHeader)
+	opts.ChaptersLimit = checkpoint.ChaptersLimit()
+	res, cp, err := importer.ImportTaduResumable(ctx, db, opts, checkpoint, onChapter)
 	if err != nil {
 		return uuid.Nil, cp, err
 	}
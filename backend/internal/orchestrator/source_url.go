@@ -0,0 +1,57 @@
+package orchestrator
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Importers is the set of importers of every supported site, resolved by original link.
+type Importers []ProposalImporter
+
+// For returns the importer that handles originalLink, or nil.
+func (imps Importers) For(originalLink string) ProposalImporter {
+	for _, imp := range imps {
+		if imp != nil && imp.CanImport(originalLink) {
+			return imp
+		}
+	}
+	return nil
+}
+
+// CanonicalURL returns the importer of originalLink and the canonical URL of the book,
+// which is the same for every form of a link to one book (mobile site, catalog page,
+// query strings). Links no importer handles are only normalized and importerName is empty.
+func (imps Importers) CanonicalURL(originalLink string) (importerName, canonicalURL string, err error) {
+	normalized, err := NormalizeURL(originalLink)
+	if err != nil {
+		return "", "", err
+	}
+	link := strings.TrimSpace(originalLink)
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	imp := imps.For(link)
+	if imp == nil {
+		return "", normalized, nil
+	}
+	return imp.Name(), imp.CanonicalURL(link), nil
+}
+
+// NormalizeURL is the site-independent part of canonical URLs: https scheme, lower-case host
+// without port and "www."/"m." prefixes, no query, fragment or trailing slash.
+func NormalizeURL(link string) (string, error) {
+	raw := strings.TrimSpace(link)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid url %q", link)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range []string{"www.", "m."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return "https://" + host + strings.TrimRight(u.EscapedPath(), "/"), nil
+}
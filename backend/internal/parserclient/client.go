@@ -11,6 +11,12 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+
+	"novels-backend/internal/telemetry"
 )
 
 type Client struct {
@@ -62,6 +68,8 @@ type ParseRequest struct {
 	HumanDelayMSMin  int    `json:"human_delay_ms_min,omitempty"`
 	HumanDelayMSMax  int    `json:"human_delay_ms_max,omitempty"`
 	CloudflareWaitMS int    `json:"cloudflare_wait_ms,omitempty"`
+	// MetadataOnly returns the book page and catalog without fetching chapter contents.
+	MetadataOnly bool `json:"metadata_only,omitempty"`
 }
 
 type ChapterRef struct {
@@ -94,7 +102,24 @@ type ParseResponse struct {
 	Debug    map[string]interface{} `json:"debug"`
 }
 
-func (c *Client) Parse(ctx context.Context, req ParseRequest) (*ParseResponse, error) {
+// Parse asks parser-service to parse req.URL. The call is traced (the trace context is
+// passed on in the traceparent header) and its latency recorded per site.
+func (c *Client) Parse(ctx context.Context, req ParseRequest) (_ *ParseResponse, err error) {
+	site := req.Site
+	if site == "" {
+		site = "auto"
+	}
+	ctx, span := telemetry.StartSpan(ctx, "parser.parse",
+		attribute.String("parser.site", site),
+		attribute.String("parser.url", req.URL),
+		attribute.Int("parser.chapters_limit", req.ChaptersLimit),
+	)
+	start := time.Now()
+	defer func() {
+		telemetry.ObserveParser(site, time.Since(start), err)
+		telemetry.EndSpan(span, err)
+	}()
+
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, err
@@ -104,6 +129,7 @@ func (c *Client) Parse(ctx context.Context, req ParseRequest) (*ParseResponse, e
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
 
 	resp, err := c.http.Do(httpReq)
 	if err != nil {
@@ -121,6 +147,25 @@ func (c *Client) Parse(ctx context.Context, req ParseRequest) (*ParseResponse, e
 	if err := dec.Decode(&out); err != nil {
 		return nil, err
 	}
+	span.SetAttributes(attribute.Int("parser.chapters", len(out.Chapters)))
 	return &out, nil
 }
 
+
+// Health checks that parser-service is up (GET /health).
+func (c *Client) Health(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("parser-service: status=%d", resp.StatusCode)
+	}
+	return nil
+}
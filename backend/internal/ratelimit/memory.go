@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Memory keeps buckets in process memory. With several instances each enforces the limits on
+// its own, so the effective limit is multiplied by the number of instances.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+const memorySweepEvery = time.Minute
+
+func NewMemory() *Memory {
+	return &Memory{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+func (m *Memory) Name() string { return "memory" }
+
+func (m *Memory) Take(_ context.Context, key string, p Policy) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(p.Limit), updated: now}
+		m.buckets[key] = b
+	}
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(p.Limit), b.tokens+elapsed*p.rate())
+	}
+	b.updated = now
+	b.period = p.Period
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return result(p, allowed, b.tokens), nil
+}
+
+// sweep drops buckets that have refilled completely; they are the same as missing ones.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepEvery {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if now.Sub(b.updated) >= b.period {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Package ratelimit throttles requests with token buckets.
+//
+// Every policy is a bucket of Limit tokens that refills completely over Period; each request
+// takes a token and is rejected when none are left. Buckets live in a Store (Redis, shared by
+// all instances, or process memory) and are keyed by policy and subject (client IP or user ID).
+// Policies can be changed at runtime from the app_settings table (see Reloader).
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// KeyBy selects the subject a policy limits.
+type KeyBy string
+
+const (
+	// KeyByIP limits each client IP.
+	KeyByIP KeyBy = "ip"
+	// KeyByUser limits each signed-in user; anonymous requests are limited by IP.
+	KeyByUser KeyBy = "user"
+)
+
+// Policy is the limit of one group of routes.
+type Policy struct {
+	// Limit is the bucket size: how many requests may be made in a burst. 0 disables the policy.
+	Limit int
+	// Period is how long an empty bucket takes to refill.
+	Period time.Duration
+	By     KeyBy
+}
+
+// rate returns tokens per second.
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+func (p Policy) validate() error {
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	if p.Limit > 0 && p.Period <= 0 {
+		return fmt.Errorf("period must be positive")
+	}
+	if p.By != KeyByIP && p.By != KeyByUser {
+		return fmt.Errorf("by must be %q or %q", KeyByIP, KeyByUser)
+	}
+	return nil
+}
+
+type policyJSON struct {
+	Limit  int    `json:"limit"`
+	Period string `json:"period"`
+	By     KeyBy  `json:"by"`
+}
+
+func (p Policy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(policyJSON{Limit: p.Limit, Period: p.Period.String(), By: p.By})
+}
+
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	var raw policyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	period, err := time.ParseDuration(raw.Period)
+	if raw.Period != "" && err != nil {
+		return fmt.Errorf("invalid period %q: %w", raw.Period, err)
+	}
+	by := raw.By
+	if by == "" {
+		by = KeyByIP
+	}
+	*p = Policy{Limit: raw.Limit, Period: period, By: by}
+	return nil
+}
+
+// ParsePolicies decodes and validates a policy set, {"auth_login": {"limit": 10, "period": "1m", "by": "ip"}, ...}.
+func ParsePolicies(raw []byte) (map[string]Policy, error) {
+	var policies map[string]Policy
+	if err := json.Unmarshal(raw, &policies); err != nil {
+		return nil, fmt.Errorf("invalid rate limit policies: %w", err)
+	}
+	for name, p := range policies {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("rate limit policy %s: %w", name, err)
+		}
+	}
+	return policies, nil
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is when the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is when the next token is available; zero if Allowed.
+	RetryAfter time.Duration
+}
+
+// result derives a Result from the tokens left in the bucket after the request.
+func result(p Policy, allowed bool, tokens float64) Result {
+	rate := p.rate()
+	r := Result{
+		Allowed:   allowed,
+		Limit:     p.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(p.Limit) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		r.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return r
+}
+
+// Store keeps token buckets.
+type Store interface {
+	Name() string
+	// Take takes a token from the bucket key of policy p.
+	Take(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// PolicyStats are the counters of one policy since the process started.
+type PolicyStats struct {
+	Name    string `json:"name"`
+	Policy  Policy `json:"policy"`
+	Allowed int64  `json:"allowed"`
+	Limited int64  `json:"limited"`
+	// Errors are store failures; such requests are let through.
+	Errors int64 `json:"errors"`
+}
+
+// Limiter applies named policies.
+type Limiter struct {
+	store    Store
+	defaults map[string]Policy
+	logger   zerolog.Logger
+
+	mu       sync.RWMutex
+	policies map[string]Policy
+	stats    map[string]*PolicyStats
+}
+
+// New creates a limiter with the built-in policies; a nil store disables limiting.
+func New(store Store, defaults map[string]Policy, logger zerolog.Logger) *Limiter {
+	l := &Limiter{
+		store:    store,
+		defaults: defaults,
+		logger:   logger.With().Str("component", "rate_limit").Logger(),
+		stats:    map[string]*PolicyStats{},
+	}
+	l.SetPolicies(nil)
+	return l
+}
+
+// Backend returns the name of the store ("none" if limiting is disabled).
+func (l *Limiter) Backend() string {
+	if l.store == nil {
+		return "none"
+	}
+	return l.store.Name()
+}
+
+// SetPolicies replaces the configured policies; policies missing from overrides keep their defaults.
+func (l *Limiter) SetPolicies(overrides map[string]Policy) {
+	policies := make(map[string]Policy, len(l.defaults)+len(overrides))
+	for name, p := range l.defaults {
+		policies[name] = p
+	}
+	for name, p := range overrides {
+		policies[name] = p
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.policies = policies
+}
+
+// Policy returns the policy name.
+func (l *Limiter) Policy(name string) (Policy, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	p, ok := l.policies[name]
+	return p, ok
+}
+
+// Allow takes a token for subject under policy name. ok is false if the request is not limited
+// at all (unknown or disabled policy, no store, store failure), in which case r is meaningless.
+func (l *Limiter) Allow(ctx context.Context, name, subject string) (r Result, ok bool) {
+	if l == nil || l.store == nil {
+		return Result{}, false
+	}
+	p, found := l.Policy(name)
+	if !found || p.Limit == 0 {
+		return Result{}, false
+	}
+
+	r, err := l.store.Take(ctx, "rl:"+name+":"+subject, p)
+	if err != nil {
+		l.count(name, func(st *PolicyStats) { st.Errors++ })
+		l.logger.Warn().Err(err).Str("policy", name).Msg("Rate limit store error, request let through")
+		return Result{}, false
+	}
+	l.count(name, func(st *PolicyStats) {
+		if r.Allowed {
+			st.Allowed++
+		} else {
+			st.Limited++
+		}
+	})
+	return r, true
+}
+
+func (l *Limiter) count(name string, fn func(*PolicyStats)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	st, ok := l.stats[name]
+	if !ok {
+		st = &PolicyStats{Name: name}
+		l.stats[name] = st
+	}
+	fn(st)
+}
+
+// Stats returns the effective policies with their counters, ordered by name.
+func (l *Limiter) Stats() []PolicyStats {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	out := make([]PolicyStats, 0, len(l.policies))
+	for name, p := range l.policies {
+		st := PolicyStats{Name: name}
+		if s, ok := l.stats[name]; ok {
+			st = *s
+		}
+		st.Policy = p
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from a bucket atomically, using the Redis clock so instances
+// with skewed clocks agree. A bucket expires once it would be full again.
+var takeScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local period_ms = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = limit
+	ts = now
+end
+
+local elapsed = math.max(0, now - ts)
+tokens = math.min(limit, tokens + elapsed * limit / period_ms)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], period_ms)
+return {allowed, tostring(tokens)}
+`)
+
+// Redis keeps buckets in Redis, shared by all instances.
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (r *Redis) Name() string { return "redis" }
+
+func (r *Redis) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	res, err := takeScript.Run(ctx, r.client, []string{key}, p.Limit, p.Period.Milliseconds()).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("take rate limit token: %w", err)
+	}
+	if len(res) != 2 {
+		return Result{}, fmt.Errorf("take rate limit token: unexpected reply %v", res)
+	}
+	allowed, _ := res[0].(int64)
+	raw, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("take rate limit token: invalid token count %q", raw)
+	}
+	return result(p, allowed == 1, tokens), nil
+}
@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+)
+
+// SettingKey is the app_settings key holding policy overrides.
+const SettingKey = "rate_limits"
+
+// SettingsSource reads app settings.
+type SettingsSource interface {
+	GetSetting(ctx context.Context, key string) (*models.AppSetting, error)
+}
+
+// Reloader keeps the policies of a limiter in sync with app_settings, so limits changed in the
+// admin panel take effect on every instance within one interval.
+type Reloader struct {
+	limiter  *Limiter
+	settings SettingsSource
+	interval time.Duration
+	logger   zerolog.Logger
+
+	last   []byte
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewReloader(limiter *Limiter, settings SettingsSource, interval time.Duration, logger zerolog.Logger) *Reloader {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &Reloader{
+		limiter:  limiter,
+		settings: settings,
+		interval: interval,
+		logger:   logger.With().Str("component", "rate_limit").Logger(),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start implements jobs.Worker.
+func (r *Reloader) Start(ctx context.Context) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			if err := r.Reload(ctx); err != nil {
+				r.logger.Error().Err(err).Msg("Failed to reload rate limit policies")
+			}
+			select {
+			case <-r.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop implements jobs.Worker.
+func (r *Reloader) Stop() {
+	close(r.stopCh)
+	r.wg.Wait()
+}
+
+// Reload applies the current setting. Invalid settings are logged and the previous policies kept.
+func (r *Reloader) Reload(ctx context.Context) error {
+	setting, err := r.settings.GetSetting(ctx, SettingKey)
+	if err != nil {
+		return err
+	}
+	var raw []byte
+	if setting != nil {
+		raw = setting.Value
+	}
+	if r.last != nil && bytes.Equal(raw, r.last) {
+		return nil
+	}
+
+	var overrides map[string]Policy
+	if len(raw) > 0 && !bytes.Equal(raw, []byte("null")) {
+		overrides, err = ParsePolicies(raw)
+		if err != nil {
+			// Reported once per value, not on every poll.
+			r.last = append([]byte{}, raw...)
+			return err
+		}
+	}
+	r.limiter.SetPolicies(overrides)
+	r.last = append([]byte{}, raw...)
+	r.logger.Info().Int("overrides", len(overrides)).Msg("Rate limit policies loaded")
+	return nil
+}
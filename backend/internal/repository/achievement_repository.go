@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"novels-backend/internal/achievements"
+	"novels-backend/internal/domain/models"
+)
+
+// ErrAchievementCodeExists is returned when another achievement has the same code.
+var ErrAchievementCodeExists = errors.New("achievement code already exists")
+
+// AchievementRepository stores achievements, their unlocks and progress, and computes the
+// metric values achievement conditions are evaluated on.
+type AchievementRepository struct {
+	db *sqlx.DB
+}
+
+func NewAchievementRepository(db *sqlx.DB) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+const achievementColumns = `a.id, a.code, a.title, COALESCE(a.description, '') AS description,
+	COALESCE(a.icon_key, '') AS icon_key, a.condition, a.xp_reward, a.created_at`
+
+// List returns all achievements, oldest first.
+func (r *AchievementRepository) List(ctx context.Context) ([]models.Achievement, error) {
+	out := []models.Achievement{}
+	err := r.db.SelectContext(ctx, &out, `SELECT `+achievementColumns+` FROM achievements a ORDER BY a.created_at, a.code`)
+	if err != nil {
+		return nil, fmt.Errorf("list achievements: %w", err)
+	}
+	return out, nil
+}
+
+// GetByID returns an achievement or nil.
+func (r *AchievementRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Achievement, error) {
+	var a models.Achievement
+	err := r.db.GetContext(ctx, &a, `SELECT `+achievementColumns+` FROM achievements a WHERE a.id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get achievement: %w", err)
+	}
+	return &a, nil
+}
+
+// Create inserts an achievement. It is backfilled by the backfill job.
+func (r *AchievementRepository) Create(ctx context.Context, a *models.Achievement) error {
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO achievements (code, title, description, icon_key, condition, xp_reward)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, a.Code, a.Title, a.Description, a.IconKey, a.Condition, a.XPReward).Scan(&a.ID, &a.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrAchievementCodeExists
+	}
+	if err != nil {
+		return fmt.Errorf("create achievement: %w", err)
+	}
+	return nil
+}
+
+// Update saves an achievement; a changed condition is backfilled again (see migration 031).
+func (r *AchievementRepository) Update(ctx context.Context, a *models.Achievement) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE achievements SET code = $2, title = $3, description = $4, icon_key = $5, condition = $6, xp_reward = $7
+		WHERE id = $1
+	`, a.ID, a.Code, a.Title, a.Description, a.IconKey, a.Condition, a.XPReward)
+	if isUniqueViolation(err) {
+		return ErrAchievementCodeExists
+	}
+	if err != nil {
+		return fmt.Errorf("update achievement: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// Locked returns the achievements with a condition the user has not unlocked yet.
+func (r *AchievementRepository) Locked(ctx context.Context, userID uuid.UUID) ([]models.Achievement, error) {
+	out := []models.Achievement{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT `+achievementColumns+`
+		FROM achievements a
+		WHERE a.condition IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM user_achievements ua WHERE ua.user_id = $1 AND ua.achievement_id = a.id)
+		ORDER BY a.created_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list locked achievements: %w", err)
+	}
+	return out, nil
+}
+
+// PendingBackfill returns the achievements with a condition not yet evaluated for all users.
+func (r *AchievementRepository) PendingBackfill(ctx context.Context) ([]models.Achievement, error) {
+	out := []models.Achievement{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT `+achievementColumns+`
+		FROM achievements a
+		WHERE a.condition IS NOT NULL AND a.backfilled_at IS NULL
+		ORDER BY a.created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list achievements to backfill: %w", err)
+	}
+	return out, nil
+}
+
+// MarkBackfilled records that the achievement was evaluated for all users, unless its condition
+// was changed meanwhile (then it is backfilled again).
+func (r *AchievementRepository) MarkBackfilled(ctx context.Context, id uuid.UUID, condition models.AchievementCondition) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE achievements SET backfilled_at = NOW()
+		WHERE id = $1 AND condition = $2::jsonb
+	`, id, condition)
+	if err != nil {
+		return fmt.Errorf("mark achievement backfilled: %w", err)
+	}
+	return nil
+}
+
+// WithBackfillLock runs fn unless another instance is backfilling; returns false if it was skipped.
+func (r *AchievementRepository) WithBackfillLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := r.db.Connx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.GetContext(ctx, &locked, `SELECT pg_try_advisory_lock(hashtext('achievement_backfill'))`); err != nil {
+		return false, fmt.Errorf("acquire backfill lock: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext('achievement_backfill'))`)
+	return true, fn(ctx)
+}
+
+// UserIDsAfter returns up to limit user IDs greater than after, in order (keyset pagination
+// for the backfill).
+func (r *AchievementRepository) UserIDsAfter(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	out := []uuid.UUID{}
+	err := r.db.SelectContext(ctx, &out, `SELECT id FROM users WHERE id > $1 ORDER BY id LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	return out, nil
+}
+
+// ProposalVoters returns the users who voted for a proposal.
+func (r *AchievementRepository) ProposalVoters(ctx context.Context, proposalID uuid.UUID) ([]uuid.UUID, error) {
+	out := []uuid.UUID{}
+	err := r.db.SelectContext(ctx, &out, `SELECT DISTINCT user_id FROM votes WHERE proposal_id = $1`, proposalID)
+	if err != nil {
+		return nil, fmt.Errorf("list proposal voters: %w", err)
+	}
+	return out, nil
+}
+
+// metricQueries compute a metric for a batch of users: $1 is the user IDs, $2 the metric
+// parameter if it has one. Users without rows have the value 0. Reads whose XP was clawed
+// back do not count.
+var metricQueries = map[string]string{
+	achievements.MetricChaptersRead: `
+		SELECT user_id, COUNT(DISTINCT ref_id) AS value
+		FROM xp_events
+		WHERE user_id = ANY($1::uuid[]) AND type = 'read_chapter' AND revoked_at IS NULL
+		GROUP BY user_id`,
+	achievements.MetricChaptersReadInNovel: `
+		SELECT user_id, MAX(chapters) AS value FROM (
+			SELECT e.user_id, c.novel_id, COUNT(DISTINCT e.ref_id) AS chapters
+			FROM xp_events e
+			JOIN chapters c ON c.id = e.ref_id
+			WHERE e.user_id = ANY($1::uuid[]) AND e.type = 'read_chapter' AND e.revoked_at IS NULL
+			GROUP BY e.user_id, c.novel_id
+		) per_novel
+		GROUP BY user_id`,
+	// Consecutive days share day - row_number, so each run of days is one group.
+	achievements.MetricReadingStreakDays: `
+		SELECT user_id, MAX(days) AS value FROM (
+			SELECT user_id, COUNT(*) AS days FROM (
+				SELECT user_id, day - (ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY day))::int AS run
+				FROM (
+					SELECT DISTINCT user_id, (created_at AT TIME ZONE 'UTC')::date AS day
+					FROM xp_events
+					WHERE user_id = ANY($1::uuid[]) AND type = 'read_chapter' AND revoked_at IS NULL
+				) days
+			) runs
+			GROUP BY user_id, run
+		) streaks
+		GROUP BY user_id`,
+	// Streaks are kept by StreakService; the longest one is stored.
+	achievements.MetricActivityStreakDays: `
+		SELECT user_id, longest_streak AS value
+		FROM user_streaks
+		WHERE user_id = ANY($1::uuid[])`,
+	achievements.MetricComments: `
+		SELECT user_id, COUNT(*) AS value
+		FROM comments
+		WHERE user_id = ANY($1::uuid[]) AND is_deleted = false
+		GROUP BY user_id`,
+	achievements.MetricCommentsWithLikes: `
+		SELECT user_id, COUNT(*) AS value
+		FROM comments
+		WHERE user_id = ANY($1::uuid[]) AND is_deleted = false AND likes_count >= $2
+		GROUP BY user_id`,
+	achievements.MetricBookmarks: `
+		SELECT user_id, COUNT(*) AS value
+		FROM bookmarks
+		WHERE user_id = ANY($1::uuid[])
+		GROUP BY user_id`,
+	achievements.MetricWonProposalsVoted: `
+		SELECT v.user_id, COUNT(DISTINCT v.proposal_id) AS value
+		FROM votes v
+		JOIN novel_proposals p ON p.id = v.proposal_id
+		WHERE v.user_id = ANY($1::uuid[]) AND p.status IN ('accepted', 'translating')
+		GROUP BY v.user_id`,
+}
+
+// MetricValues computes a metric for the given users. Users missing from the result have 0.
+func (r *AchievementRepository) MetricValues(ctx context.Context, ref achievements.Ref, userIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	query, ok := metricQueries[ref.Metric]
+	if !ok {
+		return nil, fmt.Errorf("no query for metric %q", ref.Metric)
+	}
+	rows := []struct {
+		UserID uuid.UUID `db:"user_id"`
+		Value  int64     `db:"value"`
+	}{}
+	args := []interface{}{pq.Array(userIDs)}
+	if m, _ := achievements.LookupMetric(ref.Metric); m.Param != "" {
+		args = append(args, ref.Param)
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("compute %s: %w", ref, err)
+	}
+	out := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		out[row.UserID] = row.Value
+	}
+	return out, nil
+}
+
+// SaveProgress upserts progress rows.
+func (r *AchievementRepository) SaveProgress(ctx context.Context, progress []models.AchievementProgress) error {
+	if len(progress) == 0 {
+		return nil
+	}
+	users := make([]uuid.UUID, len(progress))
+	ids := make([]uuid.UUID, len(progress))
+	current := make([]int64, len(progress))
+	target := make([]int64, len(progress))
+	for i, p := range progress {
+		users[i], ids[i], current[i], target[i] = p.UserID, p.AchievementID, p.Current, p.Target
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_achievement_progress (user_id, achievement_id, current, target)
+		SELECT * FROM unnest($1::uuid[], $2::uuid[], $3::bigint[], $4::bigint[])
+		ON CONFLICT (user_id, achievement_id) DO UPDATE SET
+			current = EXCLUDED.current,
+			target = EXCLUDED.target,
+			updated_at = NOW()
+		WHERE (user_achievement_progress.current, user_achievement_progress.target)
+		      IS DISTINCT FROM (EXCLUDED.current, EXCLUDED.target)
+	`, pq.Array(users), pq.Array(ids), pq.Array(current), pq.Array(target))
+	if err != nil {
+		return fmt.Errorf("save achievement progress: %w", err)
+	}
+	return nil
+}
+
+// Unlock unlocks an achievement and drops its progress row. It returns false if the user
+// already had it.
+func (r *AchievementRepository) Unlock(ctx context.Context, userID, achievementID uuid.UUID) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO user_achievements (user_id, achievement_id, unlocked_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, achievement_id) DO NOTHING
+	`, userID, achievementID)
+	if err != nil {
+		return false, fmt.Errorf("unlock achievement: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("unlock achievement: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM user_achievement_progress WHERE user_id = $1 AND achievement_id = $2
+	`, userID, achievementID); err != nil {
+		return false, fmt.Errorf("unlock achievement: %w", err)
+	}
+	return n > 0, tx.Commit()
+}
+
+// ListForUser returns all achievements with the user's unlock time and progress,
+// unlocked first (most recent first), then by progress.
+func (r *AchievementRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.AchievementStatus, error) {
+	out := []models.AchievementStatus{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT `+achievementColumns+`,
+		       ua.unlocked_at,
+		       COALESCE(p.current, 0) AS current,
+		       COALESCE(p.target, 0) AS target
+		FROM achievements a
+		LEFT JOIN user_achievements ua ON ua.achievement_id = a.id AND ua.user_id = $1
+		LEFT JOIN user_achievement_progress p ON p.achievement_id = a.id AND p.user_id = $1
+		ORDER BY ua.unlocked_at DESC NULLS LAST,
+		         COALESCE(p.current::float8 / NULLIF(p.target, 0), 0) DESC,
+		         a.created_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user achievements: %w", err)
+	}
+	return out, nil
+}
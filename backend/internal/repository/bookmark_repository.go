@@ -222,7 +222,7 @@ func (r *BookmarkRepository) List(ctx context.Context, filter models.BookmarksFi
 		LEFT JOIN (
 			SELECT novel_id, MAX(number) as max_chapter, MAX(published_at) as latest_published
 			FROM chapters
-			WHERE published_at IS NOT NULL
+			WHERE published_at <= NOW()
 			GROUP BY novel_id
 		) lc ON n.id = lc.novel_id
 		WHERE b.user_id = $1`
@@ -270,7 +270,7 @@ func (r *BookmarkRepository) List(ctx context.Context, filter models.BookmarksFi
 			b.id, b.user_id, b.novel_id, b.list_id, b.created_at, b.updated_at,
 			n.id as novel_id, n.slug, n.cover_image_key, n.translation_status,
 			nl.title as novel_title,
-			(SELECT COUNT(*) FROM chapters WHERE novel_id = n.id AND published_at IS NOT NULL) as chapters_count,
+			(SELECT COUNT(*) FROM chapters WHERE novel_id = n.id AND published_at <= NOW()) as chapters_count,
 			0.0 as rating,
 			rp.chapter_id as progress_chapter_id,
 			c.number as progress_chapter_num,
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // ChapterRepository репозиторий для работы с главами
@@ -22,8 +23,9 @@ func NewChapterRepository(db *sqlx.DB) *ChapterRepository {
 	return &ChapterRepository{db: db}
 }
 
-// ListByNovel получает список глав новеллы
-func (r *ChapterRepository) ListByNovel(ctx context.Context, novelSlug string, params models.ChapterListParams) ([]models.ChapterListItem, *models.NovelBrief, int, error) {
+// ListByNovel получает список вышедших глав новеллы: published_at <= visibleUntil
+// (visibleUntil позже текущего времени — ранний доступ по подписке)
+func (r *ChapterRepository) ListByNovel(ctx context.Context, novelSlug string, params models.ChapterListParams, visibleUntil time.Time) ([]models.ChapterListItem, *models.NovelBrief, int, error) {
 	// Получаем ID новеллы и краткую информацию
 	var novel models.NovelBrief
 	novelQuery := `
@@ -47,9 +49,9 @@ func (r *ChapterRepository) ListByNovel(ctx context.Context, novelSlug string, p
 	}
 
 	// Подсчет глав
-	countQuery := `SELECT COUNT(*) FROM chapters WHERE novel_id = $1`
+	countQuery := `SELECT COUNT(*) FROM chapters WHERE novel_id = $1 AND published_at <= $2`
 	var total int
-	err = r.db.GetContext(ctx, &total, countQuery, novel.ID)
+	err = r.db.GetContext(ctx, &total, countQuery, novel.ID, visibleUntil)
 	if err != nil {
 		return nil, nil, 0, fmt.Errorf("failed to count chapters: %w", err)
 	}
@@ -73,12 +75,12 @@ func (r *ChapterRepository) ListByNovel(ctx context.Context, novelSlug string, p
 	chaptersQuery := fmt.Sprintf(`
 		SELECT c.id, c.number, c.slug, c.title, c.views, c.published_at
 		FROM chapters c
-		WHERE c.novel_id = $1
+		WHERE c.novel_id = $1 AND c.published_at <= $4
 		ORDER BY %s %s
 		LIMIT $2 OFFSET $3
 	`, orderBy, order)
 
-	rows, err := r.db.QueryxContext(ctx, chaptersQuery, novel.ID, params.Limit, offset)
+	rows, err := r.db.QueryxContext(ctx, chaptersQuery, novel.ID, params.Limit, offset, visibleUntil)
 	if err != nil {
 		return nil, nil, 0, fmt.Errorf("failed to list chapters: %w", err)
 	}
@@ -99,6 +101,10 @@ func (r *ChapterRepository) ListByNovel(ctx context.Context, novelSlug string, p
 		if chapter.PublishedAt != nil && chapter.PublishedAt.After(twentyFourHoursAgo) {
 			chapter.IsNew = true
 		}
+		// Глава еще не вышла и видна по раннему доступу
+		if chapter.PublishedAt != nil && chapter.PublishedAt.After(now) {
+			chapter.IsEarlyAccess = true
+		}
 
 		chapters = append(chapters, chapter)
 	}
@@ -106,8 +112,9 @@ func (r *ChapterRepository) ListByNovel(ctx context.Context, novelSlug string, p
 	return chapters, &novel, total, nil
 }
 
-// GetByID получает главу по ID с содержимым
-func (r *ChapterRepository) GetByID(ctx context.Context, id uuid.UUID, lang string) (*models.ChapterWithContent, error) {
+// GetByID получает главу по ID с содержимым. visibleUntil ограничивает главу и соседние главы
+// вышедшими к этому времени (nil — без ограничения, для админки)
+func (r *ChapterRepository) GetByID(ctx context.Context, id uuid.UUID, lang string, visibleUntil *time.Time) (*models.ChapterWithContent, error) {
 	// We want to be resilient: if requested translation is missing, fallback to any available content/lang.
 	// This prevents 404s for imported originals that only have source language content.
 	query := `
@@ -125,10 +132,11 @@ func (r *ChapterRepository) GetByID(ctx context.Context, id uuid.UUID, lang stri
 		JOIN novels n ON c.novel_id = n.id
 		JOIN novel_localizations nl ON n.id = nl.novel_id AND nl.lang = $1
 		WHERE c.id = $2
+		  AND ($3::timestamptz IS NULL OR c.published_at <= $3)
 	`
 
 	var chapter models.ChapterWithContent
-	err := r.db.QueryRowxContext(ctx, query, lang, id).Scan(
+	err := r.db.QueryRowxContext(ctx, query, lang, id, visibleUntil).Scan(
 		&chapter.ID, &chapter.NovelID, &chapter.Number, &chapter.Slug, &chapter.Title,
 		&chapter.Views, &chapter.PublishedAt, &chapter.CreatedAt, &chapter.UpdatedAt,
 		&chapter.Content, &chapter.WordCount, &chapter.Source,
@@ -151,10 +159,11 @@ func (r *ChapterRepository) GetByID(ctx context.Context, id uuid.UUID, lang stri
 	prevQuery := `
 		SELECT id, number, title FROM chapters
 		WHERE novel_id = $1 AND number < $2
+		  AND ($3::timestamptz IS NULL OR published_at <= $3)
 		ORDER BY number DESC LIMIT 1
 	`
 	var prevChapter models.ChapterNavInfo
-	err = r.db.QueryRowxContext(ctx, prevQuery, chapter.NovelID, chapter.Number).Scan(
+	err = r.db.QueryRowxContext(ctx, prevQuery, chapter.NovelID, chapter.Number, visibleUntil).Scan(
 		&prevChapter.ID, &prevChapter.Number, &prevChapter.Title,
 	)
 	if err == nil {
@@ -164,10 +173,11 @@ func (r *ChapterRepository) GetByID(ctx context.Context, id uuid.UUID, lang stri
 	nextQuery := `
 		SELECT id, number, title FROM chapters
 		WHERE novel_id = $1 AND number > $2
+		  AND ($3::timestamptz IS NULL OR published_at <= $3)
 		ORDER BY number ASC LIMIT 1
 	`
 	var nextChapter models.ChapterNavInfo
-	err = r.db.QueryRowxContext(ctx, nextQuery, chapter.NovelID, chapter.Number).Scan(
+	err = r.db.QueryRowxContext(ctx, nextQuery, chapter.NovelID, chapter.Number, visibleUntil).Scan(
 		&nextChapter.ID, &nextChapter.Number, &nextChapter.Title,
 	)
 	if err == nil {
@@ -177,31 +187,44 @@ func (r *ChapterRepository) GetByID(ctx context.Context, id uuid.UUID, lang stri
 	return &chapter, nil
 }
 
-// Create создает новую главу
-func (r *ChapterRepository) Create(ctx context.Context, req *models.CreateChapterRequest) (*models.Chapter, error) {
+// Create создает новую главу; authorID попадает в историю ревизий текста
+func (r *ChapterRepository) Create(ctx context.Context, req *models.CreateChapterRequest, authorID *uuid.UUID) (*models.Chapter, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	if err := SetRevisionContext(ctx, tx, authorID, ""); err != nil {
+		return nil, err
+	}
+
+	// Без явной даты глава новеллы с расписанием выпуска встает в очередь (триггер в БД)
+	publishedAt := time.Now()
+	if req.PublishedAt != nil {
+		publishedAt = *req.PublishedAt
+		if err := SetManualRelease(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
 	chapter := &models.Chapter{
 		ID:          uuid.New(),
 		NovelID:     req.NovelID,
 		Number:      req.Number,
 		Slug:        req.Slug,
 		Title:       req.Title,
-		PublishedAt: timePtr(time.Now()),
+		PublishedAt: timePtr(publishedAt),
 	}
 
 	// Вставляем главу
 	query := `
 		INSERT INTO chapters (id, novel_id, number, slug, title, published_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING created_at, updated_at
+		RETURNING published_at, created_at, updated_at
 	`
 	err = tx.QueryRowxContext(ctx, query, chapter.ID, chapter.NovelID, chapter.Number,
-		chapter.Slug, chapter.Title, chapter.PublishedAt).Scan(&chapter.CreatedAt, &chapter.UpdatedAt)
+		chapter.Slug, chapter.Title, chapter.PublishedAt).Scan(&chapter.PublishedAt, &chapter.CreatedAt, &chapter.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chapter: %w", err)
 	}
@@ -236,16 +259,20 @@ func (r *ChapterRepository) Create(ctx context.Context, req *models.CreateChapte
 	return chapter, nil
 }
 
-// Update обновляет главу
-func (r *ChapterRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateChapterRequest) error {
+// Update обновляет главу; authorID попадает в историю ревизий текста
+func (r *ChapterRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateChapterRequest, authorID *uuid.UUID) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	if err := SetRevisionContext(ctx, tx, authorID, ""); err != nil {
+		return err
+	}
+
 	// Обновляем основную информацию
-	if req.Number != nil || req.Slug != nil || req.Title != nil {
+	if req.Number != nil || req.Slug != nil || req.Title != nil || req.PublishedAt != nil {
 		updates := []string{}
 		args := []interface{}{}
 		argIndex := 1
@@ -265,6 +292,11 @@ func (r *ChapterRepository) Update(ctx context.Context, id uuid.UUID, req *model
 			args = append(args, *req.Title)
 			argIndex++
 		}
+		if req.PublishedAt != nil {
+			updates = append(updates, fmt.Sprintf("published_at = $%d", argIndex))
+			args = append(args, *req.PublishedAt)
+			argIndex++
+		}
 
 		query := fmt.Sprintf("UPDATE chapters SET %s WHERE id = $%d", 
 			joinStrings(updates, ", "), argIndex)
@@ -300,6 +332,105 @@ func (r *ChapterRepository) Update(ctx context.Context, id uuid.UUID, req *model
 	return tx.Commit()
 }
 
+// GetContent получает содержимое главы на конкретном языке (без fallback)
+func (r *ChapterRepository) GetContent(ctx context.Context, chapterID uuid.UUID, lang string) (*models.ChapterContent, error) {
+	var content models.ChapterContent
+	query := `
+		SELECT chapter_id, lang, content, word_count, source, updated_at
+		FROM chapter_contents
+		WHERE chapter_id = $1 AND lang = $2
+	`
+	err := r.db.GetContext(ctx, &content, query, chapterID, lang)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get chapter content: %w", err)
+	}
+	return &content, nil
+}
+
+// SaveAutoContent сохраняет машинный перевод (source = auto).
+// Ручной перевод не перезаписывается, если не задан overwriteManual (принятый запрос на перевод заново);
+// возвращает false, если запись пропущена. note сохраняется в ревизии (например, "translation_job:<id>").
+func (r *ChapterRepository) SaveAutoContent(ctx context.Context, chapterID uuid.UUID, lang, content string, overwriteManual bool, note string) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := SetRevisionContext(ctx, tx, nil, note); err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO chapter_contents (chapter_id, lang, content, source)
+		VALUES ($1, $2, $3, 'auto')
+		ON CONFLICT (chapter_id, lang) DO UPDATE SET
+			content = EXCLUDED.content,
+			source = EXCLUDED.source
+		WHERE chapter_contents.source <> 'manual' OR $4::boolean
+	`
+	res, err := tx.ExecContext(ctx, query, chapterID, lang, content, overwriteManual)
+	if err != nil {
+		return false, fmt.Errorf("failed to save auto content: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListForExport возвращает вышедшие главы новеллы с текстом на языке lang в диапазоне номеров
+// [from, to] (to = 0 — до конца) без самого текста, с md5 текста для ключа кэша
+func (r *ChapterRepository) ListForExport(ctx context.Context, novelID uuid.UUID, lang string, from, to float64) ([]models.ExportChapter, error) {
+	chapters := []models.ExportChapter{}
+	query := `
+		SELECT c.id, c.number, c.title, md5(cc.content) AS content_hash
+		FROM chapters c
+		JOIN chapter_contents cc ON cc.chapter_id = c.id AND cc.lang = $2
+		WHERE c.novel_id = $1
+		  AND c.number >= $3::numeric
+		  AND ($4::numeric = 0 OR c.number <= $4::numeric)
+		  AND c.published_at <= NOW()
+		ORDER BY c.number
+	`
+	if err := r.db.SelectContext(ctx, &chapters, query, novelID, lang, from, to); err != nil {
+		return nil, fmt.Errorf("failed to list chapters for export: %w", err)
+	}
+	return chapters, nil
+}
+
+// GetContents получает тексты нескольких глав на языке lang
+func (r *ChapterRepository) GetContents(ctx context.Context, chapterIDs []uuid.UUID, lang string) (map[uuid.UUID]string, error) {
+	ids := make([]string, len(chapterIDs))
+	for i, id := range chapterIDs {
+		ids[i] = id.String()
+	}
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT chapter_id, content
+		FROM chapter_contents
+		WHERE chapter_id = ANY($1::uuid[]) AND lang = $2
+	`, pq.Array(ids), lang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chapter contents: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[uuid.UUID]string, len(chapterIDs))
+	for rows.Next() {
+		var id uuid.UUID
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return nil, fmt.Errorf("failed to scan chapter content: %w", err)
+		}
+		out[id] = content
+	}
+	return out, rows.Err()
+}
+
 // Delete удаляет главу
 func (r *ChapterRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := "DELETE FROM chapters WHERE id = $1"
@@ -345,3 +476,68 @@ func joinStrings(s []string, sep string) string {
 	}
 	return result
 }
+
+// ListFeed возвращает последние вышедшие главы новеллы с текстом на языке lang
+// (главы в очереди и запланированные на будущее не попадают)
+func (r *ChapterRepository) ListFeed(ctx context.Context, novelID uuid.UUID, lang string, limit int) ([]models.FeedChapter, error) {
+	chapters := []models.FeedChapter{}
+	query := `
+		SELECT c.id, c.novel_id, n.slug AS novel_slug, '' AS novel_title, c.number, c.title,
+		       c.published_at
+		FROM chapters c
+		JOIN novels n ON n.id = c.novel_id
+		JOIN chapter_contents cc ON cc.chapter_id = c.id AND cc.lang = $2
+		WHERE c.novel_id = $1
+		  AND c.published_at <= NOW()
+		ORDER BY c.published_at DESC, c.number DESC
+		LIMIT $3
+	`
+	if err := r.db.SelectContext(ctx, &chapters, query, novelID, lang, limit); err != nil {
+		return nil, fmt.Errorf("failed to list feed chapters: %w", err)
+	}
+	return chapters, nil
+}
+
+// ListBookmarkFeed возвращает последние вышедшие главы новелл из закладок пользователя
+// в списке listCode с текстом на языке lang
+func (r *ChapterRepository) ListBookmarkFeed(ctx context.Context, userID uuid.UUID, listCode models.BookmarkListCode, lang string, limit int) ([]models.FeedChapter, error) {
+	chapters := []models.FeedChapter{}
+	query := `
+		SELECT c.id, c.novel_id, n.slug AS novel_slug,
+		       COALESCE(nl.title, nru.title, n.slug) AS novel_title,
+		       c.number, c.title,
+		       c.published_at
+		FROM bookmarks b
+		JOIN bookmark_lists bl ON bl.id = b.list_id AND bl.code = $2
+		JOIN novels n ON n.id = b.novel_id
+		JOIN chapters c ON c.novel_id = n.id
+		JOIN chapter_contents cc ON cc.chapter_id = c.id AND cc.lang = $3
+		LEFT JOIN novel_localizations nl ON nl.novel_id = n.id AND nl.lang = $3
+		LEFT JOIN novel_localizations nru ON nru.novel_id = n.id AND nru.lang = 'ru'
+		WHERE b.user_id = $1
+		  AND c.published_at <= NOW()
+		ORDER BY c.published_at DESC, c.number DESC
+		LIMIT $4
+	`
+	if err := r.db.SelectContext(ctx, &chapters, query, userID, string(listCode), lang, limit); err != nil {
+		return nil, fmt.Errorf("failed to list bookmark feed chapters: %w", err)
+	}
+	return chapters, nil
+}
+
+// ListForSitemap возвращает вышедшие главы для sitemap (сначала свежие)
+func (r *ChapterRepository) ListForSitemap(ctx context.Context, limit int) ([]models.FeedChapter, error) {
+	chapters := []models.FeedChapter{}
+	query := `
+		SELECT c.id, c.novel_id, n.slug AS novel_slug, '' AS novel_title, c.number, c.title, c.published_at
+		FROM chapters c
+		JOIN novels n ON n.id = c.novel_id
+		WHERE c.published_at <= NOW()
+		ORDER BY c.published_at DESC
+		LIMIT $1
+	`
+	if err := r.db.SelectContext(ctx, &chapters, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to list chapters for sitemap: %w", err)
+	}
+	return chapters, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+// SetRevisionContext describes the writer of the chapter_contents changes made in tx:
+// the revision trigger (022_chapter_content_revisions.up.sql) stores them on every revision.
+// The settings are transaction-local.
+func SetRevisionContext(ctx context.Context, tx sqlx.ExecerContext, authorID *uuid.UUID, note string) error {
+	author := ""
+	if authorID != nil {
+		author = authorID.String()
+	}
+	_, err := tx.ExecContext(ctx, `
+		SELECT set_config('app.revision_author', $1, true), set_config('app.revision_note', $2, true)
+	`, author, note)
+	if err != nil {
+		return fmt.Errorf("set revision context: %w", err)
+	}
+	return nil
+}
+
+// ChapterRevisionsRepository reads chapter text history and restores old versions.
+type ChapterRevisionsRepository struct {
+	db *sqlx.DB
+}
+
+func NewChapterRevisionsRepository(db *sqlx.DB) *ChapterRevisionsRepository {
+	return &ChapterRevisionsRepository{db: db}
+}
+
+// List returns revisions of a chapter (without texts), newest first. Empty lang lists all languages.
+func (r *ChapterRevisionsRepository) List(ctx context.Context, chapterID uuid.UUID, lang string, limit int) ([]models.ChapterContentRevision, error) {
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+	out := []models.ChapterContentRevision{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT id, chapter_id, lang, source, word_count, author_id, note, created_at
+		FROM chapter_content_revisions
+		WHERE chapter_id = $1 AND ($2 = '' OR lang = $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3
+	`, chapterID, lang, limit); err != nil {
+		return nil, fmt.Errorf("list chapter revisions: %w", err)
+	}
+	return out, nil
+}
+
+// GetByID returns a revision of the chapter with its text, or nil.
+func (r *ChapterRevisionsRepository) GetByID(ctx context.Context, chapterID, revisionID uuid.UUID) (*models.ChapterContentRevision, error) {
+	var rev models.ChapterContentRevision
+	err := r.db.GetContext(ctx, &rev, `
+		SELECT id, chapter_id, lang, content, source, word_count, author_id, note, created_at
+		FROM chapter_content_revisions
+		WHERE chapter_id = $1 AND id = $2
+	`, chapterID, revisionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get chapter revision: %w", err)
+	}
+	return &rev, nil
+}
+
+// Rollback makes the text of an old revision current again. The write itself becomes a new
+// revision authored by authorID with note "rollback:<revision id>".
+func (r *ChapterRevisionsRepository) Rollback(ctx context.Context, rev *models.ChapterContentRevision, authorID *uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin rollback tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := SetRevisionContext(ctx, tx, authorID, "rollback:"+rev.ID.String()); err != nil {
+		return err
+	}
+
+	// Imported texts are stored with the importer's source value.
+	source := rev.Source
+	if source == "import" {
+		source = "parser"
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO chapter_contents (chapter_id, lang, content, source)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chapter_id, lang) DO UPDATE SET
+			content = EXCLUDED.content,
+			source = EXCLUDED.source
+	`, rev.ChapterID, rev.Lang, rev.Content, source); err != nil {
+		return fmt.Errorf("rollback chapter content: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+type FeedTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewFeedTokenRepository(db *sqlx.DB) *FeedTokenRepository {
+	return &FeedTokenRepository{db: db}
+}
+
+func (r *FeedTokenRepository) Create(ctx context.Context, userID uuid.UUID, tokenHash, name string) (*models.FeedToken, error) {
+	var t models.FeedToken
+	err := r.db.GetContext(ctx, &t, `
+		INSERT INTO user_feed_tokens (user_id, token_hash, name)
+		VALUES ($1, $2, $3)
+		RETURNING id, user_id, name, created_at, last_used_at, revoked_at
+	`, userID, tokenHash, name)
+	if err != nil {
+		return nil, fmt.Errorf("create feed token: %w", err)
+	}
+	return &t, nil
+}
+
+// ListByUser returns the user's tokens, newest first, revoked ones included.
+func (r *FeedTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.FeedToken, error) {
+	out := []models.FeedToken{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT id, user_id, name, created_at, last_used_at, revoked_at
+		FROM user_feed_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID); err != nil {
+		return nil, fmt.Errorf("list feed tokens: %w", err)
+	}
+	return out, nil
+}
+
+// Revoke disables a token of the user. Returns false if there is no such active token.
+func (r *FeedTokenRepository) Revoke(ctx context.Context, userID, tokenID uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_feed_tokens SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`, tokenID, userID)
+	if err != nil {
+		return false, fmt.Errorf("revoke feed token: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Use resolves an active token to its user and records the access. Returns nil if the token is unknown or revoked.
+func (r *FeedTokenRepository) Use(ctx context.Context, tokenHash string) (*uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.GetContext(ctx, &userID, `
+		UPDATE user_feed_tokens SET last_used_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+		RETURNING user_id
+	`, tokenHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("use feed token: %w", err)
+	}
+	return &userID, nil
+}
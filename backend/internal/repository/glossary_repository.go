@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+// GlossaryRepository stores per-novel translation glossaries.
+type GlossaryRepository struct {
+	db *sqlx.DB
+}
+
+func NewGlossaryRepository(db *sqlx.DB) *GlossaryRepository {
+	return &GlossaryRepository{db: db}
+}
+
+const glossaryTermColumns = `
+	id, novel_id, source_term, translations, notes, created_by, created_at, updated_at
+`
+
+// List returns all terms of a novel, longest source term first (the order terms are matched in).
+func (r *GlossaryRepository) List(ctx context.Context, novelID uuid.UUID) ([]models.GlossaryTerm, error) {
+	out := []models.GlossaryTerm{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+glossaryTermColumns+`
+		FROM novel_glossary_terms
+		WHERE novel_id = $1
+		ORDER BY char_length(source_term) DESC, source_term
+	`, novelID); err != nil {
+		return nil, fmt.Errorf("list glossary terms: %w", err)
+	}
+	return out, nil
+}
+
+func (r *GlossaryRepository) GetByID(ctx context.Context, novelID, termID uuid.UUID) (*models.GlossaryTerm, error) {
+	var t models.GlossaryTerm
+	err := r.db.GetContext(ctx, &t, `
+		SELECT `+glossaryTermColumns+` FROM novel_glossary_terms WHERE novel_id = $1 AND id = $2
+	`, novelID, termID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get glossary term: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *GlossaryRepository) GetBySourceTerm(ctx context.Context, novelID uuid.UUID, sourceTerm string) (*models.GlossaryTerm, error) {
+	var t models.GlossaryTerm
+	err := r.db.GetContext(ctx, &t, `
+		SELECT `+glossaryTermColumns+` FROM novel_glossary_terms WHERE novel_id = $1 AND source_term = $2
+	`, novelID, sourceTerm)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get glossary term by source: %w", err)
+	}
+	return &t, nil
+}
+
+// Create inserts a term. Returns nil if the novel already has a term with the same source string.
+func (r *GlossaryRepository) Create(ctx context.Context, novelID uuid.UUID, in models.GlossaryTermInput, createdBy *uuid.UUID) (*models.GlossaryTerm, error) {
+	var t models.GlossaryTerm
+	err := r.db.GetContext(ctx, &t, `
+		INSERT INTO novel_glossary_terms (novel_id, source_term, translations, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (novel_id, source_term) DO NOTHING
+		RETURNING `+glossaryTermColumns, novelID, in.SourceTerm, in.Translations, in.Notes, createdBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create glossary term: %w", err)
+	}
+	return &t, nil
+}
+
+// Update replaces a term. Returns nil if the term does not exist.
+func (r *GlossaryRepository) Update(ctx context.Context, novelID, termID uuid.UUID, in models.GlossaryTermInput) (*models.GlossaryTerm, error) {
+	var t models.GlossaryTerm
+	err := r.db.GetContext(ctx, &t, `
+		UPDATE novel_glossary_terms
+		SET source_term = $3, translations = $4, notes = $5, updated_at = NOW()
+		WHERE novel_id = $1 AND id = $2
+		RETURNING `+glossaryTermColumns, novelID, termID, in.SourceTerm, in.Translations, in.Notes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update glossary term: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *GlossaryRepository) Delete(ctx context.Context, novelID, termID uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM novel_glossary_terms WHERE novel_id = $1 AND id = $2`, novelID, termID)
+	if err != nil {
+		return false, fmt.Errorf("delete glossary term: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Violations scans translated chapter contents of a novel for source terms that were left
+// untranslated. Contents in sourceLang are skipped; lang narrows the scan to one language.
+// Terms whose target in a language equals the source string are not reported for it.
+func (r *GlossaryRepository) Violations(ctx context.Context, novelID uuid.UUID, sourceLang, lang string, limit int) ([]models.GlossaryViolation, error) {
+	if limit < 1 || limit > 1000 {
+		limit = 200
+	}
+	out := []models.GlossaryViolation{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT g.id AS term_id, g.source_term,
+		       c.id AS chapter_id, c.number AS chapter_number,
+		       cc.lang, cc.source AS content_source,
+		       (char_length(cc.content) - char_length(replace(cc.content, g.source_term, ''))) / char_length(g.source_term) AS occurrences
+		FROM novel_glossary_terms g
+		JOIN chapters c ON c.novel_id = g.novel_id
+		JOIN chapter_contents cc ON cc.chapter_id = c.id
+		WHERE g.novel_id = $1
+		  AND cc.lang <> $2
+		  AND ($3 = '' OR cc.lang = $3)
+		  AND strpos(cc.content, g.source_term) > 0
+		  AND COALESCE(g.translations -> cc.lang ->> 'target', '') <> g.source_term
+		ORDER BY c.number, cc.lang, g.source_term
+		LIMIT $4
+	`, novelID, sourceLang, lang, limit); err != nil {
+		return nil, fmt.Errorf("glossary violations: %w", err)
+	}
+	return out, nil
+}
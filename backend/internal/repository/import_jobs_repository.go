@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/telemetry"
+)
+
+// ImportJobsRepository is the durable queue for import runs.
+// Jobs are claimed with FOR UPDATE SKIP LOCKED and kept alive by heartbeats, so any
+// number of API instances can share the queue and a crashed worker's jobs are reclaimed
+// once their lease expires.
+type ImportJobsRepository struct {
+	db *sqlx.DB
+}
+
+func NewImportJobsRepository(db *sqlx.DB) *ImportJobsRepository {
+	return &ImportJobsRepository{db: db}
+}
+
+// ImportJobsRecovery summarizes what RecoverStale changed.
+type ImportJobsRecovery struct {
+	Enqueued  int64 `json:"enqueued"`
+	Paused    int64 `json:"paused"`
+	Cancelled int64 `json:"cancelled"`
+	Exhausted int64 `json:"exhausted"`
+}
+
+const importJobColumns = `
+	j.id, j.run_id, j.proposal_id, j.importer, j.status,
+	j.attempts, j.max_attempts,
+	j.lease_owner, j.lease_expires_at, j.heartbeat_at, j.cancel_requested, j.last_error,
+	j.trace_parent, j.available_at, j.created_at, j.updated_at
+`
+
+// Enqueue puts a run into the queue. A finished job (or one whose lease has expired) for the
+// same run is reset, so resuming a paused run re-uses its row; an actively leased job is left alone.
+// The trace of ctx is stored with the job and continued by the worker that runs it.
+func (r *ImportJobsRepository) Enqueue(ctx context.Context, runID, proposalID uuid.UUID, importer string) error {
+	var traceParent *string
+	if tp := telemetry.TraceParent(ctx); tp != "" {
+		traceParent = &tp
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO import_jobs (run_id, proposal_id, importer, trace_parent)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (run_id) DO UPDATE
+		SET status = 'queued',
+		    attempts = 0,
+		    cancel_requested = FALSE,
+		    lease_owner = NULL,
+		    lease_expires_at = NULL,
+		    last_error = NULL,
+		    trace_parent = EXCLUDED.trace_parent,
+		    available_at = NOW(),
+		    updated_at = NOW()
+		WHERE import_jobs.status = 'done'
+		   OR (import_jobs.status = 'leased' AND import_jobs.lease_expires_at <= NOW())
+	`, runID, proposalID, importer, traceParent)
+	if err != nil {
+		return fmt.Errorf("enqueue import job: %w", err)
+	}
+	return nil
+}
+
+// Claim leases the next available job for the importer, honouring the importer's concurrency
+// limit across all instances. Returns nil when nothing can be claimed right now.
+func (r *ImportJobsRepository) Claim(ctx context.Context, owner, importer string, limit int, lease time.Duration) (*models.ImportJob, error) {
+	if limit < 1 {
+		limit = 1
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin claim tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialize claims per importer so the active-lease count below can't race.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "import_jobs:"+importer); err != nil {
+		return nil, fmt.Errorf("lock importer queue: %w", err)
+	}
+
+	var active int
+	if err := tx.GetContext(ctx, &active, `
+		SELECT COUNT(*) FROM import_jobs
+		WHERE importer = $1 AND status = 'leased' AND lease_expires_at > NOW()
+	`, importer); err != nil {
+		return nil, fmt.Errorf("count active import jobs: %w", err)
+	}
+	if active >= limit {
+		return nil, nil
+	}
+
+	var job models.ImportJob
+	err = tx.GetContext(ctx, &job, `
+		SELECT `+importJobColumns+`
+		FROM import_jobs j
+		JOIN import_runs r ON r.id = j.run_id
+		WHERE j.importer = $1
+		  AND j.attempts < j.max_attempts
+		  AND j.cancel_requested = FALSE
+		  AND r.status IN ('queued', 'running')
+		  AND (
+		    (j.status = 'queued' AND j.available_at <= NOW())
+		    OR (j.status = 'leased' AND j.lease_expires_at <= NOW())
+		  )
+		ORDER BY j.available_at, j.created_at
+		LIMIT 1
+		FOR UPDATE OF j SKIP LOCKED
+	`, importer)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select import job: %w", err)
+	}
+
+	err = tx.GetContext(ctx, &job, `
+		UPDATE import_jobs j
+		SET status = 'leased',
+		    attempts = j.attempts + 1,
+		    lease_owner = $2,
+		    lease_expires_at = NOW() + ($3 * INTERVAL '1 second'),
+		    heartbeat_at = NOW(),
+		    updated_at = NOW()
+		WHERE j.id = $1
+		RETURNING `+importJobColumns, job.ID, owner, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("lease import job: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit claim tx: %w", err)
+	}
+	return &job, nil
+}
+
+// Heartbeat extends the lease of a job owned by owner. ok=false means the lease was lost
+// (reclaimed by another worker or finished elsewhere). The current run status is returned so
+// pause/cancel requests made on other instances reach the owner.
+func (r *ImportJobsRepository) Heartbeat(ctx context.Context, jobID uuid.UUID, owner string, lease time.Duration) (cancelRequested bool, runStatus models.ImportRunStatus, ok bool, err error) {
+	row := struct {
+		CancelRequested bool                   `db:"cancel_requested"`
+		RunStatus       models.ImportRunStatus `db:"run_status"`
+	}{}
+	err = r.db.GetContext(ctx, &row, `
+		UPDATE import_jobs j
+		SET lease_expires_at = NOW() + ($3 * INTERVAL '1 second'),
+		    heartbeat_at = NOW(),
+		    updated_at = NOW()
+		FROM import_runs r
+		WHERE j.id = $1 AND j.lease_owner = $2 AND j.status = 'leased' AND r.id = j.run_id
+		RETURNING j.cancel_requested, r.status AS run_status
+	`, jobID, owner, lease.Seconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, "", false, nil
+	}
+	if err != nil {
+		return false, "", false, fmt.Errorf("import job heartbeat: %w", err)
+	}
+	return row.CancelRequested, row.RunStatus, true, nil
+}
+
+// Finish marks a leased job as done.
+func (r *ImportJobsRepository) Finish(ctx context.Context, jobID uuid.UUID, owner string, errMsg *string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET status = 'done',
+		    lease_owner = NULL,
+		    lease_expires_at = NULL,
+		    last_error = $3,
+		    updated_at = NOW()
+		WHERE id = $1 AND lease_owner = $2
+	`, jobID, owner, errMsg)
+	if err != nil {
+		return fmt.Errorf("finish import job: %w", err)
+	}
+	return nil
+}
+
+// Release hands a leased job back to the queue without counting the attempt (graceful shutdown).
+func (r *ImportJobsRepository) Release(ctx context.Context, jobID uuid.UUID, owner string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET status = 'queued',
+		    attempts = GREATEST(attempts - 1, 0),
+		    lease_owner = NULL,
+		    lease_expires_at = NULL,
+		    available_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1 AND lease_owner = $2 AND status = 'leased'
+	`, jobID, owner)
+	if err != nil {
+		return fmt.Errorf("release import job: %w", err)
+	}
+	return nil
+}
+
+// RequestCancel flags the run's job for cancellation. A job that is still queued is removed from
+// the queue right away (dequeued=true); a leased job is stopped by its owner on the next heartbeat.
+func (r *ImportJobsRepository) RequestCancel(ctx context.Context, runID uuid.UUID) (found bool, dequeued bool, err error) {
+	var status models.ImportJobStatus
+	err = r.db.GetContext(ctx, &status, `
+		UPDATE import_jobs
+		SET cancel_requested = TRUE,
+		    status = CASE WHEN status = 'queued' THEN 'done' ELSE status END,
+		    updated_at = NOW()
+		WHERE run_id = $1 AND status <> 'done'
+		RETURNING status
+	`, runID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("request import job cancel: %w", err)
+	}
+	return true, status == models.ImportJobStatusDone, nil
+}
+
+// Dequeue removes a job that has not been picked up yet. Returns false if the job is leased or absent.
+func (r *ImportJobsRepository) Dequeue(ctx context.Context, runID uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET status = 'done', updated_at = NOW()
+		WHERE run_id = $1 AND status = 'queued'
+	`, runID)
+	if err != nil {
+		return false, fmt.Errorf("dequeue import job: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *ImportJobsRepository) GetByRunID(ctx context.Context, runID uuid.UUID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.GetContext(ctx, &job, `SELECT `+importJobColumns+` FROM import_jobs j WHERE j.run_id = $1`, runID)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListActive returns queued and leased jobs, oldest first.
+func (r *ImportJobsRepository) ListActive(ctx context.Context, limit int) ([]models.ImportJob, error) {
+	if limit < 1 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	out := []models.ImportJob{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+importJobColumns+`
+		FROM import_jobs j
+		WHERE j.status <> 'done'
+		ORDER BY j.created_at
+		LIMIT $1
+	`, limit); err != nil {
+		return nil, fmt.Errorf("list import jobs: %w", err)
+	}
+	return out, nil
+}
+
+// RecoverStale reconciles import_runs with the queue after a crash or restart:
+//   - running/queued runs without a job (started before the queue existed) are enqueued,
+//     except runs of cmd/novelctl, which never go through the queue;
+//   - pause_requested runs whose worker is gone become paused;
+//   - cancel requests for jobs whose worker is gone are applied;
+//   - jobs that exhausted max_attempts fail their run.
+//
+// Running runs with an expired lease need no handling here: Claim picks them up again and
+// the importer resumes from import_runs.checkpoint.
+func (r *ImportJobsRepository) RecoverStale(ctx context.Context) (*ImportJobsRecovery, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin recovery tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	out := &ImportJobsRecovery{}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO import_jobs (run_id, proposal_id, importer)
+		SELECT r.id, r.proposal_id, r.importer
+		FROM import_runs r
+		WHERE r.status IN ('queued', 'running')
+		  AND r.origin = 'queue'
+		  AND NOT EXISTS (SELECT 1 FROM import_jobs j WHERE j.run_id = r.id)
+		ON CONFLICT (run_id) DO NOTHING
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("enqueue orphaned import runs: %w", err)
+	}
+	out.Enqueued, _ = res.RowsAffected()
+
+	res, err = tx.ExecContext(ctx, `
+		UPDATE import_runs r
+		SET status = 'paused', updated_at = NOW()
+		WHERE r.status = 'pause_requested'
+		  AND NOT EXISTS (
+		    SELECT 1 FROM import_jobs j
+		    WHERE j.run_id = r.id AND j.status = 'leased' AND j.lease_expires_at > NOW()
+		  )
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("pause stale import runs: %w", err)
+	}
+	out.Paused, _ = res.RowsAffected()
+
+	res, err = tx.ExecContext(ctx, `
+		UPDATE import_runs r
+		SET status = 'cancelled', error = 'cancelled while worker was unavailable',
+		    finished_at = NOW(), updated_at = NOW()
+		FROM import_jobs j
+		WHERE j.run_id = r.id
+		  AND j.cancel_requested = TRUE
+		  AND j.status = 'leased' AND j.lease_expires_at <= NOW()
+		  AND r.status IN ('queued', 'running')
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("cancel stale import runs: %w", err)
+	}
+	out.Cancelled, _ = res.RowsAffected()
+
+	res, err = tx.ExecContext(ctx, `
+		UPDATE import_runs r
+		SET status = 'failed', error = 'import job exceeded max attempts',
+		    finished_at = NOW(), updated_at = NOW()
+		FROM import_jobs j
+		WHERE j.run_id = r.id
+		  AND j.attempts >= j.max_attempts
+		  AND (j.status = 'queued' OR (j.status = 'leased' AND j.lease_expires_at <= NOW()))
+		  AND r.status IN ('queued', 'running')
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("fail exhausted import runs: %w", err)
+	}
+	out.Exhausted, _ = res.RowsAffected()
+
+	// Whatever the run state now is, jobs for terminal/paused runs without a live lease are done.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE import_jobs j
+		SET status = 'done', lease_owner = NULL, lease_expires_at = NULL, updated_at = NOW()
+		FROM import_runs r
+		WHERE j.run_id = r.id
+		  AND r.status NOT IN ('queued', 'running', 'pause_requested')
+		  AND (j.status = 'queued' OR (j.status = 'leased' AND j.lease_expires_at <= NOW()))
+	`); err != nil {
+		return nil, fmt.Errorf("close stale import jobs: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit recovery tx: %w", err)
+	}
+	return out, nil
+}
@@ -37,25 +37,43 @@ func (r *ImportRunsRepository) Create(ctx context.Context, run *models.ImportRun
 	if run.UpdatedAt.IsZero() {
 		run.UpdatedAt = now
 	}
+	if run.Kind == "" {
+		run.Kind = models.ImportRunKindImport
+	}
+	if run.Origin == "" {
+		run.Origin = models.ImportRunOriginQueue
+	}
 	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO import_runs (
-			id, proposal_id, novel_id, importer, status, error,
+			id, proposal_id, novel_id, importer, kind, status, error,
 			progress_current, progress_total, checkpoint, cloudflare_blocked,
-			started_at, finished_at, created_at, updated_at
+			started_at, finished_at, created_at, updated_at, origin, source_url
 		) VALUES (
-			$1,$2,$3,$4,$5,$6,
-			$7,$8,$9,$10,
-			$11,$12,$13,$14
+			$1,$2,$3,$4,$5,$6,$7,
+			$8,$9,$10,$11,
+			$12,$13,$14,$15,$16,$17
 		)
-	`, run.ID, run.ProposalID, run.NovelID, run.Importer, run.Status, run.Error,
+	`, run.ID, run.ProposalID, run.NovelID, run.Importer, run.Kind, run.Status, run.Error,
 		run.ProgressCurrent, run.ProgressTotal, mustJSON(run.Checkpoint), run.CloudflareBlocked,
-		run.StartedAt, run.FinishedAt, run.CreatedAt, run.UpdatedAt)
+		run.StartedAt, run.FinishedAt, run.CreatedAt, run.UpdatedAt, run.Origin, run.SourceURL)
 	if err != nil {
 		return fmt.Errorf("create import run: %w", err)
 	}
 	return nil
 }
 
+// HasImportForProposal reports whether an import run (not a sync) was ever created for the proposal.
+func (r *ImportRunsRepository) HasImportForProposal(ctx context.Context, proposalID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `
+		SELECT EXISTS (SELECT 1 FROM import_runs WHERE proposal_id = $1 AND kind = $2)
+	`, proposalID, models.ImportRunKindImport)
+	if err != nil {
+		return false, fmt.Errorf("check import runs: %w", err)
+	}
+	return exists, nil
+}
+
 func (r *ImportRunsRepository) SetResult(ctx context.Context, runID uuid.UUID, status models.ImportRunStatus, novelID *uuid.UUID, errMsg *string, cloudflareBlocked *bool) error {
 	finished := time.Now().UTC()
 	_, err := r.db.ExecContext(ctx, `
@@ -123,9 +141,9 @@ func (r *ImportRunsRepository) List(ctx context.Context, limit int, status *mode
 		limit = 200
 	}
 	q := `
-		SELECT id, proposal_id, novel_id, importer, status, error,
+		SELECT id, proposal_id, novel_id, importer, kind, status, error,
 		       progress_current, progress_total, COALESCE(checkpoint, '{}'::jsonb) AS checkpoint,
-		       cloudflare_blocked,
+		       cloudflare_blocked, origin, source_url,
 		       started_at, finished_at, created_at, updated_at
 		FROM import_runs
 	`
@@ -149,9 +167,9 @@ func (r *ImportRunsRepository) List(ctx context.Context, limit int, status *mode
 func (r *ImportRunsRepository) GetByID(ctx context.Context, runID uuid.UUID) (*models.ImportRun, error) {
 	var run models.ImportRun
 	err := r.db.GetContext(ctx, &run, `
-		SELECT id, proposal_id, novel_id, importer, status, error,
+		SELECT id, proposal_id, novel_id, importer, kind, status, error,
 		       progress_current, progress_total, COALESCE(checkpoint, '{}'::jsonb) AS checkpoint,
-		       cloudflare_blocked,
+		       cloudflare_blocked, origin, source_url,
 		       started_at, finished_at, created_at, updated_at
 		FROM import_runs
 		WHERE id = $1
@@ -148,14 +148,25 @@ func (r *NewsRepository) List(ctx context.Context, params models.NewsListParams)
 	}
 	offset := (params.Page - 1) * params.Limit
 
+	// Localized title and summary when a translation exists
+	columns := "title, summary"
+	join := ""
+	if params.Lang != "" {
+		columns = "COALESCE(l.title, news_posts.title) AS title, COALESCE(NULLIF(l.summary, ''), news_posts.summary) AS summary"
+		join = fmt.Sprintf("LEFT JOIN news_localizations l ON l.news_id = news_posts.id AND l.lang = $%d", argNum)
+		args = append(args, params.Lang)
+		argNum++
+	}
+
 	// Main query - pinned first, then by date
 	query := fmt.Sprintf(`
-		SELECT id, slug, title, summary, cover_url, category, is_pinned, views_count, comments_count, published_at, author_id
+		SELECT news_posts.id, slug, %s, cover_url, category, is_pinned, views_count, comments_count, published_at, author_id
 		FROM news_posts
 		%s
+		%s
 		ORDER BY is_pinned DESC, published_at DESC
 		LIMIT $%d OFFSET $%d`,
-		whereClause, argNum, argNum+1)
+		columns, join, whereClause, argNum, argNum+1)
 
 	args = append(args, params.Limit, offset)
 
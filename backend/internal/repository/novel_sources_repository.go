@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+type NovelSourcesRepository struct {
+	db *sqlx.DB
+}
+
+func NewNovelSourcesRepository(db *sqlx.DB) *NovelSourcesRepository {
+	return &NovelSourcesRepository{db: db}
+}
+
+const novelSourceColumns = `
+	s.novel_id, s.proposal_id, s.importer, s.source_url,
+	s.sync_enabled, s.sync_interval_minutes, s.next_sync_at,
+	s.last_synced_at, s.last_sync_status, s.last_sync_error, s.last_sync_new_chapters, s.last_sync_run_id,
+	s.created_at, s.updated_at
+`
+
+// Upsert records where a novel was imported from. The first sync is scheduled one interval later.
+func (r *NovelSourcesRepository) Upsert(ctx context.Context, novelID, proposalID uuid.UUID, importer, sourceURL string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO novel_sources (novel_id, proposal_id, importer, source_url)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (novel_id) DO UPDATE SET
+			proposal_id = EXCLUDED.proposal_id,
+			importer = EXCLUDED.importer,
+			source_url = EXCLUDED.source_url,
+			updated_at = NOW()
+	`, novelID, proposalID, importer, sourceURL)
+	if err != nil {
+		return fmt.Errorf("upsert novel source: %w", err)
+	}
+	return nil
+}
+
+func (r *NovelSourcesRepository) GetByNovelID(ctx context.Context, novelID uuid.UUID) (*models.NovelSource, error) {
+	var src models.NovelSource
+	err := r.db.GetContext(ctx, &src, `SELECT `+novelSourceColumns+` FROM novel_sources s WHERE s.novel_id = $1`, novelID)
+	if err != nil {
+		return nil, err
+	}
+	return &src, nil
+}
+
+func (r *NovelSourcesRepository) List(ctx context.Context, limit int) ([]models.NovelSourceListItem, error) {
+	if limit < 1 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	out := []models.NovelSourceListItem{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+novelSourceColumns+`, n.slug
+		FROM novel_sources s
+		JOIN novels n ON n.id = s.novel_id
+		ORDER BY s.next_sync_at
+		LIMIT $1
+	`, limit); err != nil {
+		return nil, fmt.Errorf("list novel sources: %w", err)
+	}
+	return out, nil
+}
+
+// ListDue returns enabled sources whose next sync is due and which have no sync in progress.
+// A paused run counts as in progress: it can be resumed, and a second run would write the
+// same novel's chapters alongside it.
+func (r *NovelSourcesRepository) ListDue(ctx context.Context, limit int) ([]models.NovelSource, error) {
+	if limit < 1 {
+		limit = 20
+	}
+	out := []models.NovelSource{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+novelSourceColumns+`
+		FROM novel_sources s
+		WHERE s.sync_enabled = TRUE
+		  AND s.next_sync_at <= NOW()
+		  AND NOT EXISTS (
+		    SELECT 1 FROM import_runs r
+		    WHERE r.novel_id = s.novel_id
+		      AND r.status IN ('queued', 'running', 'pause_requested', 'paused')
+		  )
+		ORDER BY s.next_sync_at
+		LIMIT $1
+	`, limit); err != nil {
+		return nil, fmt.Errorf("list due novel sources: %w", err)
+	}
+	return out, nil
+}
+
+func (r *NovelSourcesRepository) Update(ctx context.Context, novelID uuid.UUID, req models.UpdateNovelSourceRequest) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE novel_sources
+		SET sync_enabled = COALESCE($2, sync_enabled),
+		    sync_interval_minutes = COALESCE($3, sync_interval_minutes),
+		    next_sync_at = COALESCE(last_synced_at, created_at) + (COALESCE($3, sync_interval_minutes) * INTERVAL '1 minute'),
+		    updated_at = NOW()
+		WHERE novel_id = $1
+	`, novelID, req.SyncEnabled, req.SyncIntervalMinutes)
+	if err != nil {
+		return fmt.Errorf("update novel source: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MarkQueued records that a sync run was enqueued and pushes next_sync_at one interval ahead.
+func (r *NovelSourcesRepository) MarkQueued(ctx context.Context, novelID, runID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE novel_sources
+		SET last_sync_status = $3,
+		    last_sync_run_id = $2,
+		    last_sync_error = NULL,
+		    next_sync_at = NOW() + (sync_interval_minutes * INTERVAL '1 minute'),
+		    updated_at = NOW()
+		WHERE novel_id = $1
+	`, novelID, runID, models.ImportRunStatusQueued)
+	if err != nil {
+		return fmt.Errorf("mark novel source queued: %w", err)
+	}
+	return nil
+}
+
+// SetSyncResult stores the outcome of a sync run.
+func (r *NovelSourcesRepository) SetSyncResult(ctx context.Context, novelID, runID uuid.UUID, status models.ImportRunStatus, newChapters int, errMsg *string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE novel_sources
+		SET last_sync_status = $3,
+		    last_sync_run_id = $2,
+		    last_sync_new_chapters = $4,
+		    last_sync_error = $5,
+		    last_synced_at = NOW(),
+		    updated_at = NOW()
+		WHERE novel_id = $1
+	`, novelID, runID, status, newChapters, errMsg)
+	if err != nil {
+		return fmt.Errorf("set novel source sync result: %w", err)
+	}
+	return nil
+}
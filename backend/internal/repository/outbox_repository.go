@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+// OutboxRepository stores domain events until their subscribers have handled them.
+type OutboxRepository struct {
+	db          *sqlx.DB
+	maxAttempts int
+}
+
+// NewOutboxRepository creates the outbox repository; events written through it are dead-lettered
+// after maxAttempts failed deliveries.
+func NewOutboxRepository(db *sqlx.DB, maxAttempts int) *OutboxRepository {
+	if maxAttempts < 1 {
+		maxAttempts = 10
+	}
+	return &OutboxRepository{db: db, maxAttempts: maxAttempts}
+}
+
+const outboxEventColumns = `
+	id, event_name, payload, status, attempts, max_attempts, error,
+	locked_by, locked_until, available_at, delivered_at, created_at, updated_at
+`
+
+// Insert writes an event. Pass the transaction of the state change the event describes,
+// so the event is stored if and only if the change is committed.
+func (r *OutboxRepository) Insert(ctx context.Context, q sqlx.QueryerContext, eventName string, payload []byte) (uuid.UUID, error) {
+	if q == nil {
+		q = r.db
+	}
+	var id uuid.UUID
+	if err := sqlx.GetContext(ctx, q, &id, `
+		INSERT INTO event_outbox (event_name, payload, max_attempts)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, eventName, payload, r.maxAttempts); err != nil {
+		return uuid.Nil, fmt.Errorf("insert outbox event: %w", err)
+	}
+	return id, nil
+}
+
+// Claim locks the next event due for delivery. Running events whose lock expired (dispatcher died) are reclaimed.
+func (r *OutboxRepository) Claim(ctx context.Context, owner string, lock time.Duration) (*models.OutboxEvent, error) {
+	var evt models.OutboxEvent
+	err := r.db.GetContext(ctx, &evt, `
+		UPDATE event_outbox
+		SET status = 'running',
+		    attempts = attempts + 1,
+		    locked_by = $1,
+		    locked_until = NOW() + ($2 * INTERVAL '1 second'),
+		    updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM event_outbox
+			WHERE (status = 'pending' AND available_at <= NOW())
+			   OR (status = 'running' AND locked_until <= NOW())
+			ORDER BY available_at, created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+outboxEventColumns, owner, lock.Seconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox event: %w", err)
+	}
+	return &evt, nil
+}
+
+// DeliveredKeys returns the subscription keys that already handled the event.
+func (r *OutboxRepository) DeliveredKeys(ctx context.Context, eventID uuid.UUID) (map[string]bool, error) {
+	var keys []string
+	if err := r.db.SelectContext(ctx, &keys, `
+		SELECT handler_key FROM event_outbox_deliveries WHERE event_id = $1
+	`, eventID); err != nil {
+		return nil, fmt.Errorf("list outbox deliveries: %w", err)
+	}
+	out := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		out[k] = true
+	}
+	return out, nil
+}
+
+// MarkHandled records that the handler with the given key processed the event.
+func (r *OutboxRepository) MarkHandled(ctx context.Context, eventID uuid.UUID, handlerKey string) error {
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO event_outbox_deliveries (event_id, handler_key)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, eventID, handlerKey); err != nil {
+		return fmt.Errorf("mark outbox delivery: %w", err)
+	}
+	return nil
+}
+
+// Complete marks an event delivered to all of its subscribers.
+func (r *OutboxRepository) Complete(ctx context.Context, eventID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET status = 'delivered', error = NULL,
+		    locked_by = NULL, locked_until = NULL,
+		    delivered_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, eventID); err != nil {
+		return fmt.Errorf("complete outbox event: %w", err)
+	}
+	return nil
+}
+
+// Fail records a delivery error. The event is retried after backoff until max_attempts is reached,
+// then it is dead-lettered. Returns the resulting status (pending or dead).
+func (r *OutboxRepository) Fail(ctx context.Context, eventID uuid.UUID, errMsg string, backoff time.Duration) (models.OutboxEventStatus, error) {
+	var status models.OutboxEventStatus
+	err := r.db.GetContext(ctx, &status, `
+		UPDATE event_outbox
+		SET status = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'pending' END,
+		    error = $2,
+		    locked_by = NULL, locked_until = NULL,
+		    available_at = NOW() + ($3 * INTERVAL '1 second'),
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING status
+	`, eventID, errMsg, backoff.Seconds())
+	if err != nil {
+		return "", fmt.Errorf("fail outbox event: %w", err)
+	}
+	return status, nil
+}
+
+// Bury dead-letters an event right away (e.g. it cannot be decoded, so retrying is pointless).
+func (r *OutboxRepository) Bury(ctx context.Context, eventID uuid.UUID, errMsg string) error {
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET status = 'dead', error = $2,
+		    locked_by = NULL, locked_until = NULL,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, eventID, errMsg); err != nil {
+		return fmt.Errorf("bury outbox event: %w", err)
+	}
+	return nil
+}
+
+// Retry puts a dead-lettered event back into the queue with a fresh attempt budget.
+// Handlers that already processed it are still skipped. Returns false if the event is not dead.
+func (r *OutboxRepository) Retry(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET status = 'pending', attempts = 0, available_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'dead'
+	`, eventID)
+	if err != nil {
+		return false, fmt.Errorf("retry outbox event: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// PruneDelivered deletes delivered events older than before. Returns the number of deleted events.
+func (r *OutboxRepository) PruneDelivered(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM event_outbox WHERE status = 'delivered' AND delivered_at < $1
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune outbox: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Stats returns queue counters and the age of the oldest undelivered event.
+func (r *OutboxRepository) Stats(ctx context.Context) (*models.OutboxStats, error) {
+	var s models.OutboxStats
+	if err := r.db.GetContext(ctx, &s, `
+		SELECT COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+		       COUNT(*) FILTER (WHERE status = 'running') AS running,
+		       COUNT(*) FILTER (WHERE status = 'delivered') AS delivered,
+		       COUNT(*) FILTER (WHERE status = 'dead') AS dead,
+		       MIN(created_at) FILTER (WHERE status IN ('pending', 'running')) AS oldest_pending_at,
+		       COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at) FILTER (WHERE status IN ('pending', 'running'))), 0)::float8 AS lag_seconds
+		FROM event_outbox
+	`); err != nil {
+		return nil, fmt.Errorf("outbox stats: %w", err)
+	}
+	return &s, nil
+}
+
+// ListDead returns dead-lettered events, newest first.
+func (r *OutboxRepository) ListDead(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	out := []models.OutboxEvent{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+outboxEventColumns+`
+		FROM event_outbox
+		WHERE status = 'dead'
+		ORDER BY updated_at DESC
+		LIMIT $1
+	`, limit); err != nil {
+		return nil, fmt.Errorf("list dead outbox events: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+// ProfileRepository reads the public profile of users: section privacy, badges, recent comments
+// and the activity feed merged from collections, proposals, achievements and wiki edits.
+type ProfileRepository struct {
+	db *sqlx.DB
+}
+
+func NewProfileRepository(db *sqlx.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+const profilePrivacyColumns = `p.achievements_public, p.collections_public, p.bookmarks_public,
+	p.comments_public, p.activity_public, p.stats_public`
+
+// Owner returns the profile with the XP level and section privacy, nil if the user does not exist.
+func (r *ProfileRepository) Owner(ctx context.Context, userID uuid.UUID) (*models.ProfileOwner, error) {
+	var o models.ProfileOwner
+	err := r.db.GetContext(ctx, &o, `
+		SELECT p.user_id, p.display_name, p.avatar_key, p.bio, u.is_banned, u.created_at,
+		       COALESCE(x.level, 1) AS level, COALESCE(x.xp_total, 0) AS xp_total,
+		       `+profilePrivacyColumns+`
+		FROM user_profiles p
+		JOIN users u ON u.id = p.user_id
+		LEFT JOIN user_xp x ON x.user_id = p.user_id
+		WHERE p.user_id = $1
+	`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+	return &o, nil
+}
+
+// Update sets the display name and bio; nil leaves a field unchanged, an empty bio clears it.
+func (r *ProfileRepository) Update(ctx context.Context, userID uuid.UUID, displayName, bio *string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_profiles SET
+			display_name = COALESCE($2, display_name),
+			bio = CASE WHEN $3::text IS NULL THEN bio ELSE NULLIF($3::text, '') END,
+			updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, displayName, bio)
+	if err != nil {
+		return fmt.Errorf("update profile: %w", err)
+	}
+	return nil
+}
+
+// SetAvatar replaces the avatar (nil removes it) and returns the key of the previous one.
+func (r *ProfileRepository) SetAvatar(ctx context.Context, userID uuid.UUID, key *string) (*string, error) {
+	var old *string
+	err := r.db.GetContext(ctx, &old, `
+		UPDATE user_profiles p SET avatar_key = $2, updated_at = NOW()
+		FROM (SELECT avatar_key FROM user_profiles WHERE user_id = $1 FOR UPDATE) prev
+		WHERE p.user_id = $1
+		RETURNING prev.avatar_key
+	`, userID, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("set avatar: %w", err)
+	}
+	return old, nil
+}
+
+// UpdatePrivacy changes the visibility of the sections set in req and returns the result.
+func (r *ProfileRepository) UpdatePrivacy(ctx context.Context, userID uuid.UUID, req models.UpdateProfilePrivacyRequest) (models.ProfilePrivacy, error) {
+	var privacy models.ProfilePrivacy
+	err := r.db.GetContext(ctx, &privacy, `
+		UPDATE user_profiles p SET
+			achievements_public = COALESCE($2, achievements_public),
+			collections_public = COALESCE($3, collections_public),
+			bookmarks_public = COALESCE($4, bookmarks_public),
+			comments_public = COALESCE($5, comments_public),
+			activity_public = COALESCE($6, activity_public),
+			stats_public = COALESCE($7, stats_public),
+			updated_at = NOW()
+		WHERE p.user_id = $1
+		RETURNING `+profilePrivacyColumns+`
+	`, userID, req.Achievements, req.Collections, req.Bookmarks, req.Comments, req.Activity, req.Stats)
+	if err != nil {
+		return privacy, fmt.Errorf("update profile privacy: %w", err)
+	}
+	return privacy, nil
+}
+
+// Badges returns the achievements the user unlocked, most recent first.
+func (r *ProfileRepository) Badges(ctx context.Context, userID uuid.UUID) ([]models.AchievementBadge, error) {
+	out := []models.AchievementBadge{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT a.id, a.code, a.title, COALESCE(a.description, '') AS description,
+		       COALESCE(a.icon_key, '') AS icon_key, ua.unlocked_at
+		FROM user_achievements ua
+		JOIN achievements a ON a.id = ua.achievement_id
+		WHERE ua.user_id = $1
+		ORDER BY ua.unlocked_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list badges: %w", err)
+	}
+	return out, nil
+}
+
+// RecentComments returns the user's latest comments with the title of what they were left on
+// (novel titles in lang).
+func (r *ProfileRepository) RecentComments(ctx context.Context, userID uuid.UUID, lang string, limit int) ([]models.ProfileComment, error) {
+	out := []models.ProfileComment{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT c.id, c.target_type, c.target_id, c.body, c.is_spoiler, c.likes_count, c.created_at,
+		       CASE c.target_type
+		            WHEN 'news' THEN COALESCE(nwl.title, nw.title)
+		            WHEN 'profile' THEN tp.display_name
+		            ELSE COALESCE(nl.title, n.slug)
+		       END AS target_title,
+		       CASE c.target_type WHEN 'news' THEN nw.slug ELSE n.slug END AS target_slug
+		FROM comments c
+		LEFT JOIN chapters ch ON c.target_type = 'chapter' AND ch.id = c.target_id
+		LEFT JOIN novels n ON n.id = CASE c.target_type WHEN 'novel' THEN c.target_id ELSE ch.novel_id END
+		LEFT JOIN novel_localizations nl ON nl.novel_id = n.id AND nl.lang = $2
+		LEFT JOIN news_posts nw ON c.target_type = 'news' AND nw.id = c.target_id
+		LEFT JOIN news_localizations nwl ON nwl.news_id = nw.id AND nwl.lang = $2
+		LEFT JOIN user_profiles tp ON c.target_type = 'profile' AND tp.user_id = c.target_id
+		WHERE c.user_id = $1 AND c.is_deleted = FALSE
+		  AND (c.target_type <> 'news' OR nw.is_published)
+		ORDER BY c.created_at DESC
+		LIMIT $3
+	`, userID, lang, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list recent comments: %w", err)
+	}
+	return out, nil
+}
+
+// Activity returns a page of the user's activity, newest first: public collections created,
+// proposals submitted (rejected ones left out), achievements unlocked and wiki edits approved.
+// Each source is cut at offset+limit before merging, which is enough for the page.
+func (r *ProfileRepository) Activity(ctx context.Context, userID uuid.UUID, lang string, limit, offset int) ([]models.ProfileActivity, error) {
+	out := []models.ProfileActivity{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT type, ref_id, title, slug, icon_key, created_at
+		FROM (
+			(SELECT 'collection_created' AS type, c.id AS ref_id, c.title, NULL::text AS slug,
+			        NULL::text AS icon_key, c.created_at
+			 FROM collections c
+			 WHERE c.user_id = $1 AND c.is_public = TRUE
+			 ORDER BY c.created_at DESC LIMIT $5)
+			UNION ALL
+			(SELECT 'proposal_submitted', p.id, p.title, NULL, NULL, p.submitted_at
+			 FROM novel_proposals p
+			 WHERE p.user_id = $1 AND p.submitted_at IS NOT NULL AND p.status <> 'rejected'
+			 ORDER BY p.submitted_at DESC LIMIT $5)
+			UNION ALL
+			(SELECT 'achievement_unlocked', a.id, a.title, NULL, a.icon_key, ua.unlocked_at
+			 FROM user_achievements ua
+			 JOIN achievements a ON a.id = ua.achievement_id
+			 WHERE ua.user_id = $1
+			 ORDER BY ua.unlocked_at DESC LIMIT $5)
+			UNION ALL
+			(SELECT 'wiki_edit_approved', er.id, COALESCE(nl.title, n.slug), n.slug, NULL, er.reviewed_at
+			 FROM novel_edit_requests er
+			 JOIN novels n ON n.id = er.novel_id
+			 LEFT JOIN novel_localizations nl ON nl.novel_id = n.id AND nl.lang = $2
+			 WHERE er.user_id = $1 AND er.status = 'approved' AND er.reviewed_at IS NOT NULL
+			 ORDER BY er.reviewed_at DESC LIMIT $5)
+		) feed
+		ORDER BY created_at DESC, ref_id
+		LIMIT $3 OFFSET $4
+	`, userID, lang, limit, offset, offset+limit)
+	if err != nil {
+		return nil, fmt.Errorf("list profile activity: %w", err)
+	}
+	return out, nil
+}
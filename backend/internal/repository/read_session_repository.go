@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+// ReadSessionRepository stores chapter read sessions, the input of read_chapter XP verification.
+type ReadSessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewReadSessionRepository(db *sqlx.DB) *ReadSessionRepository {
+	return &ReadSessionRepository{db: db}
+}
+
+const readSessionColumns = `user_id, chapter_id, novel_id, words, started_at, last_seen_at, start_position,
+	max_position, last_position, updates, regressions, capped_at, awarded_at`
+
+// Touch records a progress save at position. A chapter not saved for idleGap (and not awarded
+// yet) starts a new session, so dwell time is measured over one continuous read.
+func (r *ReadSessionRepository) Touch(ctx context.Context, userID, novelID, chapterID uuid.UUID, position int, idleGap time.Duration) (*models.ReadSession, error) {
+	// The previous session ended if the chapter was left unawarded for idleGap.
+	const restart = `(s.awarded_at IS NULL AND s.last_seen_at < NOW() - make_interval(secs => $5))`
+	var s models.ReadSession
+	err := r.db.GetContext(ctx, &s, `
+		INSERT INTO chapter_read_sessions AS s (user_id, chapter_id, novel_id, words, start_position, max_position, last_position)
+		VALUES ($1, $2, $3,
+			(SELECT COALESCE(MIN(word_count) FILTER (WHERE word_count > 0), 0) FROM chapter_contents WHERE chapter_id = $2),
+			$4, $4, $4)
+		ON CONFLICT (user_id, chapter_id) DO UPDATE SET
+			started_at = CASE WHEN `+restart+` THEN NOW() ELSE s.started_at END,
+			start_position = CASE WHEN `+restart+` THEN EXCLUDED.last_position ELSE s.start_position END,
+			max_position = CASE WHEN `+restart+` THEN EXCLUDED.last_position
+				ELSE GREATEST(s.max_position, EXCLUDED.last_position) END,
+			regressions = s.regressions + (NOT `+restart+` AND EXCLUDED.last_position < s.last_position)::int,
+			last_position = EXCLUDED.last_position,
+			updates = s.updates + 1,
+			last_seen_at = NOW()
+		RETURNING `+readSessionColumns,
+		userID, chapterID, novelID, position, idleGap.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("touch read session: %w", err)
+	}
+	return &s, nil
+}
+
+// AwardedSince counts the user's reads awarded since the given time: in the novel and in total.
+func (r *ReadSessionRepository) AwardedSince(ctx context.Context, userID, novelID uuid.UUID, since time.Time) (inNovel, total int, err error) {
+	err = r.db.QueryRowxContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE novel_id = $2), COUNT(*)
+		FROM chapter_read_sessions
+		WHERE user_id = $1 AND awarded_at >= $3
+	`, userID, novelID, since).Scan(&inNovel, &total)
+	if err != nil {
+		return 0, 0, fmt.Errorf("count awarded reads: %w", err)
+	}
+	return inNovel, total, nil
+}
+
+// MarkCapped records that a qualified read was held back by a daily cap.
+func (r *ReadSessionRepository) MarkCapped(ctx context.Context, userID, chapterID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE chapter_read_sessions SET capped_at = NOW()
+		WHERE user_id = $1 AND chapter_id = $2 AND capped_at IS NULL
+	`, userID, chapterID)
+	if err != nil {
+		return fmt.Errorf("mark read capped: %w", err)
+	}
+	return nil
+}
+
+// ClaimAward marks the read as awarded; false if it already was. A failed award is handed back
+// with ReleaseAward.
+func (r *ReadSessionRepository) ClaimAward(ctx context.Context, userID, chapterID uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE chapter_read_sessions SET awarded_at = NOW()
+		WHERE user_id = $1 AND chapter_id = $2 AND awarded_at IS NULL
+	`, userID, chapterID)
+	if err != nil {
+		return false, fmt.Errorf("claim read award: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim read award: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ReleaseAward makes a claimed read unawarded again.
+func (r *ReadSessionRepository) ReleaseAward(ctx context.Context, userID, chapterID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE chapter_read_sessions SET awarded_at = NULL WHERE user_id = $1 AND chapter_id = $2
+	`, userID, chapterID)
+	if err != nil {
+		return fmt.Errorf("release read award: %w", err)
+	}
+	return nil
+}
+
+// Report sums up the user's read sessions started within window. A finished read (not saved
+// for idleGap) is skimmed if it lasted less than the dwell time: dwellRatio of the reading
+// time at 200 words per minute, at least minDwell.
+func (r *ReadSessionRepository) Report(ctx context.Context, userID uuid.UUID, window, idleGap time.Duration, dwellRatio float64, minDwell time.Duration) (*models.ReadActivityReport, error) {
+	var report models.ReadActivityReport
+	err := r.db.GetContext(ctx, &report, `
+		WITH recent AS (
+			SELECT * FROM chapter_read_sessions
+			WHERE user_id = $1 AND started_at > NOW() - make_interval(secs => $2)
+		)
+		SELECT
+			COUNT(*) AS sessions,
+			COUNT(*) FILTER (WHERE awarded_at IS NOT NULL) AS awarded,
+			COUNT(*) FILTER (
+				WHERE awarded_at IS NULL
+				  AND last_seen_at < NOW() - make_interval(secs => $3)
+				  AND EXTRACT(EPOCH FROM last_seen_at - started_at) < GREATEST($5::float8, $4::float8 * words * 60 / 200)
+			) AS skimmed,
+			COALESCE(SUM(regressions), 0) AS regressions,
+			COUNT(*) FILTER (WHERE capped_at IS NOT NULL) AS capped,
+			COALESCE((
+				SELECT MAX(n) FROM (
+					SELECT COUNT(*) AS n FROM recent GROUP BY date_trunc('hour', started_at)
+				) per_hour
+			), 0) AS peak_per_hour
+		FROM recent
+	`, userID, window.Seconds(), idleGap.Seconds(), dwellRatio, minDwell.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("read activity report: %w", err)
+	}
+	report.WindowHours = int(window.Hours())
+	return &report, nil
+}
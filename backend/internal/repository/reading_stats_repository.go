@@ -0,0 +1,453 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"novels-backend/internal/domain/models"
+)
+
+// ReadingStatsRepository stores reading time per user, novel and day, reading sittings and the
+// cached year in review.
+type ReadingStatsRepository struct {
+	db *sqlx.DB
+}
+
+func NewReadingStatsRepository(db *sqlx.DB) *ReadingStatsRepository {
+	return &ReadingStatsRepository{db: db}
+}
+
+// ReadingCategory is what favourite categories are counted by.
+type ReadingCategory string
+
+const (
+	ReadingCategoryGenres ReadingCategory = "genres"
+	ReadingCategoryTags   ReadingCategory = "tags"
+)
+
+// categoryTables are the link and localization tables of a category: genres or tags.
+var categoryTables = map[ReadingCategory]struct{ table, link, loc, key string }{
+	ReadingCategoryGenres: {"genres", "novel_genres", "genre_localizations", "genre_id"},
+	ReadingCategoryTags:   {"tags", "novel_tags", "tag_localizations", "tag_id"},
+}
+
+// Days are passed as "2006-01-02" strings with ::date casts (see dateParam).
+const (
+	firstDay = "0001-01-01"
+	lastDay  = "9999-12-31"
+)
+
+// RecordProgress adds a progress save to the reading time of day (local to the user). The time
+// since the previous save of the user counts as reading unless it is longer than idleGap, which
+// starts a new sitting. finished adds the chapter and its words: its read was just verified.
+func (r *ReadingStatsRepository) RecordProgress(ctx context.Context, userID, novelID, chapterID uuid.UUID, now, day time.Time, idleGap time.Duration, finished bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Saves of a user are serialized: each one extends the sitting the previous one left.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('reading_stats'), hashtext($1::text))`, userID); err != nil {
+		return fmt.Errorf("lock reading stats: %w", err)
+	}
+
+	var last struct {
+		StartedAt  time.Time `db:"started_at"`
+		LastSeenAt time.Time `db:"last_seen_at"`
+	}
+	err = tx.GetContext(ctx, &last, `
+		SELECT started_at, last_seen_at FROM user_reading_sittings
+		WHERE user_id = $1
+		ORDER BY started_at DESC
+		LIMIT 1
+	`, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("get reading sitting: %w", err)
+	}
+
+	chapters := 0
+	if finished {
+		chapters = 1
+	}
+	var seconds int
+	if elapsed := now.Sub(last.LastSeenAt); err == nil && elapsed >= 0 && elapsed <= idleGap {
+		seconds = int(elapsed.Seconds())
+		_, err = tx.ExecContext(ctx, `
+			UPDATE user_reading_sittings
+			SET last_seen_at = $3, seconds = seconds + $4, chapters = chapters + $5
+			WHERE user_id = $1 AND started_at = $2
+		`, userID, last.StartedAt, now, seconds, chapters)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_reading_sittings (user_id, started_at, last_seen_at, chapters)
+			VALUES ($1, $2, $2, $3)
+			ON CONFLICT (user_id, started_at) DO NOTHING
+		`, userID, now, chapters)
+	}
+	if err != nil {
+		return fmt.Errorf("record reading sitting: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_reading_days AS d (user_id, day, novel_id, seconds, chapters, words)
+		VALUES ($1, $2::date, $3, $5, $6,
+			CASE WHEN $6 > 0 THEN COALESCE((
+				SELECT words FROM chapter_read_sessions WHERE user_id = $1 AND chapter_id = $4
+			), 0) ELSE 0 END)
+		ON CONFLICT (user_id, day, novel_id) DO UPDATE SET
+			seconds = d.seconds + EXCLUDED.seconds,
+			chapters = d.chapters + EXCLUDED.chapters,
+			words = d.words + EXCLUDED.words
+	`, userID, day.Format(models.DateLayout), novelID, chapterID, seconds, chapters)
+	if err != nil {
+		return fmt.Errorf("record reading day: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// StatsPublic returns whether the user's reading statistics are public; found is false if the
+// user has no profile.
+func (r *ReadingStatsRepository) StatsPublic(ctx context.Context, userID uuid.UUID) (public, found bool, err error) {
+	err = r.db.GetContext(ctx, &public, `SELECT stats_public FROM user_profiles WHERE user_id = $1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("get stats privacy: %w", err)
+	}
+	return public, true, nil
+}
+
+// SetStatsPublic makes the user's reading statistics public or private.
+func (r *ReadingStatsRepository) SetStatsPublic(ctx context.Context, userID uuid.UUID, public bool) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_profiles SET stats_public = $2, updated_at = NOW() WHERE user_id = $1
+	`, userID, public)
+	if err != nil {
+		return fmt.Errorf("set stats privacy: %w", err)
+	}
+	return nil
+}
+
+// Totals sums up the user's reading on days from (inclusive) to (exclusive); empty bounds are open.
+func (r *ReadingStatsRepository) Totals(ctx context.Context, userID uuid.UUID, from, to string) (models.ReadingTotals, error) {
+	var t models.ReadingTotals
+	from, to = dayBounds(from, to)
+	err := r.db.GetContext(ctx, &t, `
+		SELECT COALESCE(SUM(seconds), 0) AS seconds,
+		       COALESCE(SUM(chapters), 0) AS chapters,
+		       COALESCE(SUM(words), 0) AS words,
+		       COUNT(DISTINCT day) AS days_read,
+		       COUNT(DISTINCT novel_id) AS novels_read
+		FROM user_reading_days
+		WHERE user_id = $1 AND day >= $2::date AND day < $3::date
+	`, userID, from, to)
+	if err != nil {
+		return t, fmt.Errorf("reading totals: %w", err)
+	}
+	return t, nil
+}
+
+// Periods returns the user's reading per week (starting on Monday) or month from the day from,
+// oldest first. Periods without reading are left out.
+func (r *ReadingStatsRepository) Periods(ctx context.Context, userID uuid.UUID, period models.ReadingStatsPeriod, from string) ([]models.ReadingPeriodStats, error) {
+	out := []models.ReadingPeriodStats{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT to_char(date_trunc($2, day::timestamp), 'YYYY-MM-DD') AS start,
+		       SUM(seconds) AS seconds, SUM(chapters) AS chapters, SUM(words) AS words
+		FROM user_reading_days
+		WHERE user_id = $1 AND day >= $3::date
+		GROUP BY 1
+		ORDER BY 1
+	`, userID, string(period), from)
+	if err != nil {
+		return nil, fmt.Errorf("reading periods: %w", err)
+	}
+	return out, nil
+}
+
+// TopCategories returns the genres or tags of the novels the user read most, by chapters and
+// then time, with names in lang.
+func (r *ReadingStatsRepository) TopCategories(ctx context.Context, userID uuid.UUID, category ReadingCategory, from, to, lang string, limit int) ([]models.ReadingCategoryStats, error) {
+	t, ok := categoryTables[category]
+	if !ok {
+		return nil, fmt.Errorf("unknown reading category %q", category)
+	}
+	from, to = dayBounds(from, to)
+	out := []models.ReadingCategoryStats{}
+	err := r.db.SelectContext(ctx, &out, fmt.Sprintf(`
+		SELECT c.id, c.slug, COALESCE(l.name, c.slug) AS name,
+		       SUM(d.seconds) AS seconds, SUM(d.chapters) AS chapters
+		FROM user_reading_days d
+		JOIN %[2]s nc ON nc.novel_id = d.novel_id
+		JOIN %[1]s c ON c.id = nc.%[4]s
+		LEFT JOIN %[3]s l ON l.%[4]s = c.id AND l.lang = $4
+		WHERE d.user_id = $1 AND d.day >= $2::date AND d.day < $3::date
+		GROUP BY c.id, c.slug, l.name
+		ORDER BY chapters DESC, seconds DESC, c.slug
+		LIMIT $5
+	`, t.table, t.link, t.loc, t.key), userID, from, to, lang, limit)
+	if err != nil {
+		return nil, fmt.Errorf("top reading %s: %w", category, err)
+	}
+	return out, nil
+}
+
+// TopNovels returns the novels the user read most, by time and then chapters, with titles in lang.
+func (r *ReadingStatsRepository) TopNovels(ctx context.Context, userID uuid.UUID, from, to, lang string, limit int) ([]models.NovelReadingStats, error) {
+	from, to = dayBounds(from, to)
+	out := []models.NovelReadingStats{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT n.id AS novel_id, n.slug, COALESCE(nl.title, n.slug) AS title,
+		       CASE WHEN n.cover_image_key IS NOT NULL AND n.cover_image_key != ''
+		            THEN '/uploads/' || n.cover_image_key END AS cover_url,
+		       d.seconds, d.chapters
+		FROM (
+			SELECT novel_id, SUM(seconds) AS seconds, SUM(chapters) AS chapters
+			FROM user_reading_days
+			WHERE user_id = $1 AND day >= $2::date AND day < $3::date
+			GROUP BY novel_id
+		) d
+		JOIN novels n ON n.id = d.novel_id
+		LEFT JOIN novel_localizations nl ON nl.novel_id = n.id AND nl.lang = $4
+		ORDER BY d.seconds DESC, d.chapters DESC, n.slug
+		LIMIT $5
+	`, userID, from, to, lang, limit)
+	if err != nil {
+		return nil, fmt.Errorf("top read novels: %w", err)
+	}
+	return out, nil
+}
+
+// BusiestMonth returns the month the user read most in, nil if there was no reading.
+func (r *ReadingStatsRepository) BusiestMonth(ctx context.Context, userID uuid.UUID, from, to string) (*models.ReadingMonth, error) {
+	from, to = dayBounds(from, to)
+	var m models.ReadingMonth
+	err := r.db.GetContext(ctx, &m, `
+		SELECT to_char(day, 'YYYY-MM') AS month, SUM(seconds) AS seconds, SUM(chapters) AS chapters
+		FROM user_reading_days
+		WHERE user_id = $1 AND day >= $2::date AND day < $3::date
+		GROUP BY 1
+		ORDER BY seconds DESC, chapters DESC, month
+		LIMIT 1
+	`, userID, from, to)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("busiest reading month: %w", err)
+	}
+	return &m, nil
+}
+
+// LongestStreak returns the most consecutive days with reading.
+func (r *ReadingStatsRepository) LongestStreak(ctx context.Context, userID uuid.UUID, from, to string) (int, error) {
+	from, to = dayBounds(from, to)
+	var days int
+	// Consecutive days share day - row_number, so each run of days is one group.
+	err := r.db.GetContext(ctx, &days, `
+		SELECT COALESCE(MAX(days), 0) FROM (
+			SELECT COUNT(*) AS days FROM (
+				SELECT day - (ROW_NUMBER() OVER (ORDER BY day))::int AS run
+				FROM user_reading_days
+				WHERE user_id = $1 AND day >= $2::date AND day < $3::date
+				GROUP BY day
+			) days
+			GROUP BY run
+		) runs
+	`, userID, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("longest reading streak: %w", err)
+	}
+	return days, nil
+}
+
+// LongestBinge returns the user's longest sitting started within [from, to), nil if none.
+func (r *ReadingStatsRepository) LongestBinge(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.ReadingBinge, error) {
+	var b models.ReadingBinge
+	err := r.db.GetContext(ctx, &b, `
+		SELECT started_at, seconds, chapters
+		FROM user_reading_sittings
+		WHERE user_id = $1 AND started_at >= $2 AND started_at < $3 AND seconds > 0
+		ORDER BY seconds DESC, chapters DESC
+		LIMIT 1
+	`, userID, from, to)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("longest reading binge: %w", err)
+	}
+	return &b, nil
+}
+
+// BookmarkCompletion counts the user's bookmarks by list for the completion rate.
+func (r *ReadingStatsRepository) BookmarkCompletion(ctx context.Context, userID uuid.UUID) (models.BookmarkCompletion, error) {
+	var c models.BookmarkCompletion
+	err := r.db.GetContext(ctx, &c, `
+		SELECT COUNT(*) AS total,
+		       COUNT(*) FILTER (WHERE bl.code IN ('reading', 'completed', 'dropped')) AS started,
+		       COUNT(*) FILTER (WHERE bl.code = 'completed') AS completed,
+		       COUNT(*) FILTER (WHERE bl.code = 'dropped') AS dropped
+		FROM bookmarks b
+		JOIN bookmark_lists bl ON bl.id = b.list_id
+		WHERE b.user_id = $1
+	`, userID)
+	if err != nil {
+		return c, fmt.Errorf("bookmark completion: %w", err)
+	}
+	return c, nil
+}
+
+// CompletedNovels counts the novels the user moved to the completed list within [from, to).
+func (r *ReadingStatsRepository) CompletedNovels(ctx context.Context, userID uuid.UUID, from, to time.Time) (int, error) {
+	var n int
+	err := r.db.GetContext(ctx, &n, `
+		SELECT COUNT(*)
+		FROM bookmarks b
+		JOIN bookmark_lists bl ON bl.id = b.list_id
+		WHERE b.user_id = $1 AND bl.code = 'completed' AND b.updated_at >= $2 AND b.updated_at < $3
+	`, userID, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("count completed novels: %w", err)
+	}
+	return n, nil
+}
+
+// CategoryNames returns the names of genres or tags in lang.
+func (r *ReadingStatsRepository) CategoryNames(ctx context.Context, category ReadingCategory, ids []uuid.UUID, lang string) (map[uuid.UUID]string, error) {
+	t, ok := categoryTables[category]
+	if !ok {
+		return nil, fmt.Errorf("unknown reading category %q", category)
+	}
+	return r.names(ctx, fmt.Sprintf(`
+		SELECT c.id, COALESCE(l.name, c.slug) AS name
+		FROM %[1]s c
+		LEFT JOIN %[2]s l ON l.%[3]s = c.id AND l.lang = $2
+		WHERE c.id = ANY($1::uuid[])
+	`, t.table, t.loc, t.key), ids, lang)
+}
+
+// NovelTitles returns the titles of novels in lang.
+func (r *ReadingStatsRepository) NovelTitles(ctx context.Context, ids []uuid.UUID, lang string) (map[uuid.UUID]string, error) {
+	return r.names(ctx, `
+		SELECT n.id, COALESCE(nl.title, n.slug) AS name
+		FROM novels n
+		LEFT JOIN novel_localizations nl ON nl.novel_id = n.id AND nl.lang = $2
+		WHERE n.id = ANY($1::uuid[])
+	`, ids, lang)
+}
+
+func (r *ReadingStatsRepository) names(ctx context.Context, query string, ids []uuid.UUID, lang string) (map[uuid.UUID]string, error) {
+	out := make(map[uuid.UUID]string, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		ID   uuid.UUID `db:"id"`
+		Name string    `db:"name"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(ids), lang); err != nil {
+		return nil, fmt.Errorf("get names: %w", err)
+	}
+	for _, row := range rows {
+		out[row.ID] = row.Name
+	}
+	return out, nil
+}
+
+// YearReview returns the cached year in review, nil if it was not generated.
+func (r *ReadingStatsRepository) YearReview(ctx context.Context, userID uuid.UUID, year int) (*models.YearInReview, error) {
+	var data []byte
+	err := r.db.GetContext(ctx, &data, `SELECT data FROM user_year_reviews WHERE user_id = $1 AND year = $2`, userID, year)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get year review: %w", err)
+	}
+	var review models.YearInReview
+	if err := json.Unmarshal(data, &review); err != nil {
+		return nil, fmt.Errorf("decode year review: %w", err)
+	}
+	return &review, nil
+}
+
+// SaveYearReview stores the year in review, replacing an earlier one.
+func (r *ReadingStatsRepository) SaveYearReview(ctx context.Context, review *models.YearInReview) error {
+	data, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("encode year review: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO user_year_reviews (user_id, year, data, generated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, year) DO UPDATE SET data = EXCLUDED.data, generated_at = EXCLUDED.generated_at
+	`, review.UserID, review.Year, data, review.GeneratedAt)
+	if err != nil {
+		return fmt.Errorf("save year review: %w", err)
+	}
+	return nil
+}
+
+// StaleYearReviews returns up to limit users (greater than after, in order) who read in year
+// and whose review is missing or was generated before staleBefore.
+func (r *ReadingStatsRepository) StaleYearReviews(ctx context.Context, year int, staleBefore time.Time, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	out := []uuid.UUID{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT d.user_id
+		FROM user_reading_days d
+		LEFT JOIN user_year_reviews y ON y.user_id = d.user_id AND y.year = $1
+		WHERE d.day >= make_date($1, 1, 1) AND d.day < make_date($1 + 1, 1, 1)
+		  AND d.user_id > $3
+		  AND (y.user_id IS NULL OR y.generated_at < $2)
+		GROUP BY d.user_id
+		ORDER BY d.user_id
+		LIMIT $4
+	`, year, staleBefore, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list stale year reviews: %w", err)
+	}
+	return out, nil
+}
+
+// WithYearReviewLock runs fn unless another instance is generating reviews; returns false if
+// it was skipped.
+func (r *ReadingStatsRepository) WithYearReviewLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := r.db.Connx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.GetContext(ctx, &locked, `SELECT pg_try_advisory_lock(hashtext('year_review'))`); err != nil {
+		return false, fmt.Errorf("acquire year review lock: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext('year_review'))`)
+	return true, fn(ctx)
+}
+
+// dayBounds opens empty day bounds.
+func dayBounds(from, to string) (string, string) {
+	if from == "" {
+		from = firstDay
+	}
+	if to == "" {
+		to = lastDay
+	}
+	return from, to
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+// SetManualRelease makes the chapters inserted in tx keep their published_at even if the novel
+// has a release schedule (see the queue trigger in 025_chapter_release_schedules.up.sql).
+// The setting is transaction-local.
+func SetManualRelease(ctx context.Context, tx sqlx.ExecerContext) error {
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('app.chapter_release', 'manual', true)`); err != nil {
+		return fmt.Errorf("set manual release: %w", err)
+	}
+	return nil
+}
+
+// ReleaseScheduleRepository stores per-novel release cadences and moves chapters out of the queue.
+type ReleaseScheduleRepository struct {
+	db *sqlx.DB
+}
+
+func NewReleaseScheduleRepository(db *sqlx.DB) *ReleaseScheduleRepository {
+	return &ReleaseScheduleRepository{db: db}
+}
+
+const releaseScheduleColumns = `
+	novel_id, enabled, chapters_per_release, release_times, weekdays, timezone,
+	last_release_at, updated_by, created_at, updated_at
+`
+
+// Get returns the schedule of a novel, or nil if it has none.
+func (r *ReleaseScheduleRepository) Get(ctx context.Context, novelID uuid.UUID) (*models.ReleaseSchedule, error) {
+	var s models.ReleaseSchedule
+	err := r.db.GetContext(ctx, &s, `SELECT `+releaseScheduleColumns+` FROM novel_release_schedules WHERE novel_id = $1`, novelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get release schedule: %w", err)
+	}
+	return &s, nil
+}
+
+// ListEnabled returns all enabled schedules.
+func (r *ReleaseScheduleRepository) ListEnabled(ctx context.Context) ([]models.ReleaseSchedule, error) {
+	out := []models.ReleaseSchedule{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+releaseScheduleColumns+`
+		FROM novel_release_schedules
+		WHERE enabled = TRUE
+		ORDER BY novel_id
+	`); err != nil {
+		return nil, fmt.Errorf("list release schedules: %w", err)
+	}
+	return out, nil
+}
+
+// Upsert creates or replaces the schedule of s.NovelID. The release cursor is kept.
+func (r *ReleaseScheduleRepository) Upsert(ctx context.Context, s *models.ReleaseSchedule) error {
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO novel_release_schedules (novel_id, enabled, chapters_per_release, release_times, weekdays, timezone, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (novel_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			chapters_per_release = EXCLUDED.chapters_per_release,
+			release_times = EXCLUDED.release_times,
+			weekdays = EXCLUDED.weekdays,
+			timezone = EXCLUDED.timezone,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING last_release_at, created_at, updated_at
+	`, s.NovelID, s.Enabled, s.ChaptersPerRelease, s.ReleaseTimes, s.Weekdays, s.Timezone, s.UpdatedBy).
+		Scan(&s.LastReleaseAt, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert release schedule: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the schedule and publishes the chapters still waiting in its queue.
+// Returns sql.ErrNoRows if the novel had no schedule.
+func (r *ReleaseScheduleRepository) Delete(ctx context.Context, novelID uuid.UUID) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM novel_release_schedules WHERE novel_id = $1`, novelID)
+	if err != nil {
+		return 0, fmt.Errorf("delete release schedule: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	res, err = tx.ExecContext(ctx, `
+		UPDATE chapters SET published_at = NOW()
+		WHERE novel_id = $1 AND published_at IS NULL
+	`, novelID)
+	if err != nil {
+		return 0, fmt.Errorf("release queued chapters: %w", err)
+	}
+	released, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return int(released), nil
+}
+
+// ReleaseQueued gives the first n queued chapters of the novel (by number) the release time slot
+// and moves the schedule cursor to it. Returns how many chapters were scheduled (0 = queue empty).
+func (r *ReleaseScheduleRepository) ReleaseQueued(ctx context.Context, novelID uuid.UUID, slot time.Time, n int) (int, error) {
+	var released int
+	err := r.db.GetContext(ctx, &released, `
+		WITH picked AS (
+			SELECT id FROM chapters
+			WHERE novel_id = $1 AND published_at IS NULL
+			ORDER BY number
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		), released AS (
+			UPDATE chapters c SET published_at = $2
+			FROM picked
+			WHERE c.id = picked.id
+			RETURNING c.id
+		), moved AS (
+			UPDATE novel_release_schedules SET last_release_at = $2
+			WHERE novel_id = $1 AND EXISTS (SELECT 1 FROM released)
+		)
+		SELECT COUNT(*) FROM released
+	`, novelID, slot, n)
+	if err != nil {
+		return 0, fmt.Errorf("release queued chapters: %w", err)
+	}
+	return released, nil
+}
+
+// Pending returns how many chapters wait for a slot and the first limit chapters that are
+// not live yet (scheduled ones first, by release time, then the queue by number).
+func (r *ReleaseScheduleRepository) Pending(ctx context.Context, novelID uuid.UUID, limit int) (int, []models.ReleaseQueueItem, error) {
+	var queued int
+	if err := r.db.GetContext(ctx, &queued, `
+		SELECT COUNT(*) FROM chapters WHERE novel_id = $1 AND published_at IS NULL
+	`, novelID); err != nil {
+		return 0, nil, fmt.Errorf("count queued chapters: %w", err)
+	}
+
+	items := []models.ReleaseQueueItem{}
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT id, number, title, published_at
+		FROM chapters
+		WHERE novel_id = $1 AND (published_at IS NULL OR published_at > NOW())
+		ORDER BY published_at NULLS LAST, number
+		LIMIT $2
+	`, novelID, limit); err != nil {
+		return 0, nil, fmt.Errorf("list pending chapters: %w", err)
+	}
+	return queued, items, nil
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+// ErrRetranslationOpen is returned by Create when the chapter translation already has a
+// pending or accepted request (see migration 037).
+var ErrRetranslationOpen = errors.New("retranslation request already open")
+
+// RetranslationRepository stores chapter retranslation requests and their quota ledger.
+type RetranslationRepository struct {
+	db *sqlx.DB
+}
+
+func NewRetranslationRepository(db *sqlx.DB) *RetranslationRepository {
+	return &RetranslationRepository{db: db}
+}
+
+const retranslationRequestColumns = `
+	rr.id, rr.user_id, rr.chapter_id, rr.novel_id, rr.lang, rr.paragraph_from, rr.paragraph_to,
+	rr.reason, rr.status, rr.moderator_id, rr.moderator_comment, rr.reviewed_at,
+	rr.translation_job_id, rr.previous_content, rr.previous_source, rr.error, rr.completed_at,
+	rr.created_at, rr.updated_at
+`
+
+// Create stores a request and debits one unit of the user's allowance for the period in one
+// transaction. Concurrent requests of a user are serialized, so the limit cannot be overshot.
+// Returns false (and stores nothing) when the allowance is used up, and ErrRetranslationOpen
+// when a concurrent request for the same chapter and language got in first.
+func (r *RetranslationRepository) Create(ctx context.Context, req *models.RetranslationRequest, subscriptionID *uuid.UUID, periodStart time.Time, limit int) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin retranslation request tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('retranslation_quota:' || $1::text))`, req.UserID); err != nil {
+		return false, fmt.Errorf("lock retranslation quota: %w", err)
+	}
+	used, err := quotaUsed(ctx, tx, req.UserID, periodStart)
+	if err != nil {
+		return false, err
+	}
+	if used >= limit {
+		return false, nil
+	}
+
+	err = tx.QueryRowxContext(ctx, `
+		INSERT INTO retranslation_requests (user_id, chapter_id, novel_id, lang, paragraph_from, paragraph_to, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, status, created_at, updated_at
+	`, req.UserID, req.ChapterID, req.NovelID, req.Lang, req.ParagraphFrom, req.ParagraphTo, req.Reason).
+		Scan(&req.ID, &req.Status, &req.CreatedAt, &req.UpdatedAt)
+	if isUniqueViolation(err) {
+		return false, ErrRetranslationOpen
+	}
+	if err != nil {
+		return false, fmt.Errorf("create retranslation request: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO retranslation_quota_transactions
+			(user_id, subscription_id, period_start, delta, reason, ref_type, ref_id, idempotency_key)
+		VALUES ($1, $2, $3, -1, 'retranslation_request', 'retranslation_request', $4, $5)
+	`, req.UserID, subscriptionID, periodStart, req.ID, "retranslation_request:"+req.ID.String()); err != nil {
+		return false, fmt.Errorf("debit retranslation quota: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit retranslation request: %w", err)
+	}
+	return true, nil
+}
+
+func quotaUsed(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID, periodStart time.Time) (int, error) {
+	var used int
+	if err := sqlx.GetContext(ctx, q, &used, `
+		SELECT COALESCE(-SUM(delta), 0)
+		FROM retranslation_quota_transactions
+		WHERE user_id = $1 AND period_start = $2
+	`, userID, periodStart); err != nil {
+		return 0, fmt.Errorf("retranslation quota used: %w", err)
+	}
+	return used, nil
+}
+
+// QuotaUsed returns how many requests count against the allowance of the period.
+func (r *RetranslationRepository) QuotaUsed(ctx context.Context, userID uuid.UUID, periodStart time.Time) (int, error) {
+	return quotaUsed(ctx, r.db, userID, periodStart)
+}
+
+// Refund credits back the unit debited for a request. Idempotent.
+func (r *RetranslationRepository) Refund(ctx context.Context, requestID uuid.UUID, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO retranslation_quota_transactions
+			(user_id, subscription_id, period_start, delta, reason, ref_type, ref_id, idempotency_key)
+		SELECT user_id, subscription_id, period_start, 1, $2, ref_type, ref_id, 'retranslation_refund:' || ref_id::text
+		FROM retranslation_quota_transactions
+		WHERE ref_type = 'retranslation_request' AND ref_id = $1 AND delta < 0
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`, requestID, reason)
+	if err != nil {
+		return fmt.Errorf("refund retranslation quota: %w", err)
+	}
+	return nil
+}
+
+// ListTransactions returns the user's quota ledger, newest first.
+func (r *RetranslationRepository) ListTransactions(ctx context.Context, userID uuid.UUID, limit int) ([]models.RetranslationQuotaTransaction, error) {
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	out := []models.RetranslationQuotaTransaction{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT id, user_id, subscription_id, period_start, delta, reason, ref_type, ref_id, idempotency_key, created_at
+		FROM retranslation_quota_transactions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, userID, limit); err != nil {
+		return nil, fmt.Errorf("list retranslation quota transactions: %w", err)
+	}
+	return out, nil
+}
+
+func (r *RetranslationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RetranslationRequest, error) {
+	var req models.RetranslationRequest
+	err := r.db.GetContext(ctx, &req, `
+		SELECT `+retranslationRequestColumns+`, c.number AS chapter_number, n.slug AS novel_slug
+		FROM retranslation_requests rr
+		JOIN chapters c ON c.id = rr.chapter_id
+		JOIN novels n ON n.id = rr.novel_id
+		WHERE rr.id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get retranslation request: %w", err)
+	}
+	return &req, nil
+}
+
+// HasOpen reports whether the chapter translation already has a pending or accepted request.
+func (r *RetranslationRepository) HasOpen(ctx context.Context, chapterID uuid.UUID, lang string) (bool, error) {
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM retranslation_requests
+			WHERE chapter_id = $1 AND lang = $2 AND status IN ('pending', 'accepted')
+		)
+	`, chapterID, lang); err != nil {
+		return false, fmt.Errorf("check open retranslation request: %w", err)
+	}
+	return exists, nil
+}
+
+// List returns requests filtered by status and/or user, oldest pending first for moderators.
+func (r *RetranslationRepository) List(ctx context.Context, status *models.RetranslationRequestStatus, userID *uuid.UUID, limit, offset int) ([]models.RetranslationRequest, int, error) {
+	where := `WHERE ($1::text IS NULL OR rr.status = $1) AND ($2::uuid IS NULL OR rr.user_id = $2)`
+	var statusArg *string
+	if status != nil {
+		s := string(*status)
+		statusArg = &s
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM retranslation_requests rr `+where, statusArg, userID); err != nil {
+		return nil, 0, fmt.Errorf("count retranslation requests: %w", err)
+	}
+
+	order := `rr.created_at DESC`
+	if status != nil && *status == models.RetranslationStatusPending {
+		order = `rr.created_at ASC`
+	}
+	out := []models.RetranslationRequest{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+retranslationRequestColumns+`, c.number AS chapter_number, n.slug AS novel_slug
+		FROM retranslation_requests rr
+		JOIN chapters c ON c.id = rr.chapter_id
+		JOIN novels n ON n.id = rr.novel_id
+		`+where+`
+		ORDER BY `+order+`
+		LIMIT $3 OFFSET $4
+	`, statusArg, userID, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("list retranslation requests: %w", err)
+	}
+	return out, total, nil
+}
+
+// Accept moves a pending request to accepted and snapshots the current translation.
+// Returns false if the request is no longer pending.
+func (r *RetranslationRepository) Accept(ctx context.Context, id, moderatorID uuid.UUID, comment string, previousContent, previousSource *string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE retranslation_requests
+		SET status = 'accepted', moderator_id = $2, moderator_comment = NULLIF($3, ''),
+		    reviewed_at = NOW(), previous_content = $4, previous_source = $5, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, moderatorID, comment, previousContent, previousSource)
+	if err != nil {
+		return false, fmt.Errorf("accept retranslation request: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// SetJob links an accepted request to the translation job re-running it.
+func (r *RetranslationRepository) SetJob(ctx context.Context, id, jobID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE retranslation_requests SET translation_job_id = $2, updated_at = NOW() WHERE id = $1
+	`, id, jobID)
+	if err != nil {
+		return fmt.Errorf("set retranslation job: %w", err)
+	}
+	return nil
+}
+
+// Reject closes a pending request. Returns false if it is no longer pending.
+func (r *RetranslationRepository) Reject(ctx context.Context, id, moderatorID uuid.UUID, comment string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE retranslation_requests
+		SET status = 'rejected', moderator_id = $2, moderator_comment = NULLIF($3, ''),
+		    reviewed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, moderatorID, comment)
+	if err != nil {
+		return false, fmt.Errorf("reject retranslation request: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Finish records the outcome of an accepted request.
+func (r *RetranslationRepository) Finish(ctx context.Context, id uuid.UUID, status models.RetranslationRequestStatus, errMsg *string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE retranslation_requests
+		SET status = $2, error = $3, completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'accepted'
+	`, id, status, errMsg)
+	if err != nil {
+		return fmt.Errorf("finish retranslation request: %w", err)
+	}
+	return nil
+}
+
+// ListAcceptedForJob returns accepted requests waiting on a translation job. Requests not yet
+// linked to the job (it may finish before SetJob runs) are matched by chapter and language.
+func (r *RetranslationRepository) ListAcceptedForJob(ctx context.Context, jobID, chapterID uuid.UUID, lang string) ([]models.RetranslationRequest, error) {
+	out := []models.RetranslationRequest{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+retranslationRequestColumns+`
+		FROM retranslation_requests rr
+		WHERE rr.status = 'accepted'
+		  AND rr.chapter_id = $2 AND rr.lang = $3
+		  AND (rr.translation_job_id = $1 OR rr.translation_job_id IS NULL)
+	`, jobID, chapterID, lang); err != nil {
+		return nil, fmt.Errorf("list retranslation requests by job: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/domain/models"
+)
+
+// SourceLinksRepository keeps source_links: one row per source book (canonical URL)
+// pointing to its proposal and imported novel.
+type SourceLinksRepository struct {
+	db *sqlx.DB
+}
+
+func NewSourceLinksRepository(db *sqlx.DB) *SourceLinksRepository {
+	return &SourceLinksRepository{db: db}
+}
+
+// FindByURL returns the imported novel and the active (not rejected) proposal registered
+// for canonicalURL, novel first. excludeProposalID skips the proposal being edited.
+func (r *SourceLinksRepository) FindByURL(ctx context.Context, canonicalURL string, excludeProposalID *uuid.UUID) ([]models.DuplicateMatch, error) {
+	out := []models.DuplicateMatch{}
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT 'novel' AS kind, 'url' AS matched_by, n.id, n.slug,
+		       COALESCE((SELECT nl.title FROM novel_localizations nl WHERE nl.novel_id = n.id ORDER BY nl.lang = 'ru' DESC, nl.lang LIMIT 1), n.slug) AS title,
+		       '' AS status, 1.0::float8 AS similarity
+		FROM source_links s
+		JOIN novels n ON n.id = s.novel_id
+		WHERE s.canonical_url = $1
+		UNION ALL
+		SELECT 'proposal', 'url', p.id, NULL, p.title, p.status::text, 1.0::float8
+		FROM source_links s
+		JOIN novel_proposals p ON p.id = s.proposal_id
+		WHERE s.canonical_url = $1
+		  AND p.status <> 'rejected'
+		  AND p.id IS DISTINCT FROM $2
+		ORDER BY kind
+	`, canonicalURL, excludeProposalID)
+	if err != nil {
+		return nil, fmt.Errorf("find source link duplicates: %w", err)
+	}
+	return out, nil
+}
+
+// FindSimilarTitles returns novels and active proposals not yet imported whose title has a
+// trigram similarity of at least threshold with title, most similar first.
+func (r *SourceLinksRepository) FindSimilarTitles(ctx context.Context, title string, threshold float64, limit int, excludeProposalID *uuid.UUID) ([]models.DuplicateMatch, error) {
+	if limit < 1 {
+		limit = 5
+	}
+	out := []models.DuplicateMatch{}
+	// `%` narrows the candidates using the trigram indexes (pg_trgm.similarity_threshold,
+	// 0.3 by default); the threshold is applied on top of it.
+	err := r.db.SelectContext(ctx, &out, `
+		SELECT kind, matched_by, id, slug, title, status, similarity FROM (
+			SELECT DISTINCT ON (n.id) 'novel' AS kind, 'title' AS matched_by, n.id, n.slug, nl.title,
+			       '' AS status, similarity(nl.title, $1)::float8 AS similarity
+			FROM novel_localizations nl
+			JOIN novels n ON n.id = nl.novel_id
+			WHERE nl.title % $1
+			ORDER BY n.id, similarity(nl.title, $1) DESC
+		) novels
+		WHERE similarity >= $2
+		UNION ALL
+		SELECT 'proposal', 'title', p.id, NULL, p.title, p.status::text, similarity(p.title, $1)::float8
+		FROM novel_proposals p
+		WHERE p.title % $1
+		  AND similarity(p.title, $1) >= $2
+		  AND p.status <> 'rejected'
+		  AND p.novel_id IS NULL
+		  AND p.id IS DISTINCT FROM $3
+		ORDER BY similarity DESC
+		LIMIT $4
+	`, title, threshold, excludeProposalID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find similar titles: %w", err)
+	}
+	return out, nil
+}
+
+// ClaimForProposal registers canonicalURL for a proposal. The URL is taken over if it is free
+// or held by a rejected proposal; it returns false if an imported novel or another active
+// proposal holds it.
+func (r *SourceLinksRepository) ClaimForProposal(ctx context.Context, canonicalURL, importer string, proposalID uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO source_links (canonical_url, importer, proposal_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (canonical_url) DO UPDATE SET
+			proposal_id = EXCLUDED.proposal_id,
+			importer = EXCLUDED.importer,
+			updated_at = NOW()
+		WHERE source_links.novel_id IS NULL
+		  AND (
+			source_links.proposal_id IS NULL
+			OR source_links.proposal_id = EXCLUDED.proposal_id
+			OR EXISTS (SELECT 1 FROM novel_proposals p WHERE p.id = source_links.proposal_id AND p.status = 'rejected')
+		  )
+	`, canonicalURL, importer, proposalID)
+	if err != nil {
+		return false, fmt.Errorf("claim source link: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim source link: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ReleaseProposal frees the URLs a proposal holds except keepURL (after its link was changed).
+func (r *SourceLinksRepository) ReleaseProposal(ctx context.Context, proposalID uuid.UUID, keepURL string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE source_links SET proposal_id = NULL, updated_at = NOW()
+		WHERE proposal_id = $1 AND canonical_url <> $2
+	`, proposalID, keepURL)
+	if err != nil {
+		return fmt.Errorf("release source links: %w", err)
+	}
+	return nil
+}
+
+// LinkNovel records that a novel was imported from canonicalURL. A novel already linked to
+// the URL is kept, so importing a book twice from the CLI does not hide the first copy.
+func (r *SourceLinksRepository) LinkNovel(ctx context.Context, canonicalURL, importer string, novelID uuid.UUID, proposalID *uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO source_links (canonical_url, importer, proposal_id, novel_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (canonical_url) DO UPDATE SET
+			novel_id = COALESCE(source_links.novel_id, EXCLUDED.novel_id),
+			proposal_id = COALESCE(EXCLUDED.proposal_id, source_links.proposal_id),
+			importer = EXCLUDED.importer,
+			updated_at = NOW()
+	`, canonicalURL, importer, proposalID, novelID)
+	if err != nil {
+		return fmt.Errorf("link source novel: %w", err)
+	}
+	return nil
+}
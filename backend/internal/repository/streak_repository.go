@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"novels-backend/internal/domain/models"
+)
+
+// StreakRepository stores daily activity, streaks, streak freezes and milestone rewards.
+type StreakRepository struct {
+	db *sqlx.DB
+}
+
+func NewStreakRepository(db *sqlx.DB) *StreakRepository {
+	return &StreakRepository{db: db}
+}
+
+const streakColumns = `user_id, current_streak, longest_streak, started_on, last_active_on, freezes, updated_at`
+
+// ActivityState returns the user's timezone and the kinds recorded on the last active day,
+// or nil if the user does not exist.
+func (r *StreakRepository) ActivityState(ctx context.Context, userID uuid.UUID) (*models.ActivityState, error) {
+	var s models.ActivityState
+	err := r.db.GetContext(ctx, &s, `
+		SELECT COALESCE(p.timezone, '') AS timezone, s.last_active_on, COALESCE(d.kinds, '{}') AS kinds
+		FROM users u
+		LEFT JOIN user_profiles p ON p.user_id = u.id
+		LEFT JOIN user_streaks s ON s.user_id = u.id
+		LEFT JOIN user_activity_days d ON d.user_id = u.id AND d.day = s.last_active_on
+		WHERE u.id = $1
+	`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get activity state: %w", err)
+	}
+	return &s, nil
+}
+
+// Get returns the streak of a user; users without activity get an empty streak.
+func (r *StreakRepository) Get(ctx context.Context, userID uuid.UUID) (*models.UserStreak, error) {
+	var s models.UserStreak
+	err := r.db.GetContext(ctx, &s, `SELECT `+streakColumns+` FROM user_streaks WHERE user_id = $1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.UserStreak{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get streak: %w", err)
+	}
+	return &s, nil
+}
+
+// RecordActivity records activity of kind on day (the user's local date) and advances the
+// streak (see models.UserStreak.Advance). Days bridged by freezes are recorded as frozen and a
+// reached milestone is queued for ClaimPendingRewards. The streak row is locked, so concurrent
+// activity of a user advances the streak once.
+func (r *StreakRepository) RecordActivity(ctx context.Context, userID uuid.UUID, day time.Time, kind models.ActivityKind) (*models.UserStreak, models.StreakAdvance, error) {
+	var adv models.StreakAdvance
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, adv, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_streaks (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING
+	`, userID); err != nil {
+		return nil, adv, fmt.Errorf("create streak: %w", err)
+	}
+	var s models.UserStreak
+	if err := tx.GetContext(ctx, &s, `SELECT `+streakColumns+` FROM user_streaks WHERE user_id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, adv, fmt.Errorf("lock streak: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_activity_days (user_id, day, kinds)
+		VALUES ($1, $2::date, ARRAY[$3::text])
+		ON CONFLICT (user_id, day) DO UPDATE SET kinds = array_append(user_activity_days.kinds, $3::text)
+		WHERE NOT ($3::text = ANY(user_activity_days.kinds))
+	`, userID, day.Format(models.DateLayout), string(kind)); err != nil {
+		return nil, adv, fmt.Errorf("record activity day: %w", err)
+	}
+
+	adv = s.Advance(day)
+	if !adv.NewDay {
+		return &s, adv, tx.Commit()
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE user_streaks SET
+			current_streak = $2,
+			longest_streak = $3,
+			started_on = $4::date,
+			last_active_on = $5::date,
+			freezes = $6,
+			updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, s.Current, s.Longest, dateParam(s.StartedOn), dateParam(s.LastActiveOn), s.Freezes); err != nil {
+		return nil, adv, fmt.Errorf("update streak: %w", err)
+	}
+
+	if len(adv.Frozen) > 0 {
+		days := make([]string, len(adv.Frozen))
+		for i, d := range adv.Frozen {
+			days[i] = d.Format(models.DateLayout)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO user_activity_days (user_id, day, frozen)
+			SELECT $1, unnest($2::date[]), TRUE
+			ON CONFLICT (user_id, day) DO UPDATE SET frozen = TRUE
+		`, userID, pq.Array(days)); err != nil {
+			return nil, adv, fmt.Errorf("record frozen days: %w", err)
+		}
+	}
+
+	if adv.Milestone != nil {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO user_streak_rewards (user_id, milestone, started_on)
+			VALUES ($1, $2, $3::date)
+			ON CONFLICT (user_id, milestone, started_on) DO NOTHING
+		`, userID, adv.Milestone.Days, dateParam(s.StartedOn)); err != nil {
+			return nil, adv, fmt.Errorf("queue streak reward: %w", err)
+		}
+	}
+
+	return &s, adv, tx.Commit()
+}
+
+// dateParam passes a day as a date literal: a time.Time would be sent as a timestamp and its
+// date would then depend on the session timezone.
+func dateParam(day *time.Time) interface{} {
+	if day == nil {
+		return nil
+	}
+	return day.Format(models.DateLayout)
+}
+
+// StreakReward is a milestone reached by a user in the streak started on StartedOn.
+type StreakReward struct {
+	UserID    uuid.UUID `db:"user_id"`
+	Milestone int       `db:"milestone"`
+	StartedOn time.Time `db:"started_on"`
+}
+
+// ClaimPendingRewards marks the user's ungranted milestone rewards as granted and returns them.
+// A reward whose grant fails must be handed back with ReleaseReward.
+func (r *StreakRepository) ClaimPendingRewards(ctx context.Context, userID uuid.UUID) ([]StreakReward, error) {
+	out := []StreakReward{}
+	if err := r.db.SelectContext(ctx, &out, `
+		UPDATE user_streak_rewards SET granted_at = NOW()
+		WHERE user_id = $1 AND granted_at IS NULL
+		RETURNING user_id, milestone, started_on
+	`, userID); err != nil {
+		return nil, fmt.Errorf("claim streak rewards: %w", err)
+	}
+	return out, nil
+}
+
+// ReleaseReward makes a claimed reward pending again.
+func (r *StreakRepository) ReleaseReward(ctx context.Context, reward StreakReward) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_streak_rewards SET granted_at = NULL
+		WHERE user_id = $1 AND milestone = $2 AND started_on = $3::date
+	`, reward.UserID, reward.Milestone, reward.StartedOn.Format(models.DateLayout))
+	if err != nil {
+		return fmt.Errorf("release streak reward: %w", err)
+	}
+	return nil
+}
+
+// AddFreeze gives the user a streak freeze unless they already hold limit of them.
+func (r *StreakRepository) AddFreeze(ctx context.Context, userID uuid.UUID, limit int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_streaks (user_id, freezes) VALUES ($1, 1)
+		ON CONFLICT (user_id) DO UPDATE SET freezes = user_streaks.freezes + 1, updated_at = NOW()
+		WHERE user_streaks.freezes < $2
+	`, userID, limit)
+	if err != nil {
+		return false, fmt.Errorf("add streak freeze: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("add streak freeze: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RemoveFreeze takes back a streak freeze (after its payment failed).
+func (r *StreakRepository) RemoveFreeze(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_streaks SET freezes = freezes - 1, updated_at = NOW()
+		WHERE user_id = $1 AND freezes > 0
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("remove streak freeze: %w", err)
+	}
+	return nil
+}
+
+// Timezone returns the timezone the user chose, or "" if none.
+func (r *StreakRepository) Timezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	var tz string
+	err := r.db.GetContext(ctx, &tz, `SELECT COALESCE(timezone, '') FROM user_profiles WHERE user_id = $1`, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("get timezone: %w", err)
+	}
+	return tz, nil
+}
+
+// SetTimezone sets the timezone streak days of the user are counted in.
+func (r *StreakRepository) SetTimezone(ctx context.Context, userID uuid.UUID, tz string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_profiles SET timezone = $2, updated_at = NOW() WHERE user_id = $1
+	`, userID, tz)
+	if err != nil {
+		return fmt.Errorf("set timezone: %w", err)
+	}
+	return nil
+}
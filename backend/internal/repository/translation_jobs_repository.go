@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"novels-backend/internal/domain/models"
+)
+
+// TranslationJobsRepository is the queue of chapter machine translations.
+type TranslationJobsRepository struct {
+	db *sqlx.DB
+}
+
+func NewTranslationJobsRepository(db *sqlx.DB) *TranslationJobsRepository {
+	return &TranslationJobsRepository{db: db}
+}
+
+const translationJobColumns = `
+	id, chapter_id, novel_id, source_lang, target_lang, status, provider,
+	paragraph_from, paragraph_to, overwrite,
+	attempts, max_attempts, error, locked_by, locked_until, available_at, finished_at,
+	created_at, updated_at
+`
+
+// EnqueueNovel queues every chapter of the novel that has no manual or automatic translation
+// into the target languages yet. Chapters already queued are left alone. Returns the number of new jobs.
+func (r *TranslationJobsRepository) EnqueueNovel(ctx context.Context, novelID uuid.UUID, sourceLang string, targetLangs []string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO translation_jobs (chapter_id, novel_id, source_lang, target_lang)
+		SELECT c.id, c.novel_id, $2, l.lang
+		FROM chapters c
+		CROSS JOIN unnest($3::text[]) AS l(lang)
+		WHERE c.novel_id = $1
+		  AND l.lang <> $2
+		  AND NOT EXISTS (
+		    SELECT 1 FROM chapter_contents cc
+		    WHERE cc.chapter_id = c.id AND cc.lang = l.lang AND cc.source IN ('manual', 'auto')
+		  )
+		ON CONFLICT (chapter_id, target_lang) DO NOTHING
+	`, novelID, sourceLang, pq.Array(targetLangs))
+	if err != nil {
+		return 0, fmt.Errorf("enqueue novel translation: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// EnqueueChapter (re)queues a single chapter translation, resetting a finished job.
+func (r *TranslationJobsRepository) EnqueueChapter(ctx context.Context, chapterID, novelID uuid.UUID, sourceLang, targetLang string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.GetContext(ctx, &id, `
+		INSERT INTO translation_jobs (chapter_id, novel_id, source_lang, target_lang)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chapter_id, target_lang) DO UPDATE SET
+			source_lang = EXCLUDED.source_lang,
+			status = 'queued',
+			paragraph_from = NULL,
+			paragraph_to = NULL,
+			overwrite = FALSE,
+			attempts = 0,
+			error = NULL,
+			locked_by = NULL,
+			locked_until = NULL,
+			available_at = NOW(),
+			finished_at = NULL,
+			updated_at = NOW()
+		RETURNING id
+	`, chapterID, novelID, sourceLang, targetLang)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("enqueue chapter translation: %w", err)
+	}
+	return id, nil
+}
+
+// EnqueueRetranslation (re)queues a chapter translation that may replace a manual one,
+// optionally limited to a paragraph range.
+func (r *TranslationJobsRepository) EnqueueRetranslation(ctx context.Context, chapterID, novelID uuid.UUID, sourceLang, targetLang string, paragraphFrom, paragraphTo *int) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.GetContext(ctx, &id, `
+		INSERT INTO translation_jobs (chapter_id, novel_id, source_lang, target_lang, paragraph_from, paragraph_to, overwrite)
+		VALUES ($1, $2, $3, $4, $5, $6, TRUE)
+		ON CONFLICT (chapter_id, target_lang) DO UPDATE SET
+			source_lang = EXCLUDED.source_lang,
+			status = 'queued',
+			paragraph_from = EXCLUDED.paragraph_from,
+			paragraph_to = EXCLUDED.paragraph_to,
+			overwrite = TRUE,
+			attempts = 0,
+			error = NULL,
+			locked_by = NULL,
+			locked_until = NULL,
+			available_at = NOW(),
+			finished_at = NULL,
+			updated_at = NOW()
+		RETURNING id
+	`, chapterID, novelID, sourceLang, targetLang, paragraphFrom, paragraphTo)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("enqueue chapter retranslation: %w", err)
+	}
+	return id, nil
+}
+
+// Claim locks the next available job for owner. Running jobs whose lock expired (worker died) are reclaimed.
+func (r *TranslationJobsRepository) Claim(ctx context.Context, owner string, lock time.Duration) (*models.TranslationJob, error) {
+	var job models.TranslationJob
+	err := r.db.GetContext(ctx, &job, `
+		UPDATE translation_jobs
+		SET status = 'running',
+		    attempts = attempts + 1,
+		    locked_by = $1,
+		    locked_until = NOW() + ($2 * INTERVAL '1 second'),
+		    updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM translation_jobs
+			WHERE (status = 'queued' AND available_at <= NOW())
+			   OR (status = 'running' AND locked_until <= NOW())
+			ORDER BY available_at, created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+translationJobColumns, owner, lock.Seconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim translation job: %w", err)
+	}
+	return &job, nil
+}
+
+// Complete marks a job done (or skipped) and records which provider produced it.
+func (r *TranslationJobsRepository) Complete(ctx context.Context, jobID uuid.UUID, status models.TranslationJobStatus, provider string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE translation_jobs
+		SET status = $2, provider = $3, error = NULL,
+		    locked_by = NULL, locked_until = NULL,
+		    finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, jobID, status, provider)
+	if err != nil {
+		return fmt.Errorf("complete translation job: %w", err)
+	}
+	return nil
+}
+
+// Fail records an error. The job is retried after backoff until max_attempts is reached.
+// Returns the resulting status (queued or failed).
+func (r *TranslationJobsRepository) Fail(ctx context.Context, jobID uuid.UUID, errMsg string, backoff time.Duration) (models.TranslationJobStatus, error) {
+	var status models.TranslationJobStatus
+	err := r.db.GetContext(ctx, &status, `
+		UPDATE translation_jobs
+		SET status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'queued' END,
+		    error = $2,
+		    locked_by = NULL, locked_until = NULL,
+		    available_at = NOW() + ($3 * INTERVAL '1 second'),
+		    finished_at = CASE WHEN attempts >= max_attempts THEN NOW() ELSE NULL END,
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING status
+	`, jobID, errMsg, backoff.Seconds())
+	if err != nil {
+		return "", fmt.Errorf("fail translation job: %w", err)
+	}
+	return status, nil
+}
+
+// Stats returns per-novel/per-language counters of the queue.
+func (r *TranslationJobsRepository) Stats(ctx context.Context, novelID *uuid.UUID) ([]models.TranslationJobStats, error) {
+	q := `
+		SELECT novel_id, target_lang,
+		       COUNT(*) FILTER (WHERE status = 'queued') AS queued,
+		       COUNT(*) FILTER (WHERE status = 'running') AS running,
+		       COUNT(*) FILTER (WHERE status = 'done') AS done,
+		       COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+		       COUNT(*) FILTER (WHERE status = 'skipped') AS skipped
+		FROM translation_jobs
+	`
+	args := []any{}
+	if novelID != nil {
+		q += ` WHERE novel_id = $1`
+		args = append(args, *novelID)
+	}
+	q += ` GROUP BY novel_id, target_lang ORDER BY novel_id, target_lang`
+	out := []models.TranslationJobStats{}
+	if err := r.db.SelectContext(ctx, &out, q, args...); err != nil {
+		return nil, fmt.Errorf("translation job stats: %w", err)
+	}
+	return out, nil
+}
+
+// ListFailed returns failed jobs, newest first.
+func (r *TranslationJobsRepository) ListFailed(ctx context.Context, limit int) ([]models.TranslationJob, error) {
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	out := []models.TranslationJob{}
+	if err := r.db.SelectContext(ctx, &out, `
+		SELECT `+translationJobColumns+`
+		FROM translation_jobs
+		WHERE status = 'failed'
+		ORDER BY updated_at DESC
+		LIMIT $1
+	`, limit); err != nil {
+		return nil, fmt.Errorf("list failed translation jobs: %w", err)
+	}
+	return out, nil
+}
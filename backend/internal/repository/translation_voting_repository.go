@@ -256,6 +256,20 @@ func (r *TranslationVotingRepository) GetTopTarget(ctx context.Context) (*models
 	return &t, nil
 }
 
+// ListTranslatingNovelIDs returns novels whose translation target is currently in `translating`.
+func (r *TranslationVotingRepository) ListTranslatingNovelIDs(ctx context.Context) ([]uuid.UUID, error) {
+	const q = `
+		SELECT novel_id
+		FROM translation_vote_targets
+		WHERE status = 'translating' AND novel_id IS NOT NULL
+	`
+	out := []uuid.UUID{}
+	if err := r.db.SelectContext(ctx, &out, q); err != nil {
+		return nil, fmt.Errorf("list translating novels: %w", err)
+	}
+	return out, nil
+}
+
 func (r *TranslationVotingRepository) UpdateTargetStatus(ctx context.Context, targetID uuid.UUID, status models.TranslationVoteTargetStatus) error {
 	const q = `
 		UPDATE translation_vote_targets
@@ -269,6 +283,24 @@ func (r *TranslationVotingRepository) UpdateTargetStatus(ctx context.Context, ta
 	return nil
 }
 
+// UpdateTargetStatusTx is UpdateTargetStatus within a transaction.
+func (r *TranslationVotingRepository) UpdateTargetStatusTx(ctx context.Context, tx *sqlx.Tx, targetID uuid.UUID, status models.TranslationVoteTargetStatus) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE translation_vote_targets
+		SET status = $2, updated_at = NOW()
+		WHERE id = $1
+	`, targetID, status)
+	if err != nil {
+		return fmt.Errorf("update target status: %w", err)
+	}
+	return nil
+}
+
+// BeginTx starts a new database transaction.
+func (r *TranslationVotingRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
+
 // BindProposalToNovel converts a proposal-based target into a novel-based target (preserving votes),
 // and optionally moves it from waiting_release -> translating.
 func (r *TranslationVotingRepository) BindProposalToNovel(ctx context.Context, proposalID uuid.UUID, novelID uuid.UUID) (*models.TranslationVoteTarget, error) {
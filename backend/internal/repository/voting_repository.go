@@ -31,9 +31,9 @@ func (r *VotingRepository) CreateProposal(ctx context.Context, proposal *models.
 		INSERT INTO novel_proposals (
 			id, user_id, original_link, status,
 			title, alt_titles, author, description, cover_url,
-			genres, tags, created_at, updated_at
+			genres, tags, created_at, updated_at, submitted_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 		)
 	`
 	
@@ -43,13 +43,19 @@ func (r *VotingRepository) CreateProposal(ctx context.Context, proposal *models.
 	now := time.Now()
 	proposal.CreatedAt = now
 	proposal.UpdatedAt = now
+	// Proposals created straight into moderation are submitted right away; drafts get
+	// submitted_at in SubmitProposalForModeration.
+	var submittedAt *time.Time
+	if proposal.Status != models.ProposalStatusDraft {
+		submittedAt = &now
+	}
 	
 	_, err := r.db.ExecContext(ctx, query,
 		proposal.ID, proposal.UserID, proposal.OriginalLink, proposal.Status,
 		proposal.Title, pq.Array(proposal.AltTitles), proposal.Author,
 		proposal.Description, proposal.CoverURL,
 		pq.Array(proposal.Genres), pq.Array(proposal.Tags),
-		proposal.CreatedAt, proposal.UpdatedAt,
+		proposal.CreatedAt, proposal.UpdatedAt, submittedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("create proposal: %w", err)
@@ -308,9 +314,31 @@ func (r *VotingRepository) UpdateProposalStatus(ctx context.Context, id uuid.UUI
 	return nil
 }
 
-// SubmitProposalForModeration changes status to moderation
+// UpdateProposalStatusTx updates proposal status within a transaction
+func (r *VotingRepository) UpdateProposalStatusTx(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status models.ProposalStatus) error {
+	_, err := tx.ExecContext(ctx, `UPDATE novel_proposals SET status = $2, updated_at = NOW() WHERE id = $1`, id, status)
+	if err != nil {
+		return fmt.Errorf("update proposal status: %w", err)
+	}
+	return nil
+}
+
+// SubmitProposalForModeration changes status to moderation and records the submission time
 func (r *VotingRepository) SubmitProposalForModeration(ctx context.Context, id uuid.UUID) error {
-	return r.UpdateProposalStatus(ctx, id, models.ProposalStatusModeration, nil, nil)
+	query := `
+		UPDATE novel_proposals SET
+			status = $2,
+			moderator_id = NULL,
+			reject_reason = NULL,
+			submitted_at = COALESCE(submitted_at, NOW()),
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, models.ProposalStatusModeration); err != nil {
+		return fmt.Errorf("submit proposal: %w", err)
+	}
+	return nil
 }
 
 // DeleteProposal deletes a proposal
@@ -417,6 +445,12 @@ func (r *VotingRepository) ClosePoll(ctx context.Context, pollID uuid.UUID) erro
 	return err
 }
 
+// ClosePollTx closes a poll within a transaction
+func (r *VotingRepository) ClosePollTx(ctx context.Context, tx *sqlx.Tx, pollID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE voting_polls SET status = 'closed' WHERE id = $1", pollID)
+	return err
+}
+
 // GetVotingLeaderboard returns the current voting leaderboard
 func (r *VotingRepository) GetVotingLeaderboard(ctx context.Context, limit int) ([]models.NovelProposal, error) {
 	proposals := []models.NovelProposal{}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"novels-backend/internal/config"
+	"novels-backend/internal/database"
+	"novels-backend/internal/domain/models"
+)
+
+// execDriver is a database/sql driver that records every Exec and succeeds.
+type execDriver struct {
+	queries []string
+	args    [][]driver.NamedValue
+}
+
+func (d *execDriver) Open(string) (driver.Conn, error) { return execConn{d}, nil }
+
+type execConn struct{ d *execDriver }
+
+func (c execConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+func (c execConn) Close() error { return nil }
+func (c execConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c execConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.queries = append(c.d.queries, query)
+	c.d.args = append(c.d.args, args)
+	return driver.RowsAffected(1), nil
+}
+
+func newExecDB(t *testing.T) (*sqlx.DB, *execDriver) {
+	t.Helper()
+	d := &execDriver{}
+	name := "exec-" + uuid.NewString()
+	sql.Register(name, d)
+	db, err := sqlx.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+var paramRe = regexp.MustCompile(`\$(\d+)`)
+
+// TestCreateProposalBindsEachParameterOnce guards against reusing the status parameter in an
+// expression: Postgres would deduce text there and proposal_status for the column and reject
+// the statement with "inconsistent types deduced for parameter".
+func TestCreateProposalBindsEachParameterOnce(t *testing.T) {
+	for _, status := range []models.ProposalStatus{models.ProposalStatusDraft, models.ProposalStatusModeration} {
+		t.Run(string(status), func(t *testing.T) {
+			db, d := newExecDB(t)
+			p := &models.NovelProposal{UserID: uuid.New(), OriginalLink: "https://example.com/n/1", Status: status, Title: "Novel"}
+			if err := NewVotingRepository(db).CreateProposal(context.Background(), p); err != nil {
+				t.Fatalf("CreateProposal: %v", err)
+			}
+			if len(d.queries) != 1 {
+				t.Fatalf("queries = %d, want 1", len(d.queries))
+			}
+			uses := map[string]int{}
+			for _, m := range paramRe.FindAllStringSubmatch(d.queries[0], -1) {
+				uses[m[1]]++
+			}
+			for n, c := range uses {
+				if c != 1 {
+					t.Errorf("$%s is used %d times", n, c)
+				}
+			}
+
+			args := d.args[0]
+			if len(args) != 14 || len(uses) != len(args) {
+				t.Fatalf("args = %d, placeholders = %d, want 14", len(args), len(uses))
+			}
+			submittedAt := args[13].Value
+			if status == models.ProposalStatusDraft && submittedAt != nil {
+				t.Errorf("draft submitted_at = %v, want NULL", submittedAt)
+			}
+			if status != models.ProposalStatusDraft && submittedAt == nil {
+				t.Error("submitted_at is NULL for a proposal sent to moderation")
+			}
+		})
+	}
+}
+
+// TestCreateProposalNonDraft runs against a real database: set TEST_DATABASE_URL to a
+// disposable PostgreSQL database (migrations are applied to it).
+func TestCreateProposalNonDraft(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	db, err := database.Connect(config.DatabaseConfig{URL: url, MaxOpenConns: 5, MaxIdleConns: 5, ConnMaxLifetime: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := database.RunMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var userID uuid.UUID
+	if err := db.GetContext(ctx, &userID, `
+		INSERT INTO users (email, password_hash) VALUES ($1, 'x') RETURNING id
+	`, "proposal-"+uuid.NewString()+"@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	defer db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID)
+
+	repo := NewVotingRepository(db)
+	for _, status := range []models.ProposalStatus{models.ProposalStatusModeration, models.ProposalStatusDraft} {
+		p := &models.NovelProposal{UserID: userID, OriginalLink: "https://example.com/n/1", Status: status, Title: "Novel"}
+		if err := repo.CreateProposal(ctx, p); err != nil {
+			t.Fatalf("CreateProposal(%s): %v", status, err)
+		}
+		var submittedAt sql.NullTime
+		var stored string
+		if err := db.QueryRowxContext(ctx, `SELECT status, submitted_at FROM novel_proposals WHERE id = $1`, p.ID).
+			Scan(&stored, &submittedAt); err != nil {
+			t.Fatal(err)
+		}
+		if stored != string(status) {
+			t.Errorf("status = %s, want %s", stored, status)
+		}
+		if submittedAt.Valid != (status != models.ProposalStatusDraft) {
+			t.Errorf("%s: submitted_at = %v", status, submittedAt)
+		}
+	}
+}
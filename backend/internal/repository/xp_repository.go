@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"novels-backend/internal/domain/models"
 )
 
@@ -73,13 +76,15 @@ func (r *XPRepository) CreateOrUpdateXP(ctx context.Context, userID uuid.UUID, d
 	return &xp, nil
 }
 
-// AddXPEvent records an XP event
-func (r *XPRepository) AddXPEvent(ctx context.Context, event *models.XPEvent) error {
+// AddXPEvent records an XP event. It returns false if a unique index already has the event
+// (read_chapter is awarded once per user and chapter).
+func (r *XPRepository) AddXPEvent(ctx context.Context, event *models.XPEvent) (bool, error) {
 	query := `
 		INSERT INTO xp_events (id, user_id, type, delta, ref_type, ref_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW())`
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT DO NOTHING`
 	
-	_, err := r.db.ExecContext(ctx, query,
+	res, err := r.db.ExecContext(ctx, query,
 		event.ID,
 		event.UserID,
 		event.Type,
@@ -87,14 +92,18 @@ func (r *XPRepository) AddXPEvent(ctx context.Context, event *models.XPEvent) er
 		event.RefType,
 		event.RefID,
 	)
-	return err
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
 }
 
 // GetXPEvents retrieves XP events for a user
 func (r *XPRepository) GetXPEvents(ctx context.Context, userID uuid.UUID, limit, offset int) ([]models.XPEvent, error) {
 	var events []models.XPEvent
 	query := `
-		SELECT id, user_id, type, delta, ref_type, ref_id, created_at
+		SELECT id, user_id, type, delta, ref_type, ref_id, created_at, revoked_at, revoke_reason
 		FROM xp_events
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -104,6 +113,57 @@ func (r *XPRepository) GetXPEvents(ctx context.Context, userID uuid.UUID, limit,
 	return events, err
 }
 
+// RevokeEvents takes back the XP of the user's events in ids: each event is marked revoked and
+// a clawback event with the negative delta is recorded. Events already revoked, clawbacks and
+// events of other users are skipped. Returns the revoked events and the updated XP.
+func (r *XPRepository) RevokeEvents(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, revokedBy uuid.UUID, reason string) ([]models.XPEvent, *models.UserXP, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	revoked := []models.XPEvent{}
+	if err := tx.SelectContext(ctx, &revoked, `
+		UPDATE xp_events SET revoked_at = NOW(), revoked_by = $3, revoke_reason = $4
+		WHERE user_id = $1 AND id = ANY($2::uuid[]) AND revoked_at IS NULL AND delta > 0
+		RETURNING id, user_id, type, delta, ref_type, ref_id, created_at, revoked_at, revoke_reason
+	`, userID, pq.Array(ids), revokedBy, reason); err != nil {
+		return nil, nil, fmt.Errorf("revoke xp events: %w", err)
+	}
+
+	var taken int64
+	for _, e := range revoked {
+		taken += e.Delta
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO xp_events (id, user_id, type, delta, ref_type, ref_id, created_at)
+			VALUES ($1, $2, $3, $4, 'xp_event', $5, NOW())
+		`, uuid.New(), userID, models.XPEventClawback, -e.Delta, e.ID); err != nil {
+			return nil, nil, fmt.Errorf("record clawback: %w", err)
+		}
+	}
+
+	var xp models.UserXP
+	err = tx.GetContext(ctx, &xp, `
+		UPDATE user_xp SET xp_total = GREATEST(xp_total - $2, 0), updated_at = NOW()
+		WHERE user_id = $1
+		RETURNING user_id, xp_total, level, updated_at
+	`, userID, taken)
+	if errors.Is(err, sql.ErrNoRows) {
+		xp = models.UserXP{UserID: userID, Level: 1}
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("update xp total: %w", err)
+	}
+	if level := models.CalculateLevel(xp.XPTotal); level != xp.Level {
+		if _, err := tx.ExecContext(ctx, `UPDATE user_xp SET level = $2 WHERE user_id = $1`, userID, level); err != nil {
+			return nil, nil, fmt.Errorf("update level: %w", err)
+		}
+		xp.Level = level
+	}
+
+	return revoked, &xp, tx.Commit()
+}
+
 // CheckEventExists checks if an XP event already exists (for idempotency)
 func (r *XPRepository) CheckEventExists(ctx context.Context, userID uuid.UUID, eventType models.XPEventType, refType string, refID uuid.UUID) (bool, error) {
 	var exists bool
@@ -179,9 +239,9 @@ func (r *XPRepository) GetUserRank(ctx context.Context, userID uuid.UUID) (int,
 func (r *XPRepository) GetUserStats(ctx context.Context, userID uuid.UUID) (*models.UserStats, error) {
 	stats := &models.UserStats{UserID: userID}
 	
-	// Count chapters read
-	query := `SELECT COUNT(*) FROM reading_progress WHERE user_id = $1`
-	_ = r.db.GetContext(ctx, &stats.ChaptersRead, query, userID)
+	// Verified chapter reads and reading time (see ReadingStatsRepository)
+	query := `SELECT COALESCE(SUM(chapters), 0), COALESCE(SUM(seconds), 0) FROM user_reading_days WHERE user_id = $1`
+	_ = r.db.QueryRowxContext(ctx, query, userID).Scan(&stats.ChaptersRead, &stats.ReadingTime)
 	
 	// Count comments
 	query = `SELECT COUNT(*) FROM comments WHERE user_id = $1 AND is_deleted = false`
@@ -193,70 +253,3 @@ func (r *XPRepository) GetUserStats(ctx context.Context, userID uuid.UUID) (*mod
 	
 	return stats, nil
 }
-
-// GetAchievements retrieves all achievements
-func (r *XPRepository) GetAchievements(ctx context.Context) ([]models.Achievement, error) {
-	var achievements []models.Achievement
-	query := `SELECT id, code, title, description, icon_key, condition, xp_reward, created_at FROM achievements ORDER BY created_at`
-	err := r.db.SelectContext(ctx, &achievements, query)
-	return achievements, err
-}
-
-// GetUserAchievements retrieves user's unlocked achievements
-func (r *XPRepository) GetUserAchievements(ctx context.Context, userID uuid.UUID) ([]models.UserAchievement, error) {
-	var userAchievements []models.UserAchievement
-	query := `
-		SELECT 
-			ua.user_id, ua.achievement_id, ua.unlocked_at,
-			a.id as "achievement.id",
-			a.code as "achievement.code",
-			a.title as "achievement.title",
-			a.description as "achievement.description",
-			a.icon_key as "achievement.icon_key"
-		FROM user_achievements ua
-		JOIN achievements a ON ua.achievement_id = a.id
-		WHERE ua.user_id = $1
-		ORDER BY ua.unlocked_at DESC`
-	
-	rows, err := r.db.QueryxContext(ctx, query, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	
-	for rows.Next() {
-		var ua models.UserAchievement
-		var achievement models.Achievement
-		err := rows.Scan(
-			&ua.UserID, &ua.AchievementID, &ua.UnlockedAt,
-			&achievement.ID, &achievement.Code, &achievement.Title,
-			&achievement.Description, &achievement.IconKey,
-		)
-		if err != nil {
-			return nil, err
-		}
-		ua.Achievement = &achievement
-		userAchievements = append(userAchievements, ua)
-	}
-	
-	return userAchievements, nil
-}
-
-// UnlockAchievement unlocks an achievement for a user
-func (r *XPRepository) UnlockAchievement(ctx context.Context, userID, achievementID uuid.UUID) error {
-	query := `
-		INSERT INTO user_achievements (user_id, achievement_id, unlocked_at)
-		VALUES ($1, $2, NOW())
-		ON CONFLICT (user_id, achievement_id) DO NOTHING`
-	
-	_, err := r.db.ExecContext(ctx, query, userID, achievementID)
-	return err
-}
-
-// HasAchievement checks if user has an achievement
-func (r *XPRepository) HasAchievement(ctx context.Context, userID, achievementID uuid.UUID) (bool, error) {
-	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM user_achievements WHERE user_id = $1 AND achievement_id = $2)`
-	err := r.db.GetContext(ctx, &exists, query, userID, achievementID)
-	return exists, err
-}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/achievements"
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
+	"novels-backend/internal/repository"
+)
+
+var (
+	ErrAchievementNotFound  = errors.New("achievement not found")
+	ErrInvalidAchievement   = errors.New("invalid achievement")
+	ErrAchievementCodeTaken = errors.New("achievement code already exists")
+)
+
+// AchievementService unlocks achievements by their declarative conditions (package achievements).
+//
+// Conditions are evaluated incrementally: an XP event (or a comment like, or a won vote) only
+// re-evaluates the user's locked achievements whose metrics it can change, and each metric is
+// computed once per evaluation. New achievements and changed conditions are evaluated for all
+// users in batches by BackfillPending.
+type AchievementService struct {
+	repo   *repository.AchievementRepository
+	xp     *XPService
+	logger zerolog.Logger
+}
+
+func NewAchievementService(repo *repository.AchievementRepository, xp *XPService, logger zerolog.Logger) *AchievementService {
+	return &AchievementService{
+		repo:   repo,
+		xp:     xp,
+		logger: logger.With().Str("service", "achievements").Logger(),
+	}
+}
+
+// Register evaluates achievements on XP events, comment likes and daily vote winners.
+func (s *AchievementService) Register(bus *events.Bus) {
+	if bus == nil {
+		return
+	}
+	bus.Subscribe(events.EventXPAwarded, "achievements.evaluate_xp", func(ctx context.Context, evt events.Event) error {
+		e := evt.(events.XPAwarded)
+		return s.Evaluate(ctx, e.UserID, achievements.ForXPEvent(models.XPEventType(e.Type)))
+	}, events.WithRetry(3, time.Second))
+	bus.Subscribe(events.EventCommentLiked, "achievements.evaluate_comment_liked", func(ctx context.Context, evt events.Event) error {
+		e := evt.(events.CommentLiked)
+		return s.Evaluate(ctx, e.AuthorID, achievements.TriggerCommentLiked)
+	}, events.WithRetry(3, time.Second))
+	bus.Subscribe(events.EventDailyVoteWinnerSelected, "achievements.evaluate_voters", func(ctx context.Context, evt events.Event) error {
+		e := evt.(events.DailyVoteWinnerSelected)
+		return s.evaluateVoters(ctx, e.ProposalID)
+	}, events.WithRetry(3, time.Second))
+}
+
+// rule is an achievement with its parsed condition.
+type rule struct {
+	achievement models.Achievement
+	condition   *achievements.Condition
+}
+
+// rules parses the conditions of list; achievements with invalid conditions are logged and skipped.
+func (s *AchievementService) rules(list []models.Achievement, trigger achievements.Trigger) []rule {
+	out := make([]rule, 0, len(list))
+	for _, a := range list {
+		cond, err := achievements.Parse(a.Condition)
+		if errors.Is(err, achievements.ErrEmptyCondition) {
+			continue
+		}
+		if err != nil {
+			s.logger.Warn().Err(err).Str("achievement", a.Code).Msg("Skipping achievement with invalid condition")
+			continue
+		}
+		if trigger != "" && !cond.TriggeredBy(trigger) {
+			continue
+		}
+		out = append(out, rule{achievement: a, condition: cond})
+	}
+	return out
+}
+
+// Evaluate re-evaluates the user's locked achievements that trigger can affect.
+func (s *AchievementService) Evaluate(ctx context.Context, userID uuid.UUID, trigger achievements.Trigger) error {
+	if !achievements.Triggered(trigger) {
+		return nil
+	}
+	locked, err := s.repo.Locked(ctx, userID)
+	if err != nil {
+		return err
+	}
+	_, err = s.evaluate(ctx, []uuid.UUID{userID}, s.rules(locked, trigger))
+	return err
+}
+
+func (s *AchievementService) evaluateVoters(ctx context.Context, proposalID uuid.UUID) error {
+	voters, err := s.repo.ProposalVoters(ctx, proposalID)
+	if err != nil {
+		return err
+	}
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	rules := s.rules(list, achievements.TriggerProposalWon)
+	for start := 0; start < len(voters); start += backfillBatchSize {
+		end := min(start+backfillBatchSize, len(voters))
+		if _, err := s.evaluate(ctx, voters[start:end], rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluate computes the metrics rules need for users, saves their progress and unlocks (and
+// rewards) the achievements they now meet. Returns the number of unlocks.
+func (s *AchievementService) evaluate(ctx context.Context, userIDs []uuid.UUID, rules []rule) (int, error) {
+	if len(rules) == 0 || len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	values := make(map[uuid.UUID]achievements.Values, len(userIDs))
+	for _, id := range userIDs {
+		values[id] = achievements.Values{}
+	}
+	computed := map[achievements.Ref]bool{}
+	for _, r := range rules {
+		for _, ref := range r.condition.Refs() {
+			if computed[ref] {
+				continue
+			}
+			computed[ref] = true
+			byUser, err := s.repo.MetricValues(ctx, ref, userIDs)
+			if err != nil {
+				return 0, err
+			}
+			for id, v := range byUser {
+				values[id][ref] = v
+			}
+		}
+	}
+
+	var progress []models.AchievementProgress
+	type unlock struct {
+		userID uuid.UUID
+		rule   rule
+	}
+	var unlocks []unlock
+	for _, id := range userIDs {
+		for _, r := range rules {
+			p := r.condition.Evaluate(values[id])
+			switch {
+			case p.Done:
+				unlocks = append(unlocks, unlock{userID: id, rule: r})
+			case p.Current > 0:
+				progress = append(progress, models.AchievementProgress{
+					UserID: id, AchievementID: r.achievement.ID, Current: p.Current, Target: p.Target,
+				})
+			}
+		}
+	}
+	if err := s.repo.SaveProgress(ctx, progress); err != nil {
+		return 0, err
+	}
+
+	unlocked := 0
+	for _, u := range unlocks {
+		ok, err := s.repo.Unlock(ctx, u.userID, u.rule.achievement.ID)
+		if err != nil {
+			return unlocked, err
+		}
+		if !ok {
+			continue
+		}
+		unlocked++
+		s.logger.Info().
+			Str("user_id", u.userID.String()).
+			Str("achievement", u.rule.achievement.Code).
+			Msg("Achievement unlocked")
+		if u.rule.achievement.XPReward > 0 && s.xp != nil {
+			if err := s.xp.AwardXP(ctx, u.userID, models.XPEventAchievement, u.rule.achievement.XPReward, "achievement", u.rule.achievement.ID); err != nil {
+				return unlocked, fmt.Errorf("award achievement xp: %w", err)
+			}
+		}
+	}
+	return unlocked, nil
+}
+
+// backfillBatchSize is the number of users evaluated per query by the backfill.
+const backfillBatchSize = 500
+
+// BackfillPending evaluates new and changed achievements for all users, so users who already
+// meet a condition get the achievement without doing anything again. Only one instance
+// backfills at a time; the others skip the run. Returns the number of unlocks.
+func (s *AchievementService) BackfillPending(ctx context.Context) (int, error) {
+	total := 0
+	_, err := s.repo.WithBackfillLock(ctx, func(ctx context.Context) error {
+		var err error
+		total, err = s.backfillPending(ctx)
+		return err
+	})
+	return total, err
+}
+
+func (s *AchievementService) backfillPending(ctx context.Context) (int, error) {
+	pending, err := s.repo.PendingBackfill(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, a := range pending {
+		rules := s.rules([]models.Achievement{a}, "")
+		if len(rules) == 0 {
+			// Invalid conditions are fixed by editing them, which schedules the backfill again.
+			if err := s.repo.MarkBackfilled(ctx, a.ID, a.Condition); err != nil {
+				return total, err
+			}
+			continue
+		}
+
+		unlocked := 0
+		after := uuid.Nil
+		for {
+			if err := ctx.Err(); err != nil {
+				return total, err
+			}
+			users, err := s.repo.UserIDsAfter(ctx, after, backfillBatchSize)
+			if err != nil {
+				return total, err
+			}
+			if len(users) == 0 {
+				break
+			}
+			n, err := s.evaluate(ctx, users, rules)
+			unlocked += n
+			total += n
+			if err != nil {
+				return total, fmt.Errorf("backfill %s: %w", a.Code, err)
+			}
+			after = users[len(users)-1]
+		}
+		if err := s.repo.MarkBackfilled(ctx, a.ID, a.Condition); err != nil {
+			return total, err
+		}
+		s.logger.Info().Str("achievement", a.Code).Int("unlocked", unlocked).Msg("Achievement backfilled")
+	}
+	return total, nil
+}
+
+// List returns all achievements.
+func (s *AchievementService) List(ctx context.Context) ([]models.Achievement, error) {
+	return s.repo.List(ctx)
+}
+
+// ListForUser returns all achievements with the user's unlock times and progress.
+func (s *AchievementService) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.AchievementStatus, error) {
+	return s.repo.ListForUser(ctx, userID)
+}
+
+// Metrics returns the metrics conditions can use.
+func (s *AchievementService) Metrics() []achievements.Metric {
+	return achievements.Metrics()
+}
+
+// Create adds an achievement; existing users get it by the backfill.
+func (s *AchievementService) Create(ctx context.Context, req models.SaveAchievementRequest) (*models.Achievement, error) {
+	a, err := newAchievement(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		if errors.Is(err, repository.ErrAchievementCodeExists) {
+			return nil, ErrAchievementCodeTaken
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// Update changes an achievement; a changed condition is backfilled again. Achievements already
+// unlocked stay unlocked.
+func (s *AchievementService) Update(ctx context.Context, id uuid.UUID, req models.SaveAchievementRequest) (*models.Achievement, error) {
+	a, err := newAchievement(req)
+	if err != nil {
+		return nil, err
+	}
+	a.ID = id
+	if err := s.repo.Update(ctx, a); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrAchievementNotFound
+		case errors.Is(err, repository.ErrAchievementCodeExists):
+			return nil, ErrAchievementCodeTaken
+		}
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func newAchievement(req models.SaveAchievementRequest) (*models.Achievement, error) {
+	a := &models.Achievement{
+		Code:        strings.TrimSpace(req.Code),
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		IconKey:     strings.TrimSpace(req.IconKey),
+		XPReward:    req.XPReward,
+	}
+	switch {
+	case a.Code == "" || len(a.Code) > 50:
+		return nil, fmt.Errorf("%w: code must be 1-50 characters", ErrInvalidAchievement)
+	case a.Title == "" || len([]rune(a.Title)) > 100:
+		return nil, fmt.Errorf("%w: title must be 1-100 characters", ErrInvalidAchievement)
+	case a.XPReward < 0:
+		return nil, fmt.Errorf("%w: xpReward must not be negative", ErrInvalidAchievement)
+	}
+	cond, err := achievements.Parse(req.Condition)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAchievement, err)
+	}
+	normalized, err := json.Marshal(cond)
+	if err != nil {
+		return nil, err
+	}
+	a.Condition = normalized
+	return a, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"novels-backend/internal/domain/models"
@@ -20,13 +21,23 @@ type AdminRepository interface {
 	GetStats(ctx context.Context) (*models.AdminStatsOverview, error)
 }
 
+// ErrInvalidSetting значение настройки не прошло проверку
+var ErrInvalidSetting = errors.New("invalid setting value")
+
 // AdminService сервис для системных админ-функций
 type AdminService struct {
-	adminRepo AdminRepository
+	adminRepo  AdminRepository
+	validators map[string]func(json.RawMessage) error
 }
 
 func NewAdminService(adminRepo AdminRepository) *AdminService {
-	return &AdminService{adminRepo: adminRepo}
+	return &AdminService{adminRepo: adminRepo, validators: map[string]func(json.RawMessage) error{}}
+}
+
+// ValidateSetting задает проверку значения настройки key перед сохранением
+// (для настроек, которые читает код, например rate_limits)
+func (s *AdminService) ValidateSetting(key string, validate func(json.RawMessage) error) {
+	s.validators[key] = validate
 }
 
 // GetSettings получает все настройки
@@ -53,6 +64,11 @@ func (s *AdminService) UpdateSetting(ctx context.Context, key string, value json
 	if err != nil {
 		return fmt.Errorf("failed to check setting: %w", err)
 	}
+	if validate, ok := s.validators[key]; ok {
+		if err := validate(value); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSetting, err)
+		}
+	}
 
 	return s.adminRepo.UpdateSetting(ctx, key, value, updatedBy)
 }
@@ -29,13 +29,16 @@ var (
 // AuthService сервис аутентификации
 type AuthService struct {
 	userRepo *repository.UserRepository
+	streaks  *StreakService
 	cfg      *config.Config
 }
 
-// NewAuthService создает новый AuthService
-func NewAuthService(userRepo *repository.UserRepository, cfg *config.Config) *AuthService {
+// NewAuthService создает новый AuthService; вход и обновление токена засчитываются в серию
+// ежедневной активности (streaks может быть nil)
+func NewAuthService(userRepo *repository.UserRepository, streaks *StreakService, cfg *config.Config) *AuthService {
 	return &AuthService{
 		userRepo: userRepo,
+		streaks:  streaks,
 		cfg:      cfg,
 	}
 }
@@ -84,6 +87,7 @@ func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get created user: %w", err)
 	}
+	s.recordLogin(ctx, user.ID)
 
 	// Генерируем токены
 	return s.generateTokens(ctx, userWithProfile)
@@ -110,6 +114,7 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 
 	// Обновляем время последнего входа
 	_ = s.userRepo.UpdateLastLogin(ctx, user.ID)
+	s.recordLogin(ctx, user.ID)
 
 	// Генерируем токены
 	return s.generateTokens(ctx, user)
@@ -163,10 +168,20 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*m
 	// Отзываем старый refresh token
 	_ = s.userRepo.RevokeRefreshToken(ctx, tokenHash)
 
+	// Клиент обновляет токен хотя бы раз в JWT_ACCESS_TTL, поэтому это тоже заход на сайт
+	s.recordLogin(ctx, user.ID)
+
 	// Генерируем новые токены
 	return s.generateTokens(ctx, user)
 }
 
+// recordLogin засчитывает день в серию активности и начисляет XP за ежедневный вход
+func (s *AuthService) recordLogin(ctx context.Context, userID uuid.UUID) {
+	if s.streaks != nil {
+		s.streaks.RecordActivity(ctx, userID, models.ActivityLogin)
+	}
+}
+
 // Logout отзывает токены пользователя
 func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	tokenHash := hashToken(refreshToken)
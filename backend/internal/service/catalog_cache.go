@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/cache"
+	"novels-backend/internal/events"
+)
+
+// CatalogCache caches the hot catalog reads (popular, trending, top rated, latest updates,
+// genre and tag lists, platform stats) and drops them when the catalog changes.
+//
+// Changes are announced as CatalogChanged events, so every instance sharing the backend sees
+// them; lists are also bounded by their TTL, which covers changes nobody announces
+// (views, ratings, chapters going live on their release time). A nil *CatalogCache caches nothing.
+type CatalogCache struct {
+	lists    *cache.Namespace
+	taxonomy *cache.Namespace
+	stats    *cache.Namespace
+	bus      *events.Bus
+	logger   zerolog.Logger
+}
+
+func NewCatalogCache(store *cache.Store, bus *events.Bus, listTTL, statsTTL time.Duration, logger zerolog.Logger) *CatalogCache {
+	return &CatalogCache{
+		lists:    store.Namespace("catalog_lists", listTTL),
+		taxonomy: store.Namespace("catalog_taxonomy", listTTL),
+		stats:    store.Namespace("platform_stats", statsTTL),
+		bus:      bus,
+		logger:   logger.With().Str("service", "catalog_cache").Logger(),
+	}
+}
+
+// Register invalidates the cache on catalog changes and on novels released from proposals.
+func (c *CatalogCache) Register(bus *events.Bus) {
+	if c == nil || bus == nil {
+		return
+	}
+	bus.Subscribe(events.EventCatalogChanged, "catalog_cache.invalidate", func(ctx context.Context, evt events.Event) error {
+		e := evt.(events.CatalogChanged)
+		return c.invalidate(ctx, e.Reason)
+	}, events.WithRetry(3, time.Second))
+	bus.Subscribe(events.EventProposalReleased, "catalog_cache.invalidate_released", func(ctx context.Context, _ events.Event) error {
+		return c.invalidate(ctx, events.CatalogReasonNovel)
+	}, events.WithRetry(3, time.Second))
+}
+
+func (c *CatalogCache) invalidate(ctx context.Context, reason string) error {
+	errs := []error{c.lists.Invalidate(ctx), c.stats.Invalidate(ctx)}
+	if reason == events.CatalogReasonTaxonomy || reason == events.CatalogReasonNovel {
+		// Genre and tag lists only show names, but novel edits may add or remove links.
+		errs = append(errs, c.taxonomy.Invalidate(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Changed announces a catalog change. Errors are logged: the change itself has already
+// been saved, and the cached lists expire with their TTL anyway.
+func (c *CatalogCache) Changed(ctx context.Context, novelID *uuid.UUID, reason string) {
+	if c == nil {
+		return
+	}
+	evt := events.CatalogChanged{NovelID: novelID, Reason: reason}
+	var err error
+	if c.bus != nil {
+		err = c.bus.Publish(ctx, evt)
+	} else {
+		err = c.invalidate(ctx, reason)
+	}
+	if err != nil {
+		c.logger.Error().Err(err).Str("reason", reason).Msg("Failed to announce catalog change")
+	}
+}
+
+func (c *CatalogCache) listsNS() *cache.Namespace {
+	if c == nil {
+		return nil
+	}
+	return c.lists
+}
+
+func (c *CatalogCache) taxonomyNS() *cache.Namespace {
+	if c == nil {
+		return nil
+	}
+	return c.taxonomy
+}
+
+func (c *CatalogCache) statsNS() *cache.Namespace {
+	if c == nil {
+		return nil
+	}
+	return c.stats
+}
+
+// catalogKey builds the cache key of a catalog list.
+func catalogKey(list, lang string, limit int) string {
+	return fmt.Sprintf("%s:%s:%d", list, lang, limit)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/cache"
+	"novels-backend/internal/events"
+)
+
+// catalogLoads counts how often each cached catalog read hits its loader.
+type catalogLoads map[string]int
+
+func (l catalogLoads) load(t *testing.T, ns *cache.Namespace, name string) {
+	t.Helper()
+	if _, err := cache.Load(context.Background(), ns, name, func(context.Context) (int, error) {
+		l[name]++
+		return l[name], nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (l catalogLoads) loadAll(t *testing.T, c *CatalogCache) {
+	t.Helper()
+	l.load(t, c.listsNS(), "lists")
+	l.load(t, c.taxonomyNS(), "taxonomy")
+	l.load(t, c.statsNS(), "stats")
+}
+
+func newTestCatalogCache(bus *events.Bus) *CatalogCache {
+	store := cache.New(cache.NewMemory(100), "test", zerolog.Nop())
+	c := NewCatalogCache(store, bus, time.Minute, time.Minute, zerolog.Nop())
+	c.Register(bus)
+	return c
+}
+
+func TestCatalogCacheInvalidatesOnCatalogChanged(t *testing.T) {
+	tests := []struct {
+		reason       string
+		wantTaxonomy int
+	}{
+		{events.CatalogReasonChapter, 1},
+		{events.CatalogReasonWiki, 1},
+		{events.CatalogReasonNovel, 2},
+		{events.CatalogReasonTaxonomy, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			bus := events.NewBus(zerolog.Nop())
+			c := newTestCatalogCache(bus)
+			loads := catalogLoads{}
+			loads.loadAll(t, c)
+
+			if err := bus.Publish(context.Background(), events.CatalogChanged{Reason: tt.reason}); err != nil {
+				t.Fatalf("Publish: %v", err)
+			}
+			loads.loadAll(t, c)
+
+			if loads["lists"] != 2 || loads["stats"] != 2 {
+				t.Errorf("lists/stats loads = %d/%d, want both reloaded", loads["lists"], loads["stats"])
+			}
+			if loads["taxonomy"] != tt.wantTaxonomy {
+				t.Errorf("taxonomy loads = %d, want %d", loads["taxonomy"], tt.wantTaxonomy)
+			}
+		})
+	}
+}
+
+func TestCatalogCacheInvalidatesOnProposalReleased(t *testing.T) {
+	bus := events.NewBus(zerolog.Nop())
+	c := newTestCatalogCache(bus)
+	loads := catalogLoads{}
+	loads.loadAll(t, c)
+
+	if err := bus.Publish(context.Background(), events.ProposalReleased{ProposalID: uuid.New(), NovelID: uuid.New()}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	loads.loadAll(t, c)
+
+	for _, name := range []string{"lists", "taxonomy", "stats"} {
+		if loads[name] != 2 {
+			t.Errorf("%s loads = %d, want 2", name, loads[name])
+		}
+	}
+}
+
+func TestCatalogCacheChangedPublishesEvent(t *testing.T) {
+	bus := events.NewBus(zerolog.Nop())
+	c := newTestCatalogCache(bus)
+	var got []events.CatalogChanged
+	bus.Subscribe(events.EventCatalogChanged, "test.recorder", func(_ context.Context, evt events.Event) error {
+		got = append(got, evt.(events.CatalogChanged))
+		return nil
+	})
+	loads := catalogLoads{}
+	loads.loadAll(t, c)
+
+	novelID := uuid.New()
+	c.Changed(context.Background(), &novelID, events.CatalogReasonNovel)
+	loads.loadAll(t, c)
+
+	if len(got) != 1 || got[0].NovelID == nil || *got[0].NovelID != novelID || got[0].Reason != events.CatalogReasonNovel {
+		t.Errorf("published %+v", got)
+	}
+	if loads["lists"] != 2 {
+		t.Errorf("lists loads = %d, want 2", loads["lists"])
+	}
+}
+
+func TestCatalogCacheWithoutBusInvalidatesDirectly(t *testing.T) {
+	c := newTestCatalogCache(nil)
+	loads := catalogLoads{}
+	loads.loadAll(t, c)
+
+	c.Changed(context.Background(), nil, events.CatalogReasonChapter)
+	loads.loadAll(t, c)
+
+	if loads["lists"] != 2 || loads["taxonomy"] != 1 {
+		t.Errorf("loads = %v, want lists reloaded and taxonomy kept", loads)
+	}
+}
+
+func TestNilCatalogCache(t *testing.T) {
+	var c *CatalogCache
+	c.Changed(context.Background(), nil, events.CatalogReasonNovel)
+	c.Register(events.NewBus(zerolog.Nop()))
+
+	loads := catalogLoads{}
+	loads.loadAll(t, c)
+	loads.loadAll(t, c)
+	if loads["lists"] != 2 {
+		t.Errorf("nil cache cached a value: %v", loads)
+	}
+}
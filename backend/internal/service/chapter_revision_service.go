@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrRevisionMismatch = errors.New("revisions belong to different languages")
+
+// maxDiffCells bounds the LCS table of a paragraph diff; larger changes are reported
+// as a full replacement of the differing middle part.
+const maxDiffCells = 4_000_000
+
+// ChapterRevisionService exposes the history of chapter texts
+type ChapterRevisionService struct {
+	revisionsRepo *repository.ChapterRevisionsRepository
+	chapterRepo   *repository.ChapterRepository
+}
+
+// NewChapterRevisionService creates a new chapter revision service
+func NewChapterRevisionService(revisionsRepo *repository.ChapterRevisionsRepository, chapterRepo *repository.ChapterRepository) *ChapterRevisionService {
+	return &ChapterRevisionService{
+		revisionsRepo: revisionsRepo,
+		chapterRepo:   chapterRepo,
+	}
+}
+
+// List returns revisions of a chapter, newest first, optionally for one language.
+func (s *ChapterRevisionService) List(ctx context.Context, chapterID uuid.UUID, lang string, limit int) ([]models.ChapterContentRevision, error) {
+	if _, err := s.chapterRepo.GetNovelIDByChapter(ctx, chapterID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrChapterNotFound
+		}
+		return nil, err
+	}
+	return s.revisionsRepo.List(ctx, chapterID, lang, limit)
+}
+
+// Get returns a revision with its text.
+func (s *ChapterRevisionService) Get(ctx context.Context, chapterID, revisionID uuid.UUID) (*models.ChapterContentRevision, error) {
+	rev, err := s.revisionsRepo.GetByID(ctx, chapterID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, ErrNotFound
+	}
+	return rev, nil
+}
+
+// Diff compares two revisions of the same chapter text paragraph by paragraph.
+func (s *ChapterRevisionService) Diff(ctx context.Context, chapterID, fromID, toID uuid.UUID) (*models.ChapterRevisionDiff, error) {
+	from, err := s.Get(ctx, chapterID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.Get(ctx, chapterID, toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.Lang != to.Lang {
+		return nil, ErrRevisionMismatch
+	}
+
+	diff := &models.ChapterRevisionDiff{
+		From:       from.ID,
+		To:         to.ID,
+		Paragraphs: diffParagraphs(splitParagraphs(from.Content), splitParagraphs(to.Content)),
+	}
+	for _, p := range diff.Paragraphs {
+		switch p.Op {
+		case models.ParagraphInsert:
+			diff.Added++
+		case models.ParagraphDelete:
+			diff.Removed++
+		default:
+			diff.Unchanged++
+		}
+	}
+	return diff, nil
+}
+
+// Rollback restores the text of a revision. The restore is recorded as a new revision.
+func (s *ChapterRevisionService) Rollback(ctx context.Context, chapterID, revisionID uuid.UUID, authorID *uuid.UUID) error {
+	rev, err := s.Get(ctx, chapterID, revisionID)
+	if err != nil {
+		return err
+	}
+	if err := s.revisionsRepo.Rollback(ctx, rev, authorID); err != nil {
+		return fmt.Errorf("failed to rollback chapter content: %w", err)
+	}
+	return nil
+}
+
+// splitParagraphs returns the non-blank lines of a chapter text, trimmed.
+func splitParagraphs(text string) []string {
+	out := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// diffParagraphs computes a minimal paragraph diff (longest common subsequence).
+func diffParagraphs(a, b []string) []models.ParagraphDiff {
+	out := []models.ParagraphDiff{}
+	equal := func(i, j int) {
+		out = append(out, models.ParagraphDiff{Op: models.ParagraphEqual, OldIndex: intPtr(i + 1), NewIndex: intPtr(j + 1), Text: a[i]})
+	}
+	del := func(i int) {
+		out = append(out, models.ParagraphDiff{Op: models.ParagraphDelete, OldIndex: intPtr(i + 1), Text: a[i]})
+	}
+	ins := func(j int) {
+		out = append(out, models.ParagraphDiff{Op: models.ParagraphInsert, NewIndex: intPtr(j + 1), Text: b[j]})
+	}
+
+	// Common prefix and suffix are cheap to strip and usually cover most of the text.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	for i := 0; i < pre; i++ {
+		equal(i, i)
+	}
+
+	am, bm := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	n, m := len(am), len(bm)
+	if n*m > maxDiffCells {
+		for i := range am {
+			del(pre + i)
+		}
+		for j := range bm {
+			ins(pre + j)
+		}
+	} else {
+		// lcs[i][j] is the LCS length of am[i:] and bm[j:].
+		lcs := make([][]int32, n+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, m+1)
+		}
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if am[i] == bm[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < n && j < m {
+			switch {
+			case am[i] == bm[j]:
+				equal(pre+i, pre+j)
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				del(pre + i)
+				i++
+			default:
+				ins(pre + j)
+				j++
+			}
+		}
+		for ; i < n; i++ {
+			del(pre + i)
+		}
+		for ; j < m; j++ {
+			ins(pre + j)
+		}
+	}
+
+	for k := 0; k < suf; k++ {
+		equal(len(a)-suf+k, len(b)-suf+k)
+	}
+	return out
+}
+
+func intPtr(v int) *int {
+	return &v
+}
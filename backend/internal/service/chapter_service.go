@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
 	"novels-backend/internal/repository"
 
 	"github.com/google/uuid"
@@ -18,9 +20,14 @@ var (
 
 // ChapterService сервис для работы с главами
 type ChapterService struct {
-	chapterRepo  *repository.ChapterRepository
-	novelRepo    *repository.NovelRepository
-	progressRepo *repository.ProgressRepository
+	chapterRepo         *repository.ChapterRepository
+	novelRepo           *repository.NovelRepository
+	progressRepo        *repository.ProgressRepository
+	readVerification    *ReadVerificationService
+	streaks             *StreakService
+	readingStats        *ReadingStatsService
+	subscriptionService *SubscriptionService
+	catalog             *CatalogCache
 }
 
 // NewChapterService создает новый ChapterService
@@ -28,16 +35,40 @@ func NewChapterService(
 	chapterRepo *repository.ChapterRepository,
 	novelRepo *repository.NovelRepository,
 	progressRepo *repository.ProgressRepository,
+	readVerification *ReadVerificationService,
+	streaks *StreakService,
+	readingStats *ReadingStatsService,
+	subscriptionService *SubscriptionService,
+	catalog *CatalogCache,
 ) *ChapterService {
 	return &ChapterService{
-		chapterRepo:  chapterRepo,
-		novelRepo:    novelRepo,
-		progressRepo: progressRepo,
+		chapterRepo:         chapterRepo,
+		novelRepo:           novelRepo,
+		progressRepo:        progressRepo,
+		readVerification:    readVerification,
+		streaks:             streaks,
+		readingStats:        readingStats,
+		subscriptionService: subscriptionService,
+		catalog:             catalog,
 	}
 }
 
-// ListByNovel получает список глав новеллы
-func (s *ChapterService) ListByNovel(ctx context.Context, novelSlug string, params models.ChapterListParams) (*models.ChaptersListResponse, error) {
+// visibleUntil возвращает время, до которого главы считаются вышедшими для пользователя:
+// сейчас или позже на время раннего доступа по подписке
+func (s *ChapterService) visibleUntil(ctx context.Context, userID *uuid.UUID) time.Time {
+	now := time.Now()
+	if userID == nil || s.subscriptionService == nil {
+		return now
+	}
+	hours, err := s.subscriptionService.EarlyAccessHours(ctx, *userID)
+	if err != nil || hours <= 0 {
+		return now
+	}
+	return now.Add(time.Duration(hours) * time.Hour)
+}
+
+// ListByNovel получает список вышедших глав новеллы (с учетом раннего доступа пользователя)
+func (s *ChapterService) ListByNovel(ctx context.Context, novelSlug string, params models.ChapterListParams, userID *uuid.UUID) (*models.ChaptersListResponse, error) {
 	// Устанавливаем значения по умолчанию
 	if params.Limit <= 0 {
 		params.Limit = 50
@@ -55,7 +86,7 @@ func (s *ChapterService) ListByNovel(ctx context.Context, novelSlug string, para
 		params.Order = "asc"
 	}
 
-	chapters, novel, total, err := s.chapterRepo.ListByNovel(ctx, novelSlug, params)
+	chapters, novel, total, err := s.chapterRepo.ListByNovel(ctx, novelSlug, params, s.visibleUntil(ctx, userID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list chapters: %w", err)
 	}
@@ -80,13 +111,14 @@ func (s *ChapterService) ListByNovel(ctx context.Context, novelSlug string, para
 	}, nil
 }
 
-// GetByID получает главу по ID
-func (s *ChapterService) GetByID(ctx context.Context, id uuid.UUID, lang string) (*models.ChapterWithContent, error) {
+// GetByID получает вышедшую главу по ID (с учетом раннего доступа пользователя)
+func (s *ChapterService) GetByID(ctx context.Context, id uuid.UUID, lang string, userID *uuid.UUID) (*models.ChapterWithContent, error) {
 	if lang == "" {
 		lang = "ru"
 	}
 
-	chapter, err := s.chapterRepo.GetByID(ctx, id, lang)
+	visibleUntil := s.visibleUntil(ctx, userID)
+	chapter, err := s.chapterRepo.GetByID(ctx, id, lang, &visibleUntil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chapter: %w", err)
 	}
@@ -99,7 +131,7 @@ func (s *ChapterService) GetByID(ctx context.Context, id uuid.UUID, lang string)
 
 // GetForReader получает главу для чтения (с увеличением просмотров)
 func (s *ChapterService) GetForReader(ctx context.Context, id uuid.UUID, lang string, userID *uuid.UUID) (*models.ChapterWithContent, error) {
-	chapter, err := s.GetByID(ctx, id, lang)
+	chapter, err := s.GetByID(ctx, id, lang, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +154,7 @@ func (s *ChapterService) GetForReader(ctx context.Context, id uuid.UUID, lang st
 }
 
 // Create создает новую главу (админ)
-func (s *ChapterService) Create(ctx context.Context, req *models.CreateChapterRequest) (*models.Chapter, error) {
+func (s *ChapterService) Create(ctx context.Context, req *models.CreateChapterRequest, authorID *uuid.UUID) (*models.Chapter, error) {
 	// Проверяем существование новеллы
 	novel, err := s.novelRepo.GetByID(ctx, req.NovelID, "ru")
 	if err != nil {
@@ -132,18 +164,20 @@ func (s *ChapterService) Create(ctx context.Context, req *models.CreateChapterRe
 		return nil, ErrNovelNotFound
 	}
 
-	chapter, err := s.chapterRepo.Create(ctx, req)
+	chapter, err := s.chapterRepo.Create(ctx, req, authorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chapter: %w", err)
 	}
+	// Новая глава поднимает новеллу в «последних обновлениях»
+	s.catalog.Changed(ctx, &req.NovelID, events.CatalogReasonChapter)
 
 	return chapter, nil
 }
 
 // Update обновляет главу (админ)
-func (s *ChapterService) Update(ctx context.Context, id uuid.UUID, req *models.UpdateChapterRequest) error {
+func (s *ChapterService) Update(ctx context.Context, id uuid.UUID, req *models.UpdateChapterRequest, authorID *uuid.UUID) error {
 	// Проверяем существование главы
-	existing, err := s.chapterRepo.GetByID(ctx, id, "ru")
+	existing, err := s.chapterRepo.GetByID(ctx, id, "ru", nil)
 	if err != nil {
 		return fmt.Errorf("failed to get chapter: %w", err)
 	}
@@ -151,16 +185,17 @@ func (s *ChapterService) Update(ctx context.Context, id uuid.UUID, req *models.U
 		return ErrChapterNotFound
 	}
 
-	if err := s.chapterRepo.Update(ctx, id, req); err != nil {
+	if err := s.chapterRepo.Update(ctx, id, req, authorID); err != nil {
 		return fmt.Errorf("failed to update chapter: %w", err)
 	}
+	s.catalog.Changed(ctx, &existing.NovelID, events.CatalogReasonChapter)
 
 	return nil
 }
 
 // Delete удаляет главу (админ)
 func (s *ChapterService) Delete(ctx context.Context, id uuid.UUID) error {
-	existing, err := s.chapterRepo.GetByID(ctx, id, "ru")
+	existing, err := s.chapterRepo.GetByID(ctx, id, "ru", nil)
 	if err != nil {
 		return fmt.Errorf("failed to get chapter: %w", err)
 	}
@@ -171,6 +206,7 @@ func (s *ChapterService) Delete(ctx context.Context, id uuid.UUID) error {
 	if err := s.chapterRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete chapter: %w", err)
 	}
+	s.catalog.Changed(ctx, &existing.NovelID, events.CatalogReasonChapter)
 
 	return nil
 }
@@ -194,6 +230,21 @@ func (s *ChapterService) SaveProgress(ctx context.Context, userID, chapterID uui
 		return fmt.Errorf("failed to save progress: %w", err)
 	}
 
+	// XP за главу начисляется один раз и только за подтвержденное прочтение
+	// (время на главе и продвижение позиции); по событиям read_chapter считаются ачивки чтения
+	finished := false
+	if s.readVerification != nil {
+		finished, _ = s.readVerification.Track(ctx, userID, novelID, chapterID, position)
+	}
+	// Время чтения по дням; подтвержденная глава добавляет главу и слова в статистику
+	if s.readingStats != nil {
+		s.readingStats.RecordProgress(ctx, userID, novelID, chapterID, finished)
+	}
+	// Чтение засчитывает день в серию активности
+	if s.streaks != nil {
+		s.streaks.RecordActivity(ctx, userID, models.ActivityRead)
+	}
+
 	return nil
 }
 
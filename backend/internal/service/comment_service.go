@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
 	"novels-backend/internal/repository"
 )
 
@@ -23,12 +24,16 @@ const MaxCommentDepth = 5
 type CommentService struct {
 	commentRepo *repository.CommentRepository
 	xpService   *XPService
+	bus         *events.Bus
 }
 
-func NewCommentService(commentRepo *repository.CommentRepository, xpService *XPService) *CommentService {
+// NewCommentService creates the comment service. Likes of other users' comments are announced
+// as CommentLiked events on bus (for achievements); bus may be nil.
+func NewCommentService(commentRepo *repository.CommentRepository, xpService *XPService, bus *events.Bus) *CommentService {
 	return &CommentService{
 		commentRepo: commentRepo,
 		xpService:   xpService,
+		bus:         bus,
 	}
 }
 
@@ -191,7 +196,15 @@ func (s *CommentService) Vote(ctx context.Context, commentID uuid.UUID, userID u
 		return ErrCommentDeleted
 	}
 
-	return s.commentRepo.Vote(ctx, commentID, userID, value)
+	if err := s.commentRepo.Vote(ctx, commentID, userID, value); err != nil {
+		return err
+	}
+	// Voting again with the same value removes the vote, so the like count is not known here;
+	// the achievement metrics read it from the comment.
+	if value == 1 && comment.UserID != userID && s.bus != nil {
+		_ = s.bus.Publish(ctx, events.CommentLiked{CommentID: commentID, AuthorID: comment.UserID})
+	}
+	return nil
 }
 
 // Report reports a comment
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/epub"
+	"novels-backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrExportEmpty                = errors.New("no chapters to export")
+	ErrExportTooLarge             = errors.New("chapter range is too large")
+	ErrExportRequiresSubscription = errors.New("exporting this many chapters requires a subscription")
+)
+
+// epubFormatVersion is part of the cache key; bump it when the generated book layout changes.
+const epubFormatVersion = "epub-1"
+
+// exportContentBatch is how many chapter texts are held in memory at once while writing a book.
+const exportContentBatch = 20
+
+// ExportService builds downloadable books from novels
+type ExportService struct {
+	novelRepo           *repository.NovelRepository
+	chapterRepo         *repository.ChapterRepository
+	authorRepo          *repository.AuthorRepository
+	subscriptionService *SubscriptionService
+	uploadsDir          string
+	freeMaxChapters     int
+	maxChapters         int
+	cacheTTL            time.Duration
+}
+
+// NewExportService creates a new export service. Ranges over freeMaxChapters need the
+// "export_full" subscription feature; maxChapters caps every export. Files are cached in
+// uploadsDir/exports and removed after cacheTTL without downloads.
+func NewExportService(
+	novelRepo *repository.NovelRepository,
+	chapterRepo *repository.ChapterRepository,
+	authorRepo *repository.AuthorRepository,
+	subscriptionService *SubscriptionService,
+	uploadsDir string,
+	freeMaxChapters, maxChapters int,
+	cacheTTL time.Duration,
+) *ExportService {
+	return &ExportService{
+		novelRepo:           novelRepo,
+		chapterRepo:         chapterRepo,
+		authorRepo:          authorRepo,
+		subscriptionService: subscriptionService,
+		uploadsDir:          uploadsDir,
+		freeMaxChapters:     freeMaxChapters,
+		maxChapters:         maxChapters,
+		cacheTTL:            cacheTTL,
+	}
+}
+
+// PrepareEPUB resolves the novel and chapter range (numbers from..to, to = 0 means the last chapter),
+// checks the caller may export that many chapters and computes the cache key.
+func (s *ExportService) PrepareEPUB(ctx context.Context, slug, lang string, from, to float64, userID *uuid.UUID) (*models.EPUBExport, error) {
+	if lang == "" {
+		lang = "ru"
+	}
+
+	novel, err := s.novelRepo.GetBySlug(ctx, slug, lang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get novel: %w", err)
+	}
+	if novel == nil && lang != "ru" {
+		// Chapters may be translated before the novel card is.
+		novel, err = s.novelRepo.GetBySlug(ctx, slug, "ru")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get novel: %w", err)
+		}
+	}
+	if novel == nil {
+		return nil, ErrNovelNotFound
+	}
+
+	chapters, err := s.chapterRepo.ListForExport(ctx, novel.ID, lang, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(chapters) == 0 {
+		return nil, ErrExportEmpty
+	}
+	if err := s.checkRange(ctx, len(chapters), userID); err != nil {
+		return nil, err
+	}
+
+	exp := &models.EPUBExport{
+		Novel:    novel,
+		Lang:     lang,
+		Authors:  s.authors(ctx, novel, lang),
+		Chapters: chapters,
+	}
+	exp.Key = exportKey(exp)
+	exp.FileName = fmt.Sprintf("%s-%s-%s-%s.epub", novel.Slug, lang,
+		formatChapterNumber(chapters[0].Number), formatChapterNumber(chapters[len(chapters)-1].Number))
+	return exp, nil
+}
+
+func (s *ExportService) checkRange(ctx context.Context, n int, userID *uuid.UUID) error {
+	if s.maxChapters > 0 && n > s.maxChapters {
+		return fmt.Errorf("%w: at most %d chapters per file", ErrExportTooLarge, s.maxChapters)
+	}
+	if n <= s.freeMaxChapters {
+		return nil
+	}
+	if userID == nil || s.subscriptionService == nil {
+		return ErrExportRequiresSubscription
+	}
+	ok, err := s.subscriptionService.HasFeature(ctx, *userID, "export_full")
+	if err != nil {
+		return fmt.Errorf("failed to check subscription: %w", err)
+	}
+	if !ok {
+		return ErrExportRequiresSubscription
+	}
+	return nil
+}
+
+func (s *ExportService) authors(ctx context.Context, novel *models.NovelWithLocalization, lang string) []string {
+	out := []string{}
+	if s.authorRepo != nil {
+		if list, err := s.authorRepo.GetNovelAuthors(ctx, novel.ID, lang); err == nil {
+			for _, a := range list {
+				if a.Author != nil && a.Author.Name != "" {
+					out = append(out, a.Author.Name)
+				}
+			}
+		}
+	}
+	if len(out) == 0 && novel.Author != nil && *novel.Author != "" {
+		out = append(out, *novel.Author)
+	}
+	return out
+}
+
+// exportKey hashes everything that ends up in the book, so any edit produces a new file.
+func exportKey(exp *models.EPUBExport) string {
+	h := sha256.New()
+	n := exp.Novel
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", epubFormatVersion, n.ID, exp.Lang, n.Title)
+	if n.Description != nil {
+		io.WriteString(h, *n.Description)
+	}
+	if n.CoverImageKey != nil {
+		fmt.Fprintf(h, "\x00cover:%s", *n.CoverImageKey)
+	}
+	fmt.Fprintf(h, "\x00authors:%s", strings.Join(exp.Authors, "\x01"))
+	for _, g := range n.Genres {
+		fmt.Fprintf(h, "\x00genre:%s", g.Name)
+	}
+	for _, ch := range exp.Chapters {
+		title := ""
+		if ch.Title != nil {
+			title = *ch.Title
+		}
+		fmt.Fprintf(h, "\x00%s\x00%s\x00%s\x00%s", ch.ID, formatChapterNumber(ch.Number), title, ch.ContentHash)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *ExportService) cacheDir() string {
+	return filepath.Join(s.uploadsDir, "exports")
+}
+
+func (s *ExportService) cachePath(exp *models.EPUBExport) string {
+	return filepath.Join(s.cacheDir(), exp.Key+".epub")
+}
+
+// OpenCached returns the cached file of the export, or nil if it has not been generated yet.
+func (s *ExportService) OpenCached(exp *models.EPUBExport) (*os.File, error) {
+	f, err := os.Open(s.cachePath(exp))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cached export: %w", err)
+	}
+	// Downloads keep the file out of cache cleanup.
+	now := time.Now()
+	_ = os.Chtimes(f.Name(), now, now)
+	return f, nil
+}
+
+// WriteEPUB generates the book into w and into the cache at the same time.
+// The cached file appears only after the whole book has been written.
+func (s *ExportService) WriteEPUB(ctx context.Context, exp *models.EPUBExport, w io.Writer) error {
+	dir := s.cacheDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create export cache dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, exp.Key+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := s.buildEPUB(ctx, exp, io.MultiWriter(tmp, w)); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.cachePath(exp)); err != nil {
+		return fmt.Errorf("failed to store export file: %w", err)
+	}
+
+	s.pruneCache()
+	return nil
+}
+
+func (s *ExportService) buildEPUB(ctx context.Context, exp *models.EPUBExport, w io.Writer) error {
+	n := exp.Novel
+	meta := epub.Metadata{
+		Identifier: "urn:uuid:" + n.ID.String(),
+		Title:      n.Title,
+		Language:   exp.Lang,
+		Authors:    exp.Authors,
+		Modified:   n.UpdatedAt,
+	}
+	if n.Description != nil {
+		meta.Description = *n.Description
+	}
+	for _, g := range n.Genres {
+		meta.Subjects = append(meta.Subjects, g.Name)
+	}
+
+	book, err := epub.NewWriter(w, meta)
+	if err != nil {
+		return err
+	}
+	if err := s.addCover(book, n.CoverImageKey); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(exp.Chapters); start += exportContentBatch {
+		batch := exp.Chapters[start:min(start+exportContentBatch, len(exp.Chapters))]
+		ids := make([]uuid.UUID, len(batch))
+		for i, ch := range batch {
+			ids[i] = ch.ID
+		}
+		contents, err := s.chapterRepo.GetContents(ctx, ids, exp.Lang)
+		if err != nil {
+			return err
+		}
+		for _, ch := range batch {
+			if err := book.AddChapter(chapterTitle(exp.Lang, ch), contents[ch.ID]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return book.Close()
+}
+
+// addCover embeds the novel cover from the uploads dir; a missing or unsupported image is skipped.
+func (s *ExportService) addCover(book *epub.Writer, key *string) error {
+	if key == nil || *key == "" {
+		return nil
+	}
+	root := filepath.Clean(s.uploadsDir)
+	path := filepath.Join(root, filepath.FromSlash(*key))
+	if !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return nil
+	}
+	mediaType, _, _ := strings.Cut(mime.TypeByExtension(strings.ToLower(filepath.Ext(path))), ";")
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	return book.SetCover(mediaType, f)
+}
+
+// pruneCache removes exports nobody downloaded within cacheTTL and leftovers of failed builds.
+func (s *ExportService) pruneCache() {
+	if s.cacheTTL <= 0 {
+		return
+	}
+	entries, err := os.ReadDir(s.cacheDir())
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-s.cacheTTL)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !(strings.HasSuffix(name, ".epub") || strings.HasSuffix(name, ".tmp")) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		_ = os.Remove(filepath.Join(s.cacheDir(), name))
+	}
+}
+
+func chapterTitle(lang string, ch models.ExportChapter) string {
+	if ch.Title != nil && strings.TrimSpace(*ch.Title) != "" {
+		return strings.TrimSpace(*ch.Title)
+	}
+	if lang == "ru" {
+		return "Глава " + formatChapterNumber(ch.Number)
+	}
+	return "Chapter " + formatChapterNumber(ch.Number)
+}
+
+func formatChapterNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Feed sizes: readers poll feeds, so they only carry the latest items.
+const (
+	feedChaptersLimit = 50
+	feedNewsLimit     = 30
+)
+
+// FeedService collects the items of the Atom update feeds.
+type FeedService struct {
+	novelRepo   *repository.NovelRepository
+	chapterRepo *repository.ChapterRepository
+	newsService *NewsService
+}
+
+// NewFeedService creates a new feed service
+func NewFeedService(novelRepo *repository.NovelRepository, chapterRepo *repository.ChapterRepository, newsService *NewsService) *FeedService {
+	return &FeedService{
+		novelRepo:   novelRepo,
+		chapterRepo: chapterRepo,
+		newsService: newsService,
+	}
+}
+
+// NovelChapters returns the novel and its latest chapters published in lang.
+func (s *FeedService) NovelChapters(ctx context.Context, slug, lang string) (*models.NovelWithLocalization, []models.FeedChapter, error) {
+	novel, err := s.novelRepo.GetBySlug(ctx, slug, lang)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get novel: %w", err)
+	}
+	if novel == nil && lang != "ru" {
+		// Chapters may be translated before the novel card is.
+		novel, err = s.novelRepo.GetBySlug(ctx, slug, "ru")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get novel: %w", err)
+		}
+	}
+	if novel == nil {
+		return nil, nil, ErrNovelNotFound
+	}
+
+	chapters, err := s.chapterRepo.ListFeed(ctx, novel.ID, lang, feedChaptersLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range chapters {
+		chapters[i].NovelTitle = novel.Title
+	}
+	return novel, chapters, nil
+}
+
+// ReadingList returns the latest chapters published in lang across the user's "reading" bookmarks.
+func (s *FeedService) ReadingList(ctx context.Context, userID uuid.UUID, lang string) ([]models.FeedChapter, error) {
+	return s.chapterRepo.ListBookmarkFeed(ctx, userID, models.BookmarkListReading, lang, feedChaptersLimit)
+}
+
+// News returns the latest published news, localized to lang where a translation exists.
+func (s *FeedService) News(ctx context.Context, lang string) ([]models.NewsCard, error) {
+	resp, err := s.newsService.List(ctx, models.NewsListParams{Lang: lang, Page: 1, Limit: feedNewsLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list news: %w", err)
+	}
+	return resp.News, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// FeedTokenService issues and resolves per-user tokens for OPDS and Atom feeds
+type FeedTokenService struct {
+	repo *repository.FeedTokenRepository
+}
+
+// NewFeedTokenService creates a new feed token service
+func NewFeedTokenService(repo *repository.FeedTokenRepository) *FeedTokenService {
+	return &FeedTokenService{repo: repo}
+}
+
+// Create issues a new token. The plain token is returned only here.
+func (s *FeedTokenService) Create(ctx context.Context, userID uuid.UUID, name string) (*models.CreatedFeedToken, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("generate feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	name = strings.TrimSpace(name)
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	t, err := s.repo.Create(ctx, userID, hashFeedToken(token), name)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreatedFeedToken{FeedToken: *t, Token: token}, nil
+}
+
+// List returns the user's tokens.
+func (s *FeedTokenService) List(ctx context.Context, userID uuid.UUID) ([]models.FeedToken, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Revoke disables a token; feeds using it stop working immediately.
+func (s *FeedTokenService) Revoke(ctx context.Context, userID, tokenID uuid.UUID) error {
+	ok, err := s.repo.Revoke(ctx, userID, tokenID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Resolve returns the owner of an active token, or nil.
+func (s *FeedTokenService) Resolve(ctx context.Context, token string) (*uuid.UUID, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return s.repo.Use(ctx, hashFeedToken(token))
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
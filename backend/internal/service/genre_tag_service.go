@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
 
 	"github.com/google/uuid"
 )
@@ -30,10 +31,11 @@ type TagRepository interface {
 // GenreService сервис для работы с жанрами
 type GenreService struct {
 	genreRepo GenreRepository
+	catalog   *CatalogCache
 }
 
-func NewGenreService(genreRepo GenreRepository) *GenreService {
-	return &GenreService{genreRepo: genreRepo}
+func NewGenreService(genreRepo GenreRepository, catalog *CatalogCache) *GenreService {
+	return &GenreService{genreRepo: genreRepo, catalog: catalog}
 }
 
 func (s *GenreService) List(ctx context.Context, filter models.GenresFilter) (*models.GenresResponse, error) {
@@ -76,6 +78,7 @@ func (s *GenreService) Create(ctx context.Context, req *models.CreateGenreReques
 	if err != nil {
 		return nil, fmt.Errorf("failed to create genre: %w", err)
 	}
+	s.catalog.Changed(ctx, nil, events.CatalogReasonTaxonomy)
 	return genre, nil
 }
 
@@ -92,6 +95,7 @@ func (s *GenreService) Update(ctx context.Context, id uuid.UUID, req *models.Upd
 	if err != nil {
 		return nil, fmt.Errorf("failed to update genre: %w", err)
 	}
+	s.catalog.Changed(ctx, nil, events.CatalogReasonTaxonomy)
 
 	return s.genreRepo.GetByID(ctx, id)
 }
@@ -105,16 +109,21 @@ func (s *GenreService) Delete(ctx context.Context, id uuid.UUID) error {
 		return ErrNotFound
 	}
 
-	return s.genreRepo.Delete(ctx, id)
+	if err := s.genreRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.catalog.Changed(ctx, nil, events.CatalogReasonTaxonomy)
+	return nil
 }
 
 // TagService сервис для работы с тегами
 type TagService struct {
 	tagRepo TagRepository
+	catalog *CatalogCache
 }
 
-func NewTagService(tagRepo TagRepository) *TagService {
-	return &TagService{tagRepo: tagRepo}
+func NewTagService(tagRepo TagRepository, catalog *CatalogCache) *TagService {
+	return &TagService{tagRepo: tagRepo, catalog: catalog}
 }
 
 func (s *TagService) List(ctx context.Context, filter models.TagsFilter) (*models.TagsResponse, error) {
@@ -157,6 +166,7 @@ func (s *TagService) Create(ctx context.Context, req *models.CreateTagRequest) (
 	if err != nil {
 		return nil, fmt.Errorf("failed to create tag: %w", err)
 	}
+	s.catalog.Changed(ctx, nil, events.CatalogReasonTaxonomy)
 	return tag, nil
 }
 
@@ -173,6 +183,7 @@ func (s *TagService) Update(ctx context.Context, id uuid.UUID, req *models.Updat
 	if err != nil {
 		return nil, fmt.Errorf("failed to update tag: %w", err)
 	}
+	s.catalog.Changed(ctx, nil, events.CatalogReasonTaxonomy)
 
 	return s.tagRepo.GetByID(ctx, id)
 }
@@ -186,5 +197,9 @@ func (s *TagService) Delete(ctx context.Context, id uuid.UUID) error {
 		return ErrNotFound
 	}
 
-	return s.tagRepo.Delete(ctx, id)
+	if err := s.tagRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.catalog.Changed(ctx, nil, events.CatalogReasonTaxonomy)
+	return nil
 }
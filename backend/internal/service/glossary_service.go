@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGlossaryTermExists  = errors.New("glossary term already exists")
+	ErrInvalidGlossaryTerm = errors.New("invalid glossary term")
+)
+
+// GlossaryService manages per-novel translation glossaries
+type GlossaryService struct {
+	glossaryRepo *repository.GlossaryRepository
+	novelRepo    *repository.NovelRepository
+	sourceLang   string
+}
+
+// NewGlossaryService creates a new glossary service. sourceLang is the language
+// chapters are machine-translated from; its contents are skipped by the violations report.
+func NewGlossaryService(glossaryRepo *repository.GlossaryRepository, novelRepo *repository.NovelRepository, sourceLang string) *GlossaryService {
+	return &GlossaryService{
+		glossaryRepo: glossaryRepo,
+		novelRepo:    novelRepo,
+		sourceLang:   sourceLang,
+	}
+}
+
+// NormalizeGlossaryInput trims a glossary entry and checks it is usable.
+func NormalizeGlossaryInput(in *models.GlossaryTermInput) error {
+	in.SourceTerm = strings.TrimSpace(in.SourceTerm)
+	if in.SourceTerm == "" {
+		return fmt.Errorf("%w: sourceTerm is required", ErrInvalidGlossaryTerm)
+	}
+	if len([]rune(in.SourceTerm)) > 200 {
+		return fmt.Errorf("%w: sourceTerm is too long", ErrInvalidGlossaryTerm)
+	}
+	if in.Translations == nil {
+		in.Translations = models.GlossaryTranslations{}
+	}
+	for lang, tr := range in.Translations {
+		if len(lang) < 2 || len(lang) > 10 {
+			return fmt.Errorf("%w: invalid language %q", ErrInvalidGlossaryTerm, lang)
+		}
+		tr.Target = strings.TrimSpace(tr.Target)
+		if tr.Target == "" {
+			return fmt.Errorf("%w: target for %q is required", ErrInvalidGlossaryTerm, lang)
+		}
+		for form, v := range tr.Forms {
+			if v = strings.TrimSpace(v); v == "" {
+				delete(tr.Forms, form)
+			} else {
+				tr.Forms[form] = v
+			}
+		}
+		in.Translations[lang] = tr
+	}
+	if in.Notes != nil {
+		notes := strings.TrimSpace(*in.Notes)
+		if notes == "" {
+			in.Notes = nil
+		} else {
+			in.Notes = &notes
+		}
+	}
+	return nil
+}
+
+func (s *GlossaryService) ensureNovel(ctx context.Context, novelID uuid.UUID) error {
+	novel, err := s.novelRepo.GetByID(ctx, novelID, "ru")
+	if err != nil {
+		return err
+	}
+	if novel == nil {
+		return ErrNovelNotFound
+	}
+	return nil
+}
+
+// List returns the glossary of a novel
+func (s *GlossaryService) List(ctx context.Context, novelID uuid.UUID) ([]models.GlossaryTerm, error) {
+	if err := s.ensureNovel(ctx, novelID); err != nil {
+		return nil, err
+	}
+	return s.glossaryRepo.List(ctx, novelID)
+}
+
+// Create adds a term to the glossary
+func (s *GlossaryService) Create(ctx context.Context, novelID uuid.UUID, in models.GlossaryTermInput, userID *uuid.UUID) (*models.GlossaryTerm, error) {
+	if err := NormalizeGlossaryInput(&in); err != nil {
+		return nil, err
+	}
+	if err := s.ensureNovel(ctx, novelID); err != nil {
+		return nil, err
+	}
+	term, err := s.glossaryRepo.Create(ctx, novelID, in, userID)
+	if err != nil {
+		return nil, err
+	}
+	if term == nil {
+		return nil, ErrGlossaryTermExists
+	}
+	return term, nil
+}
+
+// Update replaces a glossary term
+func (s *GlossaryService) Update(ctx context.Context, novelID, termID uuid.UUID, in models.GlossaryTermInput) (*models.GlossaryTerm, error) {
+	if err := NormalizeGlossaryInput(&in); err != nil {
+		return nil, err
+	}
+	other, err := s.glossaryRepo.GetBySourceTerm(ctx, novelID, in.SourceTerm)
+	if err != nil {
+		return nil, err
+	}
+	if other != nil && other.ID != termID {
+		return nil, ErrGlossaryTermExists
+	}
+	term, err := s.glossaryRepo.Update(ctx, novelID, termID, in)
+	if err != nil {
+		return nil, err
+	}
+	if term == nil {
+		return nil, ErrNotFound
+	}
+	return term, nil
+}
+
+// Delete removes a glossary term
+func (s *GlossaryService) Delete(ctx context.Context, novelID, termID uuid.UUID) error {
+	deleted, err := s.glossaryRepo.Delete(ctx, novelID, termID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Violations reports chapters whose translation still contains glossary source terms.
+// lang narrows the scan to one language; empty scans every translation.
+func (s *GlossaryService) Violations(ctx context.Context, novelID uuid.UUID, lang string, limit int) ([]models.GlossaryViolation, error) {
+	if err := s.ensureNovel(ctx, novelID); err != nil {
+		return nil, err
+	}
+	return s.glossaryRepo.Violations(ctx, novelID, s.sourceLang, lang, limit)
+}
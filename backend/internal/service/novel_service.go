@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 
+	"novels-backend/internal/cache"
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
 	"novels-backend/internal/repository"
 
 	"github.com/google/uuid"
@@ -18,12 +20,15 @@ var (
 // NovelService сервис для работы с новеллами
 type NovelService struct {
 	novelRepo *repository.NovelRepository
+	catalog   *CatalogCache
 }
 
-// NewNovelService создает новый NovelService
-func NewNovelService(novelRepo *repository.NovelRepository) *NovelService {
+// NewNovelService создает новый NovelService.
+// catalog кэширует витрины каталога (может быть nil — тогда всё читается из БД)
+func NewNovelService(novelRepo *repository.NovelRepository, catalog *CatalogCache) *NovelService {
 	return &NovelService{
 		novelRepo: novelRepo,
+		catalog:   catalog,
 	}
 }
 
@@ -172,6 +177,7 @@ func (s *NovelService) Create(ctx context.Context, req *models.CreateNovelReques
 	if err != nil {
 		return nil, fmt.Errorf("failed to create novel: %w", err)
 	}
+	s.catalog.Changed(ctx, &novel.ID, events.CatalogReasonNovel)
 
 	return novel, nil
 }
@@ -201,6 +207,7 @@ func (s *NovelService) Update(ctx context.Context, id uuid.UUID, req *models.Upd
 	if err := s.novelRepo.Update(ctx, id, req); err != nil {
 		return fmt.Errorf("failed to update novel: %w", err)
 	}
+	s.catalog.Changed(ctx, &id, events.CatalogReasonNovel)
 
 	return nil
 }
@@ -218,6 +225,7 @@ func (s *NovelService) Delete(ctx context.Context, id uuid.UUID) error {
 	if err := s.novelRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete novel: %w", err)
 	}
+	s.catalog.Changed(ctx, &id, events.CatalogReasonNovel)
 
 	return nil
 }
@@ -227,24 +235,28 @@ func (s *NovelService) GetPopular(ctx context.Context, lang string, limit int) (
 	if limit <= 0 {
 		limit = 10
 	}
+	if limit > 100 {
+		limit = 100
+	}
 	if lang == "" {
 		lang = "ru"
 	}
 
-	params := models.NovelListParams{
-		Lang:  lang,
-		Limit: limit,
-		Page:  1,
-		Sort:  "views_daily",
-		Order: "desc",
-	}
-
-	novels, _, err := s.novelRepo.List(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get popular novels: %w", err)
-	}
+	return cache.Load(ctx, s.catalog.listsNS(), catalogKey("popular", lang, limit), func(ctx context.Context) ([]models.NovelCard, error) {
+		params := models.NovelListParams{
+			Lang:  lang,
+			Limit: limit,
+			Page:  1,
+			Sort:  "views_daily",
+			Order: "desc",
+		}
 
-	return novels, nil
+		novels, _, err := s.novelRepo.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get popular novels: %w", err)
+		}
+		return novels, nil
+	})
 }
 
 // GetLatestUpdates получает последние обновления
@@ -252,24 +264,28 @@ func (s *NovelService) GetLatestUpdates(ctx context.Context, lang string, limit
 	if limit <= 0 {
 		limit = 20
 	}
+	if limit > 100 {
+		limit = 100
+	}
 	if lang == "" {
 		lang = "ru"
 	}
 
-	params := models.NovelListParams{
-		Lang:  lang,
-		Limit: limit,
-		Page:  1,
-		Sort:  "updated_at",
-		Order: "desc",
-	}
-
-	novels, _, err := s.novelRepo.List(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get latest updates: %w", err)
-	}
+	return cache.Load(ctx, s.catalog.listsNS(), catalogKey("latest", lang, limit), func(ctx context.Context) ([]models.NovelCard, error) {
+		params := models.NovelListParams{
+			Lang:  lang,
+			Limit: limit,
+			Page:  1,
+			Sort:  "updated_at",
+			Order: "desc",
+		}
 
-	return novels, nil
+		novels, _, err := s.novelRepo.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest updates: %w", err)
+		}
+		return novels, nil
+	})
 }
 
 // GetNewReleases получает новинки
@@ -277,24 +293,28 @@ func (s *NovelService) GetNewReleases(ctx context.Context, lang string, limit in
 	if limit <= 0 {
 		limit = 10
 	}
+	if limit > 100 {
+		limit = 100
+	}
 	if lang == "" {
 		lang = "ru"
 	}
 
-	params := models.NovelListParams{
-		Lang:  lang,
-		Limit: limit,
-		Page:  1,
-		Sort:  "created_at",
-		Order: "desc",
-	}
-
-	novels, _, err := s.novelRepo.List(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get new releases: %w", err)
-	}
+	return cache.Load(ctx, s.catalog.listsNS(), catalogKey("new", lang, limit), func(ctx context.Context) ([]models.NovelCard, error) {
+		params := models.NovelListParams{
+			Lang:  lang,
+			Limit: limit,
+			Page:  1,
+			Sort:  "created_at",
+			Order: "desc",
+		}
 
-	return novels, nil
+		novels, _, err := s.novelRepo.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get new releases: %w", err)
+		}
+		return novels, nil
+	})
 }
 
 // GetTrending получает трендовые новеллы (по росту просмотров)
@@ -302,26 +322,30 @@ func (s *NovelService) GetTrending(ctx context.Context, lang string, limit int)
 	if limit <= 0 {
 		limit = 10
 	}
+	if limit > 100 {
+		limit = 100
+	}
 	if lang == "" {
 		lang = "ru"
 	}
 
-	// Для трендов используем views_daily как основную метрику
-	// В будущем можно добавить более сложную логику
-	params := models.NovelListParams{
-		Lang:  lang,
-		Limit: limit,
-		Page:  1,
-		Sort:  "views_daily",
-		Order: "desc",
-	}
-
-	novels, _, err := s.novelRepo.List(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get trending novels: %w", err)
-	}
+	return cache.Load(ctx, s.catalog.listsNS(), catalogKey("trending", lang, limit), func(ctx context.Context) ([]models.NovelCard, error) {
+		// Для трендов используем views_daily как основную метрику
+		// В будущем можно добавить более сложную логику
+		params := models.NovelListParams{
+			Lang:  lang,
+			Limit: limit,
+			Page:  1,
+			Sort:  "views_daily",
+			Order: "desc",
+		}
 
-	return novels, nil
+		novels, _, err := s.novelRepo.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trending novels: %w", err)
+		}
+		return novels, nil
+	})
 }
 
 // GetTopRated получает новеллы с лучшим рейтингом
@@ -329,24 +353,28 @@ func (s *NovelService) GetTopRated(ctx context.Context, lang string, limit int)
 	if limit <= 0 {
 		limit = 10
 	}
+	if limit > 100 {
+		limit = 100
+	}
 	if lang == "" {
 		lang = "ru"
 	}
 
-	params := models.NovelListParams{
-		Lang:  lang,
-		Limit: limit,
-		Page:  1,
-		Sort:  "rating",
-		Order: "desc",
-	}
-
-	novels, _, err := s.novelRepo.List(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get top rated novels: %w", err)
-	}
+	return cache.Load(ctx, s.catalog.listsNS(), catalogKey("top_rated", lang, limit), func(ctx context.Context) ([]models.NovelCard, error) {
+		params := models.NovelListParams{
+			Lang:  lang,
+			Limit: limit,
+			Page:  1,
+			Sort:  "rating",
+			Order: "desc",
+		}
 
-	return novels, nil
+		novels, _, err := s.novelRepo.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get top rated novels: %w", err)
+		}
+		return novels, nil
+	})
 }
 
 // Rate добавляет оценку новелле
@@ -393,11 +421,13 @@ func (s *NovelService) GetAllGenres(ctx context.Context, lang string) ([]models.
 	if lang == "" {
 		lang = "ru"
 	}
-	genres, err := s.novelRepo.ListAllGenres(ctx, lang)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list genres: %w", err)
-	}
-	return genres, nil
+	return cache.Load(ctx, s.catalog.taxonomyNS(), catalogKey("genres", lang, 0), func(ctx context.Context) ([]models.Genre, error) {
+		genres, err := s.novelRepo.ListAllGenres(ctx, lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list genres: %w", err)
+		}
+		return genres, nil
+	})
 }
 
 // GetAllTags получает все теги
@@ -405,9 +435,11 @@ func (s *NovelService) GetAllTags(ctx context.Context, lang string) ([]models.Ta
 	if lang == "" {
 		lang = "ru"
 	}
-	tags, err := s.novelRepo.ListAllTags(ctx, lang)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list tags: %w", err)
-	}
-	return tags, nil
+	return cache.Load(ctx, s.catalog.taxonomyNS(), catalogKey("tags", lang, 0), func(ctx context.Context) ([]models.Tag, error) {
+		tags, err := s.novelRepo.ListAllTags(ctx, lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+		return tags, nil
+	})
 }
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+)
+
+var (
+	ErrProfileSectionHidden = errors.New("profile section is private")
+	ErrInvalidProfile       = errors.New("invalid profile")
+	ErrInvalidAvatar        = errors.New("invalid avatar")
+)
+
+const (
+	// profileCollectionsLimit, profileCommentsLimit and profileActivityLimit are how many
+	// collections, comments and activity items the profile shows.
+	profileCollectionsLimit = 12
+	profileCommentsLimit    = 5
+	profileActivityLimit    = 20
+	// maxProfileActivityLimit limits a page of the activity feed.
+	maxProfileActivityLimit = 50
+
+	minDisplayNameLength = 2
+	maxDisplayNameLength = 100
+	maxBioLength         = 1000
+
+	// MaxAvatarSize is the largest avatar upload in bytes.
+	MaxAvatarSize = 2 << 20
+	avatarsDir    = "avatars"
+)
+
+// avatarTypes maps the sniffed content type of an accepted avatar to its file extension.
+var avatarTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// ProfileService builds public user profiles, shown section by section as the user allows, and
+// lets users edit their profile and avatar.
+type ProfileService struct {
+	repo        *repository.ProfileRepository
+	collections *repository.CollectionRepository
+	bookmarks   *repository.BookmarkRepository
+	uploadsDir  string
+	logger      zerolog.Logger
+}
+
+func NewProfileService(repo *repository.ProfileRepository, collections *repository.CollectionRepository, bookmarks *repository.BookmarkRepository, uploadsDir string, logger zerolog.Logger) *ProfileService {
+	return &ProfileService{
+		repo:        repo,
+		collections: collections,
+		bookmarks:   bookmarks,
+		uploadsDir:  uploadsDir,
+		logger:      logger.With().Str("service", "profile").Logger(),
+	}
+}
+
+// owner returns the profile of userID as visible to viewerID (uuid.Nil for guests): banned users
+// are only visible to themselves.
+func (s *ProfileService) owner(ctx context.Context, userID, viewerID uuid.UUID) (*models.ProfileOwner, error) {
+	owner, err := s.repo.Owner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil || (owner.IsBanned && userID != viewerID) {
+		return nil, ErrUserNotFound
+	}
+	return owner, nil
+}
+
+// CanView returns nil if viewerID (uuid.Nil for guests) may see the section of userID's profile,
+// ErrUserNotFound for missing and banned users and ErrProfileSectionHidden for hidden sections.
+// Endpoints serving a section on its own check it here, as GetProfile does.
+func (s *ProfileService) CanView(ctx context.Context, userID, viewerID uuid.UUID, section models.ProfileSection) error {
+	owner, err := s.owner(ctx, userID, viewerID)
+	if err != nil {
+		return err
+	}
+	if userID != viewerID && !owner.Public(section) {
+		return ErrProfileSectionHidden
+	}
+	return nil
+}
+
+// GetProfile returns the profile of userID as seen by viewerID (uuid.Nil for guests), with
+// names of novels and news in lang.
+func (s *ProfileService) GetProfile(ctx context.Context, userID, viewerID uuid.UUID, lang string) (*models.PublicProfile, error) {
+	owner, err := s.owner(ctx, userID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	isOwner := userID == viewerID
+	profile := &models.PublicProfile{
+		ID:             owner.UserID,
+		DisplayName:    owner.DisplayName,
+		AvatarURL:      uploadURL(owner.AvatarKey),
+		Bio:            owner.Bio,
+		Level:          owner.Level,
+		XPTotal:        owner.XPTotal,
+		JoinedAt:       owner.JoinedAt,
+		IsOwner:        isOwner,
+		Hidden:         []models.ProfileSection{},
+		Achievements:   []models.AchievementBadge{},
+		Collections:    []models.CollectionCard{},
+		BookmarkStats:  []models.BookmarkListStats{},
+		RecentComments: []models.ProfileComment{},
+		Activity:       []models.ProfileActivity{},
+	}
+	if isOwner {
+		privacy := owner.ProfilePrivacy
+		profile.Privacy = &privacy
+	}
+
+	visible := func(section models.ProfileSection) bool {
+		if isOwner || owner.Public(section) {
+			return true
+		}
+		profile.Hidden = append(profile.Hidden, section)
+		return false
+	}
+
+	if visible(models.ProfileSectionAchievements) {
+		if profile.Achievements, err = s.repo.Badges(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	if visible(models.ProfileSectionCollections) {
+		// Private collections stay private on the owner's profile too; they are listed in "My collections".
+		collections, err := s.collections.GetUserCollections(ctx, userID, false)
+		if err != nil {
+			return nil, fmt.Errorf("list collections: %w", err)
+		}
+		if len(collections) > profileCollectionsLimit {
+			collections = collections[:profileCollectionsLimit]
+		}
+		if collections != nil {
+			profile.Collections = collections
+		}
+	}
+	if visible(models.ProfileSectionBookmarks) {
+		if profile.BookmarkStats, err = s.bookmarks.GetStats(ctx, userID); err != nil {
+			return nil, fmt.Errorf("bookmark stats: %w", err)
+		}
+	}
+	if visible(models.ProfileSectionComments) {
+		if profile.RecentComments, err = s.repo.RecentComments(ctx, userID, lang, profileCommentsLimit); err != nil {
+			return nil, err
+		}
+	}
+	if visible(models.ProfileSectionActivity) {
+		if profile.Activity, err = s.repo.Activity(ctx, userID, lang, profileActivityLimit, 0); err != nil {
+			return nil, err
+		}
+	}
+	// Reading statistics are served by ReadingStatsService; only the toggle is reported here.
+	visible(models.ProfileSectionStats)
+
+	return profile, nil
+}
+
+// GetActivity returns a page of the activity feed of userID if viewerID may see it.
+func (s *ProfileService) GetActivity(ctx context.Context, userID, viewerID uuid.UUID, lang string, page, limit int) (*models.ProfileActivityPage, error) {
+	if err := s.CanView(ctx, userID, viewerID, models.ProfileSectionActivity); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = profileActivityLimit
+	}
+	limit = min(limit, maxProfileActivityLimit)
+
+	// One extra item tells whether there is a next page.
+	items, err := s.repo.Activity(ctx, userID, lang, limit+1, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.ProfileActivityPage{Items: items, Page: page, Limit: limit}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		result.HasMore = true
+	}
+	return result, nil
+}
+
+// UpdateProfile changes the user's display name and bio.
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID uuid.UUID, req models.UpdateProfileRequest) error {
+	if req.DisplayName != nil {
+		name := strings.TrimSpace(*req.DisplayName)
+		if n := utf8.RuneCountInString(name); n < minDisplayNameLength || n > maxDisplayNameLength {
+			return fmt.Errorf("%w: display name must be %d to %d characters", ErrInvalidProfile, minDisplayNameLength, maxDisplayNameLength)
+		}
+		req.DisplayName = &name
+	}
+	if req.Bio != nil {
+		bio := strings.TrimSpace(*req.Bio)
+		if utf8.RuneCountInString(bio) > maxBioLength {
+			return fmt.Errorf("%w: bio must be at most %d characters", ErrInvalidProfile, maxBioLength)
+		}
+		req.Bio = &bio
+	}
+	return s.repo.Update(ctx, userID, req.DisplayName, req.Bio)
+}
+
+// UpdatePrivacy changes which profile sections other users see.
+func (s *ProfileService) UpdatePrivacy(ctx context.Context, userID uuid.UUID, req models.UpdateProfilePrivacyRequest) (models.ProfilePrivacy, error) {
+	return s.repo.UpdatePrivacy(ctx, userID, req)
+}
+
+// SetAvatar stores a JPEG, PNG or WebP image of at most MaxAvatarSize bytes as the user's avatar
+// and returns its URL. The previous avatar file is removed.
+func (s *ProfileService) SetAvatar(ctx context.Context, userID uuid.UUID, file io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(file, MaxAvatarSize+1))
+	if err != nil {
+		return "", fmt.Errorf("read avatar: %w", err)
+	}
+	if len(data) > MaxAvatarSize {
+		return "", fmt.Errorf("%w: file is larger than %d MB", ErrInvalidAvatar, MaxAvatarSize>>20)
+	}
+	ext, ok := avatarTypes[http.DetectContentType(data)]
+	if !ok {
+		return "", fmt.Errorf("%w: only jpg, png and webp images are allowed", ErrInvalidAvatar)
+	}
+
+	dir := filepath.Join(s.uploadsDir, avatarsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create avatars dir: %w", err)
+	}
+	key := avatarsDir + "/" + uuid.New().String() + ext
+	if err := os.WriteFile(filepath.Join(s.uploadsDir, key), data, 0644); err != nil {
+		return "", fmt.Errorf("write avatar: %w", err)
+	}
+
+	old, err := s.repo.SetAvatar(ctx, userID, &key)
+	if err != nil {
+		s.removeUpload(&key)
+		return "", err
+	}
+	s.removeUpload(old)
+	return "/uploads/" + key, nil
+}
+
+// RemoveAvatar removes the user's avatar.
+func (s *ProfileService) RemoveAvatar(ctx context.Context, userID uuid.UUID) error {
+	old, err := s.repo.SetAvatar(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+	s.removeUpload(old)
+	return nil
+}
+
+// removeUpload deletes an uploaded avatar; keys outside the avatars directory (e.g. set by hand)
+// are left alone.
+func (s *ProfileService) removeUpload(key *string) {
+	if key == nil || !strings.HasPrefix(*key, avatarsDir+"/") || strings.Contains(*key, "..") {
+		return
+	}
+	if err := os.Remove(filepath.Join(s.uploadsDir, *key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.Warn().Err(err).Str("key", *key).Msg("Failed to remove old avatar")
+	}
+}
+
+func uploadURL(key *string) *string {
+	if key == nil || *key == "" {
+		return nil
+	}
+	url := "/uploads/" + *key
+	return &url
+}
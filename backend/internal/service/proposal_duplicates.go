@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"novels-backend/internal/domain/models"
+)
+
+var (
+	ErrDuplicateProposal  = errors.New("this book has already been proposed")
+	ErrNovelAlreadyExists = errors.New("this book has already been imported")
+	ErrSimilarTitlesExist = errors.New("proposals or novels with a similar title exist")
+)
+
+const (
+	// similarTitleThreshold is the pg_trgm similarity from which titles are reported as possible duplicates.
+	similarTitleThreshold = 0.6
+	similarTitlesLimit    = 5
+)
+
+// DuplicateError is returned when a proposal would duplicate an existing proposal or novel.
+// Matches point to the existing entries, best match first.
+type DuplicateError struct {
+	Err     error
+	Matches []models.DuplicateMatch
+}
+
+func (e *DuplicateError) Error() string { return e.Err.Error() }
+
+func (e *DuplicateError) Unwrap() error { return e.Err }
+
+// canonicalLink returns the importer and canonical URL of an original link.
+func (s *VotingService) canonicalLink(originalLink string) (string, string, error) {
+	importerName, canonicalURL, err := s.importers.CanonicalURL(originalLink)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidOriginalLink, err)
+	}
+	return importerName, canonicalURL, nil
+}
+
+// findDuplicates returns the novel or active proposal registered for canonicalURL as a
+// *DuplicateError; without them, proposals and novels with titles similar to title
+// unless ignoreSimilar is set.
+func (s *VotingService) findDuplicates(ctx context.Context, canonicalURL, title string, ignoreSimilar bool, excludeProposalID *uuid.UUID) error {
+	if s.sourceLinks == nil {
+		return nil
+	}
+	matches, err := s.sourceLinks.FindByURL(ctx, canonicalURL, excludeProposalID)
+	if err != nil {
+		return err
+	}
+	if len(matches) > 0 {
+		return duplicateError(matches)
+	}
+	if ignoreSimilar || strings.TrimSpace(title) == "" {
+		return nil
+	}
+	similar, err := s.sourceLinks.FindSimilarTitles(ctx, strings.TrimSpace(title), similarTitleThreshold, similarTitlesLimit, excludeProposalID)
+	if err != nil {
+		return err
+	}
+	if len(similar) > 0 {
+		return &DuplicateError{Err: ErrSimilarTitlesExist, Matches: similar}
+	}
+	return nil
+}
+
+func duplicateError(matches []models.DuplicateMatch) *DuplicateError {
+	if matches[0].Kind == models.DuplicateKindNovel {
+		return &DuplicateError{Err: ErrNovelAlreadyExists, Matches: matches}
+	}
+	return &DuplicateError{Err: ErrDuplicateProposal, Matches: matches}
+}
+
+// mergeIntoProposal resolves a CreateProposal request for an already proposed book to the
+// existing proposal. The author's own proposal that is still editable (draft or moderation)
+// takes over the new title as an alternative title and the new alt titles, genres and tags;
+// other proposals are returned as they are, so the user can vote for them instead.
+func (s *VotingService) mergeIntoProposal(ctx context.Context, userID uuid.UUID, req models.CreateProposalRequest, match models.DuplicateMatch) (*models.NovelProposal, error) {
+	existing, err := s.votingRepo.GetProposalByID(ctx, match.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrProposalNotFound
+	}
+
+	editable := existing.Status == models.ProposalStatusDraft || existing.Status == models.ProposalStatusModeration
+	if existing.UserID == userID && editable {
+		titles := append([]string{req.Title}, req.AltTitles...)
+		existing.AltTitles = mergeStrings(existing.AltTitles, titles, existing.Title)
+		existing.Genres = mergeStrings(existing.Genres, req.Genres, "")
+		existing.Tags = mergeStrings(existing.Tags, req.Tags, "")
+		if strings.TrimSpace(existing.Description) == "" {
+			existing.Description = req.Description
+		}
+		if existing.CoverURL == nil && req.CoverURL != nil {
+			existing.CoverURL = req.CoverURL
+		}
+		if err := s.votingRepo.UpdateProposal(ctx, existing); err != nil {
+			return nil, fmt.Errorf("update proposal: %w", err)
+		}
+	}
+
+	s.logger.Info().
+		Str("proposal_id", existing.ID.String()).
+		Str("user_id", userID.String()).
+		Str("original_link", req.OriginalLink).
+		Msg("Duplicate proposal merged into existing")
+	return existing, nil
+}
+
+// mergeStrings appends the values of extra missing from base (case-insensitive), skipping
+// empty values and skip.
+func mergeStrings(base, extra []string, skip string) []string {
+	seen := make(map[string]bool, len(base)+1)
+	seen[strings.ToLower(strings.TrimSpace(skip))] = true
+	out := make([]string, 0, len(base)+len(extra))
+	for _, v := range append(append([]string{}, base...), extra...) {
+		v = strings.TrimSpace(v)
+		key := strings.ToLower(v)
+		if v == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, v)
+	}
+	return out
+}
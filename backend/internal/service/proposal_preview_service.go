@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/cache"
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/importer"
+	"novels-backend/internal/orchestrator"
+)
+
+var (
+	ErrPreviewTimeout     = errors.New("source site did not answer in time")
+	ErrPreviewUnavailable = errors.New("failed to fetch book metadata from source site")
+)
+
+// BookPreviewer fetches book metadata from a source site (implemented by orchestrator.ImportOrchestrator).
+type BookPreviewer interface {
+	Preview(ctx context.Context, originalLink string, cookieHeader string) (string, *importer.BookPreview, error)
+}
+
+// ProposalPreviewService prefills proposal drafts from the original link, so users do not
+// have to copy the title and description from the source site by hand.
+//
+// Only the book page and catalog are fetched (no chapters). Results are cached per link,
+// since a user usually previews the same book several times while filling the form, and
+// every fetch is bounded by timeout so the form stays responsive when a site is slow.
+type ProposalPreviewService struct {
+	previewer BookPreviewer
+	previews  *cache.Namespace
+	timeout   time.Duration
+	logger    zerolog.Logger
+}
+
+func NewProposalPreviewService(previewer BookPreviewer, store *cache.Store, ttl, timeout time.Duration, logger zerolog.Logger) *ProposalPreviewService {
+	return &ProposalPreviewService{
+		previewer: previewer,
+		previews:  store.Namespace("proposal_preview", ttl),
+		timeout:   timeout,
+		logger:    logger.With().Str("service", "proposal_preview").Logger(),
+	}
+}
+
+// Preview returns a proposal draft for originalLink.
+func (s *ProposalPreviewService) Preview(ctx context.Context, originalLink string) (*models.ProposalPreview, error) {
+	if err := validateProposalOriginalLink(originalLink); err != nil {
+		return nil, err
+	}
+	link := strings.TrimSpace(originalLink)
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+
+	return cache.Load(ctx, s.previews, link, func(ctx context.Context) (*models.ProposalPreview, error) {
+		if s.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+		}
+
+		site, book, err := s.previewer.Preview(ctx, link, "")
+		switch {
+		case errors.Is(err, orchestrator.ErrNoImporter):
+			return nil, fmt.Errorf("%w: site is not supported", ErrInvalidOriginalLink)
+		case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+			s.logger.Warn().Str("url", link).Str("site", site).Dur("timeout", s.timeout).Msg("Proposal preview timed out")
+			return nil, ErrPreviewTimeout
+		case err != nil:
+			s.logger.Warn().Err(err).Str("url", link).Str("site", site).Msg("Proposal preview failed")
+			return nil, fmt.Errorf("%w: %v", ErrPreviewUnavailable, err)
+		case strings.TrimSpace(book.Title) == "":
+			return nil, fmt.Errorf("%w: book title not found on the page", ErrPreviewUnavailable)
+		}
+		return newProposalPreview(site, link, book), nil
+	})
+}
+
+func newProposalPreview(site, link string, book *importer.BookPreview) *models.ProposalPreview {
+	draft := models.CreateProposalRequest{
+		OriginalLink: link,
+		Title:        book.Title,
+		Author:       book.Author,
+		Description:  book.Description,
+		Genres:       []string{},
+		Tags:         []string{},
+	}
+	if cover := strings.TrimSpace(book.CoverURL); strings.HasPrefix(cover, "http://") || strings.HasPrefix(cover, "https://") {
+		draft.CoverURL = &cover
+	}
+	seen := make(map[string]bool, len(book.Tags))
+	for _, tag := range book.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		draft.Tags = append(draft.Tags, tag)
+	}
+
+	return &models.ProposalPreview{
+		Site:          site,
+		ChaptersCount: len(book.Chapters),
+		Category:      book.Category,
+		Draft:         draft,
+		FetchedAt:     time.Now().UTC(),
+	}
+}
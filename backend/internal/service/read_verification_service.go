@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+)
+
+// readSessionIdleGap ends a read session: a chapter not saved for this long is read anew.
+const readSessionIdleGap = 30 * time.Minute
+
+// wordsPerMinute is the reading speed the estimated reading time of a chapter assumes (the same
+// as ChapterWithContent.ReadingTime).
+const wordsPerMinute = 200
+
+// ReadVerificationOptions configures ReadVerificationService.
+type ReadVerificationOptions struct {
+	// DwellRatio is the share of the estimated reading time a chapter must stay open.
+	DwellRatio float64
+	// MinDwell is the least time on any chapter.
+	MinDwell time.Duration
+	// NovelDailyCap and DailyCap limit the chapters of one novel and in total that give XP
+	// within 24 hours.
+	NovelDailyCap int
+	DailyCap      int
+	// SuspicionWindow is the period the read activity report covers.
+	SuspicionWindow time.Duration
+}
+
+// ReadVerificationService awards read_chapter XP only for reads that look real. Each progress
+// save feeds a read session (repository.ReadSessionRepository); XP is awarded once the chapter
+// has been open for its dwell time and the position moved forward, unless a daily cap is
+// reached. A chapter gives XP once per user, ever.
+type ReadVerificationService struct {
+	repo   *repository.ReadSessionRepository
+	xp     *XPService
+	opts   ReadVerificationOptions
+	logger zerolog.Logger
+}
+
+func NewReadVerificationService(repo *repository.ReadSessionRepository, xp *XPService, opts ReadVerificationOptions, logger zerolog.Logger) *ReadVerificationService {
+	if opts.DwellRatio < 0 {
+		opts.DwellRatio = 0
+	}
+	if opts.SuspicionWindow <= 0 {
+		opts.SuspicionWindow = 7 * 24 * time.Hour
+	}
+	return &ReadVerificationService{
+		repo:   repo,
+		xp:     xp,
+		opts:   opts,
+		logger: logger.With().Str("service", "read_verification").Logger(),
+	}
+}
+
+// RequiredDwell returns how long a chapter of words must stay open to count as read.
+func (s *ReadVerificationService) RequiredDwell(words int) time.Duration {
+	estimated := time.Duration(float64(words) / wordsPerMinute * s.opts.DwellRatio * float64(time.Minute))
+	return max(estimated, s.opts.MinDwell)
+}
+
+// Track records a progress save and awards read_chapter XP if the read now qualifies.
+// It reports whether XP was awarded.
+func (s *ReadVerificationService) Track(ctx context.Context, userID, novelID, chapterID uuid.UUID, position int) (bool, error) {
+	session, err := s.repo.Touch(ctx, userID, novelID, chapterID, max(position, 0), readSessionIdleGap)
+	if err != nil {
+		return false, err
+	}
+	if session.AwardedAt != nil || !session.Progressed() || session.Dwell() < s.RequiredDwell(session.Words) {
+		return false, nil
+	}
+
+	inNovel, total, err := s.repo.AwardedSince(ctx, userID, novelID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return false, err
+	}
+	if (s.opts.NovelDailyCap > 0 && inNovel >= s.opts.NovelDailyCap) || (s.opts.DailyCap > 0 && total >= s.opts.DailyCap) {
+		// The read stays eligible: it is awarded by a save after the cap window moved on.
+		return false, s.repo.MarkCapped(ctx, userID, chapterID)
+	}
+
+	claimed, err := s.repo.ClaimAward(ctx, userID, chapterID)
+	if err != nil || !claimed {
+		return false, err
+	}
+	if err := s.xp.AwardXP(ctx, userID, models.XPEventReadChapter, 0, "chapter", chapterID); err != nil {
+		if rerr := s.repo.ReleaseAward(ctx, userID, chapterID); rerr != nil {
+			s.logger.Error().Err(rerr).Str("user_id", userID.String()).Msg("Failed to release read award")
+		}
+		return false, fmt.Errorf("award read xp: %w", err)
+	}
+	return true, nil
+}
+
+// Score thresholds of the read activity report.
+const (
+	// suspicionMinSessions is the least reads for the skim ratio to count.
+	suspicionMinSessions = 10
+	// suspicionPeakPerHour is the most chapters per hour a fast human reader opens.
+	suspicionPeakPerHour = 20
+)
+
+// Report returns the read activity of a user over the suspicion window with a 0-100 score:
+// up to 40 points for skimmed reads, 30 for bursts of chapters, 20 for hitting daily caps and
+// 10 for positions jumping back.
+func (s *ReadVerificationService) Report(ctx context.Context, userID uuid.UUID) (*models.ReadActivityReport, error) {
+	report, err := s.repo.Report(ctx, userID, s.opts.SuspicionWindow, readSessionIdleGap, s.opts.DwellRatio, s.opts.MinDwell)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Reasons = []string{}
+	if report.Sessions >= suspicionMinSessions && report.Skimmed > 0 {
+		report.Score += 40 * report.Skimmed / report.Sessions
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d of %d chapters left before the dwell time", report.Skimmed, report.Sessions))
+	}
+	if report.PeakPerHour > suspicionPeakPerHour {
+		report.Score += min(30, report.PeakPerHour-suspicionPeakPerHour)
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d chapters opened within one hour", report.PeakPerHour))
+	}
+	if report.Capped > 0 {
+		report.Score += min(20, 2*report.Capped)
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d reads over the daily caps", report.Capped))
+	}
+	if report.Sessions > 0 && report.Regressions > 0 {
+		report.Score += min(10, 10*report.Regressions/report.Sessions)
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d position jumps back", report.Regressions))
+	}
+	return report, nil
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+)
+
+var (
+	ErrStatsPrivate       = errors.New("reading statistics are private")
+	ErrYearReviewNotReady = errors.New("year in review is not ready")
+	ErrInvalidStatsPeriod = errors.New("invalid statistics period")
+)
+
+const (
+	// statsTopLimit is how many favourite genres, tags and novels are listed.
+	statsTopLimit = 5
+	// maxStatsPeriods limits how many weeks or months of reading are returned.
+	maxStatsPeriods = 104
+	// yearReviewBatchSize is how many users the year_review job generates reviews for at once.
+	yearReviewBatchSize = 500
+	// yearReviewRefresh is how often the review of the running year is regenerated in December.
+	yearReviewRefresh = 24 * time.Hour
+	// yearReviewLang is the language names are stored in; they are localized when served.
+	yearReviewLang = "ru"
+)
+
+// ReadingStatsService keeps reading statistics: reading time per user, novel and day (local to
+// the user's timezone, see StreakService), fed by progress saves, and the chapters and words of
+// verified reads. It serves personal statistics, public on the profile if the user allows it,
+// and the annual year in review generated by the year_review job.
+type ReadingStatsService struct {
+	repo    *repository.ReadingStatsRepository
+	streaks *StreakService
+	idleGap time.Duration
+	logger  zerolog.Logger
+}
+
+func NewReadingStatsService(repo *repository.ReadingStatsRepository, streaks *StreakService, idleGap time.Duration, logger zerolog.Logger) *ReadingStatsService {
+	if idleGap <= 0 {
+		idleGap = 5 * time.Minute
+	}
+	return &ReadingStatsService{
+		repo:    repo,
+		streaks: streaks,
+		idleGap: idleGap,
+		logger:  logger.With().Str("service", "reading_stats").Logger(),
+	}
+}
+
+// RecordProgress adds a progress save to the reading time; finished means the chapter read was
+// verified just now. Statistics must not fail the progress save, so errors are logged.
+func (s *ReadingStatsService) RecordProgress(ctx context.Context, userID, novelID, chapterID uuid.UUID, finished bool) {
+	if err := s.recordProgress(ctx, userID, novelID, chapterID, finished); err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to record reading time")
+	}
+}
+
+func (s *ReadingStatsService) recordProgress(ctx context.Context, userID, novelID, chapterID uuid.UUID, finished bool) error {
+	loc, err := s.streaks.Location(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return s.repo.RecordProgress(ctx, userID, novelID, chapterID, now, localDay(now, loc), s.idleGap, finished)
+}
+
+// CanView returns nil if viewerID (uuid.Nil for guests) may see the statistics of userID: the
+// user's own or public ones.
+func (s *ReadingStatsService) CanView(ctx context.Context, userID, viewerID uuid.UUID) error {
+	public, found, err := s.repo.StatsPublic(ctx, userID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !found:
+		return ErrUserNotFound
+	case !public && userID != viewerID:
+		return ErrStatsPrivate
+	}
+	return nil
+}
+
+// SetPublic makes the user's reading statistics public or private.
+func (s *ReadingStatsService) SetPublic(ctx context.Context, userID uuid.UUID, public bool) error {
+	return s.repo.SetStatsPublic(ctx, userID, public)
+}
+
+// GetStats returns the user's reading statistics with the last count weeks or months.
+func (s *ReadingStatsService) GetStats(ctx context.Context, userID uuid.UUID, period models.ReadingStatsPeriod, count int, lang string) (*models.ReadingStats, error) {
+	if period == "" {
+		period = models.ReadingStatsWeek
+	}
+	if period != models.ReadingStatsWeek && period != models.ReadingStatsMonth {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStatsPeriod, period)
+	}
+	if count <= 0 {
+		count = 12
+	}
+	count = min(count, maxStatsPeriods)
+
+	public, _, err := s.repo.StatsPublic(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.streaks.Location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.ReadingStats{UserID: userID, Public: public, Period: period}
+	if stats.Totals, err = s.repo.Totals(ctx, userID, "", ""); err != nil {
+		return nil, err
+	}
+
+	starts := periodStarts(localDay(time.Now(), loc), period, count)
+	rows, err := s.repo.Periods(ctx, userID, period, starts[0])
+	if err != nil {
+		return nil, err
+	}
+	byStart := make(map[string]models.ReadingPeriodStats, len(rows))
+	for _, row := range rows {
+		byStart[row.Start] = row
+	}
+	stats.Periods = make([]models.ReadingPeriodStats, 0, len(starts))
+	for _, start := range starts {
+		p := byStart[start]
+		p.Start = start
+		stats.Periods = append(stats.Periods, p)
+	}
+
+	if stats.FavoriteGenres, err = s.repo.TopCategories(ctx, userID, repository.ReadingCategoryGenres, "", "", lang, statsTopLimit); err != nil {
+		return nil, err
+	}
+	if stats.FavoriteTags, err = s.repo.TopCategories(ctx, userID, repository.ReadingCategoryTags, "", "", lang, statsTopLimit); err != nil {
+		return nil, err
+	}
+	if stats.Bookmarks, err = s.repo.BookmarkCompletion(ctx, userID); err != nil {
+		return nil, err
+	}
+	if stats.Bookmarks.Started > 0 {
+		stats.Bookmarks.CompletionRate = float64(stats.Bookmarks.Completed) / float64(stats.Bookmarks.Started)
+	}
+	if stats.LongestBinge, err = s.repo.LongestBinge(ctx, userID, time.Time{}, time.Now().Add(time.Minute)); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// periodStarts returns the first days of the count weeks (from Monday) or months up to the one
+// with today, oldest first.
+func periodStarts(today time.Time, period models.ReadingStatsPeriod, count int) []string {
+	var start time.Time
+	if period == models.ReadingStatsMonth {
+		start = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		start = today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	}
+	starts := make([]string, count)
+	for i := count - 1; i >= 0; i-- {
+		starts[i] = start.Format(models.DateLayout)
+		if period == models.ReadingStatsMonth {
+			start = start.AddDate(0, -1, 0)
+		} else {
+			start = start.AddDate(0, 0, -7)
+		}
+	}
+	return starts
+}
+
+// GetYearInReview returns the user's cached year in review with names in lang.
+func (s *ReadingStatsService) GetYearInReview(ctx context.Context, userID uuid.UUID, year int, lang string) (*models.YearInReview, error) {
+	review, err := s.repo.YearReview(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrYearReviewNotReady
+	}
+	if lang != "" && lang != yearReviewLang {
+		if err := s.localize(ctx, review, lang); err != nil {
+			return nil, err
+		}
+	}
+	return review, nil
+}
+
+func (s *ReadingStatsService) localize(ctx context.Context, review *models.YearInReview, lang string) error {
+	novelIDs := make([]uuid.UUID, 0, len(review.TopNovels))
+	for _, n := range review.TopNovels {
+		novelIDs = append(novelIDs, n.NovelID)
+	}
+	titles, err := s.repo.NovelTitles(ctx, novelIDs, lang)
+	if err != nil {
+		return err
+	}
+	for i, n := range review.TopNovels {
+		if title, ok := titles[n.NovelID]; ok {
+			review.TopNovels[i].Title = title
+		}
+	}
+
+	for category, stats := range map[repository.ReadingCategory][]models.ReadingCategoryStats{
+		repository.ReadingCategoryGenres: review.TopGenres,
+		repository.ReadingCategoryTags:   review.TopTags,
+	} {
+		ids := make([]uuid.UUID, 0, len(stats))
+		for _, c := range stats {
+			ids = append(ids, c.ID)
+		}
+		names, err := s.repo.CategoryNames(ctx, category, ids, lang)
+		if err != nil {
+			return err
+		}
+		for i, c := range stats {
+			if name, ok := names[c.ID]; ok {
+				stats[i].Name = name
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateYearReviews generates the year in review of users who read in the past year and, in
+// December, of the running year. Reviews are generated once after the year ended (so late reads
+// of December 31 count) and daily during December. Returns how many were generated.
+func (s *ReadingStatsService) GenerateYearReviews(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	_, err := s.repo.WithYearReviewLock(ctx, func(ctx context.Context) error {
+		yearStart := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		n, err := s.generateYear(ctx, now.Year()-1, yearStart)
+		total += n
+		if err != nil || now.Month() != time.December {
+			return err
+		}
+		n, err = s.generateYear(ctx, now.Year(), now.Add(-yearReviewRefresh))
+		total += n
+		return err
+	})
+	return total, err
+}
+
+func (s *ReadingStatsService) generateYear(ctx context.Context, year int, staleBefore time.Time) (int, error) {
+	generated := 0
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return generated, err
+		}
+		users, err := s.repo.StaleYearReviews(ctx, year, staleBefore, after, yearReviewBatchSize)
+		if err != nil {
+			return generated, err
+		}
+		if len(users) == 0 {
+			break
+		}
+		for _, userID := range users {
+			if _, err := s.GenerateYearReview(ctx, userID, year); err != nil {
+				return generated, fmt.Errorf("generate %d review: %w", year, err)
+			}
+			generated++
+		}
+		after = users[len(users)-1]
+	}
+	if generated > 0 {
+		s.logger.Info().Int("year", year).Int("reviews", generated).Msg("Year in review generated")
+	}
+	return generated, nil
+}
+
+// GenerateYearReview computes and caches the user's year in review.
+func (s *ReadingStatsService) GenerateYearReview(ctx context.Context, userID uuid.UUID, year int) (*models.YearInReview, error) {
+	loc, err := s.streaks.Location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	from := fmt.Sprintf("%04d-01-01", year)
+	to := fmt.Sprintf("%04d-01-01", year+1)
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+
+	review := &models.YearInReview{UserID: userID, Year: year, GeneratedAt: time.Now()}
+	if review.Totals, err = s.repo.Totals(ctx, userID, from, to); err != nil {
+		return nil, err
+	}
+	if review.TopNovels, err = s.repo.TopNovels(ctx, userID, from, to, yearReviewLang, statsTopLimit); err != nil {
+		return nil, err
+	}
+	if review.TopGenres, err = s.repo.TopCategories(ctx, userID, repository.ReadingCategoryGenres, from, to, yearReviewLang, statsTopLimit); err != nil {
+		return nil, err
+	}
+	if review.TopTags, err = s.repo.TopCategories(ctx, userID, repository.ReadingCategoryTags, from, to, yearReviewLang, statsTopLimit); err != nil {
+		return nil, err
+	}
+	if review.BusiestMonth, err = s.repo.BusiestMonth(ctx, userID, from, to); err != nil {
+		return nil, err
+	}
+	if review.LongestBinge, err = s.repo.LongestBinge(ctx, userID, start, end); err != nil {
+		return nil, err
+	}
+	if review.LongestStreak, err = s.repo.LongestStreak(ctx, userID, from, to); err != nil {
+		return nil, err
+	}
+	if review.NovelsCompleted, err = s.repo.CompletedNovels(ctx, userID, start, end); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveYearReview(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
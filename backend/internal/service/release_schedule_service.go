@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidReleaseSchedule = errors.New("invalid release schedule")
+
+const (
+	defaultReleaseTimezone = "Europe/Moscow"
+	maxReleaseTimes        = 24
+	// missedSlotGrace is how late a slot may still be filled. Slots missed while the service was
+	// down are skipped, otherwise the whole queue would come out at once after an outage.
+	missedSlotGrace = 15 * time.Minute
+	releasePreview  = 20
+)
+
+// ReleaseScheduleService manages per-novel release cadences and releases queued chapters.
+type ReleaseScheduleService struct {
+	repo      *repository.ReleaseScheduleRepository
+	novelRepo *repository.NovelRepository
+	planAhead time.Duration
+}
+
+// NewReleaseScheduleService creates a new release schedule service. Queued chapters get their
+// release time up to planAhead in advance, so subscribers with early access can read them.
+func NewReleaseScheduleService(repo *repository.ReleaseScheduleRepository, novelRepo *repository.NovelRepository, planAhead time.Duration) *ReleaseScheduleService {
+	if planAhead < 0 {
+		planAhead = 0
+	}
+	return &ReleaseScheduleService{
+		repo:      repo,
+		novelRepo: novelRepo,
+		planAhead: planAhead,
+	}
+}
+
+func (s *ReleaseScheduleService) ensureNovel(ctx context.Context, novelID uuid.UUID) error {
+	novel, err := s.novelRepo.GetByID(ctx, novelID, "ru")
+	if err != nil {
+		return err
+	}
+	if novel == nil {
+		return ErrNovelNotFound
+	}
+	return nil
+}
+
+// Get returns the schedule of a novel (nil if it has none) with its queue and upcoming releases.
+func (s *ReleaseScheduleService) Get(ctx context.Context, novelID uuid.UUID) (*models.ReleaseScheduleView, error) {
+	if err := s.ensureNovel(ctx, novelID); err != nil {
+		return nil, err
+	}
+	schedule, err := s.repo.Get(ctx, novelID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, novelID, schedule)
+}
+
+// Save creates or replaces the schedule of a novel.
+func (s *ReleaseScheduleService) Save(ctx context.Context, novelID uuid.UUID, in models.ReleaseScheduleInput, updatedBy *uuid.UUID) (*models.ReleaseScheduleView, error) {
+	if err := s.ensureNovel(ctx, novelID); err != nil {
+		return nil, err
+	}
+	schedule, err := NormalizeReleaseSchedule(in)
+	if err != nil {
+		return nil, err
+	}
+	schedule.NovelID = novelID
+	schedule.UpdatedBy = updatedBy
+	if err := s.repo.Upsert(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return s.view(ctx, novelID, schedule)
+}
+
+// Delete removes the schedule of a novel and publishes its queued chapters right away.
+// Returns how many chapters were published.
+func (s *ReleaseScheduleService) Delete(ctx context.Context, novelID uuid.UUID) (int, error) {
+	released, err := s.repo.Delete(ctx, novelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	return released, err
+}
+
+func (s *ReleaseScheduleService) view(ctx context.Context, novelID uuid.UUID, schedule *models.ReleaseSchedule) (*models.ReleaseScheduleView, error) {
+	queued, upcoming, err := s.repo.Pending(ctx, novelID, releasePreview)
+	if err != nil {
+		return nil, err
+	}
+	v := &models.ReleaseScheduleView{
+		Schedule:  schedule,
+		Queued:    queued,
+		Upcoming:  upcoming,
+		NextSlots: []time.Time{},
+	}
+	if schedule == nil || !schedule.Enabled {
+		return v, nil
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return v, nil
+	}
+	after := time.Now()
+	if schedule.LastReleaseAt != nil && schedule.LastReleaseAt.After(after) {
+		after = *schedule.LastReleaseAt
+	}
+	for i := 0; i < 5; i++ {
+		after = nextReleaseSlot(schedule, loc, after)
+		if after.IsZero() {
+			break
+		}
+		v.NextSlots = append(v.NextSlots, after)
+	}
+	return v, nil
+}
+
+// ReleaseDue assigns release times to queued chapters of every enabled schedule for all slots
+// up to now + planAhead. Chapters go live when their release time comes.
+// Returns how many chapters got a release time.
+func (s *ReleaseScheduleService) ReleaseDue(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.repo.ListEnabled(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	var errs []error
+	for i := range schedules {
+		n, err := s.releaseNovel(ctx, &schedules[i], now)
+		total += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("novel %s: %w", schedules[i].NovelID, err))
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+func (s *ReleaseScheduleService) releaseNovel(ctx context.Context, schedule *models.ReleaseSchedule, now time.Time) (int, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timezone %q: %w", schedule.Timezone, err)
+	}
+
+	cursor := schedule.CreatedAt
+	if schedule.LastReleaseAt != nil {
+		cursor = *schedule.LastReleaseAt
+	}
+	if earliest := now.Add(-missedSlotGrace); cursor.Before(earliest) {
+		cursor = earliest
+	}
+
+	horizon := now.Add(s.planAhead)
+	released := 0
+	for slot := nextReleaseSlot(schedule, loc, cursor); !slot.IsZero() && !slot.After(horizon); slot = nextReleaseSlot(schedule, loc, slot) {
+		n, err := s.repo.ReleaseQueued(ctx, schedule.NovelID, slot, schedule.ChaptersPerRelease)
+		if err != nil {
+			return released, err
+		}
+		if n == 0 {
+			break
+		}
+		released += n
+	}
+	return released, nil
+}
+
+// nextReleaseSlot returns the first release time of the schedule strictly after t
+// (zero if the schedule has no release times).
+func nextReleaseSlot(schedule *models.ReleaseSchedule, loc *time.Location, t time.Time) time.Time {
+	days := map[time.Weekday]bool{}
+	for _, d := range schedule.Weekdays {
+		days[time.Weekday(d)] = true
+	}
+
+	local := t.In(loc)
+	for d := 0; d <= 7; d++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+d, 0, 0, 0, 0, loc)
+		if len(days) > 0 && !days[day.Weekday()] {
+			continue
+		}
+		// Times skipped by a DST jump are moved forward by time.Date ("02:30" becomes 03:30),
+		// so the sorted release times are not necessarily in order: take the earliest.
+		var next time.Time
+		for _, hm := range schedule.ReleaseTimes {
+			at, err := time.Parse("15:04", hm)
+			if err != nil {
+				continue
+			}
+			slot := time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+			if slot.After(t) && (next.IsZero() || slot.Before(next)) {
+				next = slot
+			}
+		}
+		if !next.IsZero() {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// NormalizeReleaseSchedule validates the input and converts it to a schedule:
+// release times are sorted and deduplicated, the timezone defaults to Moscow time.
+func NormalizeReleaseSchedule(in models.ReleaseScheduleInput) (*models.ReleaseSchedule, error) {
+	if in.ChaptersPerRelease < 1 || in.ChaptersPerRelease > 100 {
+		return nil, fmt.Errorf("%w: chaptersPerRelease must be between 1 and 100", ErrInvalidReleaseSchedule)
+	}
+
+	tz := strings.TrimSpace(in.Timezone)
+	if tz == "" {
+		tz = defaultReleaseTimezone
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidReleaseSchedule, tz)
+	}
+
+	if len(in.ReleaseTimes) == 0 || len(in.ReleaseTimes) > maxReleaseTimes {
+		return nil, fmt.Errorf("%w: between 1 and %d release times are required", ErrInvalidReleaseSchedule, maxReleaseTimes)
+	}
+	seen := map[string]bool{}
+	times := []string{}
+	for _, raw := range in.ReleaseTimes {
+		at, err := time.Parse("15:04", strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%w: release time %q must be HH:MM", ErrInvalidReleaseSchedule, raw)
+		}
+		hm := at.Format("15:04")
+		if !seen[hm] {
+			seen[hm] = true
+			times = append(times, hm)
+		}
+	}
+	sort.Strings(times)
+
+	days := []int64{}
+	seenDays := map[int]bool{}
+	for _, d := range in.Weekdays {
+		if d < 0 || d > 6 {
+			return nil, fmt.Errorf("%w: weekdays are 0 (Sunday) to 6 (Saturday)", ErrInvalidReleaseSchedule)
+		}
+		if !seenDays[d] {
+			seenDays[d] = true
+			days = append(days, int64(d))
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+
+	enabled := true
+	if in.Enabled != nil {
+		enabled = *in.Enabled
+	}
+	return &models.ReleaseSchedule{
+		Enabled:            enabled,
+		ChaptersPerRelease: in.ChaptersPerRelease,
+		ReleaseTimes:       times,
+		Weekdays:           days,
+		Timezone:           tz,
+	}, nil
+}
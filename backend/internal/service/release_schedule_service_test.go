@@ -0,0 +1,197 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"novels-backend/internal/domain/models"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestNextReleaseSlot(t *testing.T) {
+	moscow := mustLocation(t, "Europe/Moscow")
+	berlin := mustLocation(t, "Europe/Berlin")
+	at := func(loc *time.Location, month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name     string
+		times    []string
+		weekdays []int64
+		loc      *time.Location
+		after    time.Time
+		want     time.Time
+	}{
+		{
+			name:  "later slot today",
+			times: []string{"09:00", "18:00"},
+			loc:   moscow,
+			after: at(moscow, time.October, 19, 10, 0),
+			want:  at(moscow, time.October, 19, 18, 0),
+		},
+		{
+			name:  "every slot passed today",
+			times: []string{"09:00", "18:00"},
+			loc:   moscow,
+			after: at(moscow, time.October, 19, 18, 30),
+			want:  at(moscow, time.October, 20, 9, 0),
+		},
+		{
+			name:  "a slot equal to the cursor is not returned again",
+			times: []string{"09:00", "18:00"},
+			loc:   moscow,
+			after: at(moscow, time.October, 19, 9, 0),
+			want:  at(moscow, time.October, 19, 18, 0),
+		},
+		{
+			name:  "cursor in UTC is compared in the schedule timezone",
+			times: []string{"00:15"},
+			loc:   moscow,
+			after: time.Date(2026, time.October, 19, 20, 30, 0, 0, time.UTC), // 23:30 MSK
+			want:  at(moscow, time.October, 20, 0, 15),
+		},
+		{
+			name:     "weekdays skip to the next allowed day",
+			times:    []string{"12:00"},
+			weekdays: []int64{int64(time.Monday), int64(time.Wednesday)},
+			loc:      moscow,
+			after:    at(moscow, time.October, 19, 13, 0), // Monday
+			want:     at(moscow, time.October, 21, 12, 0),
+		},
+		{
+			name:     "wraps around to next week",
+			times:    []string{"12:00"},
+			weekdays: []int64{int64(time.Monday), int64(time.Wednesday)},
+			loc:      moscow,
+			after:    at(moscow, time.October, 21, 12, 0), // Wednesday
+			want:     at(moscow, time.October, 26, 12, 0),
+		},
+		{
+			name:     "single weekday with today's slot passed is a week later",
+			times:    []string{"09:00"},
+			weekdays: []int64{int64(time.Monday)},
+			loc:      moscow,
+			after:    at(moscow, time.October, 19, 10, 0),
+			want:     at(moscow, time.October, 26, 9, 0),
+		},
+		{
+			name:  "wall clock is kept across the spring DST change",
+			times: []string{"09:00"},
+			loc:   berlin,
+			after: at(berlin, time.March, 28, 9, 0), // 08:00 UTC, CET
+			want:  time.Date(2026, time.March, 29, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "wall clock is kept across the autumn DST change",
+			times: []string{"09:00"},
+			loc:   berlin,
+			after: at(berlin, time.October, 24, 9, 0), // 07:00 UTC, CEST
+			want:  time.Date(2026, time.October, 25, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "time skipped by spring DST moves forward an hour",
+			times: []string{"02:30"},
+			loc:   berlin,
+			after: at(berlin, time.March, 29, 0, 0),
+			want:  at(berlin, time.March, 29, 3, 30),
+		},
+		{
+			name:  "skipped time is not released before an earlier-listed later time",
+			times: []string{"02:30", "03:00"},
+			loc:   berlin,
+			after: at(berlin, time.March, 29, 0, 0),
+			want:  at(berlin, time.March, 29, 3, 0),
+		},
+		{
+			name:  "invalid release times are ignored",
+			times: []string{"25:00", "10:00"},
+			loc:   moscow,
+			after: at(moscow, time.October, 19, 9, 0),
+			want:  at(moscow, time.October, 19, 10, 0),
+		},
+		{
+			name:  "no release times",
+			loc:   moscow,
+			after: at(moscow, time.October, 19, 9, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &models.ReleaseSchedule{ReleaseTimes: tt.times, Weekdays: tt.weekdays}
+			got := nextReleaseSlot(schedule, tt.loc, tt.after)
+			if !got.Equal(tt.want) {
+				t.Errorf("nextReleaseSlot = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNextReleaseSlotAcrossDST walks the slots through both DST changes: every day gets each
+// release time exactly once, in order.
+func TestNextReleaseSlotAcrossDST(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	schedule := &models.ReleaseSchedule{ReleaseTimes: []string{"01:30", "02:30", "03:00"}}
+
+	for _, start := range []time.Time{
+		time.Date(2026, time.March, 27, 12, 0, 0, 0, berlin),
+		time.Date(2026, time.October, 23, 12, 0, 0, 0, berlin),
+	} {
+		perDay := map[string]int{}
+		prev := start
+		for i := 0; i < 3*4; i++ {
+			slot := nextReleaseSlot(schedule, berlin, prev)
+			if !slot.After(prev) {
+				t.Fatalf("slot %v is not after %v", slot, prev)
+			}
+			perDay[slot.Format("2006-01-02")]++
+			prev = slot
+		}
+		if len(perDay) != 4 {
+			t.Errorf("releases per day = %v, want 4 days", perDay)
+		}
+		for day, n := range perDay {
+			if n != 3 {
+				t.Errorf("%s has %d releases, want 3", day, n)
+			}
+		}
+	}
+}
+
+func TestNormalizeReleaseSchedule(t *testing.T) {
+	got, err := NormalizeReleaseSchedule(models.ReleaseScheduleInput{
+		ChaptersPerRelease: 2,
+		ReleaseTimes:       []string{"18:00", " 9:05 ", "18:00"},
+	})
+	if err != nil {
+		t.Fatalf("NormalizeReleaseSchedule: %v", err)
+	}
+	if got.Timezone != defaultReleaseTimezone {
+		t.Errorf("timezone = %q, want %q", got.Timezone, defaultReleaseTimezone)
+	}
+	if len(got.ReleaseTimes) != 2 || got.ReleaseTimes[0] != "09:05" || got.ReleaseTimes[1] != "18:00" {
+		t.Errorf("release times = %v, want sorted and deduplicated", got.ReleaseTimes)
+	}
+
+	for _, in := range []models.ReleaseScheduleInput{
+		{ChaptersPerRelease: 0, ReleaseTimes: []string{"09:00"}},
+		{ChaptersPerRelease: 1},
+		{ChaptersPerRelease: 1, ReleaseTimes: []string{"9am"}},
+		{ChaptersPerRelease: 1, ReleaseTimes: []string{"09:00"}, Timezone: "Mars/Olympus"},
+		{ChaptersPerRelease: 1, ReleaseTimes: []string{"09:00"}, Weekdays: []int{7}},
+	} {
+		if _, err := NormalizeReleaseSchedule(in); !errors.Is(err, ErrInvalidReleaseSchedule) {
+			t.Errorf("NormalizeReleaseSchedule(%+v) error = %v, want ErrInvalidReleaseSchedule", in, err)
+		}
+	}
+}
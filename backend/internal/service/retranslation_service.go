@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
+	"novels-backend/internal/repository"
+)
+
+var (
+	ErrRetranslationNotAllowed    = errors.New("retranslation requests require a subscription with this feature")
+	ErrRetranslationQuotaExceeded = errors.New("retranslation request limit for the subscription period reached")
+	ErrRetranslationExists        = errors.New("a retranslation of this chapter is already requested")
+	ErrInvalidRetranslation       = errors.New("invalid retranslation request")
+)
+
+// RetranslationPipeline is the part of the translation pipeline used to re-run a chapter.
+type RetranslationPipeline interface {
+	EnqueueRetranslation(ctx context.Context, chapterID, novelID uuid.UUID, targetLang string, paragraphFrom, paragraphTo *int) (uuid.UUID, error)
+	SourceLang() string
+	TargetLangs() []string
+}
+
+// RetranslationService handles premium chapter retranslation requests: users flag a chapter,
+// moderators accept or reject, accepted requests re-run the translation pipeline.
+type RetranslationService struct {
+	repo         *repository.RetranslationRepository
+	chapterRepo  *repository.ChapterRepository
+	subRepo      *repository.SubscriptionRepository
+	pipeline     RetranslationPipeline
+	defaultLimit int
+	logger       zerolog.Logger
+}
+
+func NewRetranslationService(
+	repo *repository.RetranslationRepository,
+	chapterRepo *repository.ChapterRepository,
+	subRepo *repository.SubscriptionRepository,
+	pipeline RetranslationPipeline,
+	defaultLimit int,
+	logger zerolog.Logger,
+) *RetranslationService {
+	return &RetranslationService{
+		repo:         repo,
+		chapterRepo:  chapterRepo,
+		subRepo:      subRepo,
+		pipeline:     pipeline,
+		defaultLimit: defaultLimit,
+		logger:       logger.With().Str("service", "retranslation").Logger(),
+	}
+}
+
+// Register closes accepted requests when their translation job finishes.
+func (s *RetranslationService) Register(bus *events.Bus) {
+	if bus == nil {
+		return
+	}
+	bus.Subscribe(events.EventTranslationJobFinished, "retranslation.finish_requests", func(ctx context.Context, evt events.Event) error {
+		e := evt.(events.TranslationJobFinished)
+		return s.onJobFinished(ctx, e)
+	}, events.WithTimeout(30*time.Second), events.WithRetry(3, time.Second))
+}
+
+func (s *RetranslationService) onJobFinished(ctx context.Context, e events.TranslationJobFinished) error {
+	reqs, err := s.repo.ListAcceptedForJob(ctx, e.JobID, e.ChapterID, e.TargetLang)
+	if err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		status := models.RetranslationStatusCompleted
+		var errMsg *string
+		switch models.TranslationJobStatus(e.Status) {
+		case models.TranslationJobStatusFailed:
+			status = models.RetranslationStatusFailed
+			errMsg = &e.Error
+		case models.TranslationJobStatusSkipped:
+			status = models.RetranslationStatusFailed
+			msg := "nothing to translate: chapter has no source text"
+			errMsg = &msg
+		}
+		if err := s.repo.Finish(ctx, req.ID, status, errMsg); err != nil {
+			return err
+		}
+		// The user is not charged for a retranslation we could not carry out.
+		if status == models.RetranslationStatusFailed {
+			if err := s.repo.Refund(ctx, req.ID, "retranslation_failed"); err != nil {
+				return err
+			}
+		}
+		s.logger.Info().
+			Str("request_id", req.ID.String()).
+			Str("job_id", e.JobID.String()).
+			Str("status", string(status)).
+			Msg("Retranslation request finished")
+	}
+	return nil
+}
+
+// billingPeriod returns the subscription period containing now: subscriptions are billed
+// monthly or yearly from starts_at.
+func billingPeriod(sub *models.Subscription, now time.Time) (time.Time, time.Time) {
+	months := 1
+	if sub.Plan != nil && sub.Plan.Period == "yearly" {
+		months = 12
+	}
+	start := sub.StartsAt
+	for {
+		next := start.AddDate(0, months, 0)
+		if next.After(now) {
+			if sub.EndsAt.Before(next) {
+				next = sub.EndsAt
+			}
+			return start, next
+		}
+		start = next
+	}
+}
+
+// quotaContext resolves the active subscription, its current period and the allowance.
+func (s *RetranslationService) quotaContext(ctx context.Context, userID uuid.UUID) (*models.Subscription, time.Time, time.Time, int, error) {
+	sub, err := s.subRepo.GetActiveSubscription(ctx, userID)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, 0, err
+	}
+	if sub == nil || sub.Plan == nil || !sub.Plan.Features.CanRequestRetranslation {
+		return nil, time.Time{}, time.Time{}, 0, ErrRetranslationNotAllowed
+	}
+	limit := sub.Plan.Features.RetranslationRequests
+	if limit <= 0 {
+		limit = s.defaultLimit
+	}
+	start, end := billingPeriod(sub, time.Now())
+	return sub, start, end, limit, nil
+}
+
+// GetQuota returns the user's allowance for the current subscription period.
+func (s *RetranslationService) GetQuota(ctx context.Context, userID uuid.UUID) (*models.RetranslationQuota, error) {
+	_, start, end, limit, err := s.quotaContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.repo.QuotaUsed(ctx, userID, start)
+	if err != nil {
+		return nil, err
+	}
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &models.RetranslationQuota{
+		Limit:       limit,
+		Used:        used,
+		Remaining:   remaining,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	}, nil
+}
+
+// ListTransactions returns the user's quota ledger.
+func (s *RetranslationService) ListTransactions(ctx context.Context, userID uuid.UUID, limit int) ([]models.RetranslationQuotaTransaction, error) {
+	return s.repo.ListTransactions(ctx, userID, limit)
+}
+
+// CreateRequest flags a chapter translation for retranslation.
+func (s *RetranslationService) CreateRequest(ctx context.Context, userID, chapterID uuid.UUID, in models.CreateRetranslationRequest) (*models.RetranslationRequest, error) {
+	in.Lang = strings.TrimSpace(in.Lang)
+	in.Reason = strings.TrimSpace(in.Reason)
+	if in.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidRetranslation)
+	}
+	if len([]rune(in.Reason)) > 1000 {
+		return nil, fmt.Errorf("%w: reason is too long", ErrInvalidRetranslation)
+	}
+	if !s.isTargetLang(in.Lang) {
+		return nil, fmt.Errorf("%w: unsupported language %q", ErrInvalidRetranslation, in.Lang)
+	}
+	if (in.ParagraphFrom == nil) != (in.ParagraphTo == nil) {
+		return nil, fmt.Errorf("%w: paragraphFrom and paragraphTo must be set together", ErrInvalidRetranslation)
+	}
+	if in.ParagraphFrom != nil && (*in.ParagraphFrom < 1 || *in.ParagraphTo < *in.ParagraphFrom) {
+		return nil, fmt.Errorf("%w: invalid paragraph range", ErrInvalidRetranslation)
+	}
+
+	sub, start, _, limit, err := s.quotaContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	novelID, err := s.chapterRepo.GetNovelIDByChapter(ctx, chapterID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrChapterNotFound
+		}
+		return nil, err
+	}
+
+	// Fast path; the unique index behind Create settles concurrent submissions.
+	open, err := s.repo.HasOpen(ctx, chapterID, in.Lang)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, ErrRetranslationExists
+	}
+
+	req := &models.RetranslationRequest{
+		UserID:        userID,
+		ChapterID:     chapterID,
+		NovelID:       novelID,
+		Lang:          in.Lang,
+		ParagraphFrom: in.ParagraphFrom,
+		ParagraphTo:   in.ParagraphTo,
+		Reason:        in.Reason,
+	}
+	created, err := s.repo.Create(ctx, req, &sub.ID, start, limit)
+	if errors.Is(err, repository.ErrRetranslationOpen) {
+		return nil, ErrRetranslationExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrRetranslationQuotaExceeded
+	}
+	return req, nil
+}
+
+func (s *RetranslationService) isTargetLang(lang string) bool {
+	if lang == "" || lang == s.pipeline.SourceLang() {
+		return false
+	}
+	for _, l := range s.pipeline.TargetLangs() {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRequests lists requests for moderators (status filter) or for a user.
+func (s *RetranslationService) ListRequests(ctx context.Context, status *models.RetranslationRequestStatus, userID *uuid.UUID, page, limit int) (*models.RetranslationRequestListResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if page <= 0 {
+		page = 1
+	}
+	requests, total, err := s.repo.List(ctx, status, userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RetranslationRequestListResponse{
+		Requests:   requests,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+// Accept approves a pending request, keeps the current translation on the request and
+// re-runs the pipeline for the chapter.
+func (s *RetranslationService) Accept(ctx context.Context, requestID, moderatorID uuid.UUID, comment string) (*models.RetranslationRequest, error) {
+	req, err := s.repo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrNotFound
+	}
+	if req.Status != models.RetranslationStatusPending {
+		return nil, ErrInvalidAction
+	}
+
+	var prevContent, prevSource *string
+	current, err := s.chapterRepo.GetContent(ctx, req.ChapterID, req.Lang)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		prevContent, prevSource = &current.Content, &current.Source
+	}
+
+	ok, err := s.repo.Accept(ctx, requestID, moderatorID, comment, prevContent, prevSource)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidAction
+	}
+
+	jobID, err := s.pipeline.EnqueueRetranslation(ctx, req.ChapterID, req.NovelID, req.Lang, req.ParagraphFrom, req.ParagraphTo)
+	if err != nil {
+		msg := err.Error()
+		if ferr := s.repo.Finish(ctx, requestID, models.RetranslationStatusFailed, &msg); ferr != nil {
+			s.logger.Error().Err(ferr).Str("request_id", requestID.String()).Msg("Failed to mark retranslation request failed")
+		}
+		if rerr := s.repo.Refund(ctx, requestID, "retranslation_failed"); rerr != nil {
+			s.logger.Error().Err(rerr).Str("request_id", requestID.String()).Msg("Failed to refund retranslation request")
+		}
+		return nil, fmt.Errorf("enqueue retranslation: %w", err)
+	}
+	if err := s.repo.SetJob(ctx, requestID, jobID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("request_id", requestID.String()).
+		Str("chapter_id", req.ChapterID.String()).
+		Str("lang", req.Lang).
+		Str("job_id", jobID.String()).
+		Msg("Retranslation request accepted")
+	return s.repo.GetByID(ctx, requestID)
+}
+
+// Reject declines a pending request. The request still counts against the user's allowance.
+func (s *RetranslationService) Reject(ctx context.Context, requestID, moderatorID uuid.UUID, comment string) error {
+	ok, err := s.repo.Reject(ctx, requestID, moderatorID, comment)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		req, err := s.repo.GetByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			return ErrNotFound
+		}
+		return ErrInvalidAction
+	}
+	return nil
+}
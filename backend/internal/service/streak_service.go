@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/repository"
+)
+
+var (
+	ErrInvalidTimezone   = errors.New("invalid timezone")
+	ErrStreakFreezeLimit = errors.New("streak freeze limit reached")
+)
+
+// StreakService tracks daily activity streaks. A day (local to the user's timezone) counts when
+// the user logs in or reads a chapter; the first activity of a day awards daily-login XP, and
+// streak milestones (models.StreakMilestones) award bonus XP and daily votes. Streak freezes,
+// bought with tickets, cover missed days.
+type StreakService struct {
+	repo            *repository.StreakRepository
+	xp              *XPService
+	tickets         *TicketService
+	defaultTimezone *time.Location
+	freezeTicket    models.TicketType
+	freezePrice     int
+	maxFreezes      int
+	logger          zerolog.Logger
+}
+
+func NewStreakService(
+	repo *repository.StreakRepository,
+	xp *XPService,
+	tickets *TicketService,
+	defaultTimezone string,
+	freezeTicket string,
+	freezePrice, maxFreezes int,
+	logger zerolog.Logger,
+) *StreakService {
+	logger = logger.With().Str("service", "streaks").Logger()
+	loc, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		logger.Warn().Err(err).Str("timezone", defaultTimezone).Msg("Unknown default streak timezone, using UTC")
+		loc = time.UTC
+	}
+	return &StreakService{
+		repo:            repo,
+		xp:              xp,
+		tickets:         tickets,
+		defaultTimezone: loc,
+		freezeTicket:    models.TicketType(freezeTicket),
+		freezePrice:     max(freezePrice, 1),
+		maxFreezes:      max(maxFreezes, 1),
+		logger:          logger,
+	}
+}
+
+// location returns the timezone tz or the default one.
+func (s *StreakService) location(tz string) *time.Location {
+	if tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	return s.defaultTimezone
+}
+
+// Location returns the timezone the user's days are counted in.
+func (s *StreakService) Location(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	tz, err := s.repo.Timezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.location(tz), nil
+}
+
+// localDay returns the date of t in loc as midnight UTC, the form streak days are stored in.
+func localDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// RecordActivity records that the user was active today. Activity tracking must not fail the
+// login or the progress save it comes from, so errors are logged rather than returned.
+func (s *StreakService) RecordActivity(ctx context.Context, userID uuid.UUID, kind models.ActivityKind) {
+	if err := s.recordActivity(ctx, userID, kind); err != nil {
+		s.logger.Error().Err(err).
+			Str("user_id", userID.String()).
+			Str("kind", string(kind)).
+			Msg("Failed to record activity")
+	}
+}
+
+func (s *StreakService) recordActivity(ctx context.Context, userID uuid.UUID, kind models.ActivityKind) error {
+	state, err := s.repo.ActivityState(ctx, userID)
+	if err != nil || state == nil {
+		return err
+	}
+	day := localDay(time.Now(), s.location(state.Timezone))
+	// Progress is saved many times a day; only the first activity of each kind needs a write.
+	if state.Recorded(day, kind) {
+		return nil
+	}
+
+	streak, adv, err := s.repo.RecordActivity(ctx, userID, day, kind)
+	if err != nil {
+		return err
+	}
+	if adv.NewDay {
+		if len(adv.Frozen) > 0 {
+			s.logger.Info().
+				Str("user_id", userID.String()).
+				Int("frozen_days", len(adv.Frozen)).
+				Int("streak", streak.Current).
+				Msg("Streak kept by freezes")
+		}
+		// The ref is derived from the date, so the bonus is awarded once per local day.
+		ref := uuid.NewSHA1(userID, []byte(day.Format(models.DateLayout)))
+		if err := s.xp.AwardXP(ctx, userID, models.XPEventDailyLogin, 0, "day", ref); err != nil {
+			return fmt.Errorf("award daily login xp: %w", err)
+		}
+	}
+	return s.grantRewards(ctx, userID)
+}
+
+// grantRewards grants the user's pending milestone rewards. A failed reward stays pending and
+// is retried on the next recorded activity.
+func (s *StreakService) grantRewards(ctx context.Context, userID uuid.UUID) error {
+	rewards, err := s.repo.ClaimPendingRewards(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, reward := range rewards {
+		if err := s.grantReward(ctx, reward); err != nil {
+			if rerr := s.repo.ReleaseReward(ctx, reward); rerr != nil {
+				s.logger.Error().Err(rerr).Str("user_id", userID.String()).Msg("Failed to release streak reward")
+			}
+			return fmt.Errorf("grant %d-day streak reward: %w", reward.Milestone, err)
+		}
+	}
+	return nil
+}
+
+func (s *StreakService) grantReward(ctx context.Context, reward repository.StreakReward) error {
+	m := models.StreakMilestoneFor(reward.Milestone)
+	if m == nil {
+		return nil // the milestone was removed
+	}
+	if m.XP > 0 {
+		key := fmt.Sprintf("%d:%s", m.Days, reward.StartedOn.Format(models.DateLayout))
+		ref := uuid.NewSHA1(reward.UserID, []byte(key))
+		if err := s.xp.AwardXP(ctx, reward.UserID, models.XPEventStreakMilestone, m.XP, "streak_milestone", ref); err != nil {
+			return err
+		}
+	}
+	if m.DailyVotes > 0 {
+		if err := s.tickets.GrantTickets(ctx, models.GrantTicketRequest{
+			UserID: reward.UserID,
+			Type:   models.TicketTypeDailyVote,
+			Amount: m.DailyVotes,
+			Reason: models.ReasonLevelReward,
+		}); err != nil {
+			return err
+		}
+	}
+	s.logger.Info().
+		Str("user_id", reward.UserID.String()).
+		Int("milestone", m.Days).
+		Msg("Streak milestone rewarded")
+	return nil
+}
+
+// GetStreak returns the user's streak as it stands today.
+func (s *StreakService) GetStreak(ctx context.Context, userID uuid.UUID) (*models.StreakInfo, error) {
+	streak, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.Location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	today := localDay(time.Now(), loc)
+
+	current, needed := streak.CurrentOn(today)
+	info := &models.StreakInfo{
+		Current:       current,
+		Longest:       streak.Longest,
+		ActiveToday:   streak.LastActiveOn != nil && !streak.LastActiveOn.Before(today),
+		Today:         today.Format(models.DateLayout),
+		Timezone:      loc.String(),
+		FreezesNeeded: needed,
+		Freezes:       streak.Freezes,
+		MaxFreezes:    s.maxFreezes,
+		FreezePrice:   s.freezePrice,
+		FreezeTicket:  s.freezeTicket,
+		NextMilestone: models.NextStreakMilestone(current),
+	}
+	if current > 0 && streak.StartedOn != nil {
+		started := streak.StartedOn.Format(models.DateLayout)
+		info.StartedOn = &started
+	}
+	if streak.LastActiveOn != nil {
+		last := streak.LastActiveOn.Format(models.DateLayout)
+		info.LastActiveOn = &last
+	}
+	return info, nil
+}
+
+// PurchaseFreeze buys a streak freeze with tickets.
+func (s *StreakService) PurchaseFreeze(ctx context.Context, userID uuid.UUID) (*models.StreakInfo, error) {
+	balance, err := s.tickets.GetBalance(ctx, userID, s.freezeTicket)
+	if err != nil {
+		return nil, err
+	}
+	if balance < s.freezePrice {
+		return nil, ErrInsufficientTickets
+	}
+
+	added, err := s.repo.AddFreeze(ctx, userID, s.maxFreezes)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrStreakFreezeLimit
+	}
+	if err := s.tickets.SpendTickets(ctx, userID, models.SpendTicketRequest{
+		Type:    s.freezeTicket,
+		Amount:  s.freezePrice,
+		RefType: "streak_freeze",
+	}); err != nil {
+		if rerr := s.repo.RemoveFreeze(ctx, userID); rerr != nil {
+			s.logger.Error().Err(rerr).Str("user_id", userID.String()).Msg("Failed to take back unpaid streak freeze")
+		}
+		return nil, err
+	}
+
+	return s.GetStreak(ctx, userID)
+}
+
+// SetTimezone sets the timezone the user's days are counted in. Moving to a timezone that is
+// behind does not let a day count twice: days before the last active day are ignored.
+func (s *StreakService) SetTimezone(ctx context.Context, userID uuid.UUID, tz string) error {
+	tz = strings.TrimSpace(tz)
+	if tz == "" || tz == "Local" {
+		return ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidTimezone, tz)
+	}
+	return s.repo.SetTimezone(ctx, userID, tz)
+}
@@ -199,6 +199,8 @@ func (s *SubscriptionService) HasFeature(ctx context.Context, userID uuid.UUID,
 		return info.Features.CanEditDescriptions, nil
 	case "request_retranslation":
 		return info.Features.CanRequestRetranslation, nil
+	case "export_full":
+		return info.Features.CanExportFull, nil
 	case "priority_support":
 		return info.Features.PrioritySupport, nil
 	case "exclusive_badge":
@@ -208,6 +210,18 @@ func (s *SubscriptionService) HasFeature(ctx context.Context, userID uuid.UUID,
 	}
 }
 
+// EarlyAccessHours returns how many hours before release the user can read scheduled chapters
+func (s *SubscriptionService) EarlyAccessHours(ctx context.Context, userID uuid.UUID) (int, error) {
+	info, err := s.subRepo.GetUserSubscriptionInfo(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !info.HasActiveSubscription || info.Features == nil || info.Features.EarlyAccessHours < 0 {
+		return 0, nil
+	}
+	return info.Features.EarlyAccessHours, nil
+}
+
 // IsPremium checks if user has any active subscription
 func (s *SubscriptionService) IsPremium(ctx context.Context, userID uuid.UUID) (bool, error) {
 	info, err := s.subRepo.GetUserSubscriptionInfo(ctx, userID)
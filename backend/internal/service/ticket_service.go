@@ -56,6 +56,8 @@ func (s *TicketService) SpendTickets(ctx context.Context, userID uuid.UUID, req
 		reason = models.ReasonProposalCreated
 	} else if req.RefType == "translation" {
 		reason = models.ReasonTranslationRequest
+	} else if req.RefType == "streak_freeze" {
+		reason = models.ReasonStreakFreeze
 	}
 	
 	err := s.ticketRepo.SpendTickets(ctx, userID, req.Type, req.Amount, reason, req.RefType, refID)
@@ -142,9 +142,28 @@ func (s *TranslationVotingService) processTranslationWinner(ctx context.Context,
 		nextStatus = models.TranslationTargetStatusWaitingRelease
 	}
 
-	if err := s.repo.UpdateTargetStatus(ctx, top.ID, nextStatus); err != nil {
+	// Status change and event are committed together (see event_outbox).
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.repo.UpdateTargetStatusTx(ctx, tx, top.ID, nextStatus); err != nil {
 		return err
 	}
+	if s.events != nil {
+		if err := s.events.PublishTx(ctx, tx, events.TranslationVoteWinnerSelected{
+			TargetID:   top.ID,
+			NovelID:    top.NovelID,
+			ProposalID: top.ProposalID,
+		}); err != nil {
+			return fmt.Errorf("publish winner event: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
 
 	s.logger.Info().
 		Str("target_id", top.ID.String()).
@@ -152,14 +171,6 @@ func (s *TranslationVotingService) processTranslationWinner(ctx context.Context,
 		Int("tickets", top.TranslationTicketsInvested).
 		Msg("Translation vote winner selected")
 
-	if s.events != nil {
-		_ = s.events.Publish(ctx, events.TranslationVoteWinnerSelected{
-			TargetID:   top.ID,
-			NovelID:    top.NovelID,
-			ProposalID: top.ProposalID,
-		})
-	}
-
 	return nil
 }
 
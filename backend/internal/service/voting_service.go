@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"novels-backend/internal/domain/models"
 	"novels-backend/internal/events"
+	"novels-backend/internal/orchestrator"
 	"novels-backend/internal/repository"
 	"github.com/rs/zerolog"
 )
@@ -24,23 +25,31 @@ var (
 )
 
 type VotingService struct {
-	votingRepo *repository.VotingRepository
-	ticketRepo *repository.TicketRepository
-	logger     zerolog.Logger
-	events     *events.Bus
+	votingRepo  *repository.VotingRepository
+	ticketRepo  *repository.TicketRepository
+	sourceLinks *repository.SourceLinksRepository
+	importers   orchestrator.Importers
+	logger      zerolog.Logger
+	events      *events.Bus
 }
 
+// NewVotingService creates the voting service. sourceLinks and importers are used to detect
+// duplicate proposals; with a nil sourceLinks no duplicate checks are made.
 func NewVotingService(
 	votingRepo *repository.VotingRepository,
 	ticketRepo *repository.TicketRepository,
+	sourceLinks *repository.SourceLinksRepository,
+	importers orchestrator.Importers,
 	eventBus *events.Bus,
 	logger zerolog.Logger,
 ) *VotingService {
 	return &VotingService{
-		votingRepo: votingRepo,
-		ticketRepo: ticketRepo,
-		logger:     logger.With().Str("service", "voting").Logger(),
-		events:     eventBus,
+		votingRepo:  votingRepo,
+		ticketRepo:  ticketRepo,
+		sourceLinks: sourceLinks,
+		importers:   importers,
+		logger:      logger.With().Str("service", "voting").Logger(),
+		events:      eventBus,
 	}
 }
 
@@ -48,19 +57,37 @@ func NewVotingService(
 // PROPOSALS
 // ============================================
 
-// CreateProposal creates a new novel proposal
-func (s *VotingService) CreateProposal(ctx context.Context, userID uuid.UUID, req models.CreateProposalRequest) (*models.NovelProposal, error) {
+// CreateProposal creates a new novel proposal.
+// A book that is already proposed or imported (same canonical source URL) is rejected with a
+// *DuplicateError, or with req.OnDuplicate "merge" resolved to the existing proposal: then
+// merged is true, the existing proposal is returned and no ticket is spent.
+func (s *VotingService) CreateProposal(ctx context.Context, userID uuid.UUID, req models.CreateProposalRequest) (*models.NovelProposal, bool, error) {
+	if err := validateProposalOriginalLink(req.OriginalLink); err != nil {
+		return nil, false, err
+	}
+	importerName, canonicalURL, err := s.canonicalLink(req.OriginalLink)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.findDuplicates(ctx, canonicalURL, req.Title, req.IgnoreSimilarTitles, nil); err != nil {
+		var dup *DuplicateError
+		if errors.As(err, &dup) && errors.Is(err, ErrDuplicateProposal) && req.OnDuplicate == models.OnDuplicateMerge {
+			existing, err := s.mergeIntoProposal(ctx, userID, req, dup.Matches[0])
+			if err != nil {
+				return nil, false, err
+			}
+			return existing, true, nil
+		}
+		return nil, false, err
+	}
+
 	// Check if user has novel request ticket
 	balance, err := s.ticketRepo.GetBalance(ctx, userID, models.TicketTypeNovelRequest)
 	if err != nil {
-		return nil, fmt.Errorf("check balance: %w", err)
+		return nil, false, fmt.Errorf("check balance: %w", err)
 	}
 	if balance < 1 {
-		return nil, ErrInsufficientTickets
-	}
-
-	if err := validateProposalOriginalLink(req.OriginalLink); err != nil {
-		return nil, err
+		return nil, false, ErrInsufficientTickets
 	}
 	
 	// Create proposal
@@ -83,25 +110,39 @@ func (s *VotingService) CreateProposal(ctx context.Context, userID uuid.UUID, re
 	// Start transaction
 	tx, err := s.votingRepo.BeginTx(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("begin tx: %w", err)
+		return nil, false, fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback()
 	
 	// Create proposal
 	err = s.votingRepo.CreateProposal(ctx, proposal)
 	if err != nil {
-		return nil, fmt.Errorf("create proposal: %w", err)
+		return nil, false, fmt.Errorf("create proposal: %w", err)
+	}
+	if s.sourceLinks != nil {
+		claimed, err := s.sourceLinks.ClaimForProposal(ctx, canonicalURL, importerName, proposal.ID)
+		if err == nil && !claimed {
+			// Proposed concurrently by someone else since findDuplicates.
+			err = s.findDuplicates(ctx, canonicalURL, "", true, &proposal.ID)
+			if err == nil {
+				err = ErrDuplicateProposal
+			}
+		}
+		if err != nil {
+			_ = s.votingRepo.DeleteProposal(ctx, proposal.ID)
+			return nil, false, err
+		}
 	}
 	
 	// Spend novel request ticket
 	err = s.ticketRepo.SpendTickets(ctx, userID, models.TicketTypeNovelRequest, 1,
 		models.ReasonProposalCreated, "proposal", &proposal.ID)
 	if err != nil {
-		return nil, fmt.Errorf("spend ticket: %w", err)
+		return nil, false, fmt.Errorf("spend ticket: %w", err)
 	}
 	
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit: %w", err)
+		return nil, false, fmt.Errorf("commit: %w", err)
 	}
 	
 	s.logger.Info().
@@ -110,7 +151,7 @@ func (s *VotingService) CreateProposal(ctx context.Context, userID uuid.UUID, re
 		Str("title", proposal.Title).
 		Msg("Proposal created")
 	
-	return proposal, nil
+	return proposal, false, nil
 }
 
 // GetProposal returns a proposal by ID
@@ -159,10 +200,28 @@ func (s *VotingService) UpdateProposal(ctx context.Context, id, userID uuid.UUID
 	}
 	
 	// Apply updates
+	canonicalURL := ""
 	if req.OriginalLink != nil {
 		if err := validateProposalOriginalLink(*req.OriginalLink); err != nil {
 			return nil, err
 		}
+		importerName, canonical, err := s.canonicalLink(*req.OriginalLink)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.findDuplicates(ctx, canonical, "", true, &proposal.ID); err != nil {
+			return nil, err
+		}
+		if s.sourceLinks != nil {
+			claimed, err := s.sourceLinks.ClaimForProposal(ctx, canonical, importerName, proposal.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !claimed {
+				return nil, ErrDuplicateProposal
+			}
+		}
+		canonicalURL = canonical
 		proposal.OriginalLink = *req.OriginalLink
 	}
 	if req.Title != nil {
@@ -191,6 +250,12 @@ func (s *VotingService) UpdateProposal(ctx context.Context, id, userID uuid.UUID
 	if err != nil {
 		return nil, fmt.Errorf("update proposal: %w", err)
 	}
+	if canonicalURL != "" && s.sourceLinks != nil {
+		// The previous link is free for other proposals now.
+		if err := s.sourceLinks.ReleaseProposal(ctx, proposal.ID, canonicalURL); err != nil {
+			s.logger.Error().Err(err).Str("proposal_id", proposal.ID.String()).Msg("Failed to release previous source link")
+		}
+	}
 	
 	return proposal, nil
 }
@@ -511,29 +576,46 @@ func (s *VotingService) processVotingWinner(ctx context.Context, force bool) err
 		return nil
 	}
 	
+	poll, err := s.votingRepo.GetActivePoll(ctx)
+	if err != nil {
+		return fmt.Errorf("get active poll: %w", err)
+	}
+
 	// Daily vote winner => selected for release/import.
 	// Translation is handled by a separate translation voting leaderboard.
-	err = s.votingRepo.UpdateProposalStatus(ctx, topProposal.ID, models.ProposalStatusAccepted, nil, nil)
+	// The status change, poll closing and the event are committed together, so a crash
+	// cannot leave an accepted proposal whose import never starts.
+	tx, err := s.votingRepo.BeginTx(ctx)
 	if err != nil {
-		return fmt.Errorf("update proposal status: %w", err)
+		return fmt.Errorf("begin tx: %w", err)
 	}
-	
-	// Close current poll
-	poll, err := s.votingRepo.GetActivePoll(ctx)
-	if err == nil && poll != nil {
-		s.votingRepo.ClosePoll(ctx, poll.ID)
+	defer tx.Rollback()
+
+	if err := s.votingRepo.UpdateProposalStatusTx(ctx, tx, topProposal.ID, models.ProposalStatusAccepted); err != nil {
+		return err
 	}
-	
+	if poll != nil {
+		if err := s.votingRepo.ClosePollTx(ctx, tx, poll.ID); err != nil {
+			return fmt.Errorf("close poll: %w", err)
+		}
+	}
+
+	// Parent logic emits event; child (import orchestration/parsers) reacts to it.
+	if s.events != nil {
+		if err := s.events.PublishTx(ctx, tx, events.DailyVoteWinnerSelected{ProposalID: topProposal.ID}); err != nil {
+			return fmt.Errorf("publish winner event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
 	s.logger.Info().
 		Str("proposal_id", topProposal.ID.String()).
 		Str("title", topProposal.Title).
 		Int("vote_score", topProposal.VoteScore).
 		Msg("Daily vote winner selected")
 
-	// Parent logic emits event; child (import orchestration/parsers) reacts to it.
-	if s.events != nil {
-		_ = s.events.Publish(ctx, events.DailyVoteWinnerSelected{ProposalID: topProposal.ID})
-	}
-	
 	return nil
 }
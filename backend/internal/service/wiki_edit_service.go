@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"novels-backend/internal/cache"
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
 	"novels-backend/internal/repository"
 
 	"github.com/google/uuid"
@@ -15,7 +18,9 @@ type WikiEditService struct {
 	wikiRepo        *repository.WikiEditRepository
 	novelRepo       *repository.NovelRepository
 	userRepo        *repository.UserRepository
+	glossaryRepo    *repository.GlossaryRepository
 	subscriptionSvc *SubscriptionService
+	catalog         *CatalogCache
 }
 
 // NewWikiEditService creates a new wiki edit service
@@ -23,20 +28,24 @@ func NewWikiEditService(
 	wikiRepo *repository.WikiEditRepository,
 	novelRepo *repository.NovelRepository,
 	userRepo *repository.UserRepository,
+	glossaryRepo *repository.GlossaryRepository,
 	subscriptionSvc *SubscriptionService,
+	catalog *CatalogCache,
 ) *WikiEditService {
 	return &WikiEditService{
 		wikiRepo:        wikiRepo,
 		novelRepo:       novelRepo,
 		userRepo:        userRepo,
+		glossaryRepo:    glossaryRepo,
 		subscriptionSvc: subscriptionSvc,
+		catalog:         catalog,
 	}
 }
 
 // CreateEditRequest creates a new edit request (Premium users only)
 func (s *WikiEditService) CreateEditRequest(ctx context.Context, userID, novelID uuid.UUID, req *models.CreateEditRequestRequest) (*models.NovelEditRequest, error) {
 	// Check if user can edit descriptions (Premium feature)
-	canEdit, err := s.subscriptionSvc.HasFeature(ctx, userID, "can_edit_descriptions")
+	canEdit, err := s.subscriptionSvc.HasFeature(ctx, userID, "edit_descriptions")
 	if err != nil {
 		return nil, fmt.Errorf("checking feature: %w", err)
 	}
@@ -53,6 +62,18 @@ func (s *WikiEditService) CreateEditRequest(ctx context.Context, userID, novelID
 		return nil, fmt.Errorf("novel not found")
 	}
 
+	// Glossary entries are proposed as JSON; validate before anything is stored
+	for i := range req.Changes {
+		if req.Changes[i].FieldType != models.EditFieldGlossary {
+			continue
+		}
+		newValue, err := normalizeGlossaryChange(req.Changes[i].NewValue)
+		if err != nil {
+			return nil, err
+		}
+		req.Changes[i].NewValue = newValue
+	}
+
 	// Check if user already has a pending request for this novel
 	hasPending, err := s.wikiRepo.HasPendingEditRequest(ctx, userID, novelID)
 	if err != nil {
@@ -76,7 +97,12 @@ func (s *WikiEditService) CreateEditRequest(ctx context.Context, userID, novelID
 	// Add changes
 	for _, change := range req.Changes {
 		// Get old value for the field
-		oldValue, err := s.getFieldValue(ctx, novelID, change.FieldType, change.Lang)
+		var oldValue string
+		if change.FieldType == models.EditFieldGlossary {
+			oldValue, err = s.getGlossaryValue(ctx, novelID, change.NewValue)
+		} else {
+			oldValue, err = s.getFieldValue(ctx, novelID, change.FieldType, change.Lang)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("getting old value: %w", err)
 		}
@@ -142,6 +168,43 @@ func (s *WikiEditService) getFieldValue(ctx context.Context, novelID uuid.UUID,
 	return "", nil
 }
 
+// normalizeGlossaryChange validates a proposed glossary entry and returns it re-encoded.
+func normalizeGlossaryChange(value string) (string, error) {
+	var in models.GlossaryTermInput
+	if err := json.Unmarshal([]byte(value), &in); err != nil {
+		return "", fmt.Errorf("%w: newValue must be a JSON glossary entry", ErrInvalidGlossaryTerm)
+	}
+	if err := NormalizeGlossaryInput(&in); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// getGlossaryValue returns the current glossary entry for the proposed source term, if any.
+func (s *WikiEditService) getGlossaryValue(ctx context.Context, novelID uuid.UUID, newValue string) (string, error) {
+	var in models.GlossaryTermInput
+	if err := json.Unmarshal([]byte(newValue), &in); err != nil {
+		return "", err
+	}
+	term, err := s.glossaryRepo.GetBySourceTerm(ctx, novelID, in.SourceTerm)
+	if err != nil || term == nil {
+		return "", err
+	}
+	b, err := json.Marshal(models.GlossaryTermInput{
+		SourceTerm:   term.SourceTerm,
+		Translations: term.Translations,
+		Notes:        term.Notes,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 // GetEditRequest gets an edit request by ID
 func (s *WikiEditService) GetEditRequest(ctx context.Context, id uuid.UUID) (*models.NovelEditRequest, error) {
 	request, err := s.wikiRepo.GetEditRequestByID(ctx, id)
@@ -294,7 +357,12 @@ func (s *WikiEditService) ReviewEditRequest(ctx context.Context, requestID, mode
 
 	switch req.Action {
 	case "approve":
-		return s.wikiRepo.ApproveEditRequest(ctx, requestID, moderatorID, req.Comment)
+		if err := s.wikiRepo.ApproveEditRequest(ctx, requestID, moderatorID, req.Comment); err != nil {
+			return err
+		}
+		// Approved edits change titles, descriptions and genres shown in catalog lists
+		s.catalog.Changed(ctx, &request.NovelID, events.CatalogReasonWiki)
+		return nil
 	case "reject":
 		return s.wikiRepo.RejectEditRequest(ctx, requestID, moderatorID, req.Comment)
 	default:
@@ -349,12 +417,18 @@ func (s *WikiEditService) GetUserEditRequests(ctx context.Context, userID uuid.U
 
 // GetPlatformStats gets global platform statistics
 func (s *WikiEditService) GetPlatformStats(ctx context.Context) (*models.PlatformStats, error) {
-	return s.wikiRepo.GetPlatformStats(ctx)
+	return cache.Load(ctx, s.catalog.statsNS(), "platform", s.wikiRepo.GetPlatformStats)
 }
 
 // RefreshPlatformStats refreshes the cached statistics
 func (s *WikiEditService) RefreshPlatformStats(ctx context.Context) error {
-	return s.wikiRepo.RefreshPlatformStats(ctx)
+	if err := s.wikiRepo.RefreshPlatformStats(ctx); err != nil {
+		return err
+	}
+	if ns := s.catalog.statsNS(); ns != nil {
+		return ns.Invalidate(ctx)
+	}
+	return nil
 }
 
 // CountPendingRequests counts pending edit requests (for moderation badge)
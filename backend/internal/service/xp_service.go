@@ -2,18 +2,25 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
 	"novels-backend/internal/repository"
 )
 
 type XPService struct {
 	xpRepo *repository.XPRepository
+	bus    *events.Bus
 }
 
-func NewXPService(xpRepo *repository.XPRepository) *XPService {
-	return &XPService{xpRepo: xpRepo}
+// NewXPService creates the XP service. Awarded XP is announced as XPAwarded events on bus
+// (achievements are evaluated on them, see AchievementService); bus may be nil.
+func NewXPService(xpRepo *repository.XPRepository, bus *events.Bus) *XPService {
+	return &XPService{xpRepo: xpRepo, bus: bus}
 }
 
 // GetUserXP retrieves user's XP and level info
@@ -53,10 +60,13 @@ func (s *XPService) AwardXP(ctx context.Context, userID uuid.UUID, eventType mod
 		RefID:   refID,
 	}
 	
-	err = s.xpRepo.AddXPEvent(ctx, event)
+	inserted, err := s.xpRepo.AddXPEvent(ctx, event)
 	if err != nil {
 		return err
 	}
+	if !inserted {
+		return nil // Awarded concurrently
+	}
 	
 	// Update total XP
 	_, err = s.xpRepo.CreateOrUpdateXP(ctx, userID, amount)
@@ -64,8 +74,12 @@ func (s *XPService) AwardXP(ctx context.Context, userID uuid.UUID, eventType mod
 		return err
 	}
 	
-	// Check for achievements (async in production)
-	go s.checkAchievements(context.Background(), userID)
+	if s.bus != nil {
+		evt := events.XPAwarded{UserID: userID, Type: string(eventType), Delta: amount, RefType: refType, RefID: refID}
+		if err := s.bus.Publish(ctx, evt); err != nil {
+			return fmt.Errorf("publish xp event: %w", err)
+		}
+	}
 	
 	return nil
 }
@@ -83,6 +97,35 @@ func (s *XPService) GetXPEvents(ctx context.Context, userID uuid.UUID, page, lim
 	return s.xpRepo.GetXPEvents(ctx, userID, limit, offset)
 }
 
+// maxClawbackEvents bounds the events revoked by one request.
+const maxClawbackEvents = 500
+
+// ErrInvalidClawback is returned for a clawback request without events, with too many of them
+// or without a reason.
+var ErrInvalidClawback = errors.New("invalid clawback request")
+
+// Clawback revokes XP events of a user (e.g. farmed chapter reads) and takes their XP back.
+// A revoked read_chapter event still counts as awarded, so the chapter gives no XP again.
+func (s *XPService) Clawback(ctx context.Context, userID, adminID uuid.UUID, req models.ClawbackXPRequest) (*models.ClawbackXPResult, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	switch {
+	case len(req.EventIDs) == 0 || len(req.EventIDs) > maxClawbackEvents:
+		return nil, fmt.Errorf("%w: between 1 and %d eventIds are required", ErrInvalidClawback, maxClawbackEvents)
+	case req.Reason == "":
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidClawback)
+	}
+
+	revoked, xp, err := s.xpRepo.RevokeEvents(ctx, userID, req.EventIDs, adminID, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.ClawbackXPResult{Revoked: len(revoked), XPTotal: xp.XPTotal, NewLevel: xp.Level}
+	for _, e := range revoked {
+		result.XPTaken += e.Delta
+	}
+	return result, nil
+}
+
 // GetLeaderboard retrieves XP leaderboard
 func (s *XPService) GetLeaderboard(ctx context.Context, limit int) ([]models.XPLeaderboardEntry, error) {
 	if limit < 1 || limit > 100 {
@@ -101,70 +144,6 @@ func (s *XPService) GetUserStats(ctx context.Context, userID uuid.UUID) (*models
 	return s.xpRepo.GetUserStats(ctx, userID)
 }
 
-// GetAchievements retrieves all achievements
-func (s *XPService) GetAchievements(ctx context.Context) ([]models.Achievement, error) {
-	return s.xpRepo.GetAchievements(ctx)
-}
-
-// GetUserAchievements retrieves user's unlocked achievements
-func (s *XPService) GetUserAchievements(ctx context.Context, userID uuid.UUID) ([]models.UserAchievement, error) {
-	return s.xpRepo.GetUserAchievements(ctx, userID)
-}
-
-// checkAchievements checks and unlocks achievements for user
-func (s *XPService) checkAchievements(ctx context.Context, userID uuid.UUID) {
-	stats, err := s.xpRepo.GetUserStats(ctx, userID)
-	if err != nil {
-		return
-	}
-	
-	achievements, err := s.xpRepo.GetAchievements(ctx)
-	if err != nil {
-		return
-	}
-	
-	for _, achievement := range achievements {
-		// Check if already unlocked
-		hasIt, _ := s.xpRepo.HasAchievement(ctx, userID, achievement.ID)
-		if hasIt {
-			continue
-		}
-		
-		// Check conditions based on achievement code
-		shouldUnlock := false
-		switch achievement.Code {
-		case "first_chapter":
-			shouldUnlock = stats.ChaptersRead >= 1
-		case "bookworm_10":
-			shouldUnlock = stats.ChaptersRead >= 10
-		case "bookworm_100":
-			shouldUnlock = stats.ChaptersRead >= 100
-		case "bookworm_1000":
-			shouldUnlock = stats.ChaptersRead >= 1000
-		case "first_comment":
-			shouldUnlock = stats.CommentsCount >= 1
-		case "commentator_10":
-			shouldUnlock = stats.CommentsCount >= 10
-		case "commentator_100":
-			shouldUnlock = stats.CommentsCount >= 100
-		case "first_bookmark":
-			shouldUnlock = stats.BookmarksCount >= 1
-		case "collector_10":
-			shouldUnlock = stats.BookmarksCount >= 10
-		case "collector_100":
-			shouldUnlock = stats.BookmarksCount >= 100
-		}
-		
-		if shouldUnlock {
-			_ = s.xpRepo.UnlockAchievement(ctx, userID, achievement.ID)
-			// Award XP for achievement
-			if achievement.XPReward > 0 {
-				_ = s.AwardXP(ctx, userID, models.XPEventType("achievement"), achievement.XPReward, "achievement", achievement.ID)
-			}
-		}
-	}
-}
-
 // InitializeUserXP creates initial XP record for new user
 func (s *XPService) InitializeUserXP(ctx context.Context, userID uuid.UUID) error {
 	_, err := s.xpRepo.CreateOrUpdateXP(ctx, userID, 0)
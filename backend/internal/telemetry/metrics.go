@@ -0,0 +1,180 @@
+// Package telemetry holds the Prometheus metrics and OpenTelemetry tracing of the backend.
+//
+// Metrics are registered on Registry and served by Handler (GET /metrics). Recording helpers
+// are safe to call from any package and cost a map lookup when nothing scrapes them.
+package telemetry
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "novels"
+
+// Registry holds every metric of the process.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route pattern, method and status code.",
+	}, []string{"method", "route", "status"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route pattern, method and status class.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by statement type and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 5},
+	}, []string{"op", "outcome"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_run_duration_seconds",
+		Help:      "Background job run duration by job and outcome.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 15, 60, 300, 900, 3600},
+	}, []string{"job", "outcome"})
+	jobLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful run of a background job.",
+	}, []string{"job"})
+
+	importChaptersDone = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "import_run_chapters_done",
+		Help:      "Chapters processed by an import run in progress.",
+	}, []string{"run_id", "importer"})
+	importChaptersTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "import_run_chapters_total",
+		Help:      "Chapters found at the source by an import run in progress.",
+	}, []string{"run_id", "importer"})
+	importRunsFinished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "import_runs_finished_total",
+		Help:      "Import runs that stopped on this instance, by importer and outcome.",
+	}, []string{"importer", "outcome"})
+
+	eventsPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_published_total",
+		Help:      "Domain events published, by event.",
+	}, []string{"event"})
+	eventHandlerCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_handler_calls_total",
+		Help:      "Event handler calls by event, handler and outcome (ok, error, timeout).",
+	}, []string{"event", "handler", "outcome"})
+	eventHandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "event_handler_duration_seconds",
+		Help:      "Event handler latency by event and handler.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"event", "handler"})
+
+	parserDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "parser_request_duration_seconds",
+		Help:      "Parser-service call latency by site and outcome.",
+		Buckets:   []float64{.5, 1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+	}, []string{"site", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		dbQueryDuration,
+		jobDuration, jobLastSuccess,
+		importChaptersDone, importChaptersTotal, importRunsFinished,
+		eventsPublished, eventHandlerCalls, eventHandlerDuration,
+		parserDuration,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDBStats exports the connection pool stats of db (open, in use, waits, ...).
+// Only the first pool registered under a name is exported.
+func RegisterDBStats(db *sql.DB, name string) {
+	_ = Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// ObserveHTTP records a served request. route is the chi route pattern, not the raw path,
+// so that /novels/{slug} is one series.
+func ObserveHTTP(method, route string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code[:1]+"xx").Observe(d.Seconds())
+}
+
+// ObserveQuery records a database query; op is the statement type (select, insert, ...).
+func ObserveQuery(op string, d time.Duration, err error) {
+	dbQueryDuration.WithLabelValues(op, outcome(err)).Observe(d.Seconds())
+}
+
+// ObserveJob records a background job run.
+func ObserveJob(job string, d time.Duration, err error) {
+	jobDuration.WithLabelValues(job, outcome(err)).Observe(d.Seconds())
+	if err == nil {
+		jobLastSuccess.WithLabelValues(job).SetToCurrentTime()
+	}
+}
+
+// ImportProgress updates the progress gauges of an import run.
+func ImportProgress(runID, importer string, done, total int) {
+	importChaptersDone.WithLabelValues(runID, importer).Set(float64(done))
+	importChaptersTotal.WithLabelValues(runID, importer).Set(float64(total))
+}
+
+// ImportFinished drops the progress gauges of a run and counts its outcome
+// (succeeded, failed, cancelled, paused or interrupted).
+func ImportFinished(runID, importer, outcome string) {
+	importChaptersDone.DeleteLabelValues(runID, importer)
+	importChaptersTotal.DeleteLabelValues(runID, importer)
+	importRunsFinished.WithLabelValues(importer, outcome).Inc()
+}
+
+// EventPublished counts a published domain event.
+func EventPublished(event string) {
+	eventsPublished.WithLabelValues(event).Inc()
+}
+
+// ObserveEventHandler records an event handler call; timeout marks calls cut off by the handler timeout.
+func ObserveEventHandler(event, handler string, d time.Duration, err error, timeout bool) {
+	result := outcome(err)
+	if timeout {
+		result = "timeout"
+	}
+	eventHandlerCalls.WithLabelValues(event, handler, result).Inc()
+	eventHandlerDuration.WithLabelValues(event, handler).Observe(d.Seconds())
+}
+
+// ObserveParser records a parser-service call.
+func ObserveParser(site string, d time.Duration, err error) {
+	parserDuration.WithLabelValues(site, outcome(err)).Observe(d.Seconds())
+}
@@ -0,0 +1,120 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "novels-backend"
+
+// TracingOptions configures span export.
+type TracingOptions struct {
+	// Endpoint of an OTLP/HTTP collector, "localhost:4318" or "http://localhost:4318".
+	// Empty disables export: spans are still created (and trace context propagated) but dropped.
+	Endpoint string
+	// Insecure sends spans over plain HTTP; ignored when Endpoint has a scheme.
+	Insecure    bool
+	ServiceName string
+	// SampleRatio is the share of new traces recorded; incoming sampled traces are always kept.
+	SampleRatio float64
+}
+
+// InitTracing installs the global tracer provider and W3C trace context propagation.
+// The returned function flushes pending spans and must be called on shutdown.
+func InitTracing(ctx context.Context, opts TracingOptions, logger zerolog.Logger) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var clientOpts []otlptracehttp.Option
+	switch {
+	case strings.Contains(opts.Endpoint, "://"):
+		clientOpts = append(clientOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	case opts.Insecure:
+		clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(opts.Endpoint), otlptracehttp.WithInsecure())
+	default:
+		clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn().Err(err).Str("component", "tracing").Msg("Trace export error")
+	}))
+	logger.Info().Str("endpoint", opts.Endpoint).Float64("sample_ratio", opts.SampleRatio).Msg("Tracing enabled")
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the backend.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartSpan starts a span as a child of the span in ctx (or a new trace).
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err on span (if any) and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, for handing the trace over to
+// work that runs later (queued jobs). Empty if ctx carries no span.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// WithTraceParent returns ctx continuing the trace of traceparent (see TraceParent).
+func WithTraceParent(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceparent})
+}
+
+// RunJob runs a background job in its own span and records its duration and outcome.
+func RunJob(ctx context.Context, job string, fn func(context.Context) error) error {
+	ctx, span := StartSpan(ctx, "job "+job, attribute.String("job.name", job))
+	start := time.Now()
+	err := fn(ctx)
+	ObserveJob(job, time.Since(start), err)
+	EndSpan(span, err)
+	return err
+}
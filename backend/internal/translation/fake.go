@@ -0,0 +1,24 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+)
+
+// FakeTranslator is a deterministic local provider: every text is returned prefixed with
+// the language pair, e.g. "[zh>ru] 第一章". Useful for development and for checking the pipeline
+// end to end without an external service.
+type FakeTranslator struct{}
+
+func (FakeTranslator) Name() string { return "fake" }
+
+func (FakeTranslator) Translate(ctx context.Context, sourceLang, targetLang string, texts []string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]string, len(texts))
+	for i, t := range texts {
+		out[i] = fmt.Sprintf("[%s>%s] %s", sourceLang, targetLang, t)
+	}
+	return out, nil
+}
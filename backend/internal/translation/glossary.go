@@ -0,0 +1,102 @@
+package translation
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"novels-backend/internal/domain/models"
+)
+
+// Glossary terms are swapped for numbered placeholders before the text is sent to the provider,
+// so a name or rank is rendered the same way in every chapter, and the placeholders are replaced
+// with the glossary target afterwards. A provider that knows the glossary may pick an inflected
+// form with "⟦i:form⟧".
+var placeholderRe = regexp.MustCompile(`⟦\s*(\d+)\s*(?::\s*([^⟦⟧\s]+)\s*)?⟧`)
+
+type glossaryPair struct {
+	source string
+	target string
+	forms  map[string]string
+}
+
+// glossaryFor returns the terms that have a target in lang, longest source first so that
+// "林动天" wins over "林动".
+func glossaryFor(terms []models.GlossaryTerm, lang string) []glossaryPair {
+	out := make([]glossaryPair, 0, len(terms))
+	for _, t := range terms {
+		tr, ok := t.Translations[lang]
+		if !ok || strings.TrimSpace(tr.Target) == "" || t.SourceTerm == "" {
+			continue
+		}
+		out = append(out, glossaryPair{source: t.SourceTerm, target: tr.Target, forms: tr.Forms})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return utf8.RuneCountInString(out[i].source) > utf8.RuneCountInString(out[j].source)
+	})
+	return out
+}
+
+func placeholder(i int) string { return "⟦" + strconv.Itoa(i) + "⟧" }
+
+// protectTerms replaces source terms with placeholders. strings.Replacer tries pairs in
+// argument order at each position, which together with longest-first order gives longest match.
+func protectTerms(text string, pairs []glossaryPair) string {
+	if len(pairs) == 0 {
+		return text
+	}
+	args := make([]string, 0, len(pairs)*2)
+	for i, p := range pairs {
+		args = append(args, p.source, placeholder(i))
+	}
+	return strings.NewReplacer(args...).Replace(text)
+}
+
+// glossaryEntries returns the entries for the placeholders that occur in texts.
+func glossaryEntries(texts []string, pairs []glossaryPair) []GlossaryEntry {
+	if len(pairs) == 0 {
+		return nil
+	}
+	joined := strings.Join(texts, "\n")
+	var out []GlossaryEntry
+	for i, p := range pairs {
+		ph := placeholder(i)
+		if !strings.Contains(joined, ph) {
+			continue
+		}
+		out = append(out, GlossaryEntry{Placeholder: ph, Target: p.target, Forms: p.forms})
+	}
+	return out
+}
+
+// restoreTerms puts glossary targets in place of placeholders, or the requested form when the
+// provider asked for one the term has, and fixes source terms the provider echoed back untranslated.
+func restoreTerms(text string, pairs []glossaryPair) string {
+	if len(pairs) == 0 {
+		return text
+	}
+	text = placeholderRe.ReplaceAllStringFunc(text, func(m string) string {
+		sm := placeholderRe.FindStringSubmatch(m)
+		i, err := strconv.Atoi(sm[1])
+		if err != nil || i < 0 || i >= len(pairs) {
+			return m
+		}
+		if form, ok := pairs[i].forms[sm[2]]; ok && strings.TrimSpace(form) != "" {
+			return form
+		}
+		return pairs[i].target
+	})
+	args := make([]string, 0, len(pairs)*2)
+	for _, p := range pairs {
+		if strings.Contains(p.target, p.source) {
+			continue
+		}
+		args = append(args, p.source, p.target)
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return strings.NewReplacer(args...).Replace(text)
+}
@@ -0,0 +1,189 @@
+package translation
+
+import (
+	"testing"
+
+	"novels-backend/internal/domain/models"
+)
+
+func term(source string, targets map[string]string) models.GlossaryTerm {
+	tr := models.GlossaryTranslations{}
+	for lang, target := range targets {
+		tr[lang] = models.GlossaryTranslation{Target: target}
+	}
+	return models.GlossaryTerm{SourceTerm: source, Translations: tr}
+}
+
+func TestGlossaryFor(t *testing.T) {
+	terms := []models.GlossaryTerm{
+		term("林动", map[string]string{"ru": "Линь Дун", "en": "Lin Dong"}),
+		term("林动天", map[string]string{"ru": "Линь Дунтянь"}),
+		term("元丹境", map[string]string{"ru": "  "}),
+		term("", map[string]string{"ru": "пусто"}),
+		term("小貂", map[string]string{"en": "Little Marten"}),
+	}
+
+	got := glossaryFor(terms, "ru")
+	want := []glossaryPair{{source: "林动天", target: "Линь Дунтянь"}, {source: "林动", target: "Линь Дун"}}
+	if len(got) != len(want) {
+		t.Fatalf("glossaryFor = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].source != want[i].source || got[i].target != want[i].target {
+			t.Errorf("pair %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGlossaryForKeepsForms(t *testing.T) {
+	forms := map[string]string{"gen": "Линь Дуна"}
+	terms := []models.GlossaryTerm{{
+		SourceTerm:   "林动",
+		Translations: models.GlossaryTranslations{"ru": {Target: "Линь Дун", Forms: forms}},
+	}}
+	got := glossaryFor(terms, "ru")
+	if len(got) != 1 || got[0].forms["gen"] != "Линь Дуна" {
+		t.Errorf("glossaryFor = %v, want the term with its forms", got)
+	}
+}
+
+func TestGlossaryEntries(t *testing.T) {
+	pairs := []glossaryPair{
+		{source: "林动天", target: "Линь Дунтянь"},
+		{source: "林动", target: "Линь Дун", forms: map[string]string{"gen": "Линь Дуна"}},
+		{source: "岩", target: "Янь"},
+	}
+	got := glossaryEntries([]string{"⟦1⟧说", "看着⟦2⟧"}, pairs)
+	if len(got) != 2 {
+		t.Fatalf("glossaryEntries = %v, want entries for ⟦1⟧ and ⟦2⟧", got)
+	}
+	if got[0].Placeholder != "⟦1⟧" || got[0].Target != "Линь Дун" || got[0].Forms["gen"] != "Линь Дуна" {
+		t.Errorf("entry 0 = %+v", got[0])
+	}
+	if got[1].Placeholder != "⟦2⟧" || got[1].Forms != nil {
+		t.Errorf("entry 1 = %+v", got[1])
+	}
+	if got := glossaryEntries([]string{"一"}, pairs); got != nil {
+		t.Errorf("glossaryEntries without placeholders = %v", got)
+	}
+}
+
+func TestProtectTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []models.GlossaryTerm
+		want  string
+	}{
+		{
+			name:  "no terms",
+			text:  "林动说",
+			terms: nil,
+			want:  "林动说",
+		},
+		{
+			name: "longest term wins whatever the input order",
+			text: "林动天看着林动",
+			terms: []models.GlossaryTerm{
+				term("林动", map[string]string{"ru": "Линь Дун"}),
+				term("林动天", map[string]string{"ru": "Линь Дунтянь"}),
+			},
+			want: "⟦0⟧看着⟦1⟧",
+		},
+		{
+			name: "overlapping terms at different positions",
+			text: "动天宗的林动",
+			terms: []models.GlossaryTerm{
+				term("林动", map[string]string{"ru": "Линь Дун"}),
+				term("动天宗", map[string]string{"ru": "секта Дунтянь"}),
+			},
+			want: "⟦0⟧的⟦1⟧",
+		},
+		{
+			name:  "case sensitive",
+			text:  "Lin Dong met lin dong",
+			terms: []models.GlossaryTerm{term("Lin Dong", map[string]string{"ru": "Линь Дун"})},
+			want:  "⟦0⟧ met lin dong",
+		},
+		{
+			name:  "terms without a target in the language are left alone",
+			text:  "小貂跑了",
+			terms: []models.GlossaryTerm{term("小貂", map[string]string{"en": "Little Marten"})},
+			want:  "小貂跑了",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := protectTerms(tt.text, glossaryFor(tt.terms, "ru")); got != tt.want {
+				t.Errorf("protectTerms = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreTerms(t *testing.T) {
+	pairs := glossaryFor([]models.GlossaryTerm{
+		term("林动", map[string]string{"ru": "Линь Дун"}),
+		term("林动天", map[string]string{"ru": "Линь Дунтянь"}),
+		term("岩", map[string]string{"ru": "Янь (岩)"}),
+	}, "ru")
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"placeholders", "⟦0⟧ смотрел на ⟦1⟧", "Линь Дунтянь смотрел на Линь Дун"},
+		{"placeholder with spaces added by the provider", "⟦ 1 ⟧ ушёл", "Линь Дун ушёл"},
+		{"unknown placeholder is kept", "⟦7⟧ и ⟦1⟧", "⟦7⟧ и Линь Дун"},
+		{"echoed source term is replaced", "林动 улыбнулся", "Линь Дун улыбнулся"},
+		{"target containing its source is not replaced twice", "⟦2⟧ и 岩", "Янь (岩) и 岩"},
+		{"form of a term without forms falls back to the target", "у ⟦1:gen⟧", "у Линь Дун"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restoreTerms(tt.text, pairs); got != tt.want {
+				t.Errorf("restoreTerms = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreTermsForms(t *testing.T) {
+	pairs := []glossaryPair{{
+		source: "林动",
+		target: "Линь Дун",
+		forms:  map[string]string{"gen": "Линь Дуна", "dat": "Линь Дуну", "ins": " "},
+	}}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"requested form", "у ⟦0:gen⟧ и к ⟦0:dat⟧", "у Линь Дуна и к Линь Дуну"},
+		{"form with spaces added by the provider", "у ⟦ 0 : gen ⟧", "у Линь Дуна"},
+		{"unknown form falls back to the target", "⟦0:voc⟧!", "Линь Дун!"},
+		{"blank form falls back to the target", "с ⟦0:ins⟧", "с Линь Дун"},
+		{"unknown placeholder with a form is kept", "⟦3:gen⟧", "⟦3:gen⟧"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restoreTerms(tt.text, pairs); got != tt.want {
+				t.Errorf("restoreTerms = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectRestoreRoundTrip(t *testing.T) {
+	pairs := glossaryFor([]models.GlossaryTerm{
+		term("林动", map[string]string{"ru": "Линь Дун"}),
+		term("林动天", map[string]string{"ru": "Линь Дунтянь"}),
+	}, "ru")
+
+	protected := protectTerms("林动天和林动", pairs)
+	if got, want := restoreTerms(protected, pairs), "Линь Дунтянь和Линь Дун"; got != want {
+		t.Errorf("round trip = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPTranslator calls a translation service over HTTP:
+//
+//	POST {baseURL}/translate
+//	{"source": "zh", "target": "ru", "texts": ["...", "..."],
+//	 "glossary": [{"placeholder": "⟦0⟧", "target": "Линь Дун", "forms": {"gen": "Линь Дуна"}}]}
+//	-> {"translations": ["...", "..."]}
+//
+// "glossary" is sent only when the texts contain placeholders. The service keeps each
+// placeholder in the output, writing "⟦0:gen⟧" where the sentence needs one of the forms.
+//
+// Any service (or a local stand-in) implementing this contract can be plugged in.
+type HTTPTranslator struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func NewHTTPTranslator(baseURL, apiKey string, timeout time.Duration) *HTTPTranslator {
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+	return &HTTPTranslator{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (t *HTTPTranslator) Name() string { return "http" }
+
+type httpTranslateRequest struct {
+	Source   string          `json:"source"`
+	Target   string          `json:"target"`
+	Texts    []string        `json:"texts"`
+	Glossary []GlossaryEntry `json:"glossary,omitempty"`
+}
+
+type httpTranslateResponse struct {
+	Translations []string `json:"translations"`
+}
+
+func (t *HTTPTranslator) Translate(ctx context.Context, sourceLang, targetLang string, texts []string) ([]string, error) {
+	return t.TranslateWithGlossary(ctx, sourceLang, targetLang, texts, nil)
+}
+
+func (t *HTTPTranslator) TranslateWithGlossary(ctx context.Context, sourceLang, targetLang string, texts []string, glossary []GlossaryEntry) ([]string, error) {
+	b, err := json.Marshal(httpTranslateRequest{Source: sourceLang, Target: targetLang, Texts: texts, Glossary: glossary})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/translate", bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(body))
+		if len(msg) > 2000 {
+			msg = msg[:2000] + "..."
+		}
+		return nil, fmt.Errorf("translation service error: status=%d body=%s", resp.StatusCode, msg)
+	}
+
+	var out httpTranslateResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("decode translation response: %w", err)
+	}
+	if len(out.Translations) != len(texts) {
+		return nil, fmt.Errorf("translation service returned %d texts, expected %d", len(out.Translations), len(texts))
+	}
+	return out.Translations, nil
+}
@@ -0,0 +1,52 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPTranslatorSendsGlossary(t *testing.T) {
+	var got httpTranslateRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translate" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("request %s, auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(httpTranslateResponse{Translations: []string{"у ⟦0:gen⟧"}})
+	}))
+	defer srv.Close()
+
+	tr := NewHTTPTranslator(srv.URL+"/", "key", 0)
+	glossary := []GlossaryEntry{{Placeholder: "⟦0⟧", Target: "Линь Дун", Forms: map[string]string{"gen": "Линь Дуна"}}}
+	out, err := tr.TranslateWithGlossary(context.Background(), "zh", "ru", []string{"⟦0⟧的"}, glossary)
+	if err != nil {
+		t.Fatalf("TranslateWithGlossary: %v", err)
+	}
+	if len(out) != 1 || out[0] != "у ⟦0:gen⟧" {
+		t.Errorf("translations = %q", out)
+	}
+	if len(got.Glossary) != 1 || got.Glossary[0].Forms["gen"] != "Линь Дуна" || got.Source != "zh" || got.Target != "ru" {
+		t.Errorf("request = %+v", got)
+	}
+}
+
+func TestHTTPTranslatorOmitsEmptyGlossary(t *testing.T) {
+	var raw map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&raw)
+		_ = json.NewEncoder(w).Encode(httpTranslateResponse{Translations: []string{"a", "b"}})
+	}))
+	defer srv.Close()
+
+	if _, err := NewHTTPTranslator(srv.URL, "", 0).Translate(context.Background(), "zh", "ru", []string{"一", "二"}); err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if _, ok := raw["glossary"]; ok {
+		t.Errorf("request without placeholders sent a glossary: %s", raw["glossary"])
+	}
+}
@@ -0,0 +1,504 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+	"novels-backend/internal/events"
+	"novels-backend/internal/repository"
+)
+
+var errShuttingDown = errors.New("translation pipeline is shutting down")
+
+// Options configures the translation pipeline.
+type Options struct {
+	SourceLang  string
+	TargetLangs []string
+	Workers     int
+	// BatchSize is the number of paragraphs sent to the provider per call.
+	BatchSize     int
+	PollInterval  time.Duration
+	LockDuration  time.Duration
+	SweepInterval time.Duration
+	WorkerID      string
+}
+
+func (o Options) withDefaults() Options {
+	if o.SourceLang == "" {
+		o.SourceLang = "zh"
+	}
+	if len(o.TargetLangs) == 0 {
+		o.TargetLangs = []string{"ru", "en"}
+	}
+	if o.Workers < 1 {
+		o.Workers = 1
+	}
+	if o.BatchSize < 1 {
+		o.BatchSize = 40
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = 5 * time.Second
+	}
+	if o.LockDuration <= 0 {
+		o.LockDuration = 10 * time.Minute
+	}
+	if o.SweepInterval <= 0 {
+		o.SweepInterval = 30 * time.Minute
+	}
+	if o.WorkerID == "" {
+		host, _ := os.Hostname()
+		o.WorkerID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return o
+}
+
+// Pipeline machine-translates chapters of novels whose translation target is `translating`.
+// Chapters are queued per target language in translation_jobs and written back to
+// chapter_contents with source "auto"; manual translations are never overwritten.
+// Terms from the novel's glossary are kept out of MT and rendered with their glossary target.
+type Pipeline struct {
+	jobs       *repository.TranslationJobsRepository
+	chapters   *repository.ChapterRepository
+	targets    *repository.TranslationVotingRepository
+	glossary   *repository.GlossaryRepository
+	translator Translator
+	bus        *events.Bus
+	opts       Options
+	logger     zerolog.Logger
+
+	wake     chan struct{}
+	stopCh   chan struct{}
+	cancel   context.CancelCauseFunc
+	drainMu  sync.Mutex
+	draining bool
+	active   sync.WaitGroup // jobs in progress
+	wg       sync.WaitGroup
+}
+
+func NewPipeline(
+	jobs *repository.TranslationJobsRepository,
+	chapters *repository.ChapterRepository,
+	targets *repository.TranslationVotingRepository,
+	glossary *repository.GlossaryRepository,
+	translator Translator,
+	bus *events.Bus,
+	opts Options,
+	logger zerolog.Logger,
+) *Pipeline {
+	return &Pipeline{
+		jobs:       jobs,
+		chapters:   chapters,
+		targets:    targets,
+		glossary:   glossary,
+		translator: translator,
+		bus:        bus,
+		opts:       opts.withDefaults(),
+		logger:     logger.With().Str("component", "translation_pipeline").Logger(),
+		wake:       make(chan struct{}, 1),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Register subscribes the pipeline to translation lifecycle events.
+func (p *Pipeline) Register() {
+	if p.bus == nil {
+		return
+	}
+
+	// Winner of translation voting moved to `translating` (released novels only;
+	// announced proposals are picked up once released, see below).
+	p.bus.Subscribe(events.EventTranslationVoteWinnerSelected, "translation_pipeline.enqueue_winner", func(ctx context.Context, evt events.Event) error {
+		e := evt.(events.TranslationVoteWinnerSelected)
+		if e.NovelID == nil {
+			return nil
+		}
+		_, err := p.EnqueueNovel(ctx, *e.NovelID)
+		return err
+	}, events.Async(), events.WithTimeout(time.Minute))
+
+	// waiting_release -> translating happens when the proposal is imported. Async handlers start
+	// after the sync ones registered before them, so the target is already bound here.
+	p.bus.Subscribe(events.EventProposalReleased, "translation_pipeline.enqueue_released", func(ctx context.Context, evt events.Event) error {
+		e := evt.(events.ProposalReleased)
+		if p.targets == nil {
+			return nil
+		}
+		t, err := p.targets.GetTargetByNovelID(ctx, e.NovelID)
+		if err != nil || t == nil || t.Status != models.TranslationTargetStatusTranslating {
+			return err
+		}
+		_, err = p.EnqueueNovel(ctx, e.NovelID)
+		return err
+	}, events.Async(), events.WithTimeout(time.Minute))
+}
+
+// EnqueueNovel queues all untranslated chapters of a novel into the configured target languages.
+func (p *Pipeline) EnqueueNovel(ctx context.Context, novelID uuid.UUID) (int64, error) {
+	n, err := p.jobs.EnqueueNovel(ctx, novelID, p.opts.SourceLang, p.opts.TargetLangs)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		p.logger.Info().Str("novel_id", novelID.String()).Int64("jobs", n).Msg("Chapters queued for translation")
+		p.notify()
+	}
+	return n, nil
+}
+
+// EnqueueChapter (re)queues one chapter translation into targetLang.
+func (p *Pipeline) EnqueueChapter(ctx context.Context, chapterID, novelID uuid.UUID, targetLang string) (uuid.UUID, error) {
+	id, err := p.jobs.EnqueueChapter(ctx, chapterID, novelID, p.opts.SourceLang, targetLang)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	p.notify()
+	return id, nil
+}
+
+// EnqueueRetranslation queues a re-run of one chapter translation that may replace a manual
+// translation; paragraphFrom/paragraphTo limit it to a paragraph range.
+func (p *Pipeline) EnqueueRetranslation(ctx context.Context, chapterID, novelID uuid.UUID, targetLang string, paragraphFrom, paragraphTo *int) (uuid.UUID, error) {
+	id, err := p.jobs.EnqueueRetranslation(ctx, chapterID, novelID, p.opts.SourceLang, targetLang, paragraphFrom, paragraphTo)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	p.notify()
+	return id, nil
+}
+
+// SourceLang returns the language chapters are translated from.
+func (p *Pipeline) SourceLang() string { return p.opts.SourceLang }
+
+// TargetLangs returns the configured target languages.
+func (p *Pipeline) TargetLangs() []string { return p.opts.TargetLangs }
+
+func (p *Pipeline) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start implements jobs.Worker.
+func (p *Pipeline) Start(ctx context.Context) {
+	if p.translator == nil {
+		p.logger.Warn().Msg("No translation provider configured, translation workers not started")
+		return
+	}
+	p.logger.Info().
+		Str("provider", p.translator.Name()).
+		Strs("target_langs", p.opts.TargetLangs).
+		Int("workers", p.opts.Workers).
+		Msg("Starting translation pipeline")
+
+	// In-flight provider calls are cancelled on Stop, not when the scheduler ctx is cancelled.
+	runCtx, cancel := context.WithCancelCause(context.Background())
+	p.cancel = cancel
+
+	for i := 0; i < p.opts.Workers; i++ {
+		p.wg.Add(1)
+		go p.runWorker(ctx, runCtx)
+	}
+	p.wg.Add(1)
+	go p.runSweeper(ctx)
+}
+
+// Drain implements jobs.Drainer: workers stop claiming jobs and the jobs in progress are given
+// until ctx is done to finish before Stop cancels them.
+func (p *Pipeline) Drain(ctx context.Context) {
+	p.drainMu.Lock()
+	p.draining = true
+	p.drainMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// startJob reserves a slot for a job unless the pipeline is draining.
+func (p *Pipeline) startJob() bool {
+	p.drainMu.Lock()
+	defer p.drainMu.Unlock()
+	if p.draining {
+		return false
+	}
+	p.active.Add(1)
+	return true
+}
+
+// Stop implements jobs.Worker.
+func (p *Pipeline) Stop() {
+	close(p.stopCh)
+	if p.cancel != nil {
+		p.cancel(errShuttingDown)
+	}
+	p.wg.Wait()
+}
+
+// runSweeper periodically queues chapters added to translating novels after the initial enqueue
+// (e.g. by source sync) and anything missed by events.
+func (p *Pipeline) runSweeper(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.opts.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if p.targets == nil {
+				continue
+			}
+			novelIDs, err := p.targets.ListTranslatingNovelIDs(ctx)
+			if err != nil {
+				p.logger.Error().Err(err).Msg("Failed to list translating novels")
+				continue
+			}
+			for _, id := range novelIDs {
+				if _, err := p.EnqueueNovel(ctx, id); err != nil {
+					p.logger.Error().Err(err).Str("novel_id", id.String()).Msg("Failed to enqueue novel translation")
+				}
+			}
+		}
+	}
+}
+
+func (p *Pipeline) runWorker(ctx, runCtx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			default:
+			}
+			if !p.startJob() {
+				break
+			}
+			job, err := p.jobs.Claim(ctx, p.opts.WorkerID, p.opts.LockDuration)
+			if err != nil {
+				p.active.Done()
+				p.logger.Error().Err(err).Msg("Failed to claim translation job")
+				break
+			}
+			if job == nil {
+				p.active.Done()
+				break
+			}
+			p.runJob(runCtx, job)
+			p.active.Done()
+		}
+
+		select {
+		case <-p.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-p.wake:
+		}
+	}
+}
+
+func (p *Pipeline) runJob(ctx context.Context, job *models.TranslationJob) {
+	status, err := p.process(ctx, job)
+	if err == nil {
+		if err := p.jobs.Complete(context.Background(), job.ID, status, p.translator.Name()); err != nil {
+			p.logger.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to complete translation job")
+			return
+		}
+		p.publishFinished(job, status, "")
+		return
+	}
+
+	backoff := time.Duration(job.Attempts*job.Attempts) * 30 * time.Second
+	if errors.Is(context.Cause(ctx), errShuttingDown) {
+		backoff = 0
+	}
+	next, ferr := p.jobs.Fail(context.Background(), job.ID, err.Error(), backoff)
+	if ferr != nil {
+		p.logger.Error().Err(ferr).Str("job_id", job.ID.String()).Msg("Failed to record translation job failure")
+	}
+	p.logger.Warn().
+		Err(err).
+		Str("job_id", job.ID.String()).
+		Str("chapter_id", job.ChapterID.String()).
+		Str("target_lang", job.TargetLang).
+		Int("attempt", job.Attempts).
+		Msg("Translation job failed")
+	if next == models.TranslationJobStatusFailed {
+		p.publishFinished(job, next, err.Error())
+	}
+}
+
+func (p *Pipeline) publishFinished(job *models.TranslationJob, status models.TranslationJobStatus, errMsg string) {
+	if p.bus == nil {
+		return
+	}
+	evt := events.TranslationJobFinished{
+		JobID:      job.ID,
+		ChapterID:  job.ChapterID,
+		NovelID:    job.NovelID,
+		TargetLang: job.TargetLang,
+		Status:     string(status),
+		Error:      errMsg,
+	}
+	if err := p.bus.Publish(context.Background(), evt); err != nil {
+		p.logger.Error().Err(err).Str("job_id", job.ID.String()).Msg("Translation job finished handler failed")
+	}
+}
+
+func (p *Pipeline) process(ctx context.Context, job *models.TranslationJob) (models.TranslationJobStatus, error) {
+	src, err := p.chapters.GetContent(ctx, job.ChapterID, job.SourceLang)
+	if err != nil {
+		return "", err
+	}
+	if src == nil || strings.TrimSpace(src.Content) == "" {
+		return models.TranslationJobStatusSkipped, nil
+	}
+	existing, err := p.chapters.GetContent(ctx, job.ChapterID, job.TargetLang)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil && existing.Source == "manual" && !job.Overwrite {
+		return models.TranslationJobStatusSkipped, nil
+	}
+
+	var terms []models.GlossaryTerm
+	if p.glossary != nil {
+		if terms, err = p.glossary.List(ctx, job.NovelID); err != nil {
+			return "", err
+		}
+	}
+
+	var translated string
+	if job.ParagraphFrom != nil && job.ParagraphTo != nil && existing != nil {
+		translated, err = p.retranslateRange(ctx, job, src.Content, existing.Content, terms)
+	} else {
+		translated, err = p.TranslateText(ctx, job.SourceLang, job.TargetLang, src.Content, terms)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	saved, err := p.chapters.SaveAutoContent(ctx, job.ChapterID, job.TargetLang, translated, job.Overwrite, "translation_job:"+job.ID.String())
+	if err != nil {
+		return "", err
+	}
+	if !saved {
+		return models.TranslationJobStatusSkipped, nil
+	}
+	return models.TranslationJobStatusDone, nil
+}
+
+// retranslateRange re-translates paragraphs [ParagraphFrom, ParagraphTo] (1-based, blank lines
+// not counted) and splices them into the existing translation. When the existing translation
+// does not line up paragraph-for-paragraph with the source, the whole chapter is retranslated.
+func (p *Pipeline) retranslateRange(ctx context.Context, job *models.TranslationJob, source, existing string, terms []models.GlossaryTerm) (string, error) {
+	srcLines := strings.Split(source, "\n")
+	srcIdx := nonBlankLines(srcLines)
+	dstLines := strings.Split(existing, "\n")
+	dstIdx := nonBlankLines(dstLines)
+
+	from, to := *job.ParagraphFrom, *job.ParagraphTo
+	if len(srcIdx) != len(dstIdx) || from < 1 || from > len(srcIdx) {
+		return p.TranslateText(ctx, job.SourceLang, job.TargetLang, source, terms)
+	}
+	if to > len(srcIdx) {
+		to = len(srcIdx)
+	}
+
+	part := make([]string, 0, to-from+1)
+	for _, i := range srcIdx[from-1 : to] {
+		part = append(part, srcLines[i])
+	}
+	out, err := p.TranslateText(ctx, job.SourceLang, job.TargetLang, strings.Join(part, "\n"), terms)
+	if err != nil {
+		return "", err
+	}
+	outLines := strings.Split(out, "\n")
+	if len(outLines) != len(part) {
+		return "", fmt.Errorf("retranslated range has %d paragraphs, expected %d", len(outLines), len(part))
+	}
+	for k, line := range outLines {
+		dstLines[dstIdx[from-1+k]] = line
+	}
+	return strings.Join(dstLines, "\n"), nil
+}
+
+func nonBlankLines(lines []string) []int {
+	idx := make([]int, 0, len(lines))
+	for i, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// TranslateText translates a chapter paragraph by paragraph, batching calls to the provider.
+// Blank lines are preserved as-is. Glossary terms with a target in targetLang are applied.
+func (p *Pipeline) TranslateText(ctx context.Context, sourceLang, targetLang, text string, terms []models.GlossaryTerm) (string, error) {
+	if p.translator == nil {
+		return "", fmt.Errorf("no translation provider configured")
+	}
+	pairs := glossaryFor(terms, targetLang)
+	lines := strings.Split(text, "\n")
+	idx := nonBlankLines(lines)
+
+	for start := 0; start < len(idx); start += p.opts.BatchSize {
+		end := start + p.opts.BatchSize
+		if end > len(idx) {
+			end = len(idx)
+		}
+		batch := make([]string, 0, end-start)
+		for _, i := range idx[start:end] {
+			batch = append(batch, protectTerms(strings.TrimSpace(lines[i]), pairs))
+		}
+		out, err := p.translate(ctx, sourceLang, targetLang, batch, pairs)
+		if err != nil {
+			return "", err
+		}
+		if len(out) != len(batch) {
+			return "", fmt.Errorf("provider %s returned %d texts, expected %d", p.translator.Name(), len(out), len(batch))
+		}
+		for k, i := range idx[start:end] {
+			lines[i] = restoreTerms(out[k], pairs)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// translate sends a batch to the provider, along with the glossary entries it uses when the
+// provider can take them, so it can choose the inflected form a sentence needs.
+func (p *Pipeline) translate(ctx context.Context, sourceLang, targetLang string, batch []string, pairs []glossaryPair) ([]string, error) {
+	if gt, ok := p.translator.(GlossaryTranslator); ok {
+		if entries := glossaryEntries(batch, pairs); len(entries) > 0 {
+			return gt.TranslateWithGlossary(ctx, sourceLang, targetLang, batch, entries)
+		}
+	}
+	return p.translator.Translate(ctx, sourceLang, targetLang, batch)
+}
@@ -0,0 +1,222 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"novels-backend/internal/domain/models"
+)
+
+// countingTranslator is the fake provider that remembers every batch it was sent.
+type countingTranslator struct {
+	FakeTranslator
+	batches [][]string
+}
+
+func (c *countingTranslator) Translate(ctx context.Context, sourceLang, targetLang string, texts []string) ([]string, error) {
+	c.batches = append(c.batches, append([]string(nil), texts...))
+	return c.FakeTranslator.Translate(ctx, sourceLang, targetLang, texts)
+}
+
+// shortTranslator drops the last text of every batch.
+type shortTranslator struct{ FakeTranslator }
+
+func (s shortTranslator) Translate(ctx context.Context, sourceLang, targetLang string, texts []string) ([]string, error) {
+	out, err := s.FakeTranslator.Translate(ctx, sourceLang, targetLang, texts)
+	return out[:len(out)-1], err
+}
+
+// inflectingTranslator is a glossary-aware fake provider: it asks for the genitive of every
+// placeholder it was given forms for, the way a real provider would after "у".
+type inflectingTranslator struct {
+	FakeTranslator
+	glossary []GlossaryEntry
+}
+
+func (f *inflectingTranslator) TranslateWithGlossary(ctx context.Context, sourceLang, targetLang string, texts []string, glossary []GlossaryEntry) ([]string, error) {
+	f.glossary = append(f.glossary, glossary...)
+	out, err := f.FakeTranslator.Translate(ctx, sourceLang, targetLang, texts)
+	for i := range out {
+		for _, e := range glossary {
+			if _, ok := e.Forms["gen"]; ok {
+				out[i] = strings.ReplaceAll(out[i], e.Placeholder, "у "+strings.TrimSuffix(e.Placeholder, "⟧")+":gen⟧")
+			}
+		}
+	}
+	return out, err
+}
+
+func newTestPipeline(tr Translator, batchSize int) *Pipeline {
+	return NewPipeline(nil, nil, nil, nil, tr, nil, Options{BatchSize: batchSize}, zerolog.Nop())
+}
+
+func TestTranslateTextBatchesParagraphs(t *testing.T) {
+	tr := &countingTranslator{}
+	p := newTestPipeline(tr, 2)
+
+	text := "一\n\n二\n  三  \n\n四\n五"
+	got, err := p.TranslateText(context.Background(), "zh", "ru", text, nil)
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	want := "[zh>ru] 一\n\n[zh>ru] 二\n[zh>ru] 三\n\n[zh>ru] 四\n[zh>ru] 五"
+	if got != want {
+		t.Errorf("TranslateText =\n%q\nwant\n%q", got, want)
+	}
+	wantBatches := [][]string{{"一", "二"}, {"三", "四"}, {"五"}}
+	if len(tr.batches) != len(wantBatches) {
+		t.Fatalf("batches = %q, want %q", tr.batches, wantBatches)
+	}
+	for i := range wantBatches {
+		if strings.Join(tr.batches[i], "|") != strings.Join(wantBatches[i], "|") {
+			t.Errorf("batch %d = %q, want %q", i, tr.batches[i], wantBatches[i])
+		}
+	}
+}
+
+func TestTranslateTextAppliesGlossary(t *testing.T) {
+	tr := &countingTranslator{}
+	p := newTestPipeline(tr, 10)
+	terms := []models.GlossaryTerm{
+		term("林动", map[string]string{"ru": "Линь Дун"}),
+		term("林动天", map[string]string{"ru": "Линь Дунтянь"}),
+	}
+
+	got, err := p.TranslateText(context.Background(), "zh", "ru", "林动天\n林动说", terms)
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	if want := "[zh>ru] Линь Дунтянь\n[zh>ru] Линь Дун说"; got != want {
+		t.Errorf("TranslateText = %q, want %q", got, want)
+	}
+	for _, text := range tr.batches[0] {
+		if strings.Contains(text, "林动") {
+			t.Errorf("glossary term was sent to the provider: %q", text)
+		}
+	}
+}
+
+func TestTranslateTextPassesGlossaryForms(t *testing.T) {
+	tr := &inflectingTranslator{}
+	p := newTestPipeline(tr, 10)
+	terms := []models.GlossaryTerm{
+		{SourceTerm: "林动", Translations: models.GlossaryTranslations{"ru": {Target: "Линь Дун", Forms: map[string]string{"gen": "Линь Дуна"}}}},
+		term("小貂", map[string]string{"ru": "Куньчик"}),
+		term("岩", map[string]string{"ru": "Янь"}),
+	}
+
+	got, err := p.TranslateText(context.Background(), "zh", "ru", "林动的剑\n小貂", terms)
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	if want := "[zh>ru] у Линь Дуна的剑\n[zh>ru] Куньчик"; got != want {
+		t.Errorf("TranslateText = %q, want %q", got, want)
+	}
+	if len(tr.glossary) != 2 {
+		t.Fatalf("glossary sent = %+v, want only the terms in the batch", tr.glossary)
+	}
+	if e := tr.glossary[0]; e.Target != "Линь Дун" || e.Forms["gen"] != "Линь Дуна" {
+		t.Errorf("glossary entry = %+v, want the target with its forms", e)
+	}
+}
+
+func TestTranslateTextErrors(t *testing.T) {
+	if _, err := newTestPipeline(nil, 10).TranslateText(context.Background(), "zh", "ru", "一", nil); err == nil {
+		t.Error("expected an error without a provider")
+	}
+
+	if _, err := newTestPipeline(shortTranslator{}, 10).TranslateText(context.Background(), "zh", "ru", "一\n二", nil); err == nil {
+		t.Error("expected an error when the provider returns fewer texts")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := newTestPipeline(FakeTranslator{}, 10).TranslateText(ctx, "zh", "ru", "一", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRetranslateRange(t *testing.T) {
+	source := "一\n\n二\n三\n\n四"
+	existing := "Раз\n\nДва\nТри\n\nЧетыре"
+
+	tests := []struct {
+		name      string
+		existing  string
+		from, to  int
+		want      string
+		batchSent []string
+	}{
+		{
+			name:      "middle paragraphs",
+			existing:  existing,
+			from:      2,
+			to:        3,
+			want:      "Раз\n\n[zh>ru] 二\n[zh>ru] 三\n\nЧетыре",
+			batchSent: []string{"二", "三"},
+		},
+		{
+			name:      "range past the end is clamped",
+			existing:  existing,
+			from:      4,
+			to:        10,
+			want:      "Раз\n\nДва\nТри\n\n[zh>ru] 四",
+			batchSent: []string{"四"},
+		},
+		{
+			name:      "misaligned translation is retranslated whole",
+			existing:  "Раз\nДва",
+			from:      2,
+			to:        2,
+			want:      "[zh>ru] 一\n\n[zh>ru] 二\n[zh>ru] 三\n\n[zh>ru] 四",
+			batchSent: []string{"一", "二", "三", "四"},
+		},
+		{
+			name:      "range start out of bounds retranslates whole",
+			existing:  existing,
+			from:      5,
+			to:        6,
+			want:      "[zh>ru] 一\n\n[zh>ru] 二\n[zh>ru] 三\n\n[zh>ru] 四",
+			batchSent: []string{"一", "二", "三", "四"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &countingTranslator{}
+			p := newTestPipeline(tr, 10)
+			from, to := tt.from, tt.to
+			job := &models.TranslationJob{SourceLang: "zh", TargetLang: "ru", ParagraphFrom: &from, ParagraphTo: &to}
+
+			got, err := p.retranslateRange(context.Background(), job, source, tt.existing, nil)
+			if err != nil {
+				t.Fatalf("retranslateRange: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("retranslateRange =\n%q\nwant\n%q", got, tt.want)
+			}
+			if len(tr.batches) != 1 || strings.Join(tr.batches[0], "|") != strings.Join(tt.batchSent, "|") {
+				t.Errorf("sent %q, want %q", tr.batches, tt.batchSent)
+			}
+		})
+	}
+}
+
+func TestRetranslateRangeAppliesGlossary(t *testing.T) {
+	p := newTestPipeline(FakeTranslator{}, 10)
+	from, to := 2, 2
+	job := &models.TranslationJob{SourceLang: "zh", TargetLang: "ru", ParagraphFrom: &from, ParagraphTo: &to}
+	terms := []models.GlossaryTerm{term("林动", map[string]string{"ru": "Линь Дун"})}
+
+	got, err := p.retranslateRange(context.Background(), job, "一\n林动笑了", "Раз\nЛинь Дун засмеялся", terms)
+	if err != nil {
+		t.Fatalf("retranslateRange: %v", err)
+	}
+	if want := "Раз\n[zh>ru] Линь Дун笑了"; got != want {
+		t.Errorf("retranslateRange = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Translator is a machine-translation provider.
+// Translate must return exactly one result per input text, in the same order.
+type Translator interface {
+	Name() string
+	Translate(ctx context.Context, sourceLang, targetLang string, texts []string) ([]string, error)
+}
+
+// GlossaryEntry describes a placeholder in the texts: the glossary target it stands for and,
+// when editors entered them, its inflected forms keyed by case or gender ("gen", "dat", "fem").
+type GlossaryEntry struct {
+	Placeholder string            `json:"placeholder"`
+	Target      string            `json:"target"`
+	Forms       map[string]string `json:"forms,omitempty"`
+}
+
+// GlossaryTranslator is a provider that accepts the glossary as context. Besides keeping a
+// placeholder as-is it may write "⟦i:form⟧" to ask for one of the entry's forms, e.g. "⟦0:gen⟧"
+// where the sentence needs the genitive.
+type GlossaryTranslator interface {
+	Translator
+	TranslateWithGlossary(ctx context.Context, sourceLang, targetLang string, texts []string, glossary []GlossaryEntry) ([]string, error)
+}
+
+// ProviderOptions selects and configures a Translator.
+type ProviderOptions struct {
+	// Provider: "fake" or "http"; empty disables machine translation.
+	Provider string
+	HTTPURL  string
+	APIKey   string
+	Timeout  time.Duration
+}
+
+// NewTranslator builds the configured provider. Returns nil, nil when translation is disabled.
+func NewTranslator(opts ProviderOptions) (Translator, error) {
+	switch opts.Provider {
+	case "":
+		return nil, nil
+	case "fake":
+		return FakeTranslator{}, nil
+	case "http":
+		if opts.HTTPURL == "" {
+			return nil, fmt.Errorf("translation provider http: url is required")
+		}
+		return NewHTTPTranslator(opts.HTTPURL, opts.APIKey, opts.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown translation provider %q", opts.Provider)
+	}
+}
@@ -29,6 +29,8 @@ type ErrorDetail struct {
 	Code    string        `json:"code"`
 	Message string        `json:"message"`
 	Details []FieldError  `json:"details,omitempty"`
+	// Data дополнительные сведения об ошибке (например, найденные дубликаты)
+	Data interface{} `json:"data,omitempty"`
 }
 
 // FieldError представляет ошибку валидации поля
@@ -117,6 +119,24 @@ func Error(w http.ResponseWriter, statusCode int, code, message string) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+// ErrorWithData отправляет ответ с ошибкой и данными, которые помогают ее разрешить
+func ErrorWithData(w http.ResponseWriter, statusCode int, code, message string, data interface{}) {
+	resp := ErrorResponse{
+		Error: &ErrorDetail{
+			Code:    code,
+			Message: message,
+			Data:    data,
+		},
+		Meta: &Meta{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
 // ValidationError отправляет ответ с ошибками валидации
 func ValidationError(w http.ResponseWriter, errors []FieldError) {
 	resp := ErrorResponse{
@@ -179,3 +199,8 @@ func Forbidden(w http.ResponseWriter, message string) {
 func Conflict(w http.ResponseWriter, message string) {
 	Error(w, http.StatusConflict, "CONFLICT", message)
 }
+
+// TooManyRequests отправляет ответ 429 Too Many Requests
+func TooManyRequests(w http.ResponseWriter, message string) {
+	Error(w, http.StatusTooManyRequests, "RATE_LIMITED", message)
+}
@@ -0,0 +1,168 @@
+'use client';
+
+import { useEffect, useState } from 'react';
+import { useRouter } from 'next/navigation';
+import Link from 'next/link';
+import { useLocale } from 'next-intl';
+import { ArrowLeft, RotateCcw } from 'lucide-react';
+import { useAuthStore, isAdmin } from '@/store/auth';
+import { useAdminUser, useAdminUserXPEvents, useClawbackXP } from '@/lib/api/hooks/useAdminUsers';
+
+interface AdminUserPageProps {
+  params: {
+    locale: string;
+    id: string;
+  };
+}
+
+function scoreClass(score: number) {
+  if (score >= 60) return 'text-status-error';
+  if (score >= 30) return 'text-status-warning';
+  return 'text-status-success';
+}
+
+export default function AdminUserPage({ params }: AdminUserPageProps) {
+  const locale = useLocale();
+  const router = useRouter();
+  const { isAuthenticated, user, isLoading: authLoading } = useAuthStore();
+  const [page, setPage] = useState(1);
+  const [selected, setSelected] = useState<Set<string>>(new Set());
+  const hasAccess = isAuthenticated && isAdmin(user);
+
+  useEffect(() => {
+    if (!authLoading && !hasAccess) router.replace(`/${locale}`);
+  }, [authLoading, hasAccess, router, locale]);
+
+  const { data: target, isLoading } = useAdminUser(hasAccess ? params.id : '');
+  const { data: eventsData } = useAdminUserXPEvents(hasAccess ? params.id : '', page, 50);
+  const clawback = useClawbackXP();
+
+  const toggle = (id: string) => {
+    setSelected((prev) => {
+      const next = new Set(prev);
+      if (next.has(id)) next.delete(id);
+      else next.add(id);
+      return next;
+    });
+  };
+
+  const handleClawback = async () => {
+    if (selected.size === 0) return;
+    const reason = prompt(`Причина отзыва XP (${selected.size} событий):`);
+    if (!reason) return;
+    try {
+      const result = await clawback.mutateAsync({ id: params.id, eventIds: Array.from(selected), reason });
+      setSelected(new Set());
+      alert(`Отозвано событий: ${result.revoked}, XP: ${result.xpTaken}. Уровень: ${result.level}`);
+    } catch (error: any) {
+      alert(error.response?.data?.error?.message || 'Ошибка');
+    }
+  };
+
+  if (authLoading) return null;
+  if (!hasAccess) return null;
+
+  const activity = target?.readActivity;
+
+  return (
+    <div className="container-custom py-6">
+      <div className="flex items-center gap-4 mb-6">
+        <Link href={`/${locale}/admin/users`} className="btn-ghost p-2"><ArrowLeft className="w-5 h-5" /></Link>
+        <h1 className="text-2xl font-heading font-bold">{target?.email ?? 'Пользователь'}</h1>
+      </div>
+
+      {isLoading ? (
+        <div className="text-center py-12"><p className="text-foreground-secondary">Загрузка...</p></div>
+      ) : !target ? (
+        <div className="text-center py-12"><p className="text-foreground-secondary">Пользователь не найден</p></div>
+      ) : (
+        <>
+          {activity && (
+            <div className="bg-background-secondary rounded-card p-6 mb-6">
+              <div className="flex items-center justify-between mb-4">
+                <h2 className="text-lg font-semibold">Активность чтения за {Math.round(activity.windowHours / 24)} дн.</h2>
+                <span className={`text-2xl font-bold ${scoreClass(activity.score)}`} title="Оценка подозрительности (0-100)">
+                  {activity.score}
+                </span>
+              </div>
+              <div className="grid grid-cols-2 md:grid-cols-6 gap-4 text-sm">
+                <div><p className="text-foreground-muted">Открыто глав</p><p className="font-medium">{activity.sessions}</p></div>
+                <div><p className="text-foreground-muted">С XP</p><p className="font-medium">{activity.awarded}</p></div>
+                <div><p className="text-foreground-muted">Пролистано</p><p className="font-medium">{activity.skimmed}</p></div>
+                <div><p className="text-foreground-muted">Пик в час</p><p className="font-medium">{activity.peakPerHour}</p></div>
+                <div><p className="text-foreground-muted">Упор в лимит</p><p className="font-medium">{activity.capped}</p></div>
+                <div><p className="text-foreground-muted">Прыжки назад</p><p className="font-medium">{activity.regressions}</p></div>
+              </div>
+              {activity.reasons.length > 0 && (
+                <ul className="mt-4 list-disc list-inside text-sm text-foreground-secondary">
+                  {activity.reasons.map((r) => <li key={r}>{r}</li>)}
+                </ul>
+              )}
+            </div>
+          )}
+
+          <div className="bg-background-secondary rounded-card p-6">
+            <div className="flex items-center justify-between mb-4">
+              <h2 className="text-lg font-semibold">События XP</h2>
+              <button
+                onClick={handleClawback}
+                disabled={selected.size === 0 || clawback.isPending}
+                className="btn-secondary flex items-center gap-2"
+              >
+                <RotateCcw className="w-4 h-4" />
+                Отозвать ({selected.size})
+              </button>
+            </div>
+            <div className="overflow-x-auto">
+              <table className="w-full text-sm">
+                <thead>
+                  <tr className="border-b border-background-tertiary">
+                    <th className="py-3 px-4" />
+                    <th className="text-left py-3 px-4">Тип</th>
+                    <th className="text-right py-3 px-4">XP</th>
+                    <th className="text-left py-3 px-4">Объект</th>
+                    <th className="text-left py-3 px-4">Дата</th>
+                    <th className="text-left py-3 px-4">Статус</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {(eventsData?.events || []).map((e) => {
+                    const revocable = !e.revokedAt && e.delta > 0;
+                    return (
+                      <tr key={e.id} className="border-b border-background-tertiary hover:bg-background-hover">
+                        <td className="py-2 px-4">
+                          {revocable && (
+                            <input type="checkbox" checked={selected.has(e.id)} onChange={() => toggle(e.id)} />
+                          )}
+                        </td>
+                        <td className="py-2 px-4 font-mono">{e.type}</td>
+                        <td className="py-2 px-4 text-right">{e.delta}</td>
+                        <td className="py-2 px-4 font-mono text-xs">{e.refType}:{e.refId.slice(0, 8)}</td>
+                        <td className="py-2 px-4">{new Date(e.createdAt).toLocaleString(locale)}</td>
+                        <td className="py-2 px-4">
+                          {e.revokedAt ? (
+                            <span className="text-status-error" title={e.revokeReason}>Отозвано</span>
+                          ) : null}
+                        </td>
+                      </tr>
+                    );
+                  })}
+                </tbody>
+              </table>
+            </div>
+            <div className="flex justify-end gap-2 mt-6">
+              <button onClick={() => setPage((p) => Math.max(1, p - 1))} disabled={page === 1} className="btn-secondary">Назад</button>
+              <button
+                onClick={() => setPage((p) => p + 1)}
+                disabled={(eventsData?.events.length ?? 0) < 50}
+                className="btn-secondary"
+              >
+                Вперед
+              </button>
+            </div>
+          </div>
+        </>
+      )}
+    </div>
+  );
+}
@@ -91,7 +91,9 @@ export default function AdminUsersPage() {
                 <tbody>
                   {usersData.users.map((u) => (
                     <tr key={u.id} className="border-b border-background-tertiary hover:bg-background-hover">
-                      <td className="py-3 px-4 font-mono text-sm">{u.email}</td>
+                      <td className="py-3 px-4 font-mono text-sm">
+                        <Link href={`/${locale}/admin/users/${u.id}`} className="hover:text-accent-primary">{u.email}</Link>
+                      </td>
                       <td className="py-3 px-4">{u.displayName}</td>
                       <td className="py-3 px-4">
                         <div className="flex gap-1 flex-wrap">
@@ -52,6 +52,9 @@ const MAX_WIDTH_CLASSES = {
   full: 'max-w-none',
 };
 
+// How often reading progress is saved while a chapter is open
+const PROGRESS_SAVE_INTERVAL_MS = 20_000;
+
 const FONT_FAMILY_CLASSES = {
   sans: 'font-sans',
   serif: 'font-serif',
@@ -256,14 +259,23 @@ export default function ReaderPage({ params }: ReaderPageProps) {
     });
   }, [chapterId, isLoading, loaded.length, scrollToChapter]);
   
-  // Save progress when chapter loads
+  // Save progress when chapter loads, then periodically with the scroll offset inside the chapter:
+  // the server awards chapter XP only after enough time on the chapter and forward progress.
   useEffect(() => {
-    if (activeChapter && isAuthenticated) {
-      saveProgress({
-        chapterId: activeChapter.id,
-        position: 0,
-      });
-    }
+    if (!activeChapter || !isAuthenticated) return;
+    const id = activeChapter.id;
+    let lastPosition = 0;
+    saveProgress({ chapterId: id, position: 0 });
+
+    const timer = window.setInterval(() => {
+      const el = sectionTopRefs.current.get(id);
+      if (!el || document.visibilityState !== 'visible') return;
+      const position = Math.max(0, Math.round(-el.getBoundingClientRect().top));
+      if (position === lastPosition) return;
+      lastPosition = position;
+      saveProgress({ chapterId: id, position });
+    }, PROGRESS_SAVE_INTERVAL_MS);
+    return () => window.clearInterval(timer);
   }, [activeChapter?.id, isAuthenticated, saveProgress]);
   
   // Save settings to localStorage
@@ -15,9 +15,11 @@ import {
   Crown,
   ChevronRight,
   TrendingUp,
-  Sparkles
+  Sparkles,
+  Eye
 } from 'lucide-react';
 import { useUserProfile, useCurrentUser } from '@/lib/api/hooks/useAuth';
+import { useReadingStats, useUpdateStatsPrivacy } from '@/lib/api/hooks/useReadingStats';
 import { useAuthStore } from '@/store/auth';
 import { useRouter } from 'next/navigation';
 import { useQuery } from '@tanstack/react-query';
@@ -59,6 +61,8 @@ export default function ProfilePageClient() {
   const [mounted, setMounted] = useState(false);
   
   const { data: profile, isLoading, error } = useUserProfile();
+  const { data: readingStats } = useReadingStats('me', 'week', locale, isAuthenticated);
+  const updateStatsPrivacy = useUpdateStatsPrivacy();
   
   // Fetch subscription info
   const { data: subscriptionInfo } = useQuery<UserSubscriptionInfo>({
@@ -184,11 +188,11 @@ export default function ProfilePageClient() {
             <div className="flex flex-wrap justify-center md:justify-start gap-6 text-sm">
               <div className="flex items-center gap-1">
                 <BookOpen className="w-4 h-4 text-foreground-muted" />
-                <span>{profile.readChaptersCount} глав прочитано</span>
+                <span>{readingStats?.totals.chapters ?? profile.readChaptersCount} глав прочитано</span>
               </div>
               <div className="flex items-center gap-1">
                 <Clock className="w-4 h-4 text-foreground-muted" />
-                <span>{formatReadingTime(profile.readingTime)}</span>
+                <span>{formatReadingTime(readingMinutes(readingStats?.totals.seconds, profile.readingTime))}</span>
               </div>
               <div className="flex items-center gap-1">
                 <MessageSquare className="w-4 h-4 text-foreground-muted" />
@@ -199,6 +203,13 @@ export default function ProfilePageClient() {
           
           {/* Actions */}
           <div className="flex gap-2">
+            <Link
+              href={`/${locale}/users/${profile.id}`}
+              className="btn-secondary p-3"
+              title="Публичный профиль"
+            >
+              <Eye className="w-5 h-5" />
+            </Link>
             <Link href={`/${locale}/profile/settings`} className="btn-secondary p-3">
               <Settings className="w-5 h-5" />
             </Link>
@@ -270,45 +281,104 @@ export default function ProfilePageClient() {
       <div>
         {/* Stats Tab */}
         {activeTab === 'stats' && (
-          <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4">
-            <StatCard
-              icon={<BookOpen className="w-6 h-6" />}
-              label="Прочитано глав"
-              value={profile.readChaptersCount}
-              color="primary"
-            />
-            <StatCard
-              icon={<Clock className="w-6 h-6" />}
-              label="Время чтения"
-              value={formatReadingTime(profile.readingTime)}
-              color="secondary"
-            />
-            <StatCard
-              icon={<MessageSquare className="w-6 h-6" />}
-              label="Комментариев"
-              value={profile.commentsCount}
-              color="info"
-            />
-            <StatCard
-              icon={<Bookmark className="w-6 h-6" />}
-              label="В закладках"
-              value={profile.bookmarksCount}
-              color="warning"
-            />
-            <StatCard
-              icon={<TrendingUp className="w-6 h-6" />}
-              label="Уровень"
-              value={`Level ${profile.level}`}
-              subValue={`${profile.xp} XP`}
-              color="success"
-            />
-            <StatCard
-              icon={<Award className="w-6 h-6" />}
-              label="Достижения"
-              value={0}
-              subValue="Скоро"
-              color="muted"
-            />
+          <div className="space-y-6">
+            <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4">
+              <StatCard
+                icon={<BookOpen className="w-6 h-6" />}
+                label="Прочитано глав"
+                value={readingStats?.totals.chapters ?? profile.readChaptersCount}
+                color="primary"
+              />
+              <StatCard
+                icon={<Clock className="w-6 h-6" />}
+                label="Время чтения"
+                value={formatReadingTime(readingMinutes(readingStats?.totals.seconds, profile.readingTime))}
+                color="secondary"
+              />
+              <StatCard
+                icon={<MessageSquare className="w-6 h-6" />}
+                label="Комментариев"
+                value={profile.commentsCount}
+                color="info"
+              />
+              <StatCard
+                icon={<Bookmark className="w-6 h-6" />}
+                label="В закладках"
+                value={profile.bookmarksCount}
+                color="warning"
+              />
+              <StatCard
+                icon={<TrendingUp className="w-6 h-6" />}
+                label="Уровень"
+                value={`Level ${profile.level}`}
+                subValue={`${profile.xp} XP`}
+                color="success"
+              />
+              <StatCard
+                icon={<Award className="w-6 h-6" />}
+                label="Достижения"
+                value={0}
+                subValue="Скоро"
+                color="muted"
+              />
+            </div>
+
+            {readingStats && (
+              <div className="bg-background-secondary rounded-card p-6 space-y-6">
+                <div className="flex items-center justify-between">
+                  <h2 className="text-lg font-semibold">Статистика чтения</h2>
+                  <label className="flex items-center gap-2 text-sm text-foreground-secondary">
+                    <input
+                      type="checkbox"
+                      checked={readingStats.public}
+                      disabled={updateStatsPrivacy.isPending}
+                      onChange={(e) => updateStatsPrivacy.mutate(e.target.checked)}
+                    />
+                    Показывать в профиле
+                  </label>
+                </div>
+
+                {/* Chapters per week */}
+                <div>
+                  <p className="text-sm text-foreground-muted mb-2">Глав по неделям</p>
+                  <div className="flex items-end gap-1 h-24">
+                    {readingStats.periods.map((p) => {
+                      const maxChapters = Math.max(1, ...readingStats.periods.map((x) => x.chapters));
+                      return (
+                        <div
+                          key={p.start}
+                          className="flex-1 bg-accent-primary/70 rounded-t"
+                          style={{ height: `${(p.chapters / maxChapters) * 100}%` }}
+                          title={`${p.start}: ${p.chapters} глав, ${p.words} слов, ${formatReadingTime(readingMinutes(p.seconds, 0))}`}
+                        />
+                      );
+                    })}
+                  </div>
+                </div>
+
+                <div className="grid grid-cols-1 md:grid-cols-3 gap-4 text-sm">
+                  <div>
+                    <p className="text-foreground-muted mb-1">Любимые жанры</p>
+                    <p>{readingStats.favoriteGenres.map((g) => g.name).join(', ') || '—'}</p>
+                  </div>
+                  <div>
+                    <p className="text-foreground-muted mb-1">Дочитано из начатого</p>
+                    <p>
+                      {Math.round(readingStats.bookmarks.completionRate * 100)}% ({readingStats.bookmarks.completed} из{' '}
+                      {readingStats.bookmarks.started})
+                    </p>
+                  </div>
+                  <div>
+                    <p className="text-foreground-muted mb-1">Самый долгий заход</p>
+                    <p>
+                      {readingStats.longestBinge
+                        ? `${formatReadingTime(readingMinutes(readingStats.longestBinge.seconds, 0))}, ${readingStats.longestBinge.chapters} глав`
+                        : '—'}
+                    </p>
+                  </div>
+                </div>
+              </div>
+            )}
           </div>
         )}
         
@@ -459,6 +529,11 @@ function ProfileSkeleton() {
 }
 
 // Helper function
+// Reading time in minutes: from the reading statistics (seconds) if loaded
+function readingMinutes(seconds: number | undefined, fallback: number): number {
+  return seconds === undefined ? fallback : Math.round(seconds / 60);
+}
+
 function formatReadingTime(minutes: number): string {
   if (minutes < 60) return `${minutes} мин`;
   const hours = Math.floor(minutes / 60);
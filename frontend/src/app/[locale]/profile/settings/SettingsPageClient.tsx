@@ -3,10 +3,35 @@
 import { useState, useEffect } from 'react';
 import { useRouter } from 'next/navigation';
 import { useTranslations, useLocale } from 'next-intl';
-import { User, Mail, Lock, Save, ArrowLeft } from 'lucide-react';
+import { User, Mail, Lock, Save, ArrowLeft, Camera, Trash2, Eye } from 'lucide-react';
 import { useAuthStore } from '@/store/auth';
+import {
+  usePublicProfile,
+  useUpdateMyProfile,
+  useUpdateProfilePrivacy,
+  useUploadAvatar,
+  useDeleteAvatar,
+} from '@/lib/api/hooks/useProfile';
+import type { ProfileSection } from '@/lib/api/types';
 import Link from 'next/link';
 
+const PRIVACY_SECTIONS: { section: ProfileSection; label: string }[] = [
+  { section: 'achievements', label: 'Ачивки' },
+  { section: 'collections', label: 'Коллекции' },
+  { section: 'bookmarks', label: 'Статистика закладок' },
+  { section: 'comments', label: 'Последние комментарии' },
+  { section: 'activity', label: 'Лента активности' },
+  { section: 'stats', label: 'Статистика чтения' },
+];
+
+const MAX_AVATAR_SIZE = 2 * 1024 * 1024;
+
+function apiErrorMessage(error: unknown, fallback: string): string {
+  const message = (error as { response?: { data?: { error?: { message?: string } } } })?.response?.data?.error
+    ?.message;
+  return message || fallback;
+}
+
 export default function SettingsPageClient() {
   const t = useTranslations('profile');
   const locale = useLocale();
@@ -19,11 +44,18 @@ export default function SettingsPageClient() {
   
   // Form state
   const [displayName, setDisplayName] = useState('');
+  const [bio, setBio] = useState('');
   const [email, setEmail] = useState('');
   const [currentPassword, setCurrentPassword] = useState('');
   const [newPassword, setNewPassword] = useState('');
   const [confirmPassword, setConfirmPassword] = useState('');
   
+  const { data: profile } = usePublicProfile(user?.id ?? '', locale);
+  const updateProfile = useUpdateMyProfile();
+  const updatePrivacy = useUpdateProfilePrivacy();
+  const uploadAvatar = useUploadAvatar();
+  const deleteAvatar = useDeleteAvatar();
+  
   // Handle mount to prevent hydration mismatch
   useEffect(() => {
     setMounted(true);
@@ -44,6 +76,13 @@ export default function SettingsPageClient() {
     }
   }, [user]);
   
+  useEffect(() => {
+    if (profile) {
+      setDisplayName(profile.displayName);
+      setBio(profile.bio || '');
+    }
+  }, [profile]);
+  
   // Show loading or redirect during initial mount
   if (!mounted || !isAuthenticated) {
     return <SettingsSkeleton />;
@@ -55,24 +94,58 @@ export default function SettingsPageClient() {
     setMessage(null);
     
     try {
-      // TODO: Implement API call to update profile
-      // const response = await fetch('/api/profile', {
-      //   method: 'PUT',
-      //   headers: { 'Content-Type': 'application/json' },
-      //   body: JSON.stringify({ displayName, email })
-      // });
-      
-      // Simulate API call
-      await new Promise(resolve => setTimeout(resolve, 1000));
-      
+      await updateProfile.mutateAsync({ displayName, bio });
       setMessage({ type: 'success', text: 'Профиль успешно обновлён' });
     } catch (error) {
-      setMessage({ type: 'error', text: 'Ошибка при обновлении профиля' });
+      setMessage({ type: 'error', text: apiErrorMessage(error, 'Ошибка при обновлении профиля') });
     } finally {
       setLoading(false);
     }
   };
   
+  const handleAvatarChange = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0];
+    e.target.value = '';
+    if (!file) return;
+    setMessage(null);
+    
+    if (file.size > MAX_AVATAR_SIZE) {
+      setMessage({ type: 'error', text: 'Аватар должен быть не больше 2 МБ' });
+      return;
+    }
+    
+    try {
+      await uploadAvatar.mutateAsync(file);
+      setMessage({ type: 'success', text: 'Аватар обновлён' });
+    } catch (error) {
+      setMessage({ type: 'error', text: apiErrorMessage(error, 'Не удалось загрузить аватар') });
+    }
+  };
+  
+  const handleAvatarDelete = async () => {
+    setMessage(null);
+    try {
+      await deleteAvatar.mutateAsync();
+      setMessage({ type: 'success', text: 'Аватар удалён' });
+    } catch (error) {
+      setMessage({ type: 'error', text: apiErrorMessage(error, 'Не удалось удалить аватар') });
+    }
+  };
+  
+  const handlePrivacyChange = (section: ProfileSection, visible: boolean) => {
+    setMessage(null);
+    updatePrivacy.mutate(
+      { [section]: visible },
+      {
+        onError: (error) =>
+          setMessage({ type: 'error', text: apiErrorMessage(error, 'Не удалось сохранить настройки приватности') }),
+      }
+    );
+  };
+  
+  const avatarUrl = profile?.avatarUrl ?? user?.avatarUrl;
+  const avatarBusy = uploadAvatar.isPending || deleteAvatar.isPending;
+  
   const handlePasswordChange = async (e: React.FormEvent) => {
     e.preventDefault();
     setLoading(true);
@@ -144,6 +217,41 @@ export default function SettingsPageClient() {
           Основная информация
         </h2>
         
+        <div className="flex items-center gap-4 mb-6">
+          <div className="w-20 h-20 rounded-full bg-background-hover overflow-hidden flex items-center justify-center shrink-0">
+            {avatarUrl ? (
+              <img src={avatarUrl} alt={displayName} className="w-full h-full object-cover" />
+            ) : (
+              <User className="w-10 h-10 text-foreground-muted" />
+            )}
+          </div>
+          <div className="flex flex-wrap items-center gap-2">
+            <label className={`btn-secondary flex items-center gap-2 cursor-pointer ${avatarBusy ? 'opacity-50 pointer-events-none' : ''}`}>
+              <Camera className="w-4 h-4" />
+              {uploadAvatar.isPending ? 'Загрузка...' : 'Загрузить аватар'}
+              <input
+                type="file"
+                accept="image/jpeg,image/png,image/webp"
+                onChange={handleAvatarChange}
+                className="hidden"
+                disabled={avatarBusy}
+              />
+            </label>
+            {avatarUrl && (
+              <button
+                type="button"
+                onClick={handleAvatarDelete}
+                disabled={avatarBusy}
+                className="btn-ghost flex items-center gap-2 text-status-error"
+              >
+                <Trash2 className="w-4 h-4" />
+                Удалить
+              </button>
+            )}
+            <p className="w-full text-xs text-foreground-muted">JPG, PNG или WebP, до 2 МБ</p>
+          </div>
+        </div>
+        
         <form onSubmit={handleProfileUpdate} className="space-y-4">
           <div>
             <label htmlFor="displayName" className="block text-sm font-medium mb-2">
@@ -156,9 +264,26 @@ export default function SettingsPageClient() {
               onChange={(e) => setDisplayName(e.target.value)}
               className="w-full px-4 py-2 bg-background-primary border border-foreground-muted/20 rounded-lg focus:outline-none focus:border-accent-primary"
               required
+              minLength={2}
+              maxLength={100}
             />
           </div>
           
+          <div>
+            <label htmlFor="bio" className="block text-sm font-medium mb-2">
+              О себе
+            </label>
+            <textarea
+              id="bio"
+              value={bio}
+              onChange={(e) => setBio(e.target.value)}
+              rows={4}
+              maxLength={1000}
+              className="w-full px-4 py-2 bg-background-primary border border-foreground-muted/20 rounded-lg focus:outline-none focus:border-accent-primary resize-y"
+            />
+            <p className="text-xs text-foreground-muted mt-1">{bio.length}/1000</p>
+          </div>
+          
           <div>
             <label htmlFor="email" className="block text-sm font-medium mb-2 flex items-center gap-2">
               <Mail className="w-4 h-4" />
@@ -169,9 +294,10 @@ export default function SettingsPageClient() {
               id="email"
               value={email}
               onChange={(e) => setEmail(e.target.value)}
-              className="w-full px-4 py-2 bg-background-primary border border-foreground-muted/20 rounded-lg focus:outline-none focus:border-accent-primary"
-              required
+              className="w-full px-4 py-2 bg-background-primary border border-foreground-muted/20 rounded-lg focus:outline-none focus:border-accent-primary disabled:opacity-60"
+              disabled
             />
+            <p className="text-xs text-foreground-muted mt-1">Смена email пока недоступна</p>
           </div>
           
           <div className="pt-4">
@@ -187,6 +313,37 @@ export default function SettingsPageClient() {
         </form>
       </div>
       
+      {/* Privacy */}
+      <div className="bg-background-secondary rounded-card p-6 mb-6">
+        <h2 className="text-xl font-semibold mb-1 flex items-center gap-2">
+          <Eye className="w-5 h-5" />
+          Публичный профиль
+        </h2>
+        <p className="text-sm text-foreground-secondary mb-4">
+          Что видят другие пользователи на{' '}
+          {user && (
+            <Link href={`/${locale}/users/${user.id}`} className="text-accent-primary hover:underline">
+              странице вашего профиля
+            </Link>
+          )}
+        </p>
+        
+        <div className="space-y-3">
+          {PRIVACY_SECTIONS.map(({ section, label }) => (
+            <label key={section} className="flex items-center gap-3 cursor-pointer">
+              <input
+                type="checkbox"
+                checked={profile?.privacy?.[section] ?? false}
+                onChange={(e) => handlePrivacyChange(section, e.target.checked)}
+                disabled={!profile?.privacy || updatePrivacy.isPending}
+                className="w-4 h-4 accent-accent-primary"
+              />
+              <span>{label}</span>
+            </label>
+          ))}
+        </div>
+      </div>
+      
       {/* Change Password */}
       <div className="bg-background-secondary rounded-card p-6">
         <h2 className="text-xl font-semibold mb-4 flex items-center gap-2">
@@ -0,0 +1,326 @@
+'use client';
+
+import { useState } from 'react';
+import Link from 'next/link';
+import {
+  User,
+  Award,
+  Bookmark,
+  MessageSquare,
+  Activity,
+  FolderHeart,
+  FileText,
+  Pencil,
+  Lock,
+  Settings,
+  Calendar,
+} from 'lucide-react';
+import { usePublicProfile, useProfileActivity } from '@/lib/api/hooks/useProfile';
+import { CommentList } from '@/components/Comments/CommentList';
+import type { ProfileActivity, ProfileComment, ProfileSection } from '@/lib/api/types';
+
+interface UserProfileClientProps {
+  locale: string;
+  userId: string;
+}
+
+const BOOKMARK_LABELS: Record<string, string> = {
+  reading: 'Читаю',
+  planned: 'В планах',
+  completed: 'Прочитано',
+  favorites: 'Избранное',
+  dropped: 'Брошено',
+};
+
+function formatDate(value: string, locale: string) {
+  return new Date(value).toLocaleDateString(locale, { day: 'numeric', month: 'long', year: 'numeric' });
+}
+
+export default function UserProfileClient({ locale, userId }: UserProfileClientProps) {
+  const { data: profile, isLoading, error } = usePublicProfile(userId, locale);
+  const [activityPage, setActivityPage] = useState(1);
+  const activityVisible = !!profile && !profile.hidden.includes('activity');
+  const { data: activityData } = useProfileActivity(userId, activityPage, locale, activityVisible);
+
+  if (isLoading) {
+    return (
+      <div className="container-custom py-6 animate-pulse">
+        <div className="bg-background-secondary rounded-card h-40 mb-6" />
+        <div className="bg-background-secondary rounded-card h-64" />
+      </div>
+    );
+  }
+
+  if (error || !profile) {
+    return (
+      <div className="container-custom py-12 text-center">
+        <User className="w-16 h-16 mx-auto mb-4 text-foreground-muted" />
+        <h1 className="text-2xl font-heading font-bold mb-2">Пользователь не найден</h1>
+        <Link href={`/${locale}`} className="text-accent-primary hover:underline">
+          На главную
+        </Link>
+      </div>
+    );
+  }
+
+  const hidden = (section: ProfileSection) => profile.hidden.includes(section);
+  // The profile carries the first page; the feed endpoint pages further
+  const activity = activityData?.items ?? (activityPage === 1 ? profile.activity : []);
+  const hasMoreActivity = activityData?.hasMore ?? false;
+
+  return (
+    <div className="container-custom py-6 space-y-6">
+      {/* Header */}
+      <div className="bg-background-secondary rounded-card p-6">
+        <div className="flex flex-col md:flex-row items-center md:items-start gap-6">
+          <div className="w-24 h-24 rounded-full bg-background-hover overflow-hidden flex items-center justify-center shrink-0">
+            {profile.avatarUrl ? (
+              <img src={profile.avatarUrl} alt={profile.displayName} className="w-full h-full object-cover" />
+            ) : (
+              <User className="w-12 h-12 text-foreground-muted" />
+            )}
+          </div>
+          <div className="flex-1 text-center md:text-left">
+            <h1 className="text-2xl font-heading font-bold mb-1">{profile.displayName}</h1>
+            <div className="flex flex-wrap justify-center md:justify-start gap-4 text-sm text-foreground-secondary mb-3">
+              <span>Level {profile.level} · {profile.xpTotal} XP</span>
+              <span className="flex items-center gap-1">
+                <Calendar className="w-4 h-4" />
+                С нами с {formatDate(profile.joinedAt, locale)}
+              </span>
+            </div>
+            {profile.bio && <p className="text-foreground-secondary whitespace-pre-line">{profile.bio}</p>}
+          </div>
+          {profile.isOwner && (
+            <Link href={`/${locale}/profile/settings`} className="btn-secondary p-3" title="Настройки профиля">
+              <Settings className="w-5 h-5" />
+            </Link>
+          )}
+        </div>
+      </div>
+
+      <div className="grid lg:grid-cols-3 gap-6">
+        <div className="lg:col-span-2 space-y-6">
+          {/* Activity */}
+          <Section title="Активность" icon={<Activity className="w-5 h-5" />} hidden={hidden('activity')}>
+            {activity.length === 0 ? (
+              <Empty text="Пока ничего не произошло" />
+            ) : (
+              <ul className="space-y-3">
+                {activity.map((item) => (
+                  <ActivityItem key={`${item.type}-${item.refId}`} item={item} locale={locale} />
+                ))}
+              </ul>
+            )}
+            {(activityPage > 1 || hasMoreActivity) && (
+              <div className="flex justify-between mt-4 text-sm">
+                <button
+                  className="text-accent-primary hover:underline disabled:opacity-40"
+                  disabled={activityPage === 1}
+                  onClick={() => setActivityPage((p) => p - 1)}
+                >
+                  Новее
+                </button>
+                <button
+                  className="text-accent-primary hover:underline disabled:opacity-40"
+                  disabled={!hasMoreActivity}
+                  onClick={() => setActivityPage((p) => p + 1)}
+                >
+                  Раньше
+                </button>
+              </div>
+            )}
+          </Section>
+
+          {/* Recent comments */}
+          <Section title="Последние комментарии" icon={<MessageSquare className="w-5 h-5" />} hidden={hidden('comments')}>
+            {profile.recentComments.length === 0 ? (
+              <Empty text="Комментариев пока нет" />
+            ) : (
+              <ul className="space-y-4">
+                {profile.recentComments.map((comment) => (
+                  <RecentComment key={comment.id} comment={comment} locale={locale} />
+                ))}
+              </ul>
+            )}
+          </Section>
+
+          {/* Profile wall */}
+          <div className="bg-background-secondary rounded-card p-6">
+            <CommentList targetType="profile" targetId={profile.id} locale={locale} />
+          </div>
+        </div>
+
+        <div className="space-y-6">
+          {/* Achievements */}
+          <Section title="Ачивки" icon={<Award className="w-5 h-5" />} hidden={hidden('achievements')}>
+            {profile.achievements.length === 0 ? (
+              <Empty text="Ачивок пока нет" />
+            ) : (
+              <div className="flex flex-wrap gap-2">
+                {profile.achievements.map((badge) => (
+                  <span
+                    key={badge.id}
+                    title={`${badge.description}\n${formatDate(badge.unlockedAt, locale)}`}
+                    className="bg-accent-primary/10 text-accent-primary text-sm px-3 py-1 rounded-full"
+                  >
+                    {badge.title}
+                  </span>
+                ))}
+              </div>
+            )}
+          </Section>
+
+          {/* Collections */}
+          <Section title="Коллекции" icon={<FolderHeart className="w-5 h-5" />} hidden={hidden('collections')}>
+            {profile.collections.length === 0 ? (
+              <Empty text="Публичных коллекций нет" />
+            ) : (
+              <ul className="space-y-2">
+                {profile.collections.map((c) => (
+                  <li key={c.id}>
+                    <Link
+                      href={`/${locale}/collections/${c.id}`}
+                      className="flex justify-between gap-2 hover:text-accent-primary"
+                    >
+                      <span className="truncate">{c.title}</span>
+                      <span className="text-sm text-foreground-muted shrink-0">{c.itemsCount} книг</span>
+                    </Link>
+                  </li>
+                ))}
+              </ul>
+            )}
+          </Section>
+
+          {/* Bookmarks */}
+          <Section title="Закладки" icon={<Bookmark className="w-5 h-5" />} hidden={hidden('bookmarks')}>
+            {profile.bookmarkStats.length === 0 ? (
+              <Empty text="Закладок нет" />
+            ) : (
+              <ul className="space-y-1 text-sm">
+                {profile.bookmarkStats.map((s) => (
+                  <li key={s.listCode} className="flex justify-between">
+                    <span>{BOOKMARK_LABELS[s.listCode] ?? s.listCode}</span>
+                    <span className="text-foreground-muted">{s.count}</span>
+                  </li>
+                ))}
+              </ul>
+            )}
+          </Section>
+        </div>
+      </div>
+    </div>
+  );
+}
+
+function Section({
+  title,
+  icon,
+  hidden,
+  children,
+}: {
+  title: string;
+  icon: React.ReactNode;
+  hidden: boolean;
+  children: React.ReactNode;
+}) {
+  return (
+    <div className="bg-background-secondary rounded-card p-6">
+      <h2 className="text-lg font-semibold mb-4 flex items-center gap-2">
+        {icon}
+        {title}
+      </h2>
+      {hidden ? (
+        <p className="text-sm text-foreground-muted flex items-center gap-2">
+          <Lock className="w-4 h-4" />
+          Пользователь скрыл этот раздел
+        </p>
+      ) : (
+        children
+      )}
+    </div>
+  );
+}
+
+function Empty({ text }: { text: string }) {
+  return <p className="text-sm text-foreground-muted">{text}</p>;
+}
+
+function ActivityItem({ item, locale }: { item: ProfileActivity; locale: string }) {
+  const content: Record<ProfileActivity['type'], { icon: React.ReactNode; text: string; href?: string }> = {
+    collection_created: {
+      icon: <FolderHeart className="w-4 h-4" />,
+      text: 'Создал(а) коллекцию',
+      href: `/${locale}/collections/${item.refId}`,
+    },
+    proposal_submitted: {
+      icon: <FileText className="w-4 h-4" />,
+      text: 'Предложил(а) новеллу',
+      href: `/${locale}/proposals`,
+    },
+    achievement_unlocked: {
+      icon: <Award className="w-4 h-4" />,
+      text: 'Получил(а) ачивку',
+    },
+    wiki_edit_approved: {
+      icon: <Pencil className="w-4 h-4" />,
+      text: 'Правка одобрена:',
+      href: item.slug ? `/${locale}/novel/${item.slug}` : undefined,
+    },
+  };
+  const { icon, text, href } = content[item.type];
+
+  return (
+    <li className="flex items-start gap-3">
+      <span className="mt-0.5 text-foreground-muted">{icon}</span>
+      <div className="flex-1 min-w-0">
+        <span className="text-foreground-secondary">{text} </span>
+        {href ? (
+          <Link href={href} className="font-medium hover:text-accent-primary">
+            {item.title}
+          </Link>
+        ) : (
+          <span className="font-medium">{item.title}</span>
+        )}
+        <div className="text-xs text-foreground-muted">{formatDate(item.createdAt, locale)}</div>
+      </div>
+    </li>
+  );
+}
+
+function RecentComment({ comment, locale }: { comment: ProfileComment; locale: string }) {
+  const [revealed, setRevealed] = useState(!comment.isSpoiler);
+  const href =
+    comment.targetType === 'news' && comment.targetSlug
+      ? `/${locale}/news/${comment.targetSlug}`
+      : comment.targetType === 'chapter' && comment.targetSlug
+        ? `/${locale}/novel/${comment.targetSlug}/chapter/${comment.targetId}`
+        : comment.targetType === 'novel' && comment.targetSlug
+          ? `/${locale}/novel/${comment.targetSlug}`
+          : comment.targetType === 'profile'
+            ? `/${locale}/users/${comment.targetId}`
+            : undefined;
+
+  return (
+    <li className="border-b border-foreground-muted/10 pb-3 last:border-0 last:pb-0">
+      <div className="text-xs text-foreground-muted mb-1">
+        {href && comment.targetTitle ? (
+          <Link href={href} className="hover:text-accent-primary">
+            {comment.targetTitle}
+          </Link>
+        ) : (
+          comment.targetTitle
+        )}
+        {' · '}
+        {formatDate(comment.createdAt, locale)}
+      </div>
+      {revealed ? (
+        <p className="text-sm whitespace-pre-line line-clamp-4">{comment.body}</p>
+      ) : (
+        <button className="text-sm text-accent-warning hover:underline" onClick={() => setRevealed(true)}>
+          Спойлер — показать
+        </button>
+      )}
+    </li>
+  );
+}
@@ -0,0 +1,19 @@
+import { Metadata } from 'next';
+import { unstable_setRequestLocale } from 'next-intl/server';
+import UserProfileClient from './UserProfileClient';
+
+interface PageProps {
+  params: { locale: string; id: string };
+}
+
+export async function generateMetadata(): Promise<Metadata> {
+  return {
+    title: 'Профиль пользователя',
+    description: 'Профиль читателя',
+  };
+}
+
+export default function UserProfilePage({ params }: PageProps) {
+  unstable_setRequestLocale(params.locale);
+  return <UserProfileClient locale={params.locale} userId={params.id} />;
+}
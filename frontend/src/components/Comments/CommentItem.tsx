@@ -1,6 +1,7 @@
 'use client';
 
 import { useState } from 'react';
+import Link from 'next/link';
 import { useTranslations } from 'next-intl';
 import { formatDistanceToNow } from 'date-fns';
 import { ru, enUS } from 'date-fns/locale';
@@ -133,9 +134,16 @@ export function CommentItem({
         <div className="flex-1 min-w-0">
           {/* Header */}
           <div className="flex items-center gap-2 flex-wrap">
-            <span className="font-medium text-foreground-primary">
-              {comment.user?.displayName || t('deletedUser')}
-            </span>
+            {comment.user ? (
+              <Link
+                href={`/${locale}/users/${comment.user.id}`}
+                className="font-medium text-foreground-primary hover:text-accent-primary"
+              >
+                {comment.user.displayName}
+              </Link>
+            ) : (
+              <span className="font-medium text-foreground-primary">{t('deletedUser')}</span>
+            )}
             
             {comment.user && (
               <span className={`px-1.5 py-0.5 text-xs text-white rounded ${getLevelBadgeColor(comment.user.level)}`}>
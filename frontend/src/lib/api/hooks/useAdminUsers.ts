@@ -1,6 +1,6 @@
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import api from '../client';
-import type { UserAdmin, UsersResponse, BanUserRequest, UpdateUserRolesRequest } from '../types';
+import type { UserAdmin, UsersResponse, BanUserRequest, UpdateUserRolesRequest, XPEvent, ClawbackXPRequest, ClawbackXPResult } from '../types';
 
 export function useAdminUsers(params?: {
   query?: string;
@@ -76,3 +76,30 @@ export function useUpdateUserRoles() {
     },
   });
 }
+
+export function useAdminUserXPEvents(id: string, page = 1, limit = 50) {
+  return useQuery<{ events: XPEvent[]; page: number; limit: number }>({
+    queryKey: ['admin', 'users', id, 'xp-events', page, limit],
+    queryFn: async () => {
+      const { data } = await api.get<{ events: XPEvent[]; page: number; limit: number }>(
+        `/admin/users/${id}/xp-events?page=${page}&limit=${limit}`
+      );
+      return data;
+    },
+    enabled: !!id,
+  });
+}
+
+export function useClawbackXP() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: async ({ id, ...req }: { id: string } & ClawbackXPRequest) => {
+      const { data } = await api.post<ClawbackXPResult>(`/admin/users/${id}/xp-events/clawback`, req);
+      return data;
+    },
+    onSuccess: (_, { id }) => {
+      queryClient.invalidateQueries({ queryKey: ['admin', 'users', id] });
+    },
+  });
+}
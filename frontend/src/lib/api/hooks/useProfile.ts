@@ -0,0 +1,119 @@
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../client';
+import { useAuthStore } from '@/store/auth';
+import { authKeys } from './useAuth';
+import { readingStatsKeys } from './useReadingStats';
+import type {
+  ProfileActivityPage,
+  ProfilePrivacy,
+  PublicProfile,
+  UpdateProfileRequest,
+} from '../types';
+
+export const profileKeys = {
+  all: ['profiles'] as const,
+  detail: (userId: string, lang: string) => [...profileKeys.all, userId, lang] as const,
+  activity: (userId: string, page: number, lang: string) =>
+    [...profileKeys.all, userId, 'activity', page, lang] as const,
+};
+
+// Public profile; sections hidden by the user come back empty and are listed in `hidden`
+export function usePublicProfile(userId: string, lang = 'ru') {
+  return useQuery<PublicProfile>({
+    queryKey: profileKeys.detail(userId, lang),
+    queryFn: async () => {
+      const { data } = await api.get<PublicProfile>(`/users/${userId}?lang=${lang}`);
+      return data;
+    },
+    enabled: !!userId,
+    retry: false,
+  });
+}
+
+export function useProfileActivity(userId: string, page = 1, lang = 'ru', enabled = true) {
+  return useQuery<ProfileActivityPage>({
+    queryKey: profileKeys.activity(userId, page, lang),
+    queryFn: async () => {
+      const { data } = await api.get<ProfileActivityPage>(
+        `/users/${userId}/activity?page=${page}&limit=20&lang=${lang}`
+      );
+      return data;
+    },
+    enabled: !!userId && enabled,
+    retry: false,
+  });
+}
+
+// Display name and bio; the auth store keeps the display name shown in the header
+export function useUpdateMyProfile() {
+  const queryClient = useQueryClient();
+  const { user, setUser } = useAuthStore();
+
+  return useMutation({
+    mutationFn: async (req: UpdateProfileRequest) => {
+      const { data } = await api.put<PublicProfile>('/me/profile', req);
+      return data;
+    },
+    onSuccess: (profile) => {
+      if (user) {
+        setUser({ ...user, displayName: profile.displayName });
+      }
+      queryClient.invalidateQueries({ queryKey: profileKeys.all });
+      queryClient.invalidateQueries({ queryKey: authKeys.profile });
+    },
+  });
+}
+
+export function useUpdateProfilePrivacy() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: async (req: Partial<ProfilePrivacy>) => {
+      const { data } = await api.put<ProfilePrivacy>('/me/profile/privacy', req);
+      return data;
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: profileKeys.all });
+      queryClient.invalidateQueries({ queryKey: readingStatsKeys.all });
+    },
+  });
+}
+
+export function useUploadAvatar() {
+  const queryClient = useQueryClient();
+  const { user, setUser } = useAuthStore();
+
+  return useMutation({
+    mutationFn: async (file: File) => {
+      const form = new FormData();
+      form.append('file', file);
+      const { data } = await api.post<{ avatarUrl: string }>('/me/profile/avatar', form, {
+        headers: { 'Content-Type': 'multipart/form-data' },
+      });
+      return data;
+    },
+    onSuccess: ({ avatarUrl }) => {
+      if (user) {
+        setUser({ ...user, avatarUrl });
+      }
+      queryClient.invalidateQueries({ queryKey: profileKeys.all });
+    },
+  });
+}
+
+export function useDeleteAvatar() {
+  const queryClient = useQueryClient();
+  const { user, setUser } = useAuthStore();
+
+  return useMutation({
+    mutationFn: async () => {
+      await api.delete('/me/profile/avatar');
+    },
+    onSuccess: () => {
+      if (user) {
+        setUser({ ...user, avatarUrl: undefined });
+      }
+      queryClient.invalidateQueries({ queryKey: profileKeys.all });
+    },
+  });
+}
@@ -0,0 +1,52 @@
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../client';
+import type { ReadingStats, YearInReview } from '../types';
+
+export const readingStatsKeys = {
+  all: ['reading-stats'] as const,
+  stats: (userId: string, period: string, lang: string) => [...readingStatsKeys.all, userId, period, lang] as const,
+  yearInReview: (userId: string, year: number, lang: string) =>
+    [...readingStatsKeys.all, userId, 'year', year, lang] as const,
+};
+
+// Reading statistics of the current user (userId "me") or of a user who made them public
+export function useReadingStats(userId: string = 'me', period: 'week' | 'month' = 'week', lang = 'ru', enabled = true) {
+  return useQuery<ReadingStats>({
+    queryKey: readingStatsKeys.stats(userId, period, lang),
+    queryFn: async () => {
+      const path = userId === 'me' ? '/me/stats' : `/users/${userId}/stats`;
+      const { data } = await api.get<ReadingStats>(`${path}?period=${period}&lang=${lang}`);
+      return data;
+    },
+    enabled,
+    retry: false,
+  });
+}
+
+// Year in review; 404 until the year_review job generated it
+export function useYearInReview(year: number, userId: string = 'me', lang = 'ru', enabled = true) {
+  return useQuery<YearInReview>({
+    queryKey: readingStatsKeys.yearInReview(userId, year, lang),
+    queryFn: async () => {
+      const path = userId === 'me' ? '/me/year-in-review' : `/users/${userId}/year-in-review`;
+      const { data } = await api.get<YearInReview>(`${path}/${year}?lang=${lang}`);
+      return data;
+    },
+    enabled,
+    retry: false,
+  });
+}
+
+export function useUpdateStatsPrivacy() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: async (isPublic: boolean) => {
+      const { data } = await api.put<{ public: boolean }>('/me/stats/privacy', { public: isPublic });
+      return data;
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: readingStatsKeys.all });
+    },
+  });
+}
@@ -322,6 +322,44 @@ export interface CreateTagRequest {
 export interface UserAdmin extends User {
   isBanned: boolean;
   lastLoginAt?: string;
+  readActivity?: ReadActivityReport;
+}
+
+// Chapter reads of a user over the last windowHours; score is 0-100 (suspicious activity)
+export interface ReadActivityReport {
+  windowHours: number;
+  sessions: number;
+  awarded: number;
+  skimmed: number;
+  regressions: number;
+  capped: number;
+  peakPerHour: number;
+  score: number;
+  reasons: string[];
+}
+
+export interface XPEvent {
+  id: string;
+  userId: string;
+  type: string;
+  delta: number;
+  refType: string;
+  refId: string;
+  createdAt: string;
+  revokedAt?: string;
+  revokeReason?: string;
+}
+
+export interface ClawbackXPRequest {
+  eventIds: string[];
+  reason: string;
+}
+
+export interface ClawbackXPResult {
+  revoked: number;
+  xpTaken: number;
+  xpTotal: number;
+  level: number;
 }
 
 export interface BanUserRequest {
@@ -439,3 +477,156 @@ export interface AuditLogsResponse {
   page: number;
   limit: number;
 }
+
+// Reading Stats Types
+export interface ReadingTotals {
+  seconds: number;
+  chapters: number;
+  words: number;
+  daysRead: number;
+  novelsRead: number;
+}
+
+export interface ReadingPeriodStats {
+  start: string; // first day of the week (Monday) or month
+  seconds: number;
+  chapters: number;
+  words: number;
+}
+
+export interface ReadingCategoryStats {
+  id: string;
+  slug: string;
+  name: string;
+  seconds: number;
+  chapters: number;
+}
+
+export interface NovelReadingStats {
+  novelId: string;
+  slug: string;
+  title: string;
+  coverUrl?: string;
+  seconds: number;
+  chapters: number;
+}
+
+export interface ReadingBinge {
+  startedAt: string;
+  seconds: number;
+  chapters: number;
+}
+
+export interface ReadingStats {
+  userId: string;
+  public: boolean;
+  totals: ReadingTotals;
+  period: 'week' | 'month';
+  periods: ReadingPeriodStats[];
+  favoriteGenres: ReadingCategoryStats[];
+  favoriteTags: ReadingCategoryStats[];
+  bookmarks: {
+    total: number;
+    started: number;
+    completed: number;
+    dropped: number;
+    completionRate: number;
+  };
+  longestBinge?: ReadingBinge;
+}
+
+export interface YearInReview {
+  userId: string;
+  year: number;
+  totals: ReadingTotals;
+  topNovels: NovelReadingStats[];
+  topGenres: ReadingCategoryStats[];
+  topTags: ReadingCategoryStats[];
+  busiestMonth?: { month: string; seconds: number; chapters: number };
+  longestBinge?: ReadingBinge;
+  longestStreak: number;
+  novelsCompleted: number;
+  generatedAt: string;
+}
+
+// Public Profile Types
+export type ProfileSection = 'achievements' | 'collections' | 'bookmarks' | 'comments' | 'activity' | 'stats';
+
+export type ProfilePrivacy = Record<ProfileSection, boolean>;
+
+export interface AchievementBadge {
+  id: string;
+  code: string;
+  title: string;
+  description: string;
+  iconKey: string;
+  unlockedAt: string;
+}
+
+export interface ProfileCollection {
+  id: string;
+  slug: string;
+  title: string;
+  description?: string;
+  coverUrl?: string;
+  votesCount: number;
+  itemsCount: number;
+  createdAt: string;
+}
+
+export interface ProfileComment {
+  id: string;
+  targetType: 'novel' | 'chapter' | 'news' | 'profile';
+  targetId: string;
+  targetTitle?: string;
+  targetSlug?: string;
+  body: string;
+  isSpoiler: boolean;
+  likesCount: number;
+  createdAt: string;
+}
+
+export type ProfileActivityType =
+  | 'collection_created'
+  | 'proposal_submitted'
+  | 'achievement_unlocked'
+  | 'wiki_edit_approved';
+
+export interface ProfileActivity {
+  type: ProfileActivityType;
+  refId: string;
+  title: string;
+  slug?: string;
+  iconKey?: string;
+  createdAt: string;
+}
+
+export interface ProfileActivityPage {
+  items: ProfileActivity[];
+  page: number;
+  limit: number;
+  hasMore: boolean;
+}
+
+export interface PublicProfile {
+  id: string;
+  displayName: string;
+  avatarUrl?: string;
+  bio?: string;
+  level: number;
+  xpTotal: number;
+  joinedAt: string;
+  isOwner: boolean;
+  privacy?: ProfilePrivacy;
+  hidden: ProfileSection[];
+  achievements: AchievementBadge[];
+  collections: ProfileCollection[];
+  bookmarkStats: { listCode: string; count: number }[];
+  recentComments: ProfileComment[];
+  activity: ProfileActivity[];
+}
+
+export interface UpdateProfileRequest {
+  displayName?: string;
+  bio?: string;
+}
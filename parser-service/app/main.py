@@ -120,6 +120,8 @@ async def parse(req: ParseRequest) -> ParseResponse:
                 refs = refs[: req.chapters_limit]
                 print(f"[parser-service] limited chapters to {len(refs)} (limit={req.chapters_limit})", flush=True)
             book.chapters = refs
+            if req.metadata_only:
+                return ParseResponse(site="101kks", book=book, chapters=[], debug={"storage_state_path": storage_path})
             print(f"[parser-service] processing {len(refs)} chapters", flush=True)
             chapters = []
             for i, ref in enumerate(refs, start=1):
@@ -164,6 +166,8 @@ async def parse(req: ParseRequest) -> ParseResponse:
             if req.chapters_limit and req.chapters_limit > 0:
                 refs = refs[: req.chapters_limit]
             book.chapters = refs
+            if req.metadata_only:
+                return ParseResponse(site="69shuba", book=book, chapters=[], debug={"storage_state_path": storage_path})
             chapters = []
             for i, ref in enumerate(refs, start=1):
                 print(f"[parser-service] 69shuba chapter {i}/{len(refs)} url={ref.url}", flush=True)
@@ -189,6 +193,8 @@ async def parse(req: ParseRequest) -> ParseResponse:
             if req.chapters_limit and req.chapters_limit > 0:
                 refs = refs[: req.chapters_limit]
             book.chapters = refs
+            if req.metadata_only:
+                return ParseResponse(site="tadu", book=book, chapters=[], debug={"storage_state_path": storage_path})
             chapters = []
             for i, ref in enumerate(refs, start=1):
                 print(f"[parser-service] tadu chapter {i}/{len(refs)} url={ref.url}", flush=True)
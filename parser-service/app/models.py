@@ -12,6 +12,9 @@ class ParseRequest(BaseModel):
     url: str = Field(..., description="Book page URL")
     site: Optional[Site] = Field(None, description="Optional explicit site override")
     chapters_limit: int = Field(0, ge=0, description="0 = no limit")
+    metadata_only: bool = Field(
+        False, description="If true, return book metadata and the chapter list without fetching chapter contents"
+    )
     user_agent: Optional[str] = None
     referer: Optional[str] = Field(None, description="Optional referer for initial navigation")
     cookie_header: Optional[str] = Field(
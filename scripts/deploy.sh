@@ -60,7 +60,7 @@ case $ACTION in
         
     migrate)
         echo -e "${YELLOW}Running database migrations...${NC}"
-        docker-compose -f docker-compose.prod.yml exec api ./server migrate up
+        docker-compose -f docker-compose.prod.yml exec api ./migrate up
         echo -e "${GREEN}Migrations completed!${NC}"
         ;;
         